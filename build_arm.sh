@@ -35,6 +35,7 @@ function build(){
 function download_pkgs(){
     pkg_list=(
         "gopkg.in/mgo.v2"
+        "gopkg.in/yaml.v2"
         )
 
     idx=1
@@ -19,143 +19,134 @@
 package agent
 
 import (
+	"api/auth"
 	"api/common"
-	"commons/errors"
+	"api/router"
 	"commons/logger"
 	"commons/results"
 	URL "commons/url"
 	"manager/agent"
 	"net/http"
-	"strings"
 )
 
 const (
 	GET    string = "GET"
 	PUT    string = "PUT"
 	POST   string = "POST"
+	PATCH  string = "PATCH"
 	DELETE string = "DELETE"
+
+	AGENT_ID string = "agentID" // name of the path parameter for an agent id.
+	APP_ID   string = "appID"   // name of the path parameter for an app id.
 )
 
-type _SDAMAgentApisHandler struct{}
 type _SDAMAgentApis struct{}
 
-var sdamH _SDAMAgentApisHandler
 var sdam _SDAMAgentApis
 var sdamAgentController agent.AgentInterface
 
 func init() {
-	SdamAgentHandle = sdamH
 	SdamAgent = sdam
 	sdamAgentController = agent.AgentController{}
 }
 
-// Handle calls a proper function according to the url and method received from remote device.
-func (sdamH _SDAMAgentApisHandler) Handle(w http.ResponseWriter, req *http.Request) {
-	url := strings.Replace(req.URL.Path, URL.Base()+URL.Agents(), "", -1)
-	split := strings.Split(url, "/")
-	switch len(split) {
-	case 1:
-		if req.Method == GET {
+// Routes returns a list of routes which calls a proper function according to
+// the url and method received from remote device.
+// Requests to register and ping are sent by agents and do not require authentication.
+// Agents prove their identity with the secret issued at registration instead.
+func Routes() []router.Route {
+	agents := URL.Base() + URL.Agents()
+	agent := agents + "/{" + AGENT_ID + "}"
+	apps := agent + URL.Apps()
+	app := apps + "/{" + APP_ID + "}"
+
+	// Callers limited to some groups are allowed to access the agents in the groups only,
+	// and not allowed to list every agent.
+	viewer := auth.Permit(auth.VIEWER, auth.RequireAgent(AGENT_ID))
+	operator := auth.Permit(auth.OPERATOR, auth.RequireAgent(AGENT_ID))
+	admin := auth.Permit(auth.ADMIN, auth.RequireAgent(AGENT_ID))
+
+	return []router.Route{
+		{Method: GET, Pattern: agents, Middlewares: auth.Permit(auth.VIEWER, auth.RequireUnscoped), Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
 			SdamAgent.agents(w, req)
-		} else {
-			common.WriteError(w, errors.InvalidMethod{req.Method})
-		}
-
-	case 2:
-		if "/"+split[1] == URL.Register() {
-			if req.Method == POST {
-				SdamAgent.agentRegister(w, req)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else {
-			if req.Method == GET {
-				agentID := split[1]
-				SdamAgent.agent(w, req, agentID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		}
-
-	case 3:
-		agentID := split[1]
-		if "/"+split[2] == URL.Deploy() {
-			if req.Method == POST {
-				SdamAgent.agentDeployApp(w, req, agentID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else if "/"+split[2] == URL.Unregister() {
-			if req.Method == POST {
-				SdamAgent.agentUnregister(w, req, agentID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else if "/"+split[2] == URL.Ping() {
-			if req.Method == POST {
-				SdamAgent.agentPing(w, req, agentID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else if "/"+split[2] == URL.Apps() {
-			if req.Method == GET {
-				SdamAgent.agentInfoApps(w, req, agentID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else {
-			common.WriteError(w, errors.NotFoundURL{})
-		}
-
-	case 4:
-		if "/"+split[2] == URL.Apps() {
-			agentID, appID := split[1], split[3]
-			switch req.Method {
-			case GET:
-				SdamAgent.agentInfoApp(w, req, agentID, appID)
-
-			case POST:
-				SdamAgent.agentUpdateAppInfo(w, req, agentID, appID)
-
-			case DELETE:
-				SdamAgent.agentDeleteApp(w, req, agentID, appID)
-
-			default:
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else {
-			common.WriteError(w, errors.NotFoundURL{})
-		}
-
-	case 5:
-		if "/"+split[2] == URL.Apps() {
-			agentID, appID := split[1], split[3]
-			switch {
-			case "/"+split[4] == URL.Start() && req.Method == POST:
-				SdamAgent.agentStartApp(w, req, agentID, appID)
-
-			case "/"+split[4] == URL.Stop() && req.Method == POST:
-				SdamAgent.agentStopApp(w, req, agentID, appID)
-
-			case "/"+split[4] == URL.Update() && req.Method == POST:
-				SdamAgent.agentUpdateApp(w, req, agentID, appID)
-
-			default:
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else {
-			common.WriteError(w, errors.NotFoundURL{})
-		}
+		}},
+		{Method: POST, Pattern: agents + URL.Retention(), Middlewares: auth.Permit(auth.ADMIN, auth.RequireUnscoped), Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentsRetention(w, req)
+		}},
+		{Method: POST, Pattern: agents + URL.Register(), Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentRegister(w, req)
+		}},
+		{Method: GET, Pattern: agent, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agent(w, req, params[AGENT_ID])
+		}},
+		{Method: GET, Pattern: agent + URL.Events(), Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentEvents(w, req, params[AGENT_ID])
+		}},
+		{Method: GET, Pattern: agent + URL.Availability(), Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentAvailability(w, req, params[AGENT_ID])
+		}},
+		{Method: PATCH, Pattern: agent + URL.Labels(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentLabels(w, req, params[AGENT_ID])
+		}},
+		{Method: PUT, Pattern: agent + URL.HealthCheck(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentHealthCheck(w, req, params[AGENT_ID])
+		}},
+		{Method: POST, Pattern: agent + URL.Secret(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentSecret(w, req, params[AGENT_ID])
+		}},
+		{Method: PUT, Pattern: agent + URL.Retention(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentRetention(w, req, params[AGENT_ID])
+		}},
+		{Method: POST, Pattern: agent + URL.Deploy(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentDeployApp(w, req, params[AGENT_ID])
+		}},
+		{Method: POST, Pattern: agent + URL.Unregister(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentUnregister(w, req, params[AGENT_ID])
+		}},
+		{Method: POST, Pattern: agent + URL.Decommission(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentDecommission(w, req, params[AGENT_ID])
+		}},
+		{Method: POST, Pattern: agent + URL.Reconcile(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentReconcile(w, req, params[AGENT_ID])
+		}},
+		{Method: POST, Pattern: agent + URL.Ping(), Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentPing(w, req, params[AGENT_ID])
+		}},
+		{Method: GET, Pattern: apps, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentInfoApps(w, req, params[AGENT_ID])
+		}},
+		{Method: GET, Pattern: app, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentInfoApp(w, req, params[AGENT_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app, Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentUpdateAppInfo(w, req, params[AGENT_ID], params[APP_ID])
+		}},
+		{Method: DELETE, Pattern: app, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentDeleteApp(w, req, params[AGENT_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app + URL.Start(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentStartApp(w, req, params[AGENT_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app + URL.Stop(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentStopApp(w, req, params[AGENT_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app + URL.Update(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentUpdateApp(w, req, params[AGENT_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app + URL.Rollback(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamAgent.agentRollbackApp(w, req, params[AGENT_ID], params[APP_ID])
+		}},
 	}
 }
 
 // agentRegister handles requests which is used to register agent to a list of agents.
 //
-//    paths: '/api/v1/agents/{agentID}/register'
+//    paths: '/api/v1/agents/register'
 //    method: POST
-//    responses: if successful, 200 status code will be returned.
+//    responses: if successful, 200 status code will be returned with a secret issued to a new agent.
+//               401 status code will be returned if a known agent does not give its secret.
 func (sdam _SDAMAgentApis) agentRegister(w http.ResponseWriter, req *http.Request) {
-	logger.Logging(logger.DEBUG, "[AGENT] Register New Service Deployment Agent")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Register New Service Deployment Agent")
 
 	body, err := common.GetBodyFromReq(req)
 	if err != nil {
@@ -163,7 +154,7 @@ func (sdam _SDAMAgentApis) agentRegister(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	result, resp, err := sdamAgentController.AddAgent(body)
+	result, resp, err := sdamAgentController.AddAgent(req.Context(), body)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -173,21 +164,55 @@ func (sdam _SDAMAgentApis) agentRegister(w http.ResponseWriter, req *http.Reques
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentUnregister(w http.ResponseWriter, req *http.Request, agentID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Unregister New Service Deployment Agent")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Unregister New Service Deployment Agent")
 
-	result, err := sdamAgentController.DeleteAgent(agentID)
+	result, err := sdamAgentController.DeleteAgent(req.Context(), agentID)
 	common.MakeResponse(w, result, nil, err)
 }
 
+// agentDecommission handles requests which is used to decommission agent identified by the given agentID.
+// The body is optional, and the result of each step is returned.
+//
+//    paths: '/api/v1/agents/{agentID}/decommission'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+//               207 status code will be returned if the agent is removed by force in spite of failures.
+func (sdam _SDAMAgentApis) agentDecommission(w http.ResponseWriter, req *http.Request, agentID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Decommission Service Deployment Agent")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamAgentController.Decommission(req.Context(), agentID, body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentReconcile handles requests which is used to record the apps which agent identified
+// by the given agentID actually runs.
+//
+//    paths: '/api/v1/agents/{agentID}/reconcile'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentReconcile(w http.ResponseWriter, req *http.Request, agentID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Reconcile Apps")
+
+	result, resp, err := sdamAgentController.Reconcile(req.Context(), agentID)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
 // agentPing handles requests which is used to check whether an agent is up.
 //
 //    paths: '/api/v1/agents/{agentID}/ping'
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
+//               401 status code will be returned if the address changes without the secret of the agent.
 func (sdam _SDAMAgentApis) agentPing(w http.ResponseWriter, req *http.Request, agentID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Ping From Service Deployment Agent")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Ping From Service Deployment Agent")
 
-	ip := strings.Split(req.RemoteAddr, ":")[0]
+	// The address of the agent may have changed since the last ping.
+	ip := common.ClientIP(req)
 
 	body, err := common.GetBodyFromReq(req)
 	if err != nil {
@@ -195,7 +220,7 @@ func (sdam _SDAMAgentApis) agentPing(w http.ResponseWriter, req *http.Request, a
 		return
 	}
 
-	result, err := sdamAgentController.PingAgent(agentID, ip, body)
+	result, err := sdamAgentController.PingAgent(req.Context(), agentID, ip, body)
 	common.MakeResponse(w, result, nil, err)
 }
 
@@ -205,8 +230,8 @@ func (sdam _SDAMAgentApis) agentPing(w http.ResponseWriter, req *http.Request, a
 //    method: GET
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agent(w http.ResponseWriter, req *http.Request, agentID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Get Service Deployment Agent")
-	result, resp, err := sdamAgentController.GetAgent(agentID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Get Service Deployment Agent")
+	result, resp, err := sdamAgentController.GetAgent(req.Context(), agentID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -216,8 +241,113 @@ func (sdam _SDAMAgentApis) agent(w http.ResponseWriter, req *http.Request, agent
 //    method: GET
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agents(w http.ResponseWriter, req *http.Request) {
-	logger.Logging(logger.DEBUG, "[AGENT] Get All Service Deployment Agents")
-	result, resp, err := sdamAgentController.GetAgents()
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Get All Service Deployment Agents")
+	result, resp, err := sdamAgentController.GetAgents(req.Context(), req.URL.Query())
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentEvents handles requests which is used to get events of agent identified by the given agentID.
+//
+//    paths: '/api/v1/agents/{agentID}/events'
+//    method: GET
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentEvents(w http.ResponseWriter, req *http.Request, agentID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Get Events")
+	result, resp, err := sdamAgentController.GetEvents(req.Context(), agentID, req.URL.Query())
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentAvailability handles requests which is used to get availability of agent identified by the given agentID.
+//
+//    paths: '/api/v1/agents/{agentID}/availability'
+//    method: GET
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentAvailability(w http.ResponseWriter, req *http.Request, agentID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Get Availability")
+	result, resp, err := sdamAgentController.GetAvailability(req.Context(), agentID, req.URL.Query())
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentLabels handles requests which is used to change labels of agent identified by the given agentID.
+//
+//    paths: '/api/v1/agents/{agentID}/labels'
+//    method: PATCH
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentLabels(w http.ResponseWriter, req *http.Request, agentID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Update Labels")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamAgentController.UpdateLabels(req.Context(), agentID, body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentHealthCheck handles requests which is used to change the health check mode of agent
+// identified by the given agentID.
+//
+//    paths: '/api/v1/agents/{agentID}/healthcheck'
+//    method: PUT
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentHealthCheck(w http.ResponseWriter, req *http.Request, agentID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Update Health Check")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamAgentController.UpdateHealthCheck(req.Context(), agentID, body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentSecret handles requests which is used to issue a new secret to agent identified by the given agentID,
+// when the agent lost its secret.
+//
+//    paths: '/api/v1/agents/{agentID}/secret'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentSecret(w http.ResponseWriter, req *http.Request, agentID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Reset Secret")
+	result, resp, err := sdamAgentController.ResetSecret(req.Context(), agentID)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentRetention handles requests which is used to change whether agent identified by the given agentID
+// is exempt from the retention policy.
+//
+//    paths: '/api/v1/agents/{agentID}/retention'
+//    method: PUT
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentRetention(w http.ResponseWriter, req *http.Request, agentID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Update Retention")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamAgentController.UpdateExempt(req.Context(), agentID, body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentsRetention handles requests which is used to apply the retention policy to all agents.
+// The body is optional.
+//
+//    paths: '/api/v1/agents/retention'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentsRetention(w http.ResponseWriter, req *http.Request) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Apply Retention")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamAgentController.ApplyRetention(req.Context(), body)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -228,14 +358,14 @@ func (sdam _SDAMAgentApis) agents(w http.ResponseWriter, req *http.Request) {
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentDeployApp(w http.ResponseWriter, req *http.Request, agentID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Deploy App")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Deploy App")
 	body, err := common.GetBodyFromReq(req)
 	if err != nil {
 		common.MakeResponse(w, results.ERROR, nil, err)
 		return
 	}
 
-	result, resp, err := sdamAgentController.DeployApp(agentID, body)
+	result, resp, err := sdamAgentController.DeployApp(req.Context(), agentID, body)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -246,8 +376,8 @@ func (sdam _SDAMAgentApis) agentDeployApp(w http.ResponseWriter, req *http.Reque
 //    method: GET
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentInfoApps(w http.ResponseWriter, req *http.Request, agentID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Get Info Apps")
-	result, resp, err := sdamAgentController.GetApps(agentID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Get Info Apps")
+	result, resp, err := sdamAgentController.GetApps(req.Context(), agentID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -258,8 +388,8 @@ func (sdam _SDAMAgentApis) agentInfoApps(w http.ResponseWriter, req *http.Reques
 //    method: GET
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentInfoApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Get Info App")
-	result, resp, err := sdamAgentController.GetApp(agentID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Get Info App")
+	result, resp, err := sdamAgentController.GetApp(req.Context(), agentID, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -269,14 +399,14 @@ func (sdam _SDAMAgentApis) agentInfoApp(w http.ResponseWriter, req *http.Request
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentUpdateAppInfo(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Update App Info")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Update App Info")
 	body, err := common.GetBodyFromReq(req)
 	if err != nil {
 		common.MakeResponse(w, results.ERROR, nil, err)
 		return
 	}
 
-	result, resp, err := sdamAgentController.UpdateAppInfo(agentID, appID, body)
+	result, resp, err := sdamAgentController.UpdateAppInfo(req.Context(), agentID, appID, body)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -287,8 +417,8 @@ func (sdam _SDAMAgentApis) agentUpdateAppInfo(w http.ResponseWriter, req *http.R
 //    method: DELETE
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentDeleteApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Delete App")
-	result, resp, err := sdamAgentController.DeleteApp(agentID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Delete App")
+	result, resp, err := sdamAgentController.DeleteApp(req.Context(), agentID, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -299,8 +429,8 @@ func (sdam _SDAMAgentApis) agentDeleteApp(w http.ResponseWriter, req *http.Reque
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentStartApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Start App")
-	result, resp, err := sdamAgentController.StartApp(agentID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Start App")
+	result, resp, err := sdamAgentController.StartApp(req.Context(), agentID, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -311,8 +441,8 @@ func (sdam _SDAMAgentApis) agentStartApp(w http.ResponseWriter, req *http.Reques
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentStopApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Stop App")
-	result, resp, err := sdamAgentController.StopApp(agentID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Stop App")
+	result, resp, err := sdamAgentController.StopApp(req.Context(), agentID, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -323,7 +453,26 @@ func (sdam _SDAMAgentApis) agentStopApp(w http.ResponseWriter, req *http.Request
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (sdam _SDAMAgentApis) agentUpdateApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
-	logger.Logging(logger.DEBUG, "[AGENT] Update App")
-	result, resp, err := sdamAgentController.UpdateApp(agentID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Update App")
+	result, resp, err := sdamAgentController.UpdateApp(req.Context(), agentID, appID)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// agentRollbackApp handles requests related to rolling back application installed on agent
+// identified by the given agentID to an earlier revision.
+// The body is optional, and the previous revision is applied again if it is not given.
+//
+//    paths: '/api/v1/agents/{agentID}/apps/{appID}/rollback'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMAgentApis) agentRollbackApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[AGENT] Rollback App")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamAgentController.RollbackApp(req.Context(), agentID, appID, body)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
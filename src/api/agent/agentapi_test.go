@@ -17,17 +17,33 @@
 package agent
 
 import (
+	"api/router"
 	"bytes"
+	"commons/config"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 )
 
 //Test functions for Agent API Handler.
 
 type handleFunc struct {
 	functionCall string
+	agentID      string
+	appID        string
+}
+
+func newRouter() *router.Router {
+	r := router.New()
+	r.AddRoutes(Routes()...)
+	return r
 }
 
 func TestHandle(t *testing.T) {
@@ -35,9 +51,19 @@ func TestHandle(t *testing.T) {
 	mockApis := handleFunc{}
 	defaultApis := SdamAgent
 	SdamAgent = &mockApis
+	r := newRouter()
 	Input := [][]string{
 		{GET, "/api/v1/agents", "agents"},
 		{GET, "/api/v1/agents/agentID", "agent"},
+		{GET, "/api/v1/agents/agentID/events", "agentEvents"},
+		{GET, "/api/v1/agents/agentID/availability", "agentAvailability"},
+		{PATCH, "/api/v1/agents/agentID/labels", "agentLabels"},
+		{PUT, "/api/v1/agents/agentID/healthcheck", "agentHealthCheck"},
+		{POST, "/api/v1/agents/agentID/secret", "agentSecret"},
+		{PUT, "/api/v1/agents/agentID/retention", "agentRetention"},
+		{POST, "/api/v1/agents/retention", "agentsRetention"},
+		{POST, "/api/v1/agents/agentID/decommission", "agentDecommission"},
+		{POST, "/api/v1/agents/agentID/reconcile", "agentReconcile"},
 		{POST, "/api/v1/agents/agentID/deploy", "agentDeployApp"},
 		{GET, "/api/v1/agents/agentID/apps", "agentInfoApps"},
 		{GET, "/api/v1/agents/agentID/apps/appID", "agentInfoApp"},
@@ -46,6 +72,7 @@ func TestHandle(t *testing.T) {
 		{POST, "/api/v1/agents/agentID/apps/appID/start", "agentStartApp"},
 		{POST, "/api/v1/agents/agentID/apps/appID/stop", "agentStopApp"},
 		{POST, "/api/v1/agents/agentID/apps/appID/update", "agentUpdateApp"},
+		{POST, "/api/v1/agents/agentID/apps/appID/rollback", "agentRollbackApp"},
 		{POST, "/api/v1/agents/register", "agentRegister"},
 		{POST, "/api/v1/agents/agentID/unregister", "agentUnregister"},
 		{POST, "/api/v1/agents/agentID/ping", "agentPing"},
@@ -53,7 +80,7 @@ func TestHandle(t *testing.T) {
 	for _, val := range Input {
 		method, url, funcname := val[0], val[1], val[2]
 		req, _ := http.NewRequest(method, url, nil)
-		SdamAgentHandle.Handle(w, req)
+		r.ServeHTTP(w, req)
 		if mockApis.functionCall != funcname {
 			t.Error("[SDAM][Agent]Handle is invalid about " + funcname)
 		}
@@ -61,12 +88,45 @@ func TestHandle(t *testing.T) {
 	SdamAgent = defaultApis
 }
 
-func TestHandle_Invalid_Method(t *testing.T) {
+func TestHandle_Path_Params(t *testing.T) {
 	w := httptest.NewRecorder()
+	mockApis := handleFunc{}
+	defaultApis := SdamAgent
+	SdamAgent = &mockApis
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/apps/testAppID/start/", nil)
+	newRouter().ServeHTTP(w, req)
+	if mockApis.functionCall != "agentStartApp" || mockApis.agentID != "testAgentID" || mockApis.appID != "testAppID" {
+		t.Error("[SDAM][Agent]Handle is invalid about path parameters")
+	}
+	SdamAgent = defaultApis
+}
+
+func TestHandle_Invalid_URL(t *testing.T) {
+	Input := []string{
+		"/foo/api/v1/agents",
+		"/api/v1/agentsX",
+		"/api/v1/agents/agentID/appsX",
+		"/api/v1/agents/agentID/apps/appID/start/now",
+		"/api/v1/agents//apps",
+	}
+	r := newRouter()
+	for _, url := range Input {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(GET, url, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Error("[SDAM][Agent]Handle is invalid about " + url)
+		}
+	}
+}
+
+func TestHandle_Invalid_Method(t *testing.T) {
+	r := newRouter()
 	Input := map[string][]string{
 		"/api/v1/agents":                           {POST, DELETE, PUT},
 		"/api/v1/agents/agentID":                   {POST, DELETE, PUT},
 		"/api/v1/agents/agentID/deploy":            {GET, DELETE, PUT},
+		"/api/v1/agents/agentID/labels":            {GET, POST, DELETE, PUT},
 		"/api/v1/agents/agentID/apps":              {PUT},
 		"/api/v1/agents/agentID/apps/appID":        {PUT},
 		"/api/v1/agents/agentID/apps/appID/start":  {GET, DELETE, PUT},
@@ -78,15 +138,96 @@ func TestHandle_Invalid_Method(t *testing.T) {
 	}
 	for key, vals := range Input {
 		for _, val := range vals {
+			w := httptest.NewRecorder()
 			req, _ := http.NewRequest(val, key, nil)
-			SdamAgentHandle.Handle(w, req)
-			if w.Code != http.StatusBadRequest {
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") == "" {
 				t.Error("[SDAM][Agent]Handle is invalid")
 			}
 		}
 	}
 }
 
+func TestHandle_Authentication(t *testing.T) {
+	defaultConfig := config.Get()
+	cfg := config.Default()
+	cfg.Auth = config.AuthConfig{Enabled: true, AdminKey: "adminKey"}
+	config.Set(cfg)
+	mockApis := handleFunc{}
+	defaultApis := SdamAgent
+	SdamAgent = &mockApis
+	r := newRouter()
+	Input := [][]string{
+		{GET, "/api/v1/agents", ""},
+		{POST, "/api/v1/agents/agentID/deploy", ""},
+		{PATCH, "/api/v1/agents/agentID/labels", ""},
+		{POST, "/api/v1/agents/agentID/unregister", ""},
+		{POST, "/api/v1/agents/register", "agentRegister"},
+		{POST, "/api/v1/agents/agentID/ping", "agentPing"},
+	}
+	for _, val := range Input {
+		method, url, funcname := val[0], val[1], val[2]
+		mockApis.functionCall = ""
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, url, nil)
+		r.ServeHTTP(w, req)
+		if mockApis.functionCall != funcname {
+			t.Error("[SDAM][Agent]Handle is invalid about authentication of " + url)
+		}
+		if funcname == "" && w.Code != http.StatusUnauthorized {
+			t.Error("[SDAM][Agent]Handle is invalid about authentication of " + url)
+		}
+	}
+	SdamAgent = defaultApis
+	config.Set(defaultConfig)
+}
+
+func TestHandle_Scope(t *testing.T) {
+	defaultConfig := config.Get()
+	cfg := config.Default()
+	cfg.Auth = config.AuthConfig{Enabled: true, TokenSecret: "tokenSecret"}
+	config.Set(cfg)
+	mockApis := handleFunc{}
+	defaultApis := SdamAgent
+	SdamAgent = &mockApis
+	r := newRouter()
+	Input := []struct {
+		groups   []string
+		funcname string
+	}{
+		{nil, "agents"},
+		{[]string{"groupID"}, ""},
+	}
+	for _, val := range Input {
+		mockApis.functionCall = ""
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(GET, "/api/v1/agents", nil)
+		req.Header.Set("Authorization", "Bearer "+bearerToken(val.groups, "tokenSecret"))
+		r.ServeHTTP(w, req)
+		if mockApis.functionCall != val.funcname {
+			t.Errorf("[SDAM][Agent]Handle is invalid about scope of %v", val.groups)
+		}
+		if val.funcname == "" && w.Code != http.StatusForbidden {
+			t.Errorf("[SDAM][Agent]Handle is invalid about scope of %v", val.groups)
+		}
+	}
+	SdamAgent = defaultApis
+	config.Set(defaultConfig)
+}
+
+// bearerToken returns a token of a viewer limited to the groups, signed with the secret.
+func bearerToken(groups []string, secret string) string {
+	encode := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	claims := map[string]interface{}{"sub": "viewer", "exp": time.Now().Unix() + 60, "role": "viewer", "groups": groups}
+	input := encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
 //Mock functions for Agent APIs.
 
 func (mockApis *handleFunc) agentRegister(w http.ResponseWriter, req *http.Request) {
@@ -109,6 +250,42 @@ func (mockApis *handleFunc) agents(w http.ResponseWriter, req *http.Request) {
 	mockApis.functionCall = "agents"
 }
 
+func (mockApis *handleFunc) agentEvents(w http.ResponseWriter, req *http.Request, agentID string) {
+	mockApis.functionCall = "agentEvents"
+}
+
+func (mockApis *handleFunc) agentAvailability(w http.ResponseWriter, req *http.Request, agentID string) {
+	mockApis.functionCall = "agentAvailability"
+}
+
+func (mockApis *handleFunc) agentLabels(w http.ResponseWriter, req *http.Request, agentID string) {
+	mockApis.functionCall = "agentLabels"
+}
+
+func (mockApis *handleFunc) agentDecommission(w http.ResponseWriter, req *http.Request, agentID string) {
+	mockApis.functionCall = "agentDecommission"
+}
+
+func (mockApis *handleFunc) agentReconcile(w http.ResponseWriter, req *http.Request, agentID string) {
+	mockApis.functionCall = "agentReconcile"
+}
+
+func (mockApis *handleFunc) agentRetention(w http.ResponseWriter, req *http.Request, agentID string) {
+	mockApis.functionCall = "agentRetention"
+}
+
+func (mockApis *handleFunc) agentsRetention(w http.ResponseWriter, req *http.Request) {
+	mockApis.functionCall = "agentsRetention"
+}
+
+func (mockApis *handleFunc) agentHealthCheck(w http.ResponseWriter, req *http.Request, agentID string) {
+	mockApis.functionCall = "agentHealthCheck"
+}
+
+func (mockApis *handleFunc) agentSecret(w http.ResponseWriter, req *http.Request, agentID string) {
+	mockApis.functionCall = "agentSecret"
+}
+
 func (mockApis *handleFunc) agentDeployApp(w http.ResponseWriter, req *http.Request, agentID string) {
 	mockApis.functionCall = "agentDeployApp"
 }
@@ -131,6 +308,7 @@ func (mockApis *handleFunc) agentDeleteApp(w http.ResponseWriter, req *http.Requ
 
 func (mockApis *handleFunc) agentStartApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
 	mockApis.functionCall = "agentStartApp"
+	mockApis.agentID, mockApis.appID = agentID, appID
 }
 
 func (mockApis *handleFunc) agentStopApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
@@ -141,6 +319,10 @@ func (mockApis *handleFunc) agentUpdateApp(w http.ResponseWriter, req *http.Requ
 	mockApis.functionCall = "agentUpdateApp"
 }
 
+func (mockApis *handleFunc) agentRollbackApp(w http.ResponseWriter, req *http.Request, agentID string, appID string) {
+	mockApis.functionCall = "agentRollbackApp"
+}
+
 //Test functions for Agent APIs.
 
 type controllerFunc struct {
@@ -208,6 +390,211 @@ func TestAgents_controller_occurred_error(t *testing.T) {
 	}
 }
 
+func TestAgentEvents(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/agents/testAgentID/events?type=disconnected", nil)
+	sdamAgentController = mockCtrl
+	SdamAgent.agentEvents(w, req, "testAgentID")
+	if mockCtrl.functionCall != "GetEvents" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentEvents is invalid")
+	}
+}
+
+func TestAgentEvents_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/agents/testAgentID/events", nil)
+	sdamAgentController = mockCtrl
+	SdamAgent.agentEvents(w, req, "testAgentID")
+	if mockCtrl.functionCall != "GetEvents" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentEvents is invalid about controller occurred error")
+	}
+}
+
+func TestAgentAvailability(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/agents/testAgentID/availability?from=0", nil)
+	sdamAgentController = mockCtrl
+	SdamAgent.agentAvailability(w, req, "testAgentID")
+	if mockCtrl.functionCall != "GetAvailability" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentAvailability is invalid")
+	}
+}
+
+func TestAgentAvailability_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/agents/testAgentID/availability", nil)
+	sdamAgentController = mockCtrl
+	SdamAgent.agentAvailability(w, req, "testAgentID")
+	if mockCtrl.functionCall != "GetAvailability" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentAvailability is invalid about controller occurred error")
+	}
+}
+
+func TestAgentLabels(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	bod := []byte(`{"site":"plant3"}`)
+	req, _ := http.NewRequest(PATCH, "/api/v1/agents/testAgentID/labels", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentLabels(w, req, "testAgentID")
+	if mockCtrl.functionCall != "UpdateLabels" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentLabels is invalid")
+	}
+}
+
+func TestAgentLabels_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	bod := []byte("error")
+	req, _ := http.NewRequest(PATCH, "/api/v1/agents/testAgentID/labels", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentLabels(w, req, "testAgentID")
+	if mockCtrl.functionCall != "UpdateLabels" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentLabels is invalid about controller occurred error")
+	}
+}
+
+func TestAgentLabels_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PATCH, "/api/v1/agents/testAgentID/labels", nil)
+	SdamAgent.agentLabels(w, req, "testAgentID")
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Agent]agentLabels is invalid about empty body")
+	}
+}
+
+func TestAgentHealthCheck(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	bod := []byte(`{"healthcheck":"pull"}`)
+	req, _ := http.NewRequest(PUT, "/api/v1/agents/testAgentID/healthcheck", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentHealthCheck(w, req, "testAgentID")
+	if mockCtrl.functionCall != "UpdateHealthCheck" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentHealthCheck is invalid")
+	}
+}
+
+func TestAgentHealthCheck_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	bod := []byte("error")
+	req, _ := http.NewRequest(PUT, "/api/v1/agents/testAgentID/healthcheck", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentHealthCheck(w, req, "testAgentID")
+	if mockCtrl.functionCall != "UpdateHealthCheck" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentHealthCheck is invalid about controller occurred error")
+	}
+}
+
+func TestAgentHealthCheck_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PUT, "/api/v1/agents/testAgentID/healthcheck", nil)
+	SdamAgent.agentHealthCheck(w, req, "testAgentID")
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Agent]agentHealthCheck is invalid about empty body")
+	}
+}
+
+func TestAgentSecret(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/secret", nil)
+	sdamAgentController = mockCtrl
+	SdamAgent.agentSecret(w, req, "testAgentID")
+	if mockCtrl.functionCall != "ResetSecret" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentSecret is invalid")
+	}
+}
+
+func TestAgentSecret_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/secret", nil)
+	sdamAgentController = mockCtrl
+	SdamAgent.agentSecret(w, req, "testAgentID")
+	if mockCtrl.functionCall != "ResetSecret" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentSecret is invalid about controller occurred error")
+	}
+}
+
+func TestAgentRetention(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	bod := []byte(`{"exempt":true}`)
+	req, _ := http.NewRequest(PUT, "/api/v1/agents/testAgentID/retention", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentRetention(w, req, "testAgentID")
+	if mockCtrl.functionCall != "UpdateExempt" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentRetention is invalid")
+	}
+}
+
+func TestAgentRetention_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	bod := []byte("error")
+	req, _ := http.NewRequest(PUT, "/api/v1/agents/testAgentID/retention", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentRetention(w, req, "testAgentID")
+	if mockCtrl.functionCall != "UpdateExempt" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentRetention is invalid about controller occurred error")
+	}
+}
+
+func TestAgentRetention_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PUT, "/api/v1/agents/testAgentID/retention", nil)
+	SdamAgent.agentRetention(w, req, "testAgentID")
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Agent]agentRetention is invalid about empty body")
+	}
+}
+
+func TestAgentsRetention(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	bod := []byte(`{"dryrun":true}`)
+	req, _ := http.NewRequest(POST, "/api/v1/agents/retention", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentsRetention(w, req)
+	if mockCtrl.functionCall != "ApplyRetention" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentsRetention is invalid")
+	}
+}
+
+func TestAgentsRetention_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	bod := []byte("error")
+	req, _ := http.NewRequest(POST, "/api/v1/agents/retention", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentsRetention(w, req)
+	if mockCtrl.functionCall != "ApplyRetention" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentsRetention is invalid about controller occurred error")
+	}
+}
+
+func TestAgentsRetention_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/retention", nil)
+	SdamAgent.agentsRetention(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Agent]agentsRetention is invalid about empty body")
+	}
+}
+
 func TestAgentDeployApp(t *testing.T) {
 	mockCtrl := newCtrlFunc()
 	w := httptest.NewRecorder()
@@ -414,6 +801,40 @@ func TestAgentUpdateApp_controller_occurred_error(t *testing.T) {
 	}
 }
 
+func TestAgentRollbackApp(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	bod := []byte(`{"revision":2}`)
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/apps/testAppID/rollback", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentRollbackApp(w, req, "testAgentID", "testAppID")
+	if mockCtrl.functionCall != "RollbackApp" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentRollbackApp is invalid")
+	}
+}
+
+func TestAgentRollbackApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	bod := []byte("error")
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/apps/testAppID/rollback", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentRollbackApp(w, req, "testAgentID", "testAppID")
+	if mockCtrl.functionCall != "RollbackApp" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentRollbackApp is invalid about controller occurred error")
+	}
+}
+
+func TestAgentRollbackApp_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/apps/testAppID/rollback", nil)
+	SdamAgent.agentRollbackApp(w, req, "testAgentID", "testAppID")
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Agent]agentRollbackApp is invalid about empty body")
+	}
+}
+
 func TestAgentRegister(t *testing.T) {
 	mockCtrl := newCtrlFunc()
 	w := httptest.NewRecorder()
@@ -472,6 +893,63 @@ func TestAgentUnregister_controller_occurred_error(t *testing.T) {
 	}
 }
 
+func TestAgentDecommission(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	bod := []byte(`{"apps":true,"force":true}`)
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/decommission", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentDecommission(w, req, "testAgentID")
+	if mockCtrl.functionCall != "Decommission" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentDecommission is invalid")
+	}
+}
+
+func TestAgentDecommission_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	bod := []byte("error")
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/decommission", bytes.NewReader(bod))
+	sdamAgentController = mockCtrl
+	SdamAgent.agentDecommission(w, req, "testAgentID")
+	if mockCtrl.functionCall != "Decommission" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentDecommission is invalid about controller occurred error")
+	}
+}
+
+func TestAgentDecommission_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/decommission", nil)
+	SdamAgent.agentDecommission(w, req, "testAgentID")
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Agent]agentDecommission is invalid about empty body")
+	}
+}
+
+func TestAgentReconcile(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/reconcile", nil)
+	sdamAgentController = mockCtrl
+	SdamAgent.agentReconcile(w, req, "testAgentID")
+	if mockCtrl.functionCall != "Reconcile" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Agent]agentReconcile is invalid")
+	}
+}
+
+func TestAgentReconcile_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/testAgentID/reconcile", nil)
+	sdamAgentController = mockCtrl
+	SdamAgent.agentReconcile(w, req, "testAgentID")
+	if mockCtrl.functionCall != "Reconcile" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Agent]agentReconcile is invalid about controller occurred error")
+	}
+}
+
 func TestAgentPing(t *testing.T) {
 	mockCtrl := newCtrlFunc()
 	w := httptest.NewRecorder()
@@ -508,7 +986,7 @@ func TestAgentPing_empty_body(t *testing.T) {
 
 //Mock functions for Agent Controller Functions.
 
-func (mockCtrl *controllerFunc) AddAgent(ip string, body string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) AddAgent(ctx context.Context, body string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "AddAgent"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -516,7 +994,7 @@ func (mockCtrl *controllerFunc) AddAgent(ip string, body string) (int, map[strin
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) PingAgent(agentId string, ip string, body string) (int, error) {
+func (mockCtrl *controllerFunc) PingAgent(ctx context.Context, agentId string, ip string, body string) (int, error) {
 	mockCtrl.functionCall = "PingAgent"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil
@@ -524,7 +1002,7 @@ func (mockCtrl *controllerFunc) PingAgent(agentId string, ip string, body string
 	return http.StatusNotFound, nil
 }
 
-func (mockCtrl *controllerFunc) DeleteAgent(agentId string) (int, error) {
+func (mockCtrl *controllerFunc) DeleteAgent(ctx context.Context, agentId string) (int, error) {
 	mockCtrl.functionCall = "DeleteAgent"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil
@@ -532,7 +1010,7 @@ func (mockCtrl *controllerFunc) DeleteAgent(agentId string) (int, error) {
 	return http.StatusNotFound, nil
 }
 
-func (mockCtrl *controllerFunc) GetAgent(agentID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetAgent(ctx context.Context, agentID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "GetAgent"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -540,7 +1018,7 @@ func (mockCtrl *controllerFunc) GetAgent(agentID string) (int, map[string]interf
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) GetAgents() (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetAgents(ctx context.Context, query url.Values) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "GetAgents"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -548,7 +1026,47 @@ func (mockCtrl *controllerFunc) GetAgents() (int, map[string]interface{}, error)
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) DeployApp(agentID string, body string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetEvents(ctx context.Context, agentID string, query url.Values) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "GetEvents"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) GetAvailability(ctx context.Context, agentID string, query url.Values) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "GetAvailability"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) UpdateLabels(ctx context.Context, agentID string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "UpdateLabels"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) UpdateHealthCheck(ctx context.Context, agentID string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "UpdateHealthCheck"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) ResetSecret(ctx context.Context, agentID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "ResetSecret"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) DeployApp(ctx context.Context, agentID string, body string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "DeployApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -556,7 +1074,7 @@ func (mockCtrl *controllerFunc) DeployApp(agentID string, body string) (int, map
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) GetApps(agentID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetApps(ctx context.Context, agentID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "GetApps"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -564,7 +1082,7 @@ func (mockCtrl *controllerFunc) GetApps(agentID string) (int, map[string]interfa
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) GetApp(agentID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetApp(ctx context.Context, agentID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "GetApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -572,7 +1090,7 @@ func (mockCtrl *controllerFunc) GetApp(agentID string, appID string) (int, map[s
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) UpdateAppInfo(agentID string, appID string, body string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) UpdateAppInfo(ctx context.Context, agentID string, appID string, body string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "UpdateAppInfo"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -580,7 +1098,7 @@ func (mockCtrl *controllerFunc) UpdateAppInfo(agentID string, appID string, body
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) DeleteApp(agentID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) DeleteApp(ctx context.Context, agentID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "DeleteApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -588,7 +1106,7 @@ func (mockCtrl *controllerFunc) DeleteApp(agentID string, appID string) (int, ma
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) UpdateApp(agentID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) UpdateApp(ctx context.Context, agentID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "UpdateApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -596,7 +1114,15 @@ func (mockCtrl *controllerFunc) UpdateApp(agentID string, appID string) (int, ma
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) StartApp(agentID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) RollbackApp(ctx context.Context, agentID string, appID string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "RollbackApp"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) StartApp(ctx context.Context, agentID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "StartApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -604,10 +1130,42 @@ func (mockCtrl *controllerFunc) StartApp(agentID string, appID string) (int, map
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) StopApp(agentID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) StopApp(ctx context.Context, agentID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "StopApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
 	}
 	return http.StatusNotFound, nil, nil
 }
+
+func (mockCtrl *controllerFunc) Decommission(ctx context.Context, agentID string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "Decommission"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) Reconcile(ctx context.Context, agentID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "Reconcile"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) UpdateExempt(ctx context.Context, agentID string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "UpdateExempt"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) ApplyRetention(ctx context.Context, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "ApplyRetention"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
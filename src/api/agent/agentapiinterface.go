@@ -18,20 +18,23 @@ package agent
 
 import "net/http"
 
-var SdamAgentHandle SDAMAgentAPIHandlerInterface
-
 var SdamAgent SDAMAgentAPIInterface
 
-type SDAMAgentAPIHandlerInterface interface {
-	Handle(w http.ResponseWriter, req *http.Request)
-}
-
 type SDAMAgentAPIInterface interface {
 	agentRegister(w http.ResponseWriter, req *http.Request)
 	agentPing(w http.ResponseWriter, req *http.Request, agentID string)
 	agentUnregister(w http.ResponseWriter, req *http.Request, agentID string)
+	agentDecommission(w http.ResponseWriter, req *http.Request, agentID string)
+	agentReconcile(w http.ResponseWriter, req *http.Request, agentID string)
 	agent(w http.ResponseWriter, req *http.Request, agentID string)
 	agents(w http.ResponseWriter, req *http.Request)
+	agentEvents(w http.ResponseWriter, req *http.Request, agentID string)
+	agentAvailability(w http.ResponseWriter, req *http.Request, agentID string)
+	agentLabels(w http.ResponseWriter, req *http.Request, agentID string)
+	agentHealthCheck(w http.ResponseWriter, req *http.Request, agentID string)
+	agentSecret(w http.ResponseWriter, req *http.Request, agentID string)
+	agentRetention(w http.ResponseWriter, req *http.Request, agentID string)
+	agentsRetention(w http.ResponseWriter, req *http.Request)
 	agentDeployApp(w http.ResponseWriter, req *http.Request, agentID string)
 	agentInfoApps(w http.ResponseWriter, req *http.Request, agentID string)
 	agentInfoApp(w http.ResponseWriter, req *http.Request, agentID string, appID string)
@@ -40,4 +43,5 @@ type SDAMAgentAPIInterface interface {
 	agentStartApp(w http.ResponseWriter, req *http.Request, agentID string, appID string)
 	agentStopApp(w http.ResponseWriter, req *http.Request, agentID string, appID string)
 	agentUpdateApp(w http.ResponseWriter, req *http.Request, agentID string, appID string)
+	agentRollbackApp(w http.ResponseWriter, req *http.Request, agentID string, appID string)
 }
@@ -0,0 +1,164 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package api/auth provides middlewares which authenticate and authorize operators.
+// A request is accepted with an API key given by 'X-API-Key' header or
+// a bearer token given by 'Authorization' header.
+// A caller is granted one of roles, VIEWER, OPERATOR and ADMIN, and may be limited
+// to a list of groups so that it can access only the agents in the groups.
+package auth
+
+import (
+	"api/common"
+	"api/router"
+	"commons/config"
+	"commons/errors"
+	"commons/logger"
+	"context"
+	"crypto/subtle"
+	"manager/key"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	API_KEY_HEADER       = "X-API-Key"     // header used to carry an API key.
+	AUTHORIZATION_HEADER = "Authorization" // header used to carry a bearer token.
+	BEARER               = "Bearer "       // scheme of a bearer token.
+)
+
+const (
+	VIEWER   = key.VIEWER   // role allowed to get information only.
+	OPERATOR = key.OPERATOR // role allowed to start, stop and update apps in addition to VIEWER.
+	ADMIN    = key.ADMIN    // role allowed to every operation, also the name of the caller authenticated with the admin key.
+)
+
+// Identity represents an authenticated caller.
+// If Groups is empty, the caller is not limited to any group.
+type Identity struct {
+	Name   string
+	Role   string
+	Groups []string
+}
+
+// Authenticator identifies the caller of a request with its own credential scheme.
+// If the request does not carry credentials of the scheme, false is returned.
+type Authenticator interface {
+	Authenticate(req *http.Request) (Identity, bool, error)
+}
+
+type identityKey struct{}
+
+var authenticators []Authenticator
+var keyController key.KeyInterface
+var now func() time.Time
+
+func init() {
+	authenticators = []Authenticator{apiKeyAuthenticator{}, tokenAuthenticator{}}
+	keyController = key.KeyController{}
+	now = time.Now
+}
+
+// Authenticate is a middleware which rejects requests without valid credentials.
+// If authentication is not enabled, every request is passed to the next handler.
+// otherwise, Unauthorized error will be used to send an error message.
+func Authenticate(next router.Handler) router.Handler {
+	return func(w http.ResponseWriter, req *http.Request, params router.Params) {
+		if !config.Get().Auth.Enabled {
+			next(w, req, params)
+			return
+		}
+
+		identity, err := authenticate(req)
+		if err != nil {
+			logger.LoggingContext(req.Context(), logger.ERROR, err.Error())
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			common.WriteError(w, err)
+			return
+		}
+
+		logger.LoggingContext(req.Context(), logger.DEBUG, "authenticated", identity.Name)
+		ctx := context.WithValue(req.Context(), identityKey{}, identity)
+		next(w, req.WithContext(ctx), params)
+	}
+}
+
+// GetIdentity returns the caller identified by Authenticate middleware.
+// If the request is not authenticated, false is returned.
+func GetIdentity(req *http.Request) (Identity, bool) {
+	identity, ok := req.Context().Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// authenticate identifies the caller with the first scheme the request carries.
+func authenticate(req *http.Request) (Identity, error) {
+	for _, authenticator := range authenticators {
+		identity, found, err := authenticator.Authenticate(req)
+		if found {
+			return identity, err
+		}
+	}
+	return Identity{}, errors.Unauthorized{Message: "credentials are required"}
+}
+
+// apiKeyAuthenticator accepts the admin key and API keys stored in the database.
+type apiKeyAuthenticator struct{}
+
+func (apiKeyAuthenticator) Authenticate(req *http.Request) (Identity, bool, error) {
+	apiKey := req.Header.Get(API_KEY_HEADER)
+	if apiKey == "" {
+		return Identity{}, false, nil
+	}
+
+	adminKey := config.Get().Auth.AdminKey
+	if adminKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(adminKey)) == 1 {
+		return Identity{Name: ADMIN, Role: ADMIN}, true, nil
+	}
+
+	res, err := keyController.VerifyKey(req.Context(), apiKey)
+	if err != nil {
+		return Identity{}, true, err
+	}
+
+	name, _ := res[key.NAME].(string)
+	role, _ := res[key.ROLE].(string)
+	groups, _ := res[key.GROUPS].([]string)
+	return Identity{Name: name, Role: role, Groups: groups}, true, nil
+}
+
+// tokenAuthenticator accepts bearer tokens signed with the configured secret.
+type tokenAuthenticator struct{}
+
+func (tokenAuthenticator) Authenticate(req *http.Request) (Identity, bool, error) {
+	authorization := req.Header.Get(AUTHORIZATION_HEADER)
+	if !strings.HasPrefix(authorization, BEARER) {
+		return Identity{}, false, nil
+	}
+
+	secret := config.Get().Auth.TokenSecret
+	if secret == "" {
+		return Identity{}, true, errors.Unauthorized{Message: "bearer tokens are not accepted"}
+	}
+
+	claims, err := verifyToken(strings.TrimPrefix(authorization, BEARER), secret)
+	if err != nil {
+		return Identity{}, true, err
+	}
+
+	return Identity{Name: claims.Subject, Role: claims.Role, Groups: claims.Groups}, true, nil
+}
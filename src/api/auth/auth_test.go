@@ -0,0 +1,197 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package auth
+
+import (
+	"api/router"
+	"commons/config"
+	"commons/errors"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"manager/group"
+	"manager/key"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	adminKey    = "admin-key"
+	tokenSecret = "token-secret"
+	storedKey   = "stored-key"
+)
+
+type keyControllerFunc struct{}
+
+func (keyControllerFunc) CreateKey(ctx context.Context, body string) (int, map[string]interface{}, error) {
+	return 0, nil, nil
+}
+
+func (keyControllerFunc) GetKeys(ctx context.Context) (int, map[string]interface{}, error) {
+	return 0, nil, nil
+}
+
+func (keyControllerFunc) DeleteKey(ctx context.Context, keyId string) (int, error) {
+	return 0, nil
+}
+
+func (keyControllerFunc) VerifyKey(ctx context.Context, key string) (map[string]interface{}, error) {
+	if key != storedKey {
+		return nil, errors.Unauthorized{Message: "unknown api key"}
+	}
+	return map[string]interface{}{"id": "keyID", "name": "operator", "role": OPERATOR, "groups": []string{"groupID"}}, nil
+}
+
+func setUp(enabled bool) func() {
+	defaultConfig := config.Get()
+	cfg := config.Default()
+	if enabled {
+		cfg.Auth = config.AuthConfig{Enabled: true, AdminKey: adminKey, TokenSecret: tokenSecret}
+	}
+	config.Set(cfg)
+
+	keyController = keyControllerFunc{}
+	now = func() time.Time { return time.Unix(1000, 0) }
+	return func() {
+		config.Set(defaultConfig)
+		keyController = key.KeyController{}
+		groupController = group.GroupController{}
+		now = time.Now
+	}
+}
+
+func signToken(header string, claims map[string]interface{}, secret string) string {
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sign(input, secret))
+}
+
+// serve passes a request through Authenticate middleware
+// and returns the response and the identity passed to the next handler.
+func serve(req *http.Request) (*httptest.ResponseRecorder, *Identity) {
+	var identity *Identity
+	handler := Authenticate(func(w http.ResponseWriter, req *http.Request, params router.Params) {
+		found, ok := GetIdentity(req)
+		if ok {
+			identity = &found
+		} else {
+			identity = &Identity{}
+		}
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, req, nil)
+	return w, identity
+}
+
+func TestCalledAuthenticateWhenDisabled_ExpectRequestPassed(t *testing.T) {
+	tearDown := setUp(false)
+	defer tearDown()
+
+	req, _ := http.NewRequest("GET", "/api/v1/agents", nil)
+	_, identity := serve(req)
+
+	if identity == nil {
+		t.Error("Expected request to be passed")
+	}
+}
+
+func TestCalledAuthenticateWithValidCredentials_ExpectIdentityPassed(t *testing.T) {
+	tearDown := setUp(true)
+	defer tearDown()
+
+	testList := []struct {
+		name   string
+		header string
+		value  string
+		caller Identity
+	}{
+		{"AdminKey", API_KEY_HEADER, adminKey, Identity{Name: ADMIN, Role: ADMIN}},
+		{"StoredKey", API_KEY_HEADER, storedKey, Identity{Name: "operator", Role: OPERATOR, Groups: []string{"groupID"}}},
+		{"Token", AUTHORIZATION_HEADER, BEARER + signToken(`{"alg":"HS256","typ":"JWT"}`,
+			map[string]interface{}{"sub": "ci", "exp": 2000}, tokenSecret), Identity{Name: "ci", Role: VIEWER}},
+		{"TokenWithRole", AUTHORIZATION_HEADER, BEARER + signToken(`{"alg":"HS256","typ":"JWT"}`,
+			map[string]interface{}{"sub": "ci", "exp": 2000, "role": "admin", "groups": []string{"groupID"}}, tokenSecret),
+			Identity{Name: "ci", Role: ADMIN, Groups: []string{"groupID"}}},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/agents", nil)
+			req.Header.Set(test.header, test.value)
+			w, identity := serve(req)
+
+			if identity == nil {
+				t.Fatalf("Expected request to be passed, actual code: %d", w.Code)
+			}
+			if !reflect.DeepEqual(*identity, test.caller) {
+				t.Errorf("Expected caller: %v, actual caller: %v", test.caller, *identity)
+			}
+		})
+	}
+}
+
+func TestCalledAuthenticateWithInvalidCredentials_ExpectUnauthorized(t *testing.T) {
+	tearDown := setUp(true)
+	defer tearDown()
+
+	header := `{"alg":"HS256","typ":"JWT"}`
+	testList := []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{"NoCredentials", "", ""},
+		{"UnknownKey", API_KEY_HEADER, "unknown"},
+		{"MalformedToken", AUTHORIZATION_HEADER, BEARER + "token"},
+		{"InvalidSignature", AUTHORIZATION_HEADER, BEARER + signToken(header,
+			map[string]interface{}{"sub": "ci", "exp": 2000}, "other-secret")},
+		{"ExpiredToken", AUTHORIZATION_HEADER, BEARER + signToken(header,
+			map[string]interface{}{"sub": "ci", "exp": 1000}, tokenSecret)},
+		{"TokenWithoutExpiration", AUTHORIZATION_HEADER, BEARER + signToken(header,
+			map[string]interface{}{"sub": "ci"}, tokenSecret)},
+		{"NotYetValidToken", AUTHORIZATION_HEADER, BEARER + signToken(header,
+			map[string]interface{}{"sub": "ci", "exp": 2000, "nbf": 1500}, tokenSecret)},
+		{"UnknownRole", AUTHORIZATION_HEADER, BEARER + signToken(header,
+			map[string]interface{}{"sub": "ci", "exp": 2000, "role": "root"}, tokenSecret)},
+		{"UnsupportedAlgorithm", AUTHORIZATION_HEADER, BEARER + signToken(`{"alg":"none"}`,
+			map[string]interface{}{"sub": "ci", "exp": 2000}, tokenSecret)},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/agents", nil)
+			if test.header != "" {
+				req.Header.Set(test.header, test.value)
+			}
+			w, identity := serve(req)
+
+			if identity != nil {
+				t.Error("Unexpected request passed")
+			}
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("Expected code: %d, actual code: %d", http.StatusUnauthorized, w.Code)
+			}
+			if w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected WWW-Authenticate header")
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package auth
+
+import (
+	"api/common"
+	"api/router"
+	"commons/config"
+	"commons/errors"
+	"commons/logger"
+	"context"
+	"manager/group"
+	"net/http"
+)
+
+// ranks holds the order of roles. A higher role is allowed to do everything a lower role is.
+var ranks = map[string]int{
+	VIEWER:   1,
+	OPERATOR: 2,
+	ADMIN:    3,
+}
+
+var groupController group.GroupInterface
+
+func init() {
+	groupController = group.GroupController{}
+}
+
+// Permit returns middlewares which authenticate the caller and require the role.
+// The scopes are applied after them to limit the caller to its groups.
+func Permit(role string, scopes ...router.Middleware) []router.Middleware {
+	return append([]router.Middleware{Authenticate, Require(role)}, scopes...)
+}
+
+// Require returns a middleware which rejects requests from callers
+// whose role is lower than the given role.
+// Forbidden error will be used to send an error message.
+func Require(role string) router.Middleware {
+	return authorize(func(ctx context.Context, identity Identity, params router.Params) error {
+		if ranks[identity.Role] < ranks[role] {
+			return errors.Forbidden{Message: identity.Role + " is not allowed, " + role + " role is required"}
+		}
+		return nil
+	})
+}
+
+// RequireGroup returns a middleware which rejects requests to the group
+// identified by the path parameter from callers limited to other groups.
+func RequireGroup(param string) router.Middleware {
+	return authorize(func(ctx context.Context, identity Identity, params router.Params) error {
+		if identity.hasGroup(params[param]) {
+			return nil
+		}
+		return errors.Forbidden{Message: "not allowed to access group", Kind: errors.GROUP, ID: params[param]}
+	})
+}
+
+// RequireAgent returns a middleware which rejects requests to the agent
+// identified by the path parameter from callers limited to groups the agent does not belong to.
+func RequireAgent(param string) router.Middleware {
+	return authorize(func(ctx context.Context, identity Identity, params router.Params) error {
+		if len(identity.Groups) == 0 {
+			return nil
+		}
+
+		agentId := params[param]
+		for _, groupId := range identity.Groups {
+			_, res, err := groupController.GetGroup(ctx, groupId)
+			if err != nil {
+				switch err.(type) {
+				case errors.NotFound, errors.InvalidObjectId:
+					continue
+				}
+				return err
+			}
+
+			members, _ := res[group.MEMBERS].([]string)
+			for _, member := range members {
+				if member == agentId {
+					return nil
+				}
+			}
+		}
+		return errors.Forbidden{Message: "not allowed to access agent", Kind: errors.AGENT, ID: agentId}
+	})
+}
+
+// RequireUnscoped is a middleware which rejects requests from callers limited to some groups.
+// It is used for operations which are not confined to a group, e.g. creating a group.
+func RequireUnscoped(next router.Handler) router.Handler {
+	return authorize(func(ctx context.Context, identity Identity, params router.Params) error {
+		if len(identity.Groups) != 0 {
+			return errors.Forbidden{Message: "not allowed to callers limited to groups"}
+		}
+		return nil
+	})(next)
+}
+
+// authorize returns a middleware which passes a request to the next handler
+// only if check returns nil for the caller of the request.
+// If authentication is not enabled, every request is passed.
+func authorize(check func(ctx context.Context, identity Identity, params router.Params) error) router.Middleware {
+	return func(next router.Handler) router.Handler {
+		return func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			if !config.Get().Auth.Enabled {
+				next(w, req, params)
+				return
+			}
+
+			identity, ok := GetIdentity(req)
+			if !ok {
+				common.WriteError(w, errors.Unauthorized{Message: "credentials are required"})
+				return
+			}
+
+			if err := check(req.Context(), identity, params); err != nil {
+				logger.LoggingContext(req.Context(), logger.ERROR, identity.Name, err.Error())
+				common.WriteError(w, err)
+				return
+			}
+			next(w, req, params)
+		}
+	}
+}
+
+// hasGroup returns true if the caller is allowed to access the group.
+func (identity Identity) hasGroup(groupId string) bool {
+	if len(identity.Groups) == 0 {
+		return true
+	}
+	for _, allowed := range identity.Groups {
+		if allowed == groupId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package auth
+
+import (
+	"api/router"
+	"commons/errors"
+	"commons/results"
+	"context"
+	"manager/group"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	groupId      = "000000000000000000000001"
+	otherGroupId = "000000000000000000000002"
+	agentId      = "000000000000000000000003"
+)
+
+// groupControllerFunc returns a group which has the agent as a member
+// and another group which does not.
+type groupControllerFunc struct {
+	group.GroupInterface
+}
+
+func (groupControllerFunc) GetGroup(ctx context.Context, id string) (int, map[string]interface{}, error) {
+	switch id {
+	case groupId:
+		return results.OK, map[string]interface{}{"id": id, "members": []string{agentId}}, nil
+	case otherGroupId:
+		return results.OK, map[string]interface{}{"id": id, "members": []string{}}, nil
+	}
+	return results.ERROR, nil, errors.NotFound{Message: id}
+}
+
+// authorizeWith passes a request of the caller through the middleware
+// and returns the response code and whether the request is passed to the next handler.
+func authorizeWith(identity *Identity, middleware router.Middleware, params router.Params) (int, bool) {
+	passed := false
+	handler := middleware(func(w http.ResponseWriter, req *http.Request, params router.Params) {
+		passed = true
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/agents", nil)
+	if identity != nil {
+		req = req.WithContext(context.WithValue(req.Context(), identityKey{}, *identity))
+	}
+
+	w := httptest.NewRecorder()
+	handler(w, req, params)
+	return w.Code, passed
+}
+
+func TestCalledAuthorizeWhenDisabled_ExpectRequestPassed(t *testing.T) {
+	tearDown := setUp(false)
+	defer tearDown()
+
+	for _, middleware := range []router.Middleware{Require(ADMIN), RequireGroup("groupID"), RequireAgent("agentID"), RequireUnscoped} {
+		if _, passed := authorizeWith(nil, middleware, router.Params{}); !passed {
+			t.Error("Expected request to be passed")
+		}
+	}
+}
+
+func TestCalledAuthorizeWithoutIdentity_ExpectUnauthorized(t *testing.T) {
+	tearDown := setUp(true)
+	defer tearDown()
+
+	code, passed := authorizeWith(nil, Require(VIEWER), router.Params{})
+	if passed || code != http.StatusUnauthorized {
+		t.Errorf("Expected code: %d, actual code: %d", http.StatusUnauthorized, code)
+	}
+}
+
+func TestCalledRequire_ExpectHigherRolePassed(t *testing.T) {
+	tearDown := setUp(true)
+	defer tearDown()
+
+	testList := []struct {
+		role     string
+		required string
+		passed   bool
+	}{
+		{VIEWER, VIEWER, true},
+		{VIEWER, OPERATOR, false},
+		{VIEWER, ADMIN, false},
+		{OPERATOR, VIEWER, true},
+		{OPERATOR, OPERATOR, true},
+		{OPERATOR, ADMIN, false},
+		{ADMIN, VIEWER, true},
+		{ADMIN, ADMIN, true},
+		{"", VIEWER, false},
+	}
+
+	for _, test := range testList {
+		code, passed := authorizeWith(&Identity{Name: "caller", Role: test.role}, Require(test.required), router.Params{})
+		if passed != test.passed {
+			t.Errorf("Expected passed: %t, actual passed: %t for %s requiring %s", test.passed, passed, test.role, test.required)
+		}
+		if !passed && code != http.StatusForbidden {
+			t.Errorf("Expected code: %d, actual code: %d", http.StatusForbidden, code)
+		}
+	}
+}
+
+func TestCalledRequireGroup_ExpectOnlyAllowedGroupPassed(t *testing.T) {
+	tearDown := setUp(true)
+	defer tearDown()
+
+	testList := []struct {
+		name   string
+		groups []string
+		passed bool
+	}{
+		{"Unscoped", nil, true},
+		{"AllowedGroup", []string{otherGroupId, groupId}, true},
+		{"OtherGroup", []string{otherGroupId}, false},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			identity := Identity{Name: "caller", Role: ADMIN, Groups: test.groups}
+			code, passed := authorizeWith(&identity, RequireGroup("groupID"), router.Params{"groupID": groupId})
+			if passed != test.passed {
+				t.Errorf("Expected passed: %t, actual passed: %t", test.passed, passed)
+			}
+			if !passed && code != http.StatusForbidden {
+				t.Errorf("Expected code: %d, actual code: %d", http.StatusForbidden, code)
+			}
+		})
+	}
+}
+
+func TestCalledRequireAgent_ExpectOnlyMemberOfAllowedGroupPassed(t *testing.T) {
+	tearDown := setUp(true)
+	defer tearDown()
+	groupController = groupControllerFunc{}
+
+	testList := []struct {
+		name   string
+		groups []string
+		passed bool
+	}{
+		{"Unscoped", nil, true},
+		{"MemberOfAllowedGroup", []string{"unknown", otherGroupId, groupId}, true},
+		{"NotMember", []string{otherGroupId}, false},
+		{"UnknownGroup", []string{"unknown"}, false},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			identity := Identity{Name: "caller", Role: VIEWER, Groups: test.groups}
+			code, passed := authorizeWith(&identity, RequireAgent("agentID"), router.Params{"agentID": agentId})
+			if passed != test.passed {
+				t.Errorf("Expected passed: %t, actual passed: %t", test.passed, passed)
+			}
+			if !passed && code != http.StatusForbidden {
+				t.Errorf("Expected code: %d, actual code: %d", http.StatusForbidden, code)
+			}
+		})
+	}
+}
+
+func TestCalledRequireUnscoped_ExpectScopedCallerForbidden(t *testing.T) {
+	tearDown := setUp(true)
+	defer tearDown()
+
+	if _, passed := authorizeWith(&Identity{Name: "caller", Role: ADMIN}, RequireUnscoped, router.Params{}); !passed {
+		t.Error("Expected request to be passed")
+	}
+
+	code, passed := authorizeWith(&Identity{Name: "caller", Role: ADMIN, Groups: []string{groupId}}, RequireUnscoped, router.Params{})
+	if passed || code != http.StatusForbidden {
+		t.Errorf("Expected code: %d, actual code: %d", http.StatusForbidden, code)
+	}
+}
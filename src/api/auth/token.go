@@ -0,0 +1,113 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package auth
+
+import (
+	"commons/errors"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"manager/key"
+	"strings"
+)
+
+const TOKEN_ALGORITHM = "HS256" // the only algorithm accepted for bearer tokens.
+
+type (
+	tokenHeader struct {
+		Algorithm string `json:"alg"`
+	}
+
+	// tokenClaims represents the claims of a bearer token.
+	// Subject is used as the name of the caller and ExpiresAt is required.
+	// If Role is not given, the caller is granted VIEWER role.
+	tokenClaims struct {
+		Subject   string   `json:"sub"`
+		ExpiresAt int64    `json:"exp"`
+		NotBefore int64    `json:"nbf"`
+		Role      string   `json:"role"`
+		Groups    []string `json:"groups"`
+	}
+)
+
+// verifyToken checks the signature and the validity period of a JSON Web Token
+// signed with HMAC SHA-256.
+// If successful, the claims of the token are returned.
+// otherwise, Unauthorized error will be returned.
+func verifyToken(token string, secret string) (tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return tokenClaims{}, errors.Unauthorized{Message: "malformed bearer token"}
+	}
+
+	header := tokenHeader{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return tokenClaims{}, err
+	}
+	if header.Algorithm != TOKEN_ALGORITHM {
+		return tokenClaims{}, errors.Unauthorized{Message: "unsupported token algorithm: " + header.Algorithm}
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(parts[0]+"."+parts[1], secret)) {
+		return tokenClaims{}, errors.Unauthorized{Message: "invalid token signature"}
+	}
+
+	claims := tokenClaims{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return tokenClaims{}, err
+	}
+
+	if claims.Role == "" {
+		claims.Role = VIEWER
+	}
+
+	current := now().Unix()
+	switch {
+	case !key.IsRole(claims.Role):
+		return tokenClaims{}, errors.Unauthorized{Message: "unknown role: " + claims.Role}
+	case claims.Subject == "":
+		return tokenClaims{}, errors.Unauthorized{Message: "token subject is required"}
+	case claims.ExpiresAt == 0:
+		return tokenClaims{}, errors.Unauthorized{Message: "token expiration is required"}
+	case current >= claims.ExpiresAt:
+		return tokenClaims{}, errors.Unauthorized{Message: "token is expired"}
+	case current < claims.NotBefore:
+		return tokenClaims{}, errors.Unauthorized{Message: "token is not valid yet"}
+	}
+	return claims, nil
+}
+
+// sign returns the HMAC SHA-256 signature of the input.
+func sign(input string, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.Unauthorized{Message: "malformed bearer token"}
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return errors.Unauthorized{Message: "malformed bearer token"}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package api/catalog provides functionality to handle request related to the catalog of apps.
+package catalog
+
+import (
+	"api/auth"
+	"api/common"
+	"api/router"
+	"commons/logger"
+	"commons/results"
+	URL "commons/url"
+	"manager/catalog"
+	"net/http"
+)
+
+const (
+	GET    string = "GET"
+	POST   string = "POST"
+	DELETE string = "DELETE"
+
+	APP_NAME string = "appName" // name of the path parameter for a name of catalog app.
+	VERSION  string = "version" // name of the path parameter for a version of catalog app.
+)
+
+type _SDAMCatalogApis struct{}
+
+var sdam _SDAMCatalogApis
+var sdamCatalogController catalog.CatalogInterface
+
+func init() {
+	SdamCatalog = sdam
+	sdamCatalogController = catalog.CatalogController{}
+}
+
+// Routes returns a list of routes which calls a proper function according to
+// the url and method received from operators.
+// Every caller can read the catalog, which is not confined to a group,
+// but only the admin role which is not limited to any group can change it.
+func Routes() []router.Route {
+	apps := URL.Base() + URL.Catalog() + URL.Apps()
+	app := apps + "/{" + APP_NAME + "}"
+	versions := app + URL.Versions()
+	version := versions + "/{" + VERSION + "}"
+	viewer := auth.Permit(auth.VIEWER)
+	admin := auth.Permit(auth.ADMIN, auth.RequireUnscoped)
+
+	return []router.Route{
+		{Method: GET, Pattern: apps, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamCatalog.apps(w, req)
+		}},
+		{Method: POST, Pattern: apps, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamCatalog.createApp(w, req)
+		}},
+		{Method: GET, Pattern: app, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamCatalog.app(w, req, params[APP_NAME])
+		}},
+		{Method: DELETE, Pattern: app, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamCatalog.deleteApp(w, req, params[APP_NAME])
+		}},
+		{Method: POST, Pattern: versions, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamCatalog.addVersion(w, req, params[APP_NAME])
+		}},
+		{Method: GET, Pattern: version, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamCatalog.version(w, req, params[APP_NAME], params[VERSION])
+		}},
+	}
+}
+
+// apps handles requests which is used to get information of all apps in the catalog.
+//
+//    paths: '/api/v1/catalog/apps'
+//    method: GET
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMCatalogApis) apps(w http.ResponseWriter, req *http.Request) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[CATALOG] Get All Apps")
+	result, resp, err := sdamCatalogController.GetApps(req.Context())
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// createApp handles requests which is used to add new app to the catalog.
+//
+//    paths: '/api/v1/catalog/apps'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMCatalogApis) createApp(w http.ResponseWriter, req *http.Request) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[CATALOG] Create App")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamCatalogController.CreateApp(req.Context(), body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// app handles requests which is used to get information of app identified by the given name.
+//
+//    paths: '/api/v1/catalog/apps/{appName}'
+//    method: GET
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMCatalogApis) app(w http.ResponseWriter, req *http.Request, name string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[CATALOG] Get App")
+	result, resp, err := sdamCatalogController.GetApp(req.Context(), name)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// deleteApp handles requests which is used to delete app identified by the given name
+// from the catalog.
+//
+//    paths: '/api/v1/catalog/apps/{appName}'
+//    method: DELETE
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMCatalogApis) deleteApp(w http.ResponseWriter, req *http.Request, name string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[CATALOG] Delete App")
+	result, err := sdamCatalogController.DeleteApp(req.Context(), name)
+	common.MakeResponse(w, result, nil, err)
+}
+
+// addVersion handles requests which is used to add new version of descriptor
+// to app identified by the given name.
+//
+//    paths: '/api/v1/catalog/apps/{appName}/versions'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMCatalogApis) addVersion(w http.ResponseWriter, req *http.Request, name string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[CATALOG] Add Version")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamCatalogController.AddVersion(req.Context(), name, body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// version handles requests which is used to get the version of app identified by
+// the given name, including the descriptor.
+//
+//    paths: '/api/v1/catalog/apps/{appName}/versions/{version}'
+//    method: GET
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMCatalogApis) version(w http.ResponseWriter, req *http.Request, name string, version string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[CATALOG] Get Version")
+	result, resp, err := sdamCatalogController.GetVersion(req.Context(), name, version)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
@@ -0,0 +1,277 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package catalog
+
+import (
+	"api/router"
+	"bytes"
+	"commons/errors"
+	"commons/results"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+//Test functions for Catalog API Handler.
+
+type handleFunc struct {
+	functionCall string
+	name         string
+	versionName  string
+}
+
+func newRouter() *router.Router {
+	r := router.New()
+	r.AddRoutes(Routes()...)
+	return r
+}
+
+func TestHandle(t *testing.T) {
+	w := httptest.NewRecorder()
+	mockApis := handleFunc{}
+	defaultApis := SdamCatalog
+	SdamCatalog = &mockApis
+	r := newRouter()
+	Input := [][]string{
+		{GET, "/api/v1/catalog/apps", "apps"},
+		{POST, "/api/v1/catalog/apps", "createApp"},
+		{GET, "/api/v1/catalog/apps/testName", "app"},
+		{DELETE, "/api/v1/catalog/apps/testName", "deleteApp"},
+		{POST, "/api/v1/catalog/apps/testName/versions", "addVersion"},
+		{GET, "/api/v1/catalog/apps/testName/versions/testVersion", "version"},
+	}
+	for _, val := range Input {
+		method, url, funcname := val[0], val[1], val[2]
+		req, _ := http.NewRequest(method, url, nil)
+		r.ServeHTTP(w, req)
+		if mockApis.functionCall != funcname {
+			t.Error("[SDAM][Catalog]Handle is invalid about " + funcname)
+		}
+	}
+	if mockApis.name != "testName" || mockApis.versionName != "testVersion" {
+		t.Error("[SDAM][Catalog]Handle is invalid about path parameters")
+	}
+	SdamCatalog = defaultApis
+}
+
+func TestHandle_Invalid_Method(t *testing.T) {
+	r := newRouter()
+	Input := map[string][]string{
+		"/api/v1/catalog/apps":                               {DELETE},
+		"/api/v1/catalog/apps/testName":                      {POST},
+		"/api/v1/catalog/apps/testName/versions":             {GET, DELETE},
+		"/api/v1/catalog/apps/testName/versions/testVersion": {POST, DELETE},
+	}
+	for key, vals := range Input {
+		for _, val := range vals {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(val, key, nil)
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Error("[SDAM][Catalog]Handle is invalid")
+			}
+		}
+	}
+}
+
+//Mock functions for Catalog APIs.
+
+func (mockApis *handleFunc) apps(w http.ResponseWriter, req *http.Request) {
+	mockApis.functionCall = "apps"
+}
+
+func (mockApis *handleFunc) createApp(w http.ResponseWriter, req *http.Request) {
+	mockApis.functionCall = "createApp"
+}
+
+func (mockApis *handleFunc) app(w http.ResponseWriter, req *http.Request, name string) {
+	mockApis.functionCall = "app"
+	mockApis.name = name
+}
+
+func (mockApis *handleFunc) deleteApp(w http.ResponseWriter, req *http.Request, name string) {
+	mockApis.functionCall = "deleteApp"
+	mockApis.name = name
+}
+
+func (mockApis *handleFunc) addVersion(w http.ResponseWriter, req *http.Request, name string) {
+	mockApis.functionCall = "addVersion"
+	mockApis.name = name
+}
+
+func (mockApis *handleFunc) version(w http.ResponseWriter, req *http.Request, name string, version string) {
+	mockApis.functionCall = "version"
+	mockApis.name = name
+	mockApis.versionName = version
+}
+
+//Test functions for Catalog APIs.
+
+type controllerFunc struct {
+	functionCall  string
+	occurredError bool
+}
+
+func TestApps(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/catalog/apps", nil)
+	sdamCatalogController = mockCtrl
+	SdamCatalog.apps(w, req)
+	if mockCtrl.functionCall != "GetApps" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Catalog]apps is invalid")
+	}
+}
+
+func TestCreateApp(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/catalog/apps", bytes.NewReader([]byte(`{"name":"line-monitor"}`)))
+	sdamCatalogController = mockCtrl
+	SdamCatalog.createApp(w, req)
+	if mockCtrl.functionCall != "CreateApp" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Catalog]createApp is invalid")
+	}
+}
+
+func TestCreateApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := &controllerFunc{occurredError: true}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/catalog/apps", bytes.NewReader([]byte(`{"name":"line-monitor"}`)))
+	sdamCatalogController = mockCtrl
+	SdamCatalog.createApp(w, req)
+	if mockCtrl.functionCall != "CreateApp" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Catalog]createApp is invalid about controller occurred error")
+	}
+}
+
+func TestCreateApp_empty_body(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/catalog/apps", nil)
+	sdamCatalogController = mockCtrl
+	SdamCatalog.createApp(w, req)
+	if mockCtrl.functionCall != "" || w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Catalog]createApp is invalid about empty body")
+	}
+}
+
+func TestApp(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/catalog/apps/testName", nil)
+	sdamCatalogController = mockCtrl
+	SdamCatalog.app(w, req, "testName")
+	if mockCtrl.functionCall != "GetApp" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Catalog]app is invalid")
+	}
+}
+
+func TestDeleteApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := &controllerFunc{occurredError: true}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/catalog/apps/testName", nil)
+	sdamCatalogController = mockCtrl
+	SdamCatalog.deleteApp(w, req, "testName")
+	if mockCtrl.functionCall != "DeleteApp" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Catalog]deleteApp is invalid about controller occurred error")
+	}
+}
+
+func TestAddVersion(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/catalog/apps/testName/versions", bytes.NewReader([]byte(`{"version":"1.4.0"}`)))
+	sdamCatalogController = mockCtrl
+	SdamCatalog.addVersion(w, req, "testName")
+	if mockCtrl.functionCall != "AddVersion" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Catalog]addVersion is invalid")
+	}
+}
+
+func TestAddVersion_empty_body(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/catalog/apps/testName/versions", nil)
+	sdamCatalogController = mockCtrl
+	SdamCatalog.addVersion(w, req, "testName")
+	if mockCtrl.functionCall != "" || w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Catalog]addVersion is invalid about empty body")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/catalog/apps/testName/versions/testVersion", nil)
+	sdamCatalogController = mockCtrl
+	SdamCatalog.version(w, req, "testName", "testVersion")
+	if mockCtrl.functionCall != "GetVersion" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Catalog]version is invalid")
+	}
+}
+
+//Mock functions for Catalog Controller.
+
+func (mockCtrl *controllerFunc) CreateApp(ctx context.Context, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "CreateApp"
+	if mockCtrl.occurredError {
+		return results.ERROR, nil, errors.NotFound{}
+	}
+	return results.OK, map[string]interface{}{"name": "line-monitor"}, nil
+}
+
+func (mockCtrl *controllerFunc) GetApps(ctx context.Context) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "GetApps"
+	if mockCtrl.occurredError {
+		return results.ERROR, nil, errors.NotFound{}
+	}
+	return results.OK, map[string]interface{}{"apps": nil}, nil
+}
+
+func (mockCtrl *controllerFunc) GetApp(ctx context.Context, name string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "GetApp"
+	if mockCtrl.occurredError {
+		return results.ERROR, nil, errors.NotFound{}
+	}
+	return results.OK, map[string]interface{}{"name": name}, nil
+}
+
+func (mockCtrl *controllerFunc) DeleteApp(ctx context.Context, name string) (int, error) {
+	mockCtrl.functionCall = "DeleteApp"
+	if mockCtrl.occurredError {
+		return results.ERROR, errors.NotFound{}
+	}
+	return results.OK, nil
+}
+
+func (mockCtrl *controllerFunc) AddVersion(ctx context.Context, name string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "AddVersion"
+	if mockCtrl.occurredError {
+		return results.ERROR, nil, errors.NotFound{}
+	}
+	return results.OK, map[string]interface{}{"name": name, "version": "1.4.0"}, nil
+}
+
+func (mockCtrl *controllerFunc) GetVersion(ctx context.Context, name string, version string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "GetVersion"
+	if mockCtrl.occurredError {
+		return results.ERROR, nil, errors.NotFound{}
+	}
+	return results.OK, map[string]interface{}{"name": name, "version": version}, nil
+}
@@ -0,0 +1,30 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package catalog
+
+import "net/http"
+
+var SdamCatalog SDAMCatalogAPIInterface
+
+type SDAMCatalogAPIInterface interface {
+	apps(w http.ResponseWriter, req *http.Request)
+	createApp(w http.ResponseWriter, req *http.Request)
+	app(w http.ResponseWriter, req *http.Request, name string)
+	deleteApp(w http.ResponseWriter, req *http.Request, name string)
+	addVersion(w http.ResponseWriter, req *http.Request, name string)
+	version(w http.ResponseWriter, req *http.Request, name string, version string)
+}
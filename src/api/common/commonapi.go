@@ -19,10 +19,25 @@
 package common
 
 import (
+	"commons/config"
 	"commons/errors"
+	"commons/requestid"
 	"encoding/json"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"strings"
+)
+
+const (
+	MESSAGE           = "message"         // used to indicate an error message.
+	CODE              = "code"            // used to indicate a stable code of an error.
+	KIND              = "kind"            // used to indicate the kind of the resource which caused an error.
+	ID                = "id"              // used to indicate the id of the resource which caused an error.
+	CAUSE             = "cause"           // used to indicate the cause of an error.
+	REQUEST_ID        = "requestId"       // used to indicate the id of a request.
+	REQUEST_ID_HEADER = requestid.HEADER  // used to indicate the header of a request id.
+	FORWARDED_HEADER  = "X-Forwarded-For" // used to indicate the header of forwarded addresses.
 )
 
 // WriteSuccess writes the data to the connection as part of an HTTP reply.
@@ -34,11 +49,30 @@ func WriteSuccess(w http.ResponseWriter, code int, data []byte) {
 
 // WriteError writes the data to the connection as part of an HTTP reply.
 // The http status code depend on an error type.
-// An error message will be included as a body.
+// An error message will be included as a body, together with the code,
+// the kind and the id of the resource and the cause of the error if exist.
+// If the response has a request id header, the request id is also included.
 func WriteError(w http.ResponseWriter, err error) {
 	code := convertToHttpStatusCode(err)
 	data := make(map[string]interface{})
-	data["message"] = err.Error()
+	data[MESSAGE] = err.Error()
+	data[CODE] = errors.INTERNAL_SERVER_ERROR
+
+	var detail errors.Error
+	if errors.As(err, &detail) {
+		data[CODE] = detail.Code()
+		if kind, id := detail.Resource(); kind != "" || id != "" {
+			data[KIND] = kind
+			data[ID] = id
+		}
+		if cause := detail.Unwrap(); cause != nil {
+			data[CAUSE] = cause.Error()
+		}
+	}
+
+	if requestId := w.Header().Get(REQUEST_ID_HEADER); requestId != "" {
+		data[REQUEST_ID] = requestId
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -71,39 +105,103 @@ func ChangeToJson(src map[string]interface{}) []byte {
 // In other cases, an appropriate error will be returned.
 func GetBodyFromReq(req *http.Request) (string, error) {
 	if req.Body == nil {
-		return "", errors.InvalidParam{"body is empty"}
+		return "", errors.InvalidParam{Message: "body is empty"}
 	}
 
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		return "", errors.IOError{err.Error()}
+		return "", errors.IOError{Cause: err}
 	}
 	return string(body), nil
 }
 
+// ClientIP returns the address of the client which sent the request.
+// X-Forwarded-For header is used only if the request comes from one of the proxies
+// in server.trusted_proxies. In that case, the header is read from the right,
+// and the first address which is not a trusted proxy is returned.
+func ClientIP(req *http.Request) string {
+	ip := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		ip = host
+	}
+
+	proxies := config.Get().Server.TrustedProxies
+	if !isTrustedProxy(ip, proxies) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(req.Header[FORWARDED_HEADER], ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(address) == nil {
+			// A malformed entry can not be trusted, nor anything left of it.
+			break
+		}
+		ip = address
+		if !isTrustedProxy(address, proxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// isTrustedProxy returns true if the ip is one of the proxies,
+// or included in one of the networks of the proxies.
+func isTrustedProxy(ip string, proxies []string) bool {
+	address := net.ParseIP(ip)
+	if address == nil {
+		return false
+	}
+
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			if network.Contains(address) {
+				return true
+			}
+		} else if trusted := net.ParseIP(proxy); trusted != nil && trusted.Equal(address) {
+			return true
+		}
+	}
+	return false
+}
+
 // convertToHttpStatusCode converts an error object to http status code.
+// The error is found by the chain of wrapped errors, and its code decides the status.
 // The following codes are used.
 //
 //    400 (Bad Request)
+//    401 (Unauthorized)
+//    403 (Forbidden)
 //    404 (Not Found)
+//    405 (Method Not Allowed)
 // 	  500 (Internal Server Error)
 //    503 (Service Unavailable)
 func convertToHttpStatusCode(err error) int {
+	var detail errors.Error
+	if !errors.As(err, &detail) {
+		return http.StatusInternalServerError
+	}
+
 	code := http.StatusInternalServerError
-	switch err.(type) {
-	case errors.InvalidParam,
-		errors.InvalidJSON,
-		errors.InvalidMethod,
-		errors.InvalidObjectId:
+	switch detail.Code() {
+	case errors.INVALID_PARAM,
+		errors.INVALID_JSON,
+		errors.INVALID_OBJECT_ID:
 		code = http.StatusBadRequest
-	case errors.NotFoundURL,
-		errors.NotFound:
+	case errors.UNAUTHORIZED:
+		code = http.StatusUnauthorized
+	case errors.FORBIDDEN:
+		code = http.StatusForbidden
+	case errors.INVALID_METHOD:
+		code = http.StatusMethodNotAllowed
+	case errors.NOT_FOUND_URL,
+		errors.NOT_FOUND:
 		code = http.StatusNotFound
-	case errors.DBConnectionError,
-		errors.DBOperationError:
+	case errors.DB_CONNECTION_ERROR,
+		errors.DB_OPERATION_ERROR:
 		code = http.StatusServiceUnavailable
-	case errors.IOError,
-		errors.InternalServerError:
+	case errors.IO_ERROR,
+		errors.INTERNAL_SERVER_ERROR:
 		code = http.StatusInternalServerError
 	}
 
@@ -18,10 +18,14 @@ package common
 
 import (
 	"bytes"
+	"commons/config"
 	Errors "commons/errors"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
@@ -42,6 +46,44 @@ func TestWriteError(t *testing.T) {
 	}
 }
 
+func TestWriteErrorWithResourceAndCause(t *testing.T) {
+	cause := errors.New("not found")
+	w := httptest.NewRecorder()
+	w.Header().Set(REQUEST_ID_HEADER, "request")
+	WriteError(w, Errors.NotFound{Kind: Errors.AGENT, ID: "agent", Cause: cause})
+	if w.Code != http.StatusNotFound {
+		t.Error("WriteError is invalid")
+	}
+
+	body := make(map[string]interface{})
+	json.Unmarshal(w.Body.Bytes(), &body)
+	expected := map[string]interface{}{
+		"message":   "not found target: agent agent: not found",
+		"code":      Errors.NOT_FOUND,
+		"kind":      Errors.AGENT,
+		"id":        "agent",
+		"cause":     "not found",
+		"requestId": "request",
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("Expected body: %v, actual body: %v", expected, body)
+	}
+}
+
+func TestWriteErrorWithWrappedError(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteError(w, fmt.Errorf("failed to deploy: %w", Errors.InvalidParam{Message: "body is empty"}))
+	if w.Code != http.StatusBadRequest {
+		t.Error("WriteError is invalid")
+	}
+
+	body := make(map[string]interface{})
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if body["code"] != Errors.INVALID_PARAM {
+		t.Errorf("Expected code: %s, actual code: %v", Errors.INVALID_PARAM, body["code"])
+	}
+}
+
 func TestMakeResponse(t *testing.T) {
 	w := httptest.NewRecorder()
 	MakeResponse(w, http.StatusOK, nil, nil)
@@ -76,6 +118,41 @@ func TestGetBodyFromReqWithEmptyBody(t *testing.T) {
 	}
 }
 
+func TestClientIP(t *testing.T) {
+	defaultConfig := config.Get()
+	cfg := config.Default()
+	cfg.Server.TrustedProxies = []string{"10.0.0.1", "192.168.0.0/16"}
+	config.Set(cfg)
+	defer config.Set(defaultConfig)
+
+	testList := []struct {
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		{"172.16.0.5:4000", nil, "172.16.0.5"},
+		{"172.16.0.5:4000", []string{"1.2.3.4"}, "172.16.0.5"},
+		{"10.0.0.1:4000", nil, "10.0.0.1"},
+		{"10.0.0.1:4000", []string{"1.2.3.4"}, "1.2.3.4"},
+		{"10.0.0.1:4000", []string{"6.6.6.6, 1.2.3.4, 192.168.1.1"}, "1.2.3.4"},
+		{"10.0.0.1:4000", []string{"6.6.6.6", "1.2.3.4"}, "1.2.3.4"},
+		{"10.0.0.1:4000", []string{"1.2.3.4, unknown"}, "10.0.0.1"},
+		{"192.168.1.1:4000", []string{"192.168.1.2"}, "192.168.1.2"},
+		{"[::1]:4000", []string{"1.2.3.4"}, "::1"},
+	}
+
+	for _, test := range testList {
+		req, _ := http.NewRequest("POST", "/api/v1/test/url", nil)
+		req.RemoteAddr = test.remoteAddr
+		for _, value := range test.forwarded {
+			req.Header.Add(FORWARDED_HEADER, value)
+		}
+		if ip := ClientIP(req); ip != test.expected {
+			t.Errorf("Expected ip: %s, actual ip: %s for %s %v", test.expected, ip, test.remoteAddr, test.forwarded)
+		}
+	}
+}
+
 func TestConvertToHttpStatusCodeWithInvalidParam(t *testing.T) {
 	err := Errors.InvalidParam{}
 	code := convertToHttpStatusCode(err)
@@ -92,10 +169,26 @@ func TestConvertToHttpStatusCodeWithInvalidJSON(t *testing.T) {
 	}
 }
 
+func TestConvertToHttpStatusCodeWithUnauthorized(t *testing.T) {
+	err := Errors.Unauthorized{}
+	code := convertToHttpStatusCode(err)
+	if code != http.StatusUnauthorized {
+		t.Error("convertToHttpStatusCode is invalid")
+	}
+}
+
+func TestConvertToHttpStatusCodeWithForbidden(t *testing.T) {
+	err := Errors.Forbidden{}
+	code := convertToHttpStatusCode(err)
+	if code != http.StatusForbidden {
+		t.Error("convertToHttpStatusCode is invalid")
+	}
+}
+
 func TestConvertToHttpStatusCodeWithInvalidMethod(t *testing.T) {
 	err := Errors.InvalidMethod{}
 	code := convertToHttpStatusCode(err)
-	if code != http.StatusBadRequest {
+	if code != http.StatusMethodNotAllowed {
 		t.Error("convertToHttpStatusCode is invalid")
 	}
 }
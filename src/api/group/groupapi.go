@@ -19,14 +19,14 @@
 package group
 
 import (
+	"api/auth"
 	"api/common"
-	"commons/errors"
+	"api/router"
 	"commons/logger"
 	"commons/results"
 	URL "commons/url"
 	"manager/group"
 	"net/http"
-	"strings"
 )
 
 const (
@@ -34,141 +34,138 @@ const (
 	PUT    string = "PUT"
 	POST   string = "POST"
 	DELETE string = "DELETE"
+
+	GROUP_ID string = "groupID" // name of the path parameter for a group id.
+	APP_ID   string = "appID"   // name of the path parameter for an app id.
+
+	SELECTOR string = "selector" // name of the query parameter for a label selector.
 )
 
-type _SDAMGroupApisHandler struct{}
 type _SDAMGroupApis struct{}
 
-var sdamH _SDAMGroupApisHandler
 var sdam _SDAMGroupApis
 var sdamGroupController group.GroupInterface
 
 func init() {
-	SdamGroupHandle = sdamH
 	SdamGroup = sdam
 	sdamGroupController = group.GroupController{}
 }
 
-// Handle calls a proper function according to the url and method received from remote device.
-func (sdamH _SDAMGroupApisHandler) Handle(w http.ResponseWriter, req *http.Request) {
-	url := strings.Replace(req.URL.Path, URL.Base()+URL.Groups(), "", -1)
-	split := strings.Split(url, "/")
-	switch len(split) {
-	case 1:
-		if req.Method == GET {
+// Routes returns a list of routes which calls a proper function according to
+// the url and method received from remote device.
+func Routes() []router.Route {
+	groups := URL.Base() + URL.Groups()
+	group := groups + "/{" + GROUP_ID + "}"
+	apps := group + URL.Apps()
+	app := apps + "/{" + APP_ID + "}"
+
+	// Callers limited to some groups are allowed to access those groups only,
+	// and not allowed to list every group, create a group or add agents to a group.
+	viewer := auth.Permit(auth.VIEWER, auth.RequireGroup(GROUP_ID))
+	operator := auth.Permit(auth.OPERATOR, auth.RequireGroup(GROUP_ID))
+	admin := auth.Permit(auth.ADMIN, auth.RequireGroup(GROUP_ID))
+
+	// Agents selected by labels are not limited to any group,
+	// so only unscoped callers are allowed to access them.
+	selected := URL.Base() + URL.Agents()
+	selectedApp := selected + URL.Apps() + "/{" + APP_ID + "}"
+	selectedOperator := auth.Permit(auth.OPERATOR, auth.RequireUnscoped)
+	selectedAdmin := auth.Permit(auth.ADMIN, auth.RequireUnscoped)
+
+	return []router.Route{
+		{Method: GET, Pattern: groups, Middlewares: auth.Permit(auth.VIEWER, auth.RequireUnscoped), Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
 			SdamGroup.groups(w, req)
-		} else {
-			common.WriteError(w, errors.InvalidMethod{req.Method})
-		}
-
-	case 2:
-		if "/"+split[1] == URL.Create() {
-			if req.Method == POST {
-				SdamGroup.createGroup(w, req)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else {
-			if req.Method == GET || req.Method == DELETE {
-				groupID := split[1]
-				SdamGroup.group(w, req, groupID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		}
-
-	case 3:
-		groupID := split[1]
-		switch {
-		case "/"+split[2] == URL.Deploy():
-			if req.Method == POST {
-				SdamGroup.groupDeployApp(w, req, groupID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-
-		case "/"+split[2] == URL.Join():
-			if req.Method == POST {
-				SdamGroup.groupJoin(w, req, groupID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-
-		case "/"+split[2] == URL.Leave():
-			if req.Method == POST {
-				SdamGroup.groupLeave(w, req, groupID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-
-		case "/"+split[2] == URL.Apps():
-			if req.Method == GET {
-				SdamGroup.groupInfoApps(w, req, groupID)
-			} else {
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-
-		default:
-			common.WriteError(w, errors.NotFoundURL{})
-		}
-
-	case 4:
-		if "/"+split[2] == URL.Apps() {
-			groupID, appID := split[1], split[3]
-			switch req.Method {
-			case GET:
-				SdamGroup.groupInfoApp(w, req, groupID, appID)
-
-			case POST:
-				SdamGroup.groupUpdateAppInfo(w, req, groupID, appID)
-
-			case DELETE:
-				SdamGroup.groupDeleteApp(w, req, groupID, appID)
-
-			default:
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else {
-			common.WriteError(w, errors.NotFoundURL{})
-		}
-
-	case 5:
-		if "/"+split[2] == URL.Apps() {
-			groupID, appID := split[1], split[3]
-			switch {
-			case "/"+split[4] == URL.Start() && req.Method == POST:
-				SdamGroup.groupStartApp(w, req, groupID, appID)
-
-			case "/"+split[4] == URL.Stop() && req.Method == POST:
-				SdamGroup.groupStopApp(w, req, groupID, appID)
-
-			case "/"+split[4] == URL.Update() && req.Method == POST:
-				SdamGroup.groupUpdateApp(w, req, groupID, appID)
-
-			default:
-				common.WriteError(w, errors.InvalidMethod{req.Method})
-			}
-		} else {
-			common.WriteError(w, errors.NotFoundURL{})
-		}
+		}},
+		{Method: POST, Pattern: groups + URL.Create(), Middlewares: auth.Permit(auth.ADMIN, auth.RequireUnscoped), Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.createGroup(w, req)
+		}},
+		{Method: GET, Pattern: group, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.group(w, req, params[GROUP_ID])
+		}},
+		{Method: DELETE, Pattern: group, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.group(w, req, params[GROUP_ID])
+		}},
+		{Method: GET, Pattern: group + URL.Availability(), Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupAvailability(w, req, params[GROUP_ID])
+		}},
+		{Method: POST, Pattern: group + URL.Deploy(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupDeployApp(w, req, params[GROUP_ID])
+		}},
+		{Method: POST, Pattern: group + URL.Join(), Middlewares: auth.Permit(auth.ADMIN, auth.RequireUnscoped), Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupJoin(w, req, params[GROUP_ID])
+		}},
+		{Method: POST, Pattern: group + URL.Leave(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupLeave(w, req, params[GROUP_ID])
+		}},
+		{Method: GET, Pattern: group + URL.Desired(), Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupDesiredState(w, req, params[GROUP_ID])
+		}},
+		{Method: PUT, Pattern: group + URL.Desired(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupDesiredState(w, req, params[GROUP_ID])
+		}},
+		{Method: DELETE, Pattern: group + URL.Desired(), Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupDesiredState(w, req, params[GROUP_ID])
+		}},
+		{Method: POST, Pattern: group + URL.Converge(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupConverge(w, req, params[GROUP_ID])
+		}},
+		{Method: GET, Pattern: apps, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupInfoApps(w, req, params[GROUP_ID])
+		}},
+		{Method: GET, Pattern: app, Middlewares: viewer, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupInfoApp(w, req, params[GROUP_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app, Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupUpdateAppInfo(w, req, params[GROUP_ID], params[APP_ID])
+		}},
+		{Method: DELETE, Pattern: app, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupDeleteApp(w, req, params[GROUP_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app + URL.Start(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupStartApp(w, req, params[GROUP_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app + URL.Stop(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupStopApp(w, req, params[GROUP_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app + URL.Update(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupUpdateApp(w, req, params[GROUP_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: app + URL.Rollback(), Middlewares: operator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.groupRollbackApp(w, req, params[GROUP_ID], params[APP_ID])
+		}},
+		{Method: POST, Pattern: selected + URL.Deploy(), Middlewares: selectedAdmin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.selectorDeployApp(w, req)
+		}},
+		{Method: DELETE, Pattern: selectedApp, Middlewares: selectedAdmin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.selectorDeleteApp(w, req, params[APP_ID])
+		}},
+		{Method: POST, Pattern: selectedApp + URL.Start(), Middlewares: selectedOperator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.selectorStartApp(w, req, params[APP_ID])
+		}},
+		{Method: POST, Pattern: selectedApp + URL.Stop(), Middlewares: selectedOperator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.selectorStopApp(w, req, params[APP_ID])
+		}},
+		{Method: POST, Pattern: selectedApp + URL.Update(), Middlewares: selectedOperator, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamGroup.selectorUpdateApp(w, req, params[APP_ID])
+		}},
 	}
 }
 
 // createGroup handles requests which is used to create new group.
 //
 //    paths: '/api/v1/groups/create'
-//    method: GET
+//    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) createGroup(w http.ResponseWriter, req *http.Request) {
-	logger.Logging(logger.DEBUG, "[GROUP] Create SDA Group")
-	result, resp, err := sdamGroupController.CreateGroup()
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Create SDA Group")
+	result, resp, err := sdamGroupController.CreateGroup(req.Context())
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
-// group handles requests which is used to get information of group identified by the given groupID.
+// group handles requests which is used to get or delete group identified by the given groupID.
 //
 //    paths: '/api/v1/groups/{groupID}'
-//    method: GET
+//    method: GET, DELETE
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) group(w http.ResponseWriter, req *http.Request, groupID string) {
 	var result int
@@ -176,11 +173,11 @@ func (Groupasdam _SDAMGroupApis) group(w http.ResponseWriter, req *http.Request,
 	var err error
 	switch req.Method {
 	case GET:
-		logger.Logging(logger.DEBUG, "[GROUP] Get SDA Group")
-		result, resp, err = sdamGroupController.GetGroup(groupID)
+		logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Get SDA Group")
+		result, resp, err = sdamGroupController.GetGroup(req.Context(), groupID)
 	case DELETE:
-		logger.Logging(logger.DEBUG, "[GROUP] Delete SDA Group")
-		result, resp, err = sdamGroupController.DeleteGroup(groupID)
+		logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Delete SDA Group")
+		result, resp, err = sdamGroupController.DeleteGroup(req.Context(), groupID)
 	}
 
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
@@ -192,8 +189,20 @@ func (Groupasdam _SDAMGroupApis) group(w http.ResponseWriter, req *http.Request,
 //    method: GET
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groups(w http.ResponseWriter, req *http.Request) {
-	logger.Logging(logger.DEBUG, "[GROUP] Get All SDA Groups")
-	result, resp, err := sdamGroupController.GetGroups()
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Get All SDA Groups")
+	result, resp, err := sdamGroupController.GetGroups(req.Context(), req.URL.Query())
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// groupAvailability handles requests which is used to get availability of members of the group
+// identified by the given groupID.
+//
+//    paths: '/api/v1/groups/{groupID}/availability'
+//    method: GET
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMGroupApis) groupAvailability(w http.ResponseWriter, req *http.Request, groupID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Get Availability")
+	result, resp, err := sdamGroupController.GetAvailability(req.Context(), groupID, req.URL.Query())
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -204,14 +213,14 @@ func (Groupasdam _SDAMGroupApis) groups(w http.ResponseWriter, req *http.Request
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupJoin(w http.ResponseWriter, req *http.Request, groupID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Join SDA Group")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Join SDA Group")
 	body, err := common.GetBodyFromReq(req)
 	if err != nil {
 		common.MakeResponse(w, results.ERROR, nil, err)
 		return
 	}
 
-	result, resp, err := sdamGroupController.JoinGroup(groupID, body)
+	result, resp, err := sdamGroupController.JoinGroup(req.Context(), groupID, body)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -222,32 +231,76 @@ func (Groupasdam _SDAMGroupApis) groupJoin(w http.ResponseWriter, req *http.Requ
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupLeave(w http.ResponseWriter, req *http.Request, groupID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Leave SDA Group")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Leave SDA Group")
 	body, err := common.GetBodyFromReq(req)
 	if err != nil {
 		common.MakeResponse(w, results.ERROR, nil, err)
 		return
 	}
 
-	result, resp, err := sdamGroupController.LeaveGroup(groupID, body)
+	result, resp, err := sdamGroupController.LeaveGroup(req.Context(), groupID, body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// groupDesiredState handles requests which is used to declare, get or remove the desired state
+// of group identified by the given groupID.
+//
+//    paths: '/api/v1/groups/{groupID}/desired'
+//    method: GET, PUT, DELETE
+//    responses: if successful, 200 status code will be returned.
+func (Groupasdam _SDAMGroupApis) groupDesiredState(w http.ResponseWriter, req *http.Request, groupID string) {
+	var result int
+	var resp map[string]interface{}
+	var err error
+	switch req.Method {
+	case GET:
+		logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Get Desired State")
+		result, resp, err = sdamGroupController.GetDesiredState(req.Context(), groupID)
+	case PUT:
+		logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Set Desired State")
+		var body string
+		body, err = common.GetBodyFromReq(req)
+		if err != nil {
+			common.MakeResponse(w, results.ERROR, nil, err)
+			return
+		}
+		result, resp, err = sdamGroupController.SetDesiredState(req.Context(), groupID, body)
+	case DELETE:
+		logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Delete Desired State")
+		result, resp, err = sdamGroupController.DeleteDesiredState(req.Context(), groupID)
+	}
+
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// groupConverge handles requests which is used to converge members of group
+// identified by the given groupID toward its desired state right away.
+//
+//    paths: '/api/v1/groups/{groupID}/converge'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+//               207 status code will be returned if the convergence fails on some members.
+func (Groupasdam _SDAMGroupApis) groupConverge(w http.ResponseWriter, req *http.Request, groupID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Converge")
+	result, resp, err := sdamGroupController.Converge(req.Context(), groupID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
 // groupDeployApp handles requests which is used to deploy new application to group
 // identified by the given groupID.
 //
-//    paths: '/api/v1/groups/{groupID}/apps/deploy'
+//    paths: '/api/v1/groups/{groupID}/deploy'
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupDeployApp(w http.ResponseWriter, req *http.Request, groupID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Deploy App")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Deploy App")
 	body, err := common.GetBodyFromReq(req)
 	if err != nil {
 		common.MakeResponse(w, results.ERROR, nil, err)
 		return
 	}
 
-	result, resp, err := sdamGroupController.DeployApp(groupID, body)
+	result, resp, err := sdamGroupController.DeployApp(req.Context(), groupID, body)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -258,8 +311,8 @@ func (Groupasdam _SDAMGroupApis) groupDeployApp(w http.ResponseWriter, req *http
 //    method: GET
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupInfoApps(w http.ResponseWriter, req *http.Request, groupID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Get Info Apps")
-	result, resp, err := sdamGroupController.GetApps(groupID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Get Info Apps")
+	result, resp, err := sdamGroupController.GetApps(req.Context(), groupID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -270,8 +323,8 @@ func (Groupasdam _SDAMGroupApis) groupInfoApps(w http.ResponseWriter, req *http.
 //    method: GET
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupInfoApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Get Info App")
-	result, resp, err := sdamGroupController.GetApp(groupID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Get Info App")
+	result, resp, err := sdamGroupController.GetApp(req.Context(), groupID, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -282,14 +335,14 @@ func (Groupasdam _SDAMGroupApis) groupInfoApp(w http.ResponseWriter, req *http.R
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupUpdateAppInfo(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Update App Info")
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Update App Info")
 	body, err := common.GetBodyFromReq(req)
 	if err != nil {
 		common.MakeResponse(w, results.ERROR, nil, err)
 		return
 	}
 
-	result, resp, err := sdamGroupController.UpdateAppInfo(groupID, appID, body)
+	result, resp, err := sdamGroupController.UpdateAppInfo(req.Context(), groupID, appID, body)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -300,8 +353,8 @@ func (Groupasdam _SDAMGroupApis) groupUpdateAppInfo(w http.ResponseWriter, req *
 //    method: DELETE
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupDeleteApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Delete App")
-	result, resp, err := sdamGroupController.DeleteApp(groupID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Delete App")
+	result, resp, err := sdamGroupController.DeleteApp(req.Context(), groupID, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -312,8 +365,8 @@ func (Groupasdam _SDAMGroupApis) groupDeleteApp(w http.ResponseWriter, req *http
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupStartApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Start App")
-	result, resp, err := sdamGroupController.StartApp(groupID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Start App")
+	result, resp, err := sdamGroupController.StartApp(req.Context(), groupID, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -324,8 +377,8 @@ func (Groupasdam _SDAMGroupApis) groupStartApp(w http.ResponseWriter, req *http.
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupStopApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Stop App")
-	result, resp, err := sdamGroupController.StopApp(groupID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Stop App")
+	result, resp, err := sdamGroupController.StopApp(req.Context(), groupID, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
 
@@ -336,7 +389,98 @@ func (Groupasdam _SDAMGroupApis) groupStopApp(w http.ResponseWriter, req *http.R
 //    method: POST
 //    responses: if successful, 200 status code will be returned.
 func (Groupasdam _SDAMGroupApis) groupUpdateApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
-	logger.Logging(logger.DEBUG, "[GROUP] Update App")
-	result, resp, err := sdamGroupController.UpdateApp(groupID, appID)
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Update App")
+	result, resp, err := sdamGroupController.UpdateApp(req.Context(), groupID, appID)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// groupRollbackApp handles requests related to rolling back application installed on group
+// identified by the given groupID to an earlier revision.
+// The body is optional, and the previous revision of each member is applied again if it is not given.
+//
+//    paths: '/api/v1/groups/{groupID}/apps/{appID}/rollback'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+//               207 status code will be returned if the rollback fails on some members.
+func (Groupasdam _SDAMGroupApis) groupRollbackApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Rollback App")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamGroupController.RollbackApp(req.Context(), groupID, appID, body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// selectorDeployApp handles requests which is used to deploy new application to agents
+// whose labels match the selector query parameter.
+//
+//    paths: '/api/v1/agents/deploy?selector={selector}'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (Groupasdam _SDAMGroupApis) selectorDeployApp(w http.ResponseWriter, req *http.Request) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Deploy App By Selector")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	selector := req.URL.Query().Get(SELECTOR)
+	result, resp, err := sdamGroupController.DeployAppBySelector(req.Context(), selector, body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// selectorDeleteApp handles requests related to delete application installed on agents
+// whose labels match the selector query parameter.
+//
+//    paths: '/api/v1/agents/apps/{appID}?selector={selector}'
+//    method: DELETE
+//    responses: if successful, 200 status code will be returned.
+func (Groupasdam _SDAMGroupApis) selectorDeleteApp(w http.ResponseWriter, req *http.Request, appID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Delete App By Selector")
+	selector := req.URL.Query().Get(SELECTOR)
+	result, resp, err := sdamGroupController.DeleteAppBySelector(req.Context(), selector, appID)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// selectorStartApp handles requests related to start application installed on agents
+// whose labels match the selector query parameter.
+//
+//    paths: '/api/v1/agents/apps/{appID}/start?selector={selector}'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (Groupasdam _SDAMGroupApis) selectorStartApp(w http.ResponseWriter, req *http.Request, appID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Start App By Selector")
+	selector := req.URL.Query().Get(SELECTOR)
+	result, resp, err := sdamGroupController.StartAppBySelector(req.Context(), selector, appID)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// selectorStopApp handles requests related to stop application installed on agents
+// whose labels match the selector query parameter.
+//
+//    paths: '/api/v1/agents/apps/{appID}/stop?selector={selector}'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (Groupasdam _SDAMGroupApis) selectorStopApp(w http.ResponseWriter, req *http.Request, appID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Stop App By Selector")
+	selector := req.URL.Query().Get(SELECTOR)
+	result, resp, err := sdamGroupController.StopAppBySelector(req.Context(), selector, appID)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// selectorUpdateApp handles requests related to updating application installed on agents
+// whose labels match the selector query parameter.
+//
+//    paths: '/api/v1/agents/apps/{appID}/update?selector={selector}'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (Groupasdam _SDAMGroupApis) selectorUpdateApp(w http.ResponseWriter, req *http.Request, appID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[GROUP] Update App By Selector")
+	selector := req.URL.Query().Get(SELECTOR)
+	result, resp, err := sdamGroupController.UpdateAppBySelector(req.Context(), selector, appID)
 	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
 }
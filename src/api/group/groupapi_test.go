@@ -17,17 +17,33 @@
 package group
 
 import (
+	"api/router"
 	"bytes"
+	"commons/config"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 )
 
 //Test functions for Group API Handler.
 
 type handleFunc struct {
 	functionCall string
+	groupID      string
+	appID        string
+}
+
+func newRouter() *router.Router {
+	r := router.New()
+	r.AddRoutes(Routes()...)
+	return r
 }
 
 func TestHandle(t *testing.T) {
@@ -35,14 +51,20 @@ func TestHandle(t *testing.T) {
 	mockHandle := handleFunc{}
 	defaultApis := SdamGroup
 	SdamGroup = &mockHandle
+	r := newRouter()
 	Input := [][]string{
 		{GET, "/api/v1/groups", "groups"},
 		{POST, "/api/v1/groups/create", "createGroup"},
 		{GET, "/api/v1/groups/groupID", "group"},
 		{DELETE, "/api/v1/groups/groupID", "group"},
+		{GET, "/api/v1/groups/groupID/availability", "groupAvailability"},
 		{POST, "/api/v1/groups/groupID/deploy", "groupDeployApp"},
 		{POST, "/api/v1/groups/groupID/join", "groupJoin"},
 		{POST, "/api/v1/groups/groupID/leave", "groupLeave"},
+		{GET, "/api/v1/groups/groupID/desired", "groupDesiredState"},
+		{PUT, "/api/v1/groups/groupID/desired", "groupDesiredState"},
+		{DELETE, "/api/v1/groups/groupID/desired", "groupDesiredState"},
+		{POST, "/api/v1/groups/groupID/converge", "groupConverge"},
 		{GET, "/api/v1/groups/groupID/apps", "groupInfoApps"},
 		{GET, "/api/v1/groups/groupID/apps/appID", "groupInfoApp"},
 		{POST, "/api/v1/groups/groupID/apps/appID", "groupUpdateAppInfo"},
@@ -50,11 +72,17 @@ func TestHandle(t *testing.T) {
 		{POST, "/api/v1/groups/groupID/apps/appID/start", "groupStartApp"},
 		{POST, "/api/v1/groups/groupID/apps/appID/stop", "groupStopApp"},
 		{POST, "/api/v1/groups/groupID/apps/appID/update", "groupUpdateApp"},
+		{POST, "/api/v1/groups/groupID/apps/appID/rollback", "groupRollbackApp"},
+		{POST, "/api/v1/agents/deploy?selector=site=plant3", "selectorDeployApp"},
+		{DELETE, "/api/v1/agents/apps/appID?selector=site=plant3", "selectorDeleteApp"},
+		{POST, "/api/v1/agents/apps/appID/start?selector=site=plant3", "selectorStartApp"},
+		{POST, "/api/v1/agents/apps/appID/stop?selector=site=plant3", "selectorStopApp"},
+		{POST, "/api/v1/agents/apps/appID/update?selector=site=plant3", "selectorUpdateApp"},
 	}
 	for _, val := range Input {
 		method, url, funcname := val[0], val[1], val[2]
 		req, _ := http.NewRequest(method, url, nil)
-		SdamGroupHandle.Handle(w, req)
+		r.ServeHTTP(w, req)
 		if mockHandle.functionCall != funcname {
 			t.Error("[SDAM][Group]Handle is invalid about " + funcname)
 		}
@@ -62,8 +90,39 @@ func TestHandle(t *testing.T) {
 	SdamGroup = defaultApis
 }
 
-func TestHandle_Invalid_Method(t *testing.T) {
+func TestHandle_Path_Params(t *testing.T) {
 	w := httptest.NewRecorder()
+	mockHandle := handleFunc{}
+	defaultApis := SdamGroup
+	SdamGroup = &mockHandle
+	req, _ := http.NewRequest(POST, "/api/v1/groups/testGroupID/apps/testAppID/start", nil)
+	newRouter().ServeHTTP(w, req)
+	if mockHandle.functionCall != "groupStartApp" || mockHandle.groupID != "testGroupID" || mockHandle.appID != "testAppID" {
+		t.Error("[SDAM][Group]Handle is invalid about path parameters")
+	}
+	SdamGroup = defaultApis
+}
+
+func TestHandle_Invalid_URL(t *testing.T) {
+	Input := []string{
+		"/foo/api/v1/groups",
+		"/api/v1/groupsX",
+		"/api/v1/groups/groupID/deployX",
+		"/api/v1/groups/groupID/apps/appID/stop/now",
+	}
+	r := newRouter()
+	for _, url := range Input {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(POST, url, nil)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Error("[SDAM][Group]Handle is invalid about " + url)
+		}
+	}
+}
+
+func TestHandle_Invalid_Method(t *testing.T) {
+	r := newRouter()
 	Input := map[string][]string{
 		"/api/v1/groups":                           {POST, DELETE, PUT},
 		"/api/v1/groups/create":                    {GET, DELETE, PUT},
@@ -71,23 +130,75 @@ func TestHandle_Invalid_Method(t *testing.T) {
 		"/api/v1/groups/groupID/deploy":            {GET, DELETE, PUT},
 		"/api/v1/groups/groupID/join":              {GET, DELETE, PUT},
 		"/api/v1/groups/groupID/leave":             {GET, DELETE, PUT},
+		"/api/v1/groups/groupID/desired":           {POST},
+		"/api/v1/groups/groupID/converge":          {GET, DELETE, PUT},
 		"/api/v1/groups/groupID/apps":              {POST, DELETE, PUT},
 		"/api/v1/groups/groupID/apps/appID":        {PUT},
 		"/api/v1/groups/groupID/apps/appID/start":  {GET, DELETE, PUT},
 		"/api/v1/groups/groupID/apps/appID/stop":   {GET, DELETE, PUT},
 		"/api/v1/groups/groupID/apps/appID/update": {GET, DELETE, PUT},
+		"/api/v1/agents/deploy":                    {GET, DELETE, PUT},
+		"/api/v1/agents/apps/appID":                {GET, POST, PUT},
+		"/api/v1/agents/apps/appID/start":          {GET, DELETE, PUT},
 	}
 	for key, vals := range Input {
 		for _, val := range vals {
+			w := httptest.NewRecorder()
 			req, _ := http.NewRequest(val, key, nil)
-			SdamGroupHandle.Handle(w, req)
-			if w.Code != http.StatusBadRequest {
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") == "" {
 				t.Error("[SDAM][Group]Handle is invalid")
 			}
 		}
 	}
 }
 
+func TestHandle_Scope(t *testing.T) {
+	defaultConfig := config.Get()
+	cfg := config.Default()
+	cfg.Auth = config.AuthConfig{Enabled: true, TokenSecret: "tokenSecret"}
+	config.Set(cfg)
+	mockHandle := handleFunc{}
+	defaultApis := SdamGroup
+	SdamGroup = &mockHandle
+	r := newRouter()
+	Input := []struct {
+		groups   []string
+		funcname string
+	}{
+		{nil, "groups"},
+		{[]string{"groupID"}, ""},
+	}
+	for _, val := range Input {
+		mockHandle.functionCall = ""
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(GET, "/api/v1/groups", nil)
+		req.Header.Set("Authorization", "Bearer "+bearerToken(val.groups, "tokenSecret"))
+		r.ServeHTTP(w, req)
+		if mockHandle.functionCall != val.funcname {
+			t.Errorf("[SDAM][Group]Handle is invalid about scope of %v", val.groups)
+		}
+		if val.funcname == "" && w.Code != http.StatusForbidden {
+			t.Errorf("[SDAM][Group]Handle is invalid about scope of %v", val.groups)
+		}
+	}
+	SdamGroup = defaultApis
+	config.Set(defaultConfig)
+}
+
+// bearerToken returns a token of a viewer limited to the groups, signed with the secret.
+func bearerToken(groups []string, secret string) string {
+	encode := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	claims := map[string]interface{}{"sub": "viewer", "exp": time.Now().Unix() + 60, "role": "viewer", "groups": groups}
+	input := encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
 //Mock functions for Group APIs.
 
 func (mockHandle *handleFunc) createGroup(w http.ResponseWriter, req *http.Request) {
@@ -102,6 +213,10 @@ func (mockHandle *handleFunc) groups(w http.ResponseWriter, req *http.Request) {
 	mockHandle.functionCall = "groups"
 }
 
+func (mockHandle *handleFunc) groupAvailability(w http.ResponseWriter, req *http.Request, groupID string) {
+	mockHandle.functionCall = "groupAvailability"
+}
+
 func (mockHandle *handleFunc) groupJoin(w http.ResponseWriter, req *http.Request, groupID string) {
 	mockHandle.functionCall = "groupJoin"
 }
@@ -132,6 +247,7 @@ func (mockHandle *handleFunc) groupDeleteApp(w http.ResponseWriter, req *http.Re
 
 func (mockHandle *handleFunc) groupStartApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
 	mockHandle.functionCall = "groupStartApp"
+	mockHandle.groupID, mockHandle.appID = groupID, appID
 }
 
 func (mockHandle *handleFunc) groupStopApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
@@ -142,10 +258,43 @@ func (mockHandle *handleFunc) groupUpdateApp(w http.ResponseWriter, req *http.Re
 	mockHandle.functionCall = "groupUpdateApp"
 }
 
+func (mockHandle *handleFunc) groupRollbackApp(w http.ResponseWriter, req *http.Request, groupID string, appID string) {
+	mockHandle.functionCall = "groupRollbackApp"
+}
+
+func (mockHandle *handleFunc) groupDesiredState(w http.ResponseWriter, req *http.Request, groupID string) {
+	mockHandle.functionCall = "groupDesiredState"
+}
+
+func (mockHandle *handleFunc) groupConverge(w http.ResponseWriter, req *http.Request, groupID string) {
+	mockHandle.functionCall = "groupConverge"
+}
+
+func (mockHandle *handleFunc) selectorDeployApp(w http.ResponseWriter, req *http.Request) {
+	mockHandle.functionCall = "selectorDeployApp"
+}
+
+func (mockHandle *handleFunc) selectorDeleteApp(w http.ResponseWriter, req *http.Request, appID string) {
+	mockHandle.functionCall = "selectorDeleteApp"
+}
+
+func (mockHandle *handleFunc) selectorStartApp(w http.ResponseWriter, req *http.Request, appID string) {
+	mockHandle.functionCall = "selectorStartApp"
+}
+
+func (mockHandle *handleFunc) selectorStopApp(w http.ResponseWriter, req *http.Request, appID string) {
+	mockHandle.functionCall = "selectorStopApp"
+}
+
+func (mockHandle *handleFunc) selectorUpdateApp(w http.ResponseWriter, req *http.Request, appID string) {
+	mockHandle.functionCall = "selectorUpdateApp"
+}
+
 //Test functions for Group APIs.
 
 type controllerFunc struct {
 	functionCall  string
+	selector      string
 	occurredError bool
 }
 
@@ -255,6 +404,29 @@ func TestGroups_controller_occurred_error(t *testing.T) {
 	}
 }
 
+func TestGroupAvailability(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/groups/testGroupID/availability?from=0", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.groupAvailability(w, req, "testGroupID")
+	if mockCtrl.functionCall != "GetAvailability" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]groupAvailability is invalid")
+	}
+}
+
+func TestGroupAvailability_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/groups/testGroupID/availability", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.groupAvailability(w, req, "testGroupID")
+	if mockCtrl.functionCall != "GetAvailability" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]groupAvailability is invalid about controller occurred error")
+	}
+}
+
 func TestGroupJoin(t *testing.T) {
 	mockCtrl := newCtrlFunc()
 	w := httptest.NewRecorder()
@@ -529,9 +701,270 @@ func TestGroupUpdateApp_controller_occurred_error(t *testing.T) {
 	}
 }
 
+func TestGroupRollbackApp(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	bod := []byte(`{"revision":2}`)
+	req, _ := http.NewRequest(POST, "/api/v1/groups/testGroupID/apps/testAppID/rollback", bytes.NewReader(bod))
+	sdamGroupController = mockCtrl
+	SdamGroup.groupRollbackApp(w, req, "testGroupID", "testAppID")
+	if mockCtrl.functionCall != "RollbackApp" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]groupRollbackApp is invalid")
+	}
+}
+
+func TestGroupRollbackApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	bod := []byte("error")
+	req, _ := http.NewRequest(POST, "/api/v1/groups/testGroupID/apps/testAppID/rollback", bytes.NewReader(bod))
+	sdamGroupController = mockCtrl
+	SdamGroup.groupRollbackApp(w, req, "testGroupID", "testAppID")
+	if mockCtrl.functionCall != "RollbackApp" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]groupRollbackApp is invalid about controller occurred error")
+	}
+}
+
+func TestGroupRollbackApp_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/groups/testGroupID/apps/testAppID/rollback", nil)
+	SdamGroup.groupRollbackApp(w, req, "testGroupID", "testAppID")
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Group]groupRollbackApp is invalid about empty body")
+	}
+}
+
+func TestGroupDesiredStateGET(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/groups/testGroupID/desired", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.groupDesiredState(w, req, "testGroupID")
+	if mockCtrl.functionCall != "GetDesiredState" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]groupDesiredState is invalid")
+	}
+}
+
+func TestGroupDesiredStateGET_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/groups/testGroupID/desired", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.groupDesiredState(w, req, "testGroupID")
+	if mockCtrl.functionCall != "GetDesiredState" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]groupDesiredState is invalid about controller occurred error")
+	}
+}
+
+func TestGroupDesiredStatePUT(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PUT, "/api/v1/groups/testGroupID/desired", bytes.NewReader([]byte(`{"apps":[{"app":"line-monitor","version":"1.4.0"}]}`)))
+	sdamGroupController = mockCtrl
+	SdamGroup.groupDesiredState(w, req, "testGroupID")
+	if mockCtrl.functionCall != "SetDesiredState" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]groupDesiredState is invalid")
+	}
+}
+
+func TestGroupDesiredStatePUT_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PUT, "/api/v1/groups/testGroupID/desired", bytes.NewReader([]byte(`{"apps":[{"app":"line-monitor","version":"1.4.0"}]}`)))
+	sdamGroupController = mockCtrl
+	SdamGroup.groupDesiredState(w, req, "testGroupID")
+	if mockCtrl.functionCall != "SetDesiredState" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]groupDesiredState is invalid about controller occurred error")
+	}
+}
+
+func TestGroupDesiredStateDELETE(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/groups/testGroupID/desired", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.groupDesiredState(w, req, "testGroupID")
+	if mockCtrl.functionCall != "DeleteDesiredState" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]groupDesiredState is invalid")
+	}
+}
+
+func TestGroupDesiredStateDELETE_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/groups/testGroupID/desired", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.groupDesiredState(w, req, "testGroupID")
+	if mockCtrl.functionCall != "DeleteDesiredState" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]groupDesiredState is invalid about controller occurred error")
+	}
+}
+
+func TestGroupDesiredStatePUT_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(PUT, "/api/v1/groups/testGroupID/desired", nil)
+	SdamGroup.groupDesiredState(w, req, "testGroupID")
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Group]groupDesiredState is invalid about empty body")
+	}
+}
+
+func TestGroupConverge(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/groups/testGroupID/converge", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.groupConverge(w, req, "testGroupID")
+	if mockCtrl.functionCall != "Converge" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]groupConverge is invalid")
+	}
+}
+
+func TestGroupConverge_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/groups/testGroupID/converge", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.groupConverge(w, req, "testGroupID")
+	if mockCtrl.functionCall != "Converge" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]groupConverge is invalid about controller occurred error")
+	}
+}
+
+func TestSelectorDeployApp(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	bod, _ := json.Marshal(testBody)
+	req, _ := http.NewRequest(POST, "/api/v1/agents/deploy?selector=site=plant3", bytes.NewReader(bod))
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorDeployApp(w, req)
+	if mockCtrl.functionCall != "DeployAppBySelector" || mockCtrl.selector != "site=plant3" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]selectorDeployApp is invalid")
+	}
+}
+
+func TestSelectorDeployApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	bod := []byte("error")
+	req, _ := http.NewRequest(POST, "/api/v1/agents/deploy?selector=site=plant3", bytes.NewReader(bod))
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorDeployApp(w, req)
+	if mockCtrl.functionCall != "DeployAppBySelector" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]selectorDeployApp is invalid about controller occurred error")
+	}
+}
+
+func TestSelectorDeployApp_empty_body(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/deploy?selector=site=plant3", nil)
+	SdamGroup.selectorDeployApp(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Group]selectorDeployApp is invalid about emtpy body")
+	}
+}
+
+func TestSelectorDeleteApp(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/agents/apps/testAppID?selector=site=plant3", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorDeleteApp(w, req, "testAppID")
+	if mockCtrl.functionCall != "DeleteAppBySelector" || mockCtrl.selector != "site=plant3" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]selectorDeleteApp is invalid")
+	}
+}
+
+func TestSelectorDeleteApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/agents/apps/testAppID?selector=site=plant3", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorDeleteApp(w, req, "testAppID")
+	if mockCtrl.functionCall != "DeleteAppBySelector" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]selectorDeleteApp is invalid about controller occurred error")
+	}
+}
+
+func TestSelectorStartApp(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/apps/testAppID/start?selector=site=plant3", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorStartApp(w, req, "testAppID")
+	if mockCtrl.functionCall != "StartAppBySelector" || mockCtrl.selector != "site=plant3" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]selectorStartApp is invalid")
+	}
+}
+
+func TestSelectorStartApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/apps/testAppID/start?selector=site=plant3", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorStartApp(w, req, "testAppID")
+	if mockCtrl.functionCall != "StartAppBySelector" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]selectorStartApp is invalid about controller occurred error")
+	}
+}
+
+func TestSelectorStopApp(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/apps/testAppID/stop?selector=site=plant3", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorStopApp(w, req, "testAppID")
+	if mockCtrl.functionCall != "StopAppBySelector" || mockCtrl.selector != "site=plant3" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]selectorStopApp is invalid")
+	}
+}
+
+func TestSelectorStopApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/apps/testAppID/stop?selector=site=plant3", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorStopApp(w, req, "testAppID")
+	if mockCtrl.functionCall != "StopAppBySelector" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]selectorStopApp is invalid about controller occurred error")
+	}
+}
+
+func TestSelectorUpdateApp(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/apps/testAppID/update?selector=site=plant3", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorUpdateApp(w, req, "testAppID")
+	if mockCtrl.functionCall != "UpdateAppBySelector" || mockCtrl.selector != "site=plant3" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Group]selectorUpdateApp is invalid")
+	}
+}
+
+func TestSelectorUpdateApp_controller_occurred_error(t *testing.T) {
+	mockCtrl := newCtrlFunc()
+	mockCtrl.occurredError = true
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/agents/apps/testAppID/update?selector=site=plant3", nil)
+	sdamGroupController = mockCtrl
+	SdamGroup.selectorUpdateApp(w, req, "testAppID")
+	if mockCtrl.functionCall != "UpdateAppBySelector" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Group]selectorUpdateApp is invalid about controller occurred error")
+	}
+}
+
 //Mock functions for Group Controller Functions.
 
-func (mockCtrl *controllerFunc) CreateGroup() (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) CreateGroup(ctx context.Context) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "CreateGroup"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -539,7 +972,7 @@ func (mockCtrl *controllerFunc) CreateGroup() (int, map[string]interface{}, erro
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) GetGroup(groupID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetGroup(ctx context.Context, groupID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "GetGroup"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -547,7 +980,7 @@ func (mockCtrl *controllerFunc) GetGroup(groupID string) (int, map[string]interf
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) DeleteGroup(groupID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) DeleteGroup(ctx context.Context, groupID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "DeleteGroup"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -555,7 +988,7 @@ func (mockCtrl *controllerFunc) DeleteGroup(groupID string) (int, map[string]int
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) GetGroups() (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetGroups(ctx context.Context, query url.Values) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "GetGroups"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -563,7 +996,15 @@ func (mockCtrl *controllerFunc) GetGroups() (int, map[string]interface{}, error)
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) JoinGroup(groupID string, body string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetAvailability(ctx context.Context, groupID string, query url.Values) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "GetAvailability"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) JoinGroup(ctx context.Context, groupID string, body string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "JoinApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -571,7 +1012,7 @@ func (mockCtrl *controllerFunc) JoinGroup(groupID string, body string) (int, map
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) LeaveGroup(groupID string, body string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) LeaveGroup(ctx context.Context, groupID string, body string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "LeaveApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -579,7 +1020,7 @@ func (mockCtrl *controllerFunc) LeaveGroup(groupID string, body string) (int, ma
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) DeployApp(groupID string, body string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) DeployApp(ctx context.Context, groupID string, body string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "DeployApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -587,7 +1028,7 @@ func (mockCtrl *controllerFunc) DeployApp(groupID string, body string) (int, map
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) GetApps(groupID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetApps(ctx context.Context, groupID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "GetApps"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -595,7 +1036,7 @@ func (mockCtrl *controllerFunc) GetApps(groupID string) (int, map[string]interfa
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) GetApp(groupID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) GetApp(ctx context.Context, groupID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "GetApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -603,7 +1044,7 @@ func (mockCtrl *controllerFunc) GetApp(groupID string, appID string) (int, map[s
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) UpdateAppInfo(groupID string, appID string, body string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) UpdateAppInfo(ctx context.Context, groupID string, appID string, body string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "UpdateAppInfo"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -611,7 +1052,7 @@ func (mockCtrl *controllerFunc) UpdateAppInfo(groupID string, appID string, body
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) DeleteApp(groupID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) DeleteApp(ctx context.Context, groupID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "DeleteApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -619,7 +1060,7 @@ func (mockCtrl *controllerFunc) DeleteApp(groupID string, appID string) (int, ma
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) UpdateApp(groupID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) UpdateApp(ctx context.Context, groupID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "UpdateApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -627,7 +1068,15 @@ func (mockCtrl *controllerFunc) UpdateApp(groupID string, appID string) (int, ma
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) StartApp(groupID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) RollbackApp(ctx context.Context, groupID string, appID string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "RollbackApp"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) StartApp(ctx context.Context, groupID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "StartApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
@@ -635,10 +1084,87 @@ func (mockCtrl *controllerFunc) StartApp(groupID string, appID string) (int, map
 	return http.StatusNotFound, nil, nil
 }
 
-func (mockCtrl *controllerFunc) StopApp(groupID string, appID string) (int, map[string]interface{}, error) {
+func (mockCtrl *controllerFunc) StopApp(ctx context.Context, groupID string, appID string) (int, map[string]interface{}, error) {
 	mockCtrl.functionCall = "StopApp"
 	if !mockCtrl.occurredError {
 		return http.StatusOK, nil, nil
 	}
 	return http.StatusNotFound, nil, nil
 }
+
+func (mockCtrl *controllerFunc) DeployAppBySelector(ctx context.Context, selector string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "DeployAppBySelector"
+	mockCtrl.selector = selector
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) DeleteAppBySelector(ctx context.Context, selector string, appID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "DeleteAppBySelector"
+	mockCtrl.selector = selector
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) UpdateAppBySelector(ctx context.Context, selector string, appID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "UpdateAppBySelector"
+	mockCtrl.selector = selector
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) StartAppBySelector(ctx context.Context, selector string, appID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "StartAppBySelector"
+	mockCtrl.selector = selector
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) StopAppBySelector(ctx context.Context, selector string, appID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "StopAppBySelector"
+	mockCtrl.selector = selector
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) SetDesiredState(ctx context.Context, groupID string, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "SetDesiredState"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) GetDesiredState(ctx context.Context, groupID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "GetDesiredState"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) DeleteDesiredState(ctx context.Context, groupID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "DeleteDesiredState"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
+
+func (mockCtrl *controllerFunc) Converge(ctx context.Context, groupID string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "Converge"
+	if !mockCtrl.occurredError {
+		return http.StatusOK, nil, nil
+	}
+	return http.StatusNotFound, nil, nil
+}
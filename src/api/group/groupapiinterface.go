@@ -18,18 +18,13 @@ package group
 
 import "net/http"
 
-var SdamGroupHandle SDAMGroupAPIHandlerInterface
-
 var SdamGroup SDAMGroupAPIInterface
 
-type SDAMGroupAPIHandlerInterface interface {
-	Handle(w http.ResponseWriter, req *http.Request)
-}
-
 type SDAMGroupAPIInterface interface {
 	createGroup(w http.ResponseWriter, req *http.Request)
 	group(w http.ResponseWriter, req *http.Request, groupID string)
 	groups(w http.ResponseWriter, req *http.Request)
+	groupAvailability(w http.ResponseWriter, req *http.Request, groupID string)
 	groupJoin(w http.ResponseWriter, req *http.Request, groupID string)
 	groupLeave(w http.ResponseWriter, req *http.Request, groupID string)
 	groupDeployApp(w http.ResponseWriter, req *http.Request, groupID string)
@@ -40,4 +35,12 @@ type SDAMGroupAPIInterface interface {
 	groupStartApp(w http.ResponseWriter, req *http.Request, groupID string, appID string)
 	groupStopApp(w http.ResponseWriter, req *http.Request, groupID string, appID string)
 	groupUpdateApp(w http.ResponseWriter, req *http.Request, groupID string, appID string)
+	groupRollbackApp(w http.ResponseWriter, req *http.Request, groupID string, appID string)
+	groupDesiredState(w http.ResponseWriter, req *http.Request, groupID string)
+	groupConverge(w http.ResponseWriter, req *http.Request, groupID string)
+	selectorDeployApp(w http.ResponseWriter, req *http.Request)
+	selectorDeleteApp(w http.ResponseWriter, req *http.Request, appID string)
+	selectorStartApp(w http.ResponseWriter, req *http.Request, appID string)
+	selectorStopApp(w http.ResponseWriter, req *http.Request, appID string)
+	selectorUpdateApp(w http.ResponseWriter, req *http.Request, appID string)
 }
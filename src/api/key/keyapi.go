@@ -0,0 +1,109 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package api/key provides functionality to handle request related to API keys of operators.
+package key
+
+import (
+	"api/auth"
+	"api/common"
+	"api/router"
+	"commons/logger"
+	"commons/results"
+	URL "commons/url"
+	"manager/key"
+	"net/http"
+)
+
+const (
+	GET    string = "GET"
+	POST   string = "POST"
+	DELETE string = "DELETE"
+
+	KEY_ID string = "keyID" // name of the path parameter for a key id.
+)
+
+type _SDAMKeyApis struct{}
+
+var sdam _SDAMKeyApis
+var sdamKeyController key.KeyInterface
+
+func init() {
+	SdamKey = sdam
+	sdamKeyController = key.KeyController{}
+}
+
+// Routes returns a list of routes which calls a proper function according to
+// the url and method received from operators.
+// All routes require the admin role which is not limited to any group.
+func Routes() []router.Route {
+	keys := URL.Base() + URL.Admin() + URL.Keys()
+	key := keys + "/{" + KEY_ID + "}"
+	admin := auth.Permit(auth.ADMIN, auth.RequireUnscoped)
+
+	return []router.Route{
+		{Method: GET, Pattern: keys, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamKey.keys(w, req)
+		}},
+		{Method: POST, Pattern: keys, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamKey.createKey(w, req)
+		}},
+		{Method: DELETE, Pattern: key, Middlewares: admin, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			SdamKey.deleteKey(w, req, params[KEY_ID])
+		}},
+	}
+}
+
+// createKey handles requests which is used to issue new API key.
+// The issued key is included in the response only once.
+//
+//    paths: '/api/v1/admin/keys'
+//    method: POST
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMKeyApis) createKey(w http.ResponseWriter, req *http.Request) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[KEY] Create API Key")
+	body, err := common.GetBodyFromReq(req)
+	if err != nil {
+		common.MakeResponse(w, results.ERROR, nil, err)
+		return
+	}
+
+	result, resp, err := sdamKeyController.CreateKey(req.Context(), body)
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// keys handles requests which is used to get information of all API keys issued.
+//
+//    paths: '/api/v1/admin/keys'
+//    method: GET
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMKeyApis) keys(w http.ResponseWriter, req *http.Request) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[KEY] Get All API Keys")
+	result, resp, err := sdamKeyController.GetKeys(req.Context())
+	common.MakeResponse(w, result, common.ChangeToJson(resp), err)
+}
+
+// deleteKey handles requests which is used to revoke API key identified by the given keyID.
+//
+//    paths: '/api/v1/admin/keys/{keyID}'
+//    method: DELETE
+//    responses: if successful, 200 status code will be returned.
+func (sdam _SDAMKeyApis) deleteKey(w http.ResponseWriter, req *http.Request, keyID string) {
+	logger.LoggingContext(req.Context(), logger.DEBUG, "[KEY] Delete API Key")
+	result, err := sdamKeyController.DeleteKey(req.Context(), keyID)
+	common.MakeResponse(w, result, nil, err)
+}
@@ -0,0 +1,192 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package key
+
+import (
+	"api/router"
+	"bytes"
+	"commons/errors"
+	"commons/results"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+//Test functions for API Key API Handler.
+
+type handleFunc struct {
+	functionCall string
+	keyID        string
+}
+
+func newRouter() *router.Router {
+	r := router.New()
+	r.AddRoutes(Routes()...)
+	return r
+}
+
+func TestHandle(t *testing.T) {
+	w := httptest.NewRecorder()
+	mockApis := handleFunc{}
+	defaultApis := SdamKey
+	SdamKey = &mockApis
+	r := newRouter()
+	Input := [][]string{
+		{GET, "/api/v1/admin/keys", "keys"},
+		{POST, "/api/v1/admin/keys", "createKey"},
+		{DELETE, "/api/v1/admin/keys/testKeyID", "deleteKey"},
+	}
+	for _, val := range Input {
+		method, url, funcname := val[0], val[1], val[2]
+		req, _ := http.NewRequest(method, url, nil)
+		r.ServeHTTP(w, req)
+		if mockApis.functionCall != funcname {
+			t.Error("[SDAM][Key]Handle is invalid about " + funcname)
+		}
+	}
+	if mockApis.keyID != "testKeyID" {
+		t.Error("[SDAM][Key]Handle is invalid about path parameters")
+	}
+	SdamKey = defaultApis
+}
+
+func TestHandle_Invalid_Method(t *testing.T) {
+	r := newRouter()
+	Input := map[string][]string{
+		"/api/v1/admin/keys":           {DELETE},
+		"/api/v1/admin/keys/testKeyID": {GET, POST},
+	}
+	for key, vals := range Input {
+		for _, val := range vals {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(val, key, nil)
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Error("[SDAM][Key]Handle is invalid")
+			}
+		}
+	}
+}
+
+//Mock functions for API Key APIs.
+
+func (mockApis *handleFunc) createKey(w http.ResponseWriter, req *http.Request) {
+	mockApis.functionCall = "createKey"
+}
+
+func (mockApis *handleFunc) keys(w http.ResponseWriter, req *http.Request) {
+	mockApis.functionCall = "keys"
+}
+
+func (mockApis *handleFunc) deleteKey(w http.ResponseWriter, req *http.Request, keyID string) {
+	mockApis.functionCall = "deleteKey"
+	mockApis.keyID = keyID
+}
+
+//Test functions for API Key APIs.
+
+type controllerFunc struct {
+	functionCall  string
+	occurredError bool
+}
+
+func TestCreateKey(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/admin/keys", bytes.NewReader([]byte(`{"name":"operator"}`)))
+	sdamKeyController = mockCtrl
+	SdamKey.createKey(w, req)
+	if mockCtrl.functionCall != "CreateKey" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Key]createKey is invalid")
+	}
+}
+
+func TestCreateKey_empty_body(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(POST, "/api/v1/admin/keys", nil)
+	sdamKeyController = mockCtrl
+	SdamKey.createKey(w, req)
+	if mockCtrl.functionCall != "" || w.Code != http.StatusBadRequest {
+		t.Error("[SDAM][Key]createKey is invalid about empty body")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/admin/keys", nil)
+	sdamKeyController = mockCtrl
+	SdamKey.keys(w, req)
+	if mockCtrl.functionCall != "GetKeys" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Key]keys is invalid")
+	}
+}
+
+func TestDeleteKey(t *testing.T) {
+	mockCtrl := &controllerFunc{}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/admin/keys/testKeyID", nil)
+	sdamKeyController = mockCtrl
+	SdamKey.deleteKey(w, req, "testKeyID")
+	if mockCtrl.functionCall != "DeleteKey" || w.Code != http.StatusOK {
+		t.Error("[SDAM][Key]deleteKey is invalid")
+	}
+}
+
+func TestDeleteKey_controller_occurred_error(t *testing.T) {
+	mockCtrl := &controllerFunc{occurredError: true}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(DELETE, "/api/v1/admin/keys/testKeyID", nil)
+	sdamKeyController = mockCtrl
+	SdamKey.deleteKey(w, req, "testKeyID")
+	if mockCtrl.functionCall != "DeleteKey" || w.Code != http.StatusNotFound {
+		t.Error("[SDAM][Key]deleteKey is invalid about controller occurred error")
+	}
+}
+
+//Mock functions for API Key Controller.
+
+func (mockCtrl *controllerFunc) CreateKey(ctx context.Context, body string) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "CreateKey"
+	if mockCtrl.occurredError {
+		return results.ERROR, nil, errors.NotFound{}
+	}
+	return results.OK, map[string]interface{}{"id": "testKeyID", "key": "key"}, nil
+}
+
+func (mockCtrl *controllerFunc) GetKeys(ctx context.Context) (int, map[string]interface{}, error) {
+	mockCtrl.functionCall = "GetKeys"
+	if mockCtrl.occurredError {
+		return results.ERROR, nil, errors.NotFound{}
+	}
+	return results.OK, map[string]interface{}{"keys": nil}, nil
+}
+
+func (mockCtrl *controllerFunc) DeleteKey(ctx context.Context, keyId string) (int, error) {
+	mockCtrl.functionCall = "DeleteKey"
+	if mockCtrl.occurredError {
+		return results.ERROR, errors.NotFound{}
+	}
+	return results.OK, nil
+}
+
+func (mockCtrl *controllerFunc) VerifyKey(ctx context.Context, key string) (map[string]interface{}, error) {
+	mockCtrl.functionCall = "VerifyKey"
+	return nil, nil
+}
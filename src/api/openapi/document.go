@@ -0,0 +1,4312 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package openapi
+
+// document is the OpenAPI 3 document which describes every API under /api/v1.
+// Schemas of request bodies are also used to validate requests.
+const document = `{
+  "openapi": "3.0.0",
+  "info": {
+    "title": "Service Deployment Agent Manager",
+    "version": "1.0.0",
+    "description": "APIs to deploy apps to edge devices running Service Deployment Agent and to manage groups of them."
+  },
+  "security": [
+    {
+      "apiKey": []
+    },
+    {
+      "bearer": []
+    }
+  ],
+  "paths": {
+    "/api/v1/openapi.json": {
+      "get": {
+        "operationId": "getOpenAPI",
+        "summary": "Get this document.",
+        "tags": [
+          "document"
+        ],
+        "security": [],
+        "responses": {
+          "200": {
+            "description": "OpenAPI document.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/v1/agents": {
+      "get": {
+        "operationId": "getAgents",
+        "summary": "Get a list of agents.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/status"
+          },
+          {
+            "$ref": "#/components/parameters/host"
+          },
+          {
+            "$ref": "#/components/parameters/app"
+          },
+          {
+            "$ref": "#/components/parameters/group"
+          },
+          {
+            "$ref": "#/components/parameters/selector"
+          },
+          {
+            "$ref": "#/components/parameters/sort"
+          },
+          {
+            "$ref": "#/components/parameters/limit"
+          },
+          {
+            "$ref": "#/components/parameters/cursor"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A list of agents.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Agents"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/retention": {
+      "post": {
+        "operationId": "applyRetention",
+        "summary": "Apply the retention policy to disconnected agents, or report it with dryrun.",
+        "tags": [
+          "agent"
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Retention"
+        },
+        "responses": {
+          "200": {
+            "description": "Agents which became stale or were deleted.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/RetentionReport"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/register": {
+      "post": {
+        "operationId": "registerAgent",
+        "summary": "Register an agent.",
+        "tags": [
+          "agent"
+        ],
+        "security": [],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Register"
+        },
+        "responses": {
+          "200": {
+            "description": "The id of the agent, with a secret issued to a new agent.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Registration"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}": {
+      "get": {
+        "operationId": "getAgent",
+        "summary": "Get an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The agent.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Agent"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/events": {
+      "get": {
+        "operationId": "getAgentEvents",
+        "summary": "Get events of an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/eventType"
+          },
+          {
+            "$ref": "#/components/parameters/from"
+          },
+          {
+            "$ref": "#/components/parameters/to"
+          },
+          {
+            "$ref": "#/components/parameters/eventSort"
+          },
+          {
+            "$ref": "#/components/parameters/limit"
+          },
+          {
+            "$ref": "#/components/parameters/cursor"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A list of events.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Events"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/availability": {
+      "get": {
+        "operationId": "getAgentAvailability",
+        "summary": "Get availability of an agent in a period.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/from"
+          },
+          {
+            "$ref": "#/components/parameters/to"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The availability of the agent.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Availability"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/labels": {
+      "patch": {
+        "operationId": "updateAgentLabels",
+        "summary": "Change labels of an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Labels"
+        },
+        "responses": {
+          "200": {
+            "description": "The labels of the agent.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/AgentLabels"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/healthcheck": {
+      "put": {
+        "operationId": "updateAgentHealthCheck",
+        "summary": "Change the health check mode of an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/HealthCheck"
+        },
+        "responses": {
+          "200": {
+            "description": "The health check mode of the agent.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/HealthCheck"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/deploy": {
+      "post": {
+        "operationId": "deployAgentApp",
+        "summary": "Deploy an app to an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Deployment"
+        },
+        "responses": {
+          "200": {
+            "description": "The id of the deployed app.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Id"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/decommission": {
+      "post": {
+        "operationId": "decommissionAgent",
+        "summary": "Decommission an agent, optionally stopping and deleting its apps, and remove it from all groups. If a step on the device fails without force, the agent is left and the report is returned with 500.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Decommission"
+        },
+        "responses": {
+          "200": {
+            "description": "All steps succeeded.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/DecommissionReport"
+                }
+              }
+            }
+          },
+          "207": {
+            "description": "The agent was removed by force in spite of failures of the device.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/DecommissionReport"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/retention": {
+      "put": {
+        "operationId": "updateAgentRetention",
+        "summary": "Change whether an agent is exempt from the retention policy.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Exempt"
+        },
+        "responses": {
+          "200": {
+            "description": "Whether the agent is exempt.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Exempt"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/secret": {
+      "post": {
+        "operationId": "resetAgentSecret",
+        "summary": "Issue a new secret to an agent which lost its secret. The previous secret is no longer accepted.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The id of the agent with the new secret.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Registration"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/reconcile": {
+      "post": {
+        "operationId": "reconcileAgent",
+        "summary": "Record the apps which an agent runs. If the agent fails to report its apps, its response is returned.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The apps of the agent and the changes.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Reconciliation"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/unregister": {
+      "post": {
+        "operationId": "unregisterAgent",
+        "summary": "Unregister an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/ping": {
+      "post": {
+        "operationId": "pingAgent",
+        "summary": "Notify that an agent is alive.",
+        "tags": [
+          "agent"
+        ],
+        "security": [],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Ping"
+        },
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/apps": {
+      "get": {
+        "operationId": "getAgentApps",
+        "summary": "Get a list of apps on an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A list of apps.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/apps/{appID}": {
+      "get": {
+        "operationId": "getAgentApp",
+        "summary": "Get an app on an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The app.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "post": {
+        "operationId": "updateAgentAppInfo",
+        "summary": "Update the compose file of an app on an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Deployment"
+        },
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "delete": {
+        "operationId": "deleteAgentApp",
+        "summary": "Delete an app from an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/apps/{appID}/start": {
+      "post": {
+        "operationId": "startAgentApp",
+        "summary": "Start an app on an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/apps/{appID}/stop": {
+      "post": {
+        "operationId": "stopAgentApp",
+        "summary": "Stop an app on an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/apps/{appID}/update": {
+      "post": {
+        "operationId": "updateAgentApp",
+        "summary": "Update the images of an app on an agent.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/{agentID}/apps/{appID}/rollback": {
+      "post": {
+        "operationId": "rollbackAgentApp",
+        "summary": "Apply the descriptor of an earlier revision of an app on an agent again, the previous one unless a revision is given.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agentID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Rollback"
+        },
+        "responses": {
+          "200": {
+            "description": "The new revision and the revision applied again.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Rollback"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/deploy": {
+      "post": {
+        "operationId": "deploySelectedApp",
+        "summary": "Deploy an app to agents matching a label selector.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/requiredSelector"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Deployment"
+        },
+        "responses": {
+          "200": {
+            "description": "The id of the deployed app.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Id"
+                }
+              }
+            }
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/apps/{appID}": {
+      "delete": {
+        "operationId": "deleteSelectedApp",
+        "summary": "Delete an app from agents matching a label selector.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/appID"
+          },
+          {
+            "$ref": "#/components/parameters/requiredSelector"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/apps/{appID}/start": {
+      "post": {
+        "operationId": "startSelectedApp",
+        "summary": "Start an app on agents matching a label selector.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/appID"
+          },
+          {
+            "$ref": "#/components/parameters/requiredSelector"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/apps/{appID}/stop": {
+      "post": {
+        "operationId": "stopSelectedApp",
+        "summary": "Stop an app on agents matching a label selector.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/appID"
+          },
+          {
+            "$ref": "#/components/parameters/requiredSelector"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/agents/apps/{appID}/update": {
+      "post": {
+        "operationId": "updateSelectedApp",
+        "summary": "Update the images of an app on agents matching a label selector.",
+        "tags": [
+          "agent"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/appID"
+          },
+          {
+            "$ref": "#/components/parameters/requiredSelector"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups": {
+      "get": {
+        "operationId": "getGroups",
+        "summary": "Get a list of groups.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/agent"
+          },
+          {
+            "$ref": "#/components/parameters/sort"
+          },
+          {
+            "$ref": "#/components/parameters/limit"
+          },
+          {
+            "$ref": "#/components/parameters/cursor"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A list of groups.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Groups"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/create": {
+      "post": {
+        "operationId": "createGroup",
+        "summary": "Create a group.",
+        "tags": [
+          "group"
+        ],
+        "responses": {
+          "200": {
+            "description": "The id of the group.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Id"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}": {
+      "get": {
+        "operationId": "getGroup",
+        "summary": "Get a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The group.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Group"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "delete": {
+        "operationId": "deleteGroup",
+        "summary": "Delete a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/availability": {
+      "get": {
+        "operationId": "getGroupAvailability",
+        "summary": "Get availability of members of a group in a period.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          },
+          {
+            "$ref": "#/components/parameters/from"
+          },
+          {
+            "$ref": "#/components/parameters/to"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The availability of all members taken together and of each member.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/GroupAvailability"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/deploy": {
+      "post": {
+        "operationId": "deployGroupApp",
+        "summary": "Deploy an app to all members of a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Deployment"
+        },
+        "responses": {
+          "200": {
+            "description": "The id of the deployed app.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Id"
+                }
+              }
+            }
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/join": {
+      "post": {
+        "operationId": "joinGroup",
+        "summary": "Add agents to a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Members"
+        },
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/leave": {
+      "post": {
+        "operationId": "leaveGroup",
+        "summary": "Remove agents from a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Members"
+        },
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/apps": {
+      "get": {
+        "operationId": "getGroupApps",
+        "summary": "Get a list of apps on members of a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "A list of apps.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/GroupApps"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/apps/{appID}": {
+      "get": {
+        "operationId": "getGroupApp",
+        "summary": "Get an app on members of a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The app on each member.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "post": {
+        "operationId": "updateGroupAppInfo",
+        "summary": "Update the compose file of an app on members of a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Deployment"
+        },
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "delete": {
+        "operationId": "deleteGroupApp",
+        "summary": "Delete an app from members of a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/apps/{appID}/start": {
+      "post": {
+        "operationId": "startGroupApp",
+        "summary": "Start an app on members of a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/apps/{appID}/stop": {
+      "post": {
+        "operationId": "stopGroupApp",
+        "summary": "Stop an app on members of a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/apps/{appID}/update": {
+      "post": {
+        "operationId": "updateGroupApp",
+        "summary": "Update the images of an app on members of a group.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Responses"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/apps/{appID}/rollback": {
+      "post": {
+        "operationId": "rollbackGroupApp",
+        "summary": "Apply the descriptor of an earlier revision of an app on members of a group again, the previous one of each member unless a revision is given.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          },
+          {
+            "$ref": "#/components/parameters/appID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Rollback"
+        },
+        "responses": {
+          "200": {
+            "description": "The result of each member.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/RollbackReport"
+                }
+              }
+            }
+          },
+          "207": {
+            "description": "Some of the members failed.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/RollbackReport"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/desired": {
+      "get": {
+        "operationId": "getGroupDesiredState",
+        "summary": "Get the desired state of a group with the status of the last convergence of each member.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The desired state and the convergence of each member.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/DesiredStateReport"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "put": {
+        "operationId": "setGroupDesiredState",
+        "summary": "Declare the apps which members of a group should run, and start converging the members.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/DesiredState"
+        },
+        "responses": {
+          "200": {
+            "description": "The desired state.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/DesiredState"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "delete": {
+        "operationId": "deleteGroupDesiredState",
+        "summary": "Remove the desired state of a group. Apps on the members are left as they are.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/groups/{groupID}/converge": {
+      "post": {
+        "operationId": "convergeGroup",
+        "summary": "Converge members of a group toward its desired state right away.",
+        "tags": [
+          "group"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/groupID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The result of the convergence of each member.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/DesiredStateReport"
+                }
+              }
+            }
+          },
+          "207": {
+            "description": "The convergence failed on some members.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/DesiredStateReport"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/admin/keys": {
+      "get": {
+        "operationId": "getKeys",
+        "summary": "Get a list of API keys.",
+        "tags": [
+          "key"
+        ],
+        "responses": {
+          "200": {
+            "description": "A list of API keys.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Keys"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "post": {
+        "operationId": "createKey",
+        "summary": "Issue an API key.",
+        "tags": [
+          "key"
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/Key"
+        },
+        "responses": {
+          "200": {
+            "description": "The issued key. The key is shown only once.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/IssuedKey"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/admin/keys/{keyID}": {
+      "delete": {
+        "operationId": "deleteKey",
+        "summary": "Revoke an API key.",
+        "tags": [
+          "key"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/keyID"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/catalog/apps": {
+      "get": {
+        "operationId": "getCatalogApps",
+        "summary": "Get a list of apps in the catalog.",
+        "tags": [
+          "catalog"
+        ],
+        "responses": {
+          "200": {
+            "description": "A list of catalog apps.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/CatalogApps"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "post": {
+        "operationId": "createCatalogApp",
+        "summary": "Add an app to the catalog.",
+        "tags": [
+          "catalog"
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/CatalogApp"
+        },
+        "responses": {
+          "200": {
+            "description": "The catalog app.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/CatalogApp"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/catalog/apps/{appName}": {
+      "get": {
+        "operationId": "getCatalogApp",
+        "summary": "Get an app in the catalog with its versions.",
+        "tags": [
+          "catalog"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/appName"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The catalog app.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/CatalogApp"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      },
+      "delete": {
+        "operationId": "deleteCatalogApp",
+        "summary": "Delete an app and all its versions from the catalog.",
+        "tags": [
+          "catalog"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/appName"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success."
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/catalog/apps/{appName}/versions": {
+      "post": {
+        "operationId": "addCatalogVersion",
+        "summary": "Add a version of the descriptor to an app in the catalog.",
+        "tags": [
+          "catalog"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/appName"
+          }
+        ],
+        "requestBody": {
+          "$ref": "#/components/requestBodies/CatalogVersion"
+        },
+        "responses": {
+          "200": {
+            "description": "The version without the descriptor.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/CatalogVersion"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    },
+    "/api/v1/catalog/apps/{appName}/versions/{version}": {
+      "get": {
+        "operationId": "getCatalogVersion",
+        "summary": "Get a version of an app in the catalog including the descriptor.",
+        "tags": [
+          "catalog"
+        ],
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/appName"
+          },
+          {
+            "$ref": "#/components/parameters/version"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The version.",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/CatalogVersion"
+                }
+              }
+            }
+          },
+          "400": {
+            "$ref": "#/components/responses/BadRequest"
+          },
+          "401": {
+            "$ref": "#/components/responses/Unauthorized"
+          },
+          "403": {
+            "$ref": "#/components/responses/Forbidden"
+          },
+          "404": {
+            "$ref": "#/components/responses/NotFound"
+          },
+          "500": {
+            "$ref": "#/components/responses/InternalServerError"
+          },
+          "503": {
+            "$ref": "#/components/responses/ServiceUnavailable"
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "required": [
+          "message",
+          "code"
+        ],
+        "properties": {
+          "message": {
+            "type": "string"
+          },
+          "code": {
+            "type": "string",
+            "description": "Stable code of the error.",
+            "enum": [
+              "not_found_url",
+              "invalid_method",
+              "unauthorized",
+              "forbidden",
+              "invalid_param",
+              "invalid_json",
+              "invalid_object_id",
+              "not_found",
+              "db_connection_error",
+              "db_operation_error",
+              "io_error",
+              "internal_server_error"
+            ]
+          },
+          "kind": {
+            "type": "string",
+            "description": "Kind of the resource which caused the error, e.g. agent, group, app, key and catalog."
+          },
+          "id": {
+            "type": "string",
+            "description": "Id of the resource which caused the error."
+          },
+          "cause": {
+            "type": "string",
+            "description": "Cause of the error."
+          },
+          "requestId": {
+            "type": "string",
+            "description": "Id of the request."
+          }
+        }
+      },
+      "Id": {
+        "type": "object",
+        "required": [
+          "id"
+        ],
+        "properties": {
+          "id": {
+            "type": "string"
+          }
+        }
+      },
+      "Registration": {
+        "type": "object",
+        "required": [
+          "id"
+        ],
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "secret": {
+            "type": "string",
+            "description": "Secret issued to the agent, which is returned only once."
+          }
+        }
+      },
+      "Agent": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "host": {
+            "type": "string"
+          },
+          "port": {
+            "type": "string"
+          },
+          "apps": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "status": {
+            "type": "string",
+            "enum": [
+              "connected",
+              "disconnected",
+              "stale"
+            ]
+          },
+          "lastseen": {
+            "type": "integer",
+            "description": "Time of the last ping in seconds since the epoch, or 0 if no ping has been received."
+          },
+          "interval": {
+            "type": "integer",
+            "description": "Interval of pings given by the last ping in seconds."
+          },
+          "metadata": {
+            "type": "object",
+            "description": "Metadata of the device which an agent runs on.",
+            "properties": {
+              "hostname": {
+                "type": "string"
+              },
+              "os": {
+                "type": "string"
+              },
+              "arch": {
+                "type": "string"
+              },
+              "kernel": {
+                "type": "string"
+              },
+              "dockerversion": {
+                "type": "string",
+                "description": "Version of Docker engine."
+              },
+              "sdaversion": {
+                "type": "string",
+                "description": "Version of Service Deployment Agent."
+              },
+              "freecpu": {
+                "type": "number",
+                "minimum": 0,
+                "maximum": 100,
+                "description": "Percentage of idle CPU."
+              },
+              "freememory": {
+                "type": "integer",
+                "minimum": 0,
+                "description": "Free memory in bytes."
+              },
+              "freedisk": {
+                "type": "integer",
+                "minimum": 0,
+                "description": "Free disk space in bytes."
+              }
+            }
+          },
+          "labels": {
+            "type": "object",
+            "description": "Labels of an agent. Keys and values consist of at most 63 alphanumerics, '-' and '_', and values may also include '.'.",
+            "additionalProperties": {
+              "type": "string"
+            }
+          },
+          "deviceid": {
+            "type": "string",
+            "description": "Stable identity of the device declared by the agent."
+          },
+          "addresses": {
+            "type": "array",
+            "description": "Recent changes of the address of the agent, oldest first.",
+            "items": {
+              "type": "object",
+              "properties": {
+                "host": {
+                  "type": "string"
+                },
+                "port": {
+                  "type": "string"
+                },
+                "changedat": {
+                  "type": "integer",
+                  "description": "Time of the change in seconds since the epoch."
+                }
+              }
+            }
+          },
+          "healthcheck": {
+            "type": "string",
+            "description": "Health check mode of the agent. If it is empty, health.mode of the configuration is used."
+          },
+          "exempt": {
+            "type": "boolean",
+            "description": "Whether the agent is exempt from the retention policy."
+          },
+          "catalog": {
+            "type": "object",
+            "description": "Catalog versions of apps deployed from the catalog, keyed by app id.",
+            "additionalProperties": {
+              "$ref": "#/components/schemas/CatalogReference"
+            }
+          }
+        }
+      },
+      "AgentLabels": {
+        "type": "object",
+        "properties": {
+          "labels": {
+            "type": "object",
+            "description": "Labels of an agent. Keys and values consist of at most 63 alphanumerics, '-' and '_', and values may also include '.'.",
+            "additionalProperties": {
+              "type": "string"
+            }
+          }
+        }
+      },
+      "Agents": {
+        "type": "object",
+        "properties": {
+          "agents": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/Agent"
+            }
+          },
+          "next": {
+            "type": "string",
+            "description": "Cursor of the next page."
+          }
+        }
+      },
+      "Event": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "agentid": {
+            "type": "string"
+          },
+          "type": {
+            "type": "string",
+            "enum": [
+              "registered",
+              "unregistered",
+              "connected",
+              "disconnected",
+              "address",
+              "appfound",
+              "applost",
+              "stale"
+            ]
+          },
+          "time": {
+            "type": "integer",
+            "description": "Time of the event in seconds since the epoch."
+          },
+          "detail": {
+            "type": "string",
+            "description": "Address of a registration, the old and the new address of an address change, the cause of a change of the status, e.g. probe, decommission or retention, or the id of an app found or lost."
+          }
+        }
+      },
+      "Events": {
+        "type": "object",
+        "properties": {
+          "events": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/Event"
+            }
+          },
+          "next": {
+            "type": "string",
+            "description": "Cursor of the next page."
+          }
+        }
+      },
+      "Availability": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "from": {
+            "type": "integer"
+          },
+          "to": {
+            "type": "integer"
+          },
+          "observed": {
+            "type": "integer",
+            "description": "Seconds in which agents were registered."
+          },
+          "connected": {
+            "type": "integer",
+            "description": "Seconds in which agents were connected."
+          },
+          "uptime": {
+            "type": "number",
+            "description": "Percentage of connected time to observed time."
+          },
+          "disconnects": {
+            "type": "integer",
+            "description": "Number of disconnects."
+          },
+          "mtbd": {
+            "type": "integer",
+            "description": "Mean time between disconnects in seconds. Not given if there was no disconnect."
+          }
+        }
+      },
+      "GroupAvailability": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "from": {
+            "type": "integer"
+          },
+          "to": {
+            "type": "integer"
+          },
+          "observed": {
+            "type": "integer",
+            "description": "Seconds in which agents were registered."
+          },
+          "connected": {
+            "type": "integer",
+            "description": "Seconds in which agents were connected."
+          },
+          "uptime": {
+            "type": "number",
+            "description": "Percentage of connected time to observed time."
+          },
+          "disconnects": {
+            "type": "integer",
+            "description": "Number of disconnects."
+          },
+          "mtbd": {
+            "type": "integer",
+            "description": "Mean time between disconnects in seconds. Not given if there was no disconnect."
+          },
+          "members": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "id": {
+                  "type": "string"
+                },
+                "observed": {
+                  "type": "integer",
+                  "description": "Seconds in which agents were registered."
+                },
+                "connected": {
+                  "type": "integer",
+                  "description": "Seconds in which agents were connected."
+                },
+                "uptime": {
+                  "type": "number",
+                  "description": "Percentage of connected time to observed time."
+                },
+                "disconnects": {
+                  "type": "integer",
+                  "description": "Number of disconnects."
+                },
+                "mtbd": {
+                  "type": "integer",
+                  "description": "Mean time between disconnects in seconds. Not given if there was no disconnect."
+                }
+              }
+            }
+          }
+        }
+      },
+      "Group": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "members": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          }
+        }
+      },
+      "Groups": {
+        "type": "object",
+        "properties": {
+          "groups": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/Group"
+            }
+          },
+          "next": {
+            "type": "string",
+            "description": "Cursor of the next page."
+          }
+        }
+      },
+      "GroupApps": {
+        "type": "object",
+        "properties": {
+          "apps": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "id": {
+                  "type": "string"
+                },
+                "members": {
+                  "type": "array",
+                  "items": {
+                    "type": "string"
+                  }
+                }
+              }
+            }
+          }
+        }
+      },
+      "Responses": {
+        "type": "object",
+        "properties": {
+          "responses": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "id": {
+                  "type": "string"
+                },
+                "code": {
+                  "type": "integer"
+                },
+                "message": {
+                  "type": "string"
+                }
+              }
+            }
+          }
+        }
+      },
+      "RegisterRequest": {
+        "type": "object",
+        "required": [
+          "ip"
+        ],
+        "properties": {
+          "ip": {
+            "type": "string",
+            "minLength": 1
+          },
+          "port": {
+            "type": "string",
+            "pattern": "^[0-9]+$",
+            "description": "Port on which the agent listens."
+          },
+          "deviceid": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Stable identity of the device, used to recognize the agent when its address changes."
+          },
+          "metadata": {
+            "type": "object",
+            "description": "Metadata of the device which an agent runs on.",
+            "properties": {
+              "hostname": {
+                "type": "string"
+              },
+              "os": {
+                "type": "string"
+              },
+              "arch": {
+                "type": "string"
+              },
+              "kernel": {
+                "type": "string"
+              },
+              "dockerversion": {
+                "type": "string",
+                "description": "Version of Docker engine."
+              },
+              "sdaversion": {
+                "type": "string",
+                "description": "Version of Service Deployment Agent."
+              },
+              "freecpu": {
+                "type": "number",
+                "minimum": 0,
+                "maximum": 100,
+                "description": "Percentage of idle CPU."
+              },
+              "freememory": {
+                "type": "integer",
+                "minimum": 0,
+                "description": "Free memory in bytes."
+              },
+              "freedisk": {
+                "type": "integer",
+                "minimum": 0,
+                "description": "Free disk space in bytes."
+              }
+            }
+          },
+          "labels": {
+            "type": "object",
+            "description": "Labels of an agent. Keys and values consist of at most 63 alphanumerics, '-' and '_', and values may also include '.'.",
+            "additionalProperties": {
+              "type": "string"
+            }
+          },
+          "healthcheck": {
+            "type": "string",
+            "enum": [
+              "push",
+              "pull",
+              "both"
+            ],
+            "description": "How the liveness of the agent is decided: by its pings (push), by health probes of the manager (pull), or by either of them (both)."
+          },
+          "secret": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Secret issued to the agent when it was registered. It is required to register again and to change the address of the agent."
+          }
+        }
+      },
+      "DecommissionRequest": {
+        "type": "object",
+        "properties": {
+          "apps": {
+            "type": "boolean",
+            "description": "Stop and delete all apps on the agent first."
+          },
+          "force": {
+            "type": "boolean",
+            "description": "Remove the agent from all groups and the database even if its device fails or is offline."
+          }
+        }
+      },
+      "DecommissionReport": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "force": {
+            "type": "boolean"
+          },
+          "decommissioned": {
+            "type": "boolean",
+            "description": "Whether the agent was removed."
+          },
+          "steps": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "step": {
+                  "type": "string",
+                  "enum": [
+                    "stopapp",
+                    "deleteapp",
+                    "unregister",
+                    "leavegroup",
+                    "clearhealth",
+                    "deleteagent"
+                  ]
+                },
+                "target": {
+                  "type": "string",
+                  "description": "Id of the app or the group of the step."
+                },
+                "result": {
+                  "type": "string",
+                  "enum": [
+                    "ok",
+                    "failed",
+                    "skipped"
+                  ]
+                },
+                "code": {
+                  "type": "integer",
+                  "description": "Response code of the device."
+                },
+                "message": {
+                  "type": "string"
+                }
+              }
+            }
+          }
+        }
+      },
+      "Reconciliation": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "apps": {
+            "type": "array",
+            "description": "Apps which the agent runs.",
+            "items": {
+              "type": "string"
+            }
+          },
+          "found": {
+            "type": "array",
+            "description": "Apps which were not recorded.",
+            "items": {
+              "type": "string"
+            }
+          },
+          "lost": {
+            "type": "array",
+            "description": "Recorded apps which the agent does not run.",
+            "items": {
+              "type": "string"
+            }
+          }
+        }
+      },
+      "Exempt": {
+        "type": "object",
+        "required": [
+          "exempt"
+        ],
+        "properties": {
+          "exempt": {
+            "type": "boolean"
+          }
+        }
+      },
+      "RetentionRequest": {
+        "type": "object",
+        "properties": {
+          "dryrun": {
+            "type": "boolean",
+            "description": "Report agents without changing them."
+          }
+        }
+      },
+      "RetentionReport": {
+        "type": "object",
+        "properties": {
+          "dryrun": {
+            "type": "boolean"
+          },
+          "stale": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "deleted": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          }
+        }
+      },
+      "HealthCheck": {
+        "type": "object",
+        "required": [
+          "healthcheck"
+        ],
+        "properties": {
+          "healthcheck": {
+            "type": "string",
+            "enum": [
+              "push",
+              "pull",
+              "both"
+            ],
+            "description": "How the liveness of the agent is decided: by its pings (push), by health probes of the manager (pull), or by either of them (both)."
+          }
+        }
+      },
+      "LabelsRequest": {
+        "type": "object",
+        "description": "Labels to set. A label whose value is null is removed, and the others are left as they are.",
+        "additionalProperties": {
+          "type": "string",
+          "nullable": true
+        }
+      },
+      "PingRequest": {
+        "type": "object",
+        "required": [
+          "interval"
+        ],
+        "properties": {
+          "interval": {
+            "type": "string",
+            "pattern": "^[0-9]+$",
+            "description": "Interval of pings in the unit."
+          },
+          "unit": {
+            "type": "string",
+            "enum": [
+              "min",
+              "sec"
+            ],
+            "description": "Unit of the interval. The default is min."
+          },
+          "port": {
+            "type": "string",
+            "pattern": "^[0-9]+$",
+            "description": "Port on which the agent listens."
+          },
+          "metadata": {
+            "type": "object",
+            "description": "Metadata of the device which an agent runs on.",
+            "properties": {
+              "hostname": {
+                "type": "string"
+              },
+              "os": {
+                "type": "string"
+              },
+              "arch": {
+                "type": "string"
+              },
+              "kernel": {
+                "type": "string"
+              },
+              "dockerversion": {
+                "type": "string",
+                "description": "Version of Docker engine."
+              },
+              "sdaversion": {
+                "type": "string",
+                "description": "Version of Service Deployment Agent."
+              },
+              "freecpu": {
+                "type": "number",
+                "minimum": 0,
+                "maximum": 100,
+                "description": "Percentage of idle CPU."
+              },
+              "freememory": {
+                "type": "integer",
+                "minimum": 0,
+                "description": "Free memory in bytes."
+              },
+              "freedisk": {
+                "type": "integer",
+                "minimum": 0,
+                "description": "Free disk space in bytes."
+              }
+            }
+          },
+          "secret": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Secret issued to the agent when it was registered. It is required to register again and to change the address of the agent."
+          }
+        }
+      },
+      "MembersRequest": {
+        "type": "object",
+        "required": [
+          "agents"
+        ],
+        "properties": {
+          "agents": {
+            "type": "array",
+            "minItems": 1,
+            "items": {
+              "type": "string",
+              "pattern": "^[0-9a-f]{24}$"
+            }
+          }
+        }
+      },
+      "ComposeFile": {
+        "type": "object",
+        "description": "Docker compose file.",
+        "required": [
+          "services"
+        ],
+        "properties": {
+          "version": {
+            "description": "Version of the compose file format."
+          },
+          "services": {
+            "type": "object",
+            "minProperties": 1
+          }
+        }
+      },
+      "CatalogReference": {
+        "type": "object",
+        "description": "Reference to a version of an app in the catalog.",
+        "required": [
+          "app",
+          "version"
+        ],
+        "properties": {
+          "app": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Name of the catalog app."
+          },
+          "version": {
+            "type": "string",
+            "minLength": 1
+          }
+        }
+      },
+      "RollbackRequest": {
+        "type": "object",
+        "properties": {
+          "revision": {
+            "type": "integer",
+            "minimum": 1,
+            "description": "Number of the revision to apply again. If it is not given, the previous revision is applied."
+          }
+        }
+      },
+      "Rollback": {
+        "type": "object",
+        "properties": {
+          "revision": {
+            "type": "integer",
+            "description": "Number of the new revision which records the rollback."
+          },
+          "target": {
+            "type": "integer",
+            "description": "Number of the revision applied again."
+          }
+        }
+      },
+      "RollbackReport": {
+        "type": "object",
+        "properties": {
+          "responses": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "id": {
+                  "type": "string"
+                },
+                "code": {
+                  "type": "integer"
+                },
+                "message": {
+                  "type": "string"
+                },
+                "revision": {
+                  "type": "integer",
+                  "description": "Number of the new revision if the rollback succeeded."
+                },
+                "target": {
+                  "type": "integer",
+                  "description": "Number of the revision applied again, if the member has it."
+                }
+              }
+            }
+          }
+        }
+      },
+      "DesiredApp": {
+        "type": "object",
+        "description": "App which members of a group should run.",
+        "required": [
+          "app",
+          "version"
+        ],
+        "properties": {
+          "app": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Name of the catalog app."
+          },
+          "version": {
+            "type": "string",
+            "minLength": 1
+          },
+          "state": {
+            "type": "string",
+            "enum": [
+              "running",
+              "stopped"
+            ],
+            "description": "Whether the app should be running or stopped. The default is running."
+          }
+        }
+      },
+      "DesiredState": {
+        "type": "object",
+        "required": [
+          "apps"
+        ],
+        "properties": {
+          "apps": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/DesiredApp"
+            }
+          },
+          "prune": {
+            "type": "boolean",
+            "description": "Whether apps which are not declared are deleted from the members."
+          }
+        }
+      },
+      "Convergence": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "status": {
+            "type": "string",
+            "enum": [
+              "converged",
+              "failed",
+              "unreachable",
+              "pending"
+            ]
+          },
+          "actions": {
+            "type": "array",
+            "description": "Actions taken on the member.",
+            "items": {
+              "type": "string"
+            }
+          },
+          "failures": {
+            "type": "array",
+            "description": "Actions which failed on the member, with the reason.",
+            "items": {
+              "type": "string"
+            }
+          },
+          "time": {
+            "type": "integer",
+            "description": "Time of the convergence in seconds since the epoch."
+          }
+        }
+      },
+      "DesiredStateReport": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "apps": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/DesiredApp"
+            }
+          },
+          "prune": {
+            "type": "boolean"
+          },
+          "members": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/Convergence"
+            }
+          }
+        }
+      },
+      "DeploymentVariables": {
+        "type": "object",
+        "description": "Variables of descriptor templates keyed by agent id. Each value is a map of variable names to values which override fields and labels of the agent.",
+        "additionalProperties": {
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        }
+      },
+      "CatalogDeployment": {
+        "type": "object",
+        "description": "Reference to a version of an app in the catalog with variables of each agent.",
+        "required": [
+          "app",
+          "version"
+        ],
+        "properties": {
+          "app": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Name of the catalog app."
+          },
+          "version": {
+            "type": "string",
+            "minLength": 1
+          },
+          "variables": {
+            "$ref": "#/components/schemas/DeploymentVariables"
+          }
+        }
+      },
+      "TemplatedDescriptor": {
+        "type": "object",
+        "description": "Docker compose file with variables of each agent.",
+        "required": [
+          "descriptor"
+        ],
+        "properties": {
+          "descriptor": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Docker compose file which may have placeholders such as {{ agent.host }}, {{ labels.site }} or {{ station }}."
+          },
+          "variables": {
+            "$ref": "#/components/schemas/DeploymentVariables"
+          }
+        }
+      },
+      "Deployment": {
+        "description": "Docker compose file, or a reference to a version of an app in the catalog whose descriptor is deployed. Placeholders of variables in the descriptor are rendered for each agent.",
+        "anyOf": [
+          {
+            "$ref": "#/components/schemas/ComposeFile"
+          },
+          {
+            "$ref": "#/components/schemas/CatalogDeployment"
+          },
+          {
+            "$ref": "#/components/schemas/TemplatedDescriptor"
+          }
+        ]
+      },
+      "CatalogApp": {
+        "type": "object",
+        "properties": {
+          "name": {
+            "type": "string"
+          },
+          "description": {
+            "type": "string"
+          },
+          "versions": {
+            "type": "array",
+            "description": "Versions of the app, oldest first. Descriptors are not included.",
+            "items": {
+              "type": "object",
+              "properties": {
+                "version": {
+                  "type": "string"
+                },
+                "description": {
+                  "type": "string"
+                },
+                "checksum": {
+                  "type": "string",
+                  "description": "SHA-256 checksum of the descriptor in hex."
+                },
+                "createdat": {
+                  "type": "integer",
+                  "description": "Time when the version was added in seconds since the epoch."
+                }
+              }
+            }
+          }
+        }
+      },
+      "CatalogApps": {
+        "type": "object",
+        "properties": {
+          "apps": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/CatalogApp"
+            }
+          }
+        }
+      },
+      "CatalogVersion": {
+        "type": "object",
+        "properties": {
+          "name": {
+            "type": "string"
+          },
+          "version": {
+            "type": "string"
+          },
+          "description": {
+            "type": "string"
+          },
+          "checksum": {
+            "type": "string",
+            "description": "SHA-256 checksum of the descriptor in hex."
+          },
+          "createdat": {
+            "type": "integer",
+            "description": "Time when the version was added in seconds since the epoch."
+          },
+          "descriptor": {
+            "type": "string",
+            "description": "Docker compose file of the version."
+          }
+        }
+      },
+      "CatalogAppRequest": {
+        "type": "object",
+        "required": [
+          "name"
+        ],
+        "properties": {
+          "name": {
+            "type": "string",
+            "pattern": "^[a-z0-9][a-z0-9._-]{0,62}$"
+          },
+          "description": {
+            "type": "string"
+          }
+        }
+      },
+      "CatalogVersionRequest": {
+        "type": "object",
+        "required": [
+          "version",
+          "descriptor"
+        ],
+        "properties": {
+          "version": {
+            "type": "string",
+            "pattern": "^[A-Za-z0-9][A-Za-z0-9._+-]{0,62}$"
+          },
+          "description": {
+            "type": "string"
+          },
+          "descriptor": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Docker compose file of the version."
+          }
+        }
+      },
+      "KeyRequest": {
+        "type": "object",
+        "required": [
+          "name",
+          "role"
+        ],
+        "properties": {
+          "name": {
+            "type": "string",
+            "minLength": 1
+          },
+          "role": {
+            "type": "string",
+            "enum": [
+              "viewer",
+              "operator",
+              "admin"
+            ]
+          },
+          "groups": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "pattern": "^[0-9a-f]{24}$"
+            }
+          }
+        }
+      },
+      "Key": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "name": {
+            "type": "string"
+          },
+          "role": {
+            "type": "string"
+          },
+          "groups": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          }
+        }
+      },
+      "Keys": {
+        "type": "object",
+        "properties": {
+          "keys": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/Key"
+            }
+          }
+        }
+      },
+      "IssuedKey": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "name": {
+            "type": "string"
+          },
+          "role": {
+            "type": "string"
+          },
+          "groups": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "key": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "requestBodies": {
+      "Register": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/RegisterRequest"
+            }
+          }
+        }
+      },
+      "Ping": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/PingRequest"
+            }
+          }
+        }
+      },
+      "Members": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/MembersRequest"
+            }
+          }
+        }
+      },
+      "Deployment": {
+        "required": true,
+        "content": {
+          "application/yaml": {
+            "schema": {
+              "$ref": "#/components/schemas/Deployment"
+            }
+          }
+        }
+      },
+      "Labels": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/LabelsRequest"
+            }
+          }
+        }
+      },
+      "HealthCheck": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/HealthCheck"
+            }
+          }
+        }
+      },
+      "Decommission": {
+        "required": false,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/DecommissionRequest"
+            }
+          }
+        }
+      },
+      "Exempt": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Exempt"
+            }
+          }
+        }
+      },
+      "Retention": {
+        "required": false,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/RetentionRequest"
+            }
+          }
+        }
+      },
+      "Key": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/KeyRequest"
+            }
+          }
+        }
+      },
+      "CatalogApp": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/CatalogAppRequest"
+            }
+          }
+        }
+      },
+      "CatalogVersion": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/CatalogVersionRequest"
+            }
+          }
+        }
+      },
+      "Rollback": {
+        "required": false,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/RollbackRequest"
+            }
+          }
+        }
+      },
+      "DesiredState": {
+        "required": true,
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/DesiredState"
+            }
+          }
+        }
+      }
+    },
+    "parameters": {
+      "agentID": {
+        "name": "agentID",
+        "in": "path",
+        "required": true,
+        "description": "Id of an agent.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "groupID": {
+        "name": "groupID",
+        "in": "path",
+        "required": true,
+        "description": "Id of a group.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "appID": {
+        "name": "appID",
+        "in": "path",
+        "required": true,
+        "description": "Id of an app.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "keyID": {
+        "name": "keyID",
+        "in": "path",
+        "required": true,
+        "description": "Id of an API key.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "appName": {
+        "name": "appName",
+        "in": "path",
+        "required": true,
+        "description": "Name of an app in the catalog.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "version": {
+        "name": "version",
+        "in": "path",
+        "required": true,
+        "description": "Version of an app in the catalog.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "status": {
+        "name": "status",
+        "in": "query",
+        "required": false,
+        "description": "Status of agents.",
+        "schema": {
+          "type": "string",
+          "enum": [
+            "connected",
+            "disconnected",
+            "stale"
+          ]
+        }
+      },
+      "host": {
+        "name": "host",
+        "in": "query",
+        "required": false,
+        "description": "Glob pattern of agent addresses, e.g. 10.0.*.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "app": {
+        "name": "app",
+        "in": "query",
+        "required": false,
+        "description": "Id of an app installed in agents.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "group": {
+        "name": "group",
+        "in": "query",
+        "required": false,
+        "description": "Id of a group including agents.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "selector": {
+        "name": "selector",
+        "in": "query",
+        "required": false,
+        "description": "Comma separated labels which agents should have, e.g. site=plant3,arch=arm64.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "requiredSelector": {
+        "name": "selector",
+        "in": "query",
+        "required": true,
+        "description": "Comma separated labels which target agents should have, e.g. site=plant3,arch=arm64.",
+        "schema": {
+          "type": "string",
+          "minLength": 1
+        }
+      },
+      "agent": {
+        "name": "agent",
+        "in": "query",
+        "required": false,
+        "description": "Id of an agent included in groups.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "eventType": {
+        "name": "type",
+        "in": "query",
+        "required": false,
+        "description": "Comma separated types of events.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "from": {
+        "name": "from",
+        "in": "query",
+        "required": false,
+        "description": "Start of a period in seconds since the epoch. For availability, 7 days before the end by default.",
+        "schema": {
+          "type": "integer",
+          "minimum": 0
+        }
+      },
+      "to": {
+        "name": "to",
+        "in": "query",
+        "required": false,
+        "description": "End of a period in seconds since the epoch. For availability, the current time by default.",
+        "schema": {
+          "type": "integer",
+          "minimum": 0
+        }
+      },
+      "eventSort": {
+        "name": "sort",
+        "in": "query",
+        "required": false,
+        "description": "id, the order in which events are recorded, or -id for the reverse order.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "sort": {
+        "name": "sort",
+        "in": "query",
+        "required": false,
+        "description": "Field to sort by, id by default. A leading '-' means descending order. Agents can also be sorted by host and status.",
+        "schema": {
+          "type": "string"
+        }
+      },
+      "limit": {
+        "name": "limit",
+        "in": "query",
+        "required": false,
+        "description": "Maximum number of items in a page. 0 means no limit.",
+        "schema": {
+          "type": "integer",
+          "minimum": 0
+        }
+      },
+      "cursor": {
+        "name": "cursor",
+        "in": "query",
+        "required": false,
+        "description": "The next cursor returned with the previous page.",
+        "schema": {
+          "type": "string"
+        }
+      }
+    },
+    "responses": {
+      "BadRequest": {
+        "description": "Invalid parameters.",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            }
+          }
+        }
+      },
+      "Unauthorized": {
+        "description": "Credentials are missing or invalid.",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            }
+          }
+        }
+      },
+      "Forbidden": {
+        "description": "The caller is not allowed to do the request.",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            }
+          }
+        }
+      },
+      "NotFound": {
+        "description": "The target is not found.",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            }
+          }
+        }
+      },
+      "InternalServerError": {
+        "description": "Internal server error.",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            }
+          }
+        }
+      },
+      "ServiceUnavailable": {
+        "description": "Database is not available.",
+        "content": {
+          "application/json": {
+            "schema": {
+              "$ref": "#/components/schemas/Error"
+            }
+          }
+        }
+      }
+    },
+    "securitySchemes": {
+      "apiKey": {
+        "type": "apiKey",
+        "in": "header",
+        "name": "X-API-Key"
+      },
+      "bearer": {
+        "type": "http",
+        "scheme": "bearer",
+        "bearerFormat": "JWT"
+      }
+    }
+  }
+}
+`
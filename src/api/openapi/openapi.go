@@ -0,0 +1,159 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package api/openapi serves the OpenAPI document of Service Deployment Agent Manager
+// and validates bodies of requests against schemas in the document.
+package openapi
+
+import (
+	"api/common"
+	"api/router"
+	"bytes"
+	"commons/errors"
+	"commons/logger"
+	URL "commons/url"
+	"encoding/json"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	GET string = "GET"
+
+	JSON_CONTENT = "application/json" // media type of a JSON body.
+	YAML_CONTENT = "application/yaml" // media type of a YAML body, e.g. docker compose file.
+)
+
+var spec map[string]interface{}
+
+func init() {
+	if err := json.Unmarshal([]byte(document), &spec); err != nil {
+		panic("invalid openapi document: " + err.Error())
+	}
+}
+
+// Routes returns a route which serves the OpenAPI document.
+// The document is served without authentication.
+func Routes() []router.Route {
+	return []router.Route{
+		{Method: GET, Pattern: URL.Base() + URL.OpenAPI(), Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			logger.LoggingContext(req.Context(), logger.DEBUG, "[OPENAPI] Get OpenAPI Document")
+			common.WriteSuccess(w, http.StatusOK, []byte(document))
+		}},
+	}
+}
+
+// Validate returns the routes with a middleware which validates bodies of requests.
+// The middleware is added only to the routes whose operation in the document has a request body.
+// It runs after the other middlewares of the route, and responds with InvalidParam error
+// if the body does not match the schema.
+func Validate(routes []router.Route) []router.Route {
+	validated := make([]router.Route, len(routes))
+	for i, route := range routes {
+		validated[i] = route
+		requestBody, exists := findRequestBody(route.Method, route.Pattern)
+		if !exists {
+			continue
+		}
+
+		middlewares := make([]router.Middleware, 0, len(route.Middlewares)+1)
+		middlewares = append(middlewares, route.Middlewares...)
+		validated[i].Middlewares = append(middlewares, validateBody(requestBody))
+	}
+	return validated
+}
+
+// findRequestBody returns the request body object of the operation
+// identified by the method and the path in the document.
+func findRequestBody(method string, path string) (map[string]interface{}, bool) {
+	paths, _ := spec["paths"].(map[string]interface{})
+	item, _ := paths[path].(map[string]interface{})
+	operation, _ := item[strings.ToLower(method)].(map[string]interface{})
+	requestBody, exists := operation["requestBody"].(map[string]interface{})
+	if !exists {
+		return nil, false
+	}
+	return resolve(requestBody), true
+}
+
+// validateBody returns a middleware which checks the body of a request
+// with the schema of the request body object.
+func validateBody(requestBody map[string]interface{}) router.Middleware {
+	required, _ := requestBody["required"].(bool)
+	content, _ := requestBody["content"].(map[string]interface{})
+
+	return func(next router.Handler) router.Handler {
+		return func(w http.ResponseWriter, req *http.Request, params router.Params) {
+			var body []byte
+			if req.Body != nil {
+				data, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					common.WriteError(w, errors.IOError{Cause: err})
+					return
+				}
+				body = data
+			}
+
+			if err := validateContent(content, required, body); err != nil {
+				logger.LoggingContext(req.Context(), logger.ERROR, err.Error())
+				common.WriteError(w, err)
+				return
+			}
+
+			// Restore the body to be read by the handler.
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			next(w, req, params)
+		}
+	}
+}
+
+// validateContent decodes the body according to the media type described in content,
+// and checks it with the schema of the media type.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func validateContent(content map[string]interface{}, required bool, body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		if required {
+			return errors.InvalidParam{Message: "body is empty"}
+		}
+		return nil
+	}
+
+	var value interface{}
+	var mediaType map[string]interface{}
+	switch {
+	case content[JSON_CONTENT] != nil:
+		mediaType, _ = content[JSON_CONTENT].(map[string]interface{})
+		if err := json.Unmarshal(body, &value); err != nil {
+			return errors.InvalidParam{Message: "body is not a valid json", Cause: err}
+		}
+	case content[YAML_CONTENT] != nil:
+		mediaType, _ = content[YAML_CONTENT].(map[string]interface{})
+		var yamlValue interface{}
+		if err := yaml.Unmarshal(body, &yamlValue); err != nil {
+			return errors.InvalidParam{Message: "body is not a valid yaml", Cause: err}
+		}
+		value = convertYAML(yamlValue)
+	default:
+		return nil
+	}
+
+	schema, _ := mediaType["schema"].(map[string]interface{})
+	return validate(schema, value, "body")
+}
@@ -0,0 +1,182 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package openapi
+
+import (
+	"api/agent"
+	"api/catalog"
+	"api/group"
+	"api/key"
+	"api/router"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDocumentDescribesAllRoutes(t *testing.T) {
+	paths := spec["paths"].(map[string]interface{})
+
+	routes := append(append(append(append(agent.Routes(), group.Routes()...), key.Routes()...), catalog.Routes()...), Routes()...)
+	for _, route := range routes {
+		item, exists := paths[route.Pattern].(map[string]interface{})
+		if !exists {
+			t.Errorf("Expected path: %s in the document", route.Pattern)
+			continue
+		}
+		if _, exists := item[strings.ToLower(route.Method)]; !exists {
+			t.Errorf("Expected operation: %s %s in the document", route.Method, route.Pattern)
+		}
+	}
+}
+
+func TestDocumentReferencesExist(t *testing.T) {
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if ref, exists := value["$ref"].(string); exists && resolve(value) == nil {
+				t.Errorf("Unresolved reference: %s", ref)
+			}
+			for _, item := range value {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range value {
+				walk(item)
+			}
+		}
+	}
+	walk(spec)
+}
+
+func TestServeDocument(t *testing.T) {
+	r := router.New()
+	r.AddRoutes(Routes()...)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(GET, "/api/v1/openapi.json", nil)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected code: %d, actual code: %d", http.StatusOK, w.Code)
+	}
+
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil || doc["openapi"] != "3.0.0" {
+		t.Errorf("Unexpected document: %s", w.Body.String())
+	}
+}
+
+// serveWithValidation sends a request to the route validated with the document,
+// and returns the response and the body read by the handler.
+func serveWithValidation(method string, pattern string, url string, body string) (*httptest.ResponseRecorder, *string) {
+	var received *string
+	routes := Validate([]router.Route{{Method: method, Pattern: pattern, Handler: func(w http.ResponseWriter, req *http.Request, params router.Params) {
+		data, _ := ioutil.ReadAll(req.Body)
+		str := string(data)
+		received = &str
+	}}})
+
+	r := router.New()
+	r.AddRoutes(routes...)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	r.ServeHTTP(w, req)
+	return w, received
+}
+
+func TestCalledValidateWithValidBody_ExpectBodyPassed(t *testing.T) {
+	compose := "version: '2'\nservices:\n  consul:\n    image: consul\n"
+	testList := []struct {
+		name    string
+		pattern string
+		url     string
+		body    string
+	}{
+		{"Register", "/api/v1/agents/register", "/api/v1/agents/register", `{"ip":"192.168.0.1"}`},
+		{"Ping", "/api/v1/agents/{agentID}/ping", "/api/v1/agents/agentID/ping", `{"interval":"10"}`},
+		{"Join", "/api/v1/groups/{groupID}/join", "/api/v1/groups/groupID/join", `{"agents":["000000000000000000000001"]}`},
+		{"Leave", "/api/v1/groups/{groupID}/leave", "/api/v1/groups/groupID/leave", `{"agents":["000000000000000000000001"]}`},
+		{"AgentDeploy", "/api/v1/agents/{agentID}/deploy", "/api/v1/agents/agentID/deploy", compose},
+		{"GroupDeploy", "/api/v1/groups/{groupID}/deploy", "/api/v1/groups/groupID/deploy", compose},
+		{"CatalogDeploy", "/api/v1/groups/{groupID}/deploy", "/api/v1/groups/groupID/deploy", `{"app":"line-monitor","version":"1.4.0"}`},
+		{"TemplatedDeploy", "/api/v1/groups/{groupID}/deploy", "/api/v1/groups/groupID/deploy", `{"descriptor":"services: {}","variables":{"000000000000000000000001":{"station":"S-1"}}}`},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			w, received := serveWithValidation("POST", test.pattern, test.url, test.body)
+
+			if received == nil {
+				t.Fatalf("Expected request to be passed, actual code: %d, body: %s", w.Code, w.Body.String())
+			}
+			if *received != test.body {
+				t.Errorf("Expected body: %s, actual body: %s", test.body, *received)
+			}
+		})
+	}
+}
+
+func TestCalledValidateWithInvalidBody_ExpectInvalidParam(t *testing.T) {
+	testList := []struct {
+		name    string
+		pattern string
+		url     string
+		body    string
+		message string
+	}{
+		{"EmptyBody", "/api/v1/agents/register", "/api/v1/agents/register", "", "body is empty"},
+		{"MalformedJSON", "/api/v1/agents/register", "/api/v1/agents/register", `{"ip"}`, "not a valid json"},
+		{"NotObject", "/api/v1/agents/register", "/api/v1/agents/register", `["ip"]`, "body should be object"},
+		{"MissingIP", "/api/v1/agents/register", "/api/v1/agents/register", `{"host":"192.168.0.1"}`, "body.ip is required"},
+		{"NumberIP", "/api/v1/agents/register", "/api/v1/agents/register", `{"ip":1}`, "body.ip should be string"},
+		{"NumberInterval", "/api/v1/agents/{agentID}/ping", "/api/v1/agents/agentID/ping", `{"interval":10}`, "body.interval should be string"},
+		{"InvalidInterval", "/api/v1/agents/{agentID}/ping", "/api/v1/agents/agentID/ping", `{"interval":"ten"}`, "body.interval should match"},
+		{"EmptyAgents", "/api/v1/groups/{groupID}/join", "/api/v1/groups/groupID/join", `{"agents":[]}`, "body.agents should have at least 1 items"},
+		{"InvalidAgentId", "/api/v1/groups/{groupID}/leave", "/api/v1/groups/groupID/leave", `{"agents":["agent"]}`, "body.agents[0] should match"},
+		{"MalformedYAML", "/api/v1/agents/{agentID}/deploy", "/api/v1/agents/agentID/deploy", "services: [", "not a valid yaml"},
+		{"MissingServices", "/api/v1/groups/{groupID}/deploy", "/api/v1/groups/groupID/deploy", "version: '2'\n", "body.services is required"},
+		{"MissingCatalogVersion", "/api/v1/agents/{agentID}/deploy", "/api/v1/agents/agentID/deploy", `{"app":"line-monitor"}`, "body.version is required"},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			w, received := serveWithValidation("POST", test.pattern, test.url, test.body)
+
+			if received != nil {
+				t.Error("Unexpected request passed")
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected code: %d, actual code: %d", http.StatusBadRequest, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), test.message) {
+				t.Errorf("Expected message: %s, actual body: %s", test.message, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCalledValidateWithoutRequestBody_ExpectRouteUnchanged(t *testing.T) {
+	routes := Validate([]router.Route{{Method: GET, Pattern: "/api/v1/agents"}})
+	if len(routes[0].Middlewares) != 0 {
+		t.Error("Unexpected middleware added")
+	}
+}
@@ -0,0 +1,250 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package openapi
+
+import (
+	"commons/errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const REF_PREFIX = "#/components/" // prefix of a reference to a component of the document.
+
+// resolve returns the object referred by '$ref' field of the object.
+// If the object is not a reference, the object itself is returned.
+func resolve(object map[string]interface{}) map[string]interface{} {
+	ref, isRef := object["$ref"].(string)
+	if !isRef || !strings.HasPrefix(ref, REF_PREFIX) {
+		return object
+	}
+
+	var current interface{} = spec["components"]
+	for _, name := range strings.Split(strings.TrimPrefix(ref, REF_PREFIX), "/") {
+		parent, _ := current.(map[string]interface{})
+		current = parent[name]
+	}
+	resolved, _ := current.(map[string]interface{})
+	return resolve(resolved)
+}
+
+// validate checks the value decoded from JSON or YAML with a subset of the JSON schema,
+// i.e. anyOf, type, enum, required, properties, minProperties, items, minItems, minLength and pattern.
+// The path is used to point out the invalid value in an error message.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func validate(schema map[string]interface{}, value interface{}, path string) error {
+	schema = resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	if alternatives, exists := schema["anyOf"].([]interface{}); exists {
+		if err := validateAnyOf(alternatives, value, path); err != nil {
+			return err
+		}
+	}
+
+	if expected, exists := schema["type"].(string); exists && !isType(value, expected) {
+		return errors.InvalidParam{Message: fmt.Sprintf("%s should be %s, not %s", path, expected, typeOf(value))}
+	}
+
+	if enum, exists := schema["enum"].([]interface{}); exists && !contains(enum, value) {
+		return errors.InvalidParam{Message: fmt.Sprintf("%s should be one of %v", path, enum)}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return validateObject(schema, value, path)
+	case []interface{}:
+		return validateArray(schema, value, path)
+	case string:
+		return validateString(schema, value, path)
+	}
+	return nil
+}
+
+// validateAnyOf checks the value matches at least one of the alternatives.
+// If none matches, the error of the alternative which declares a field of the value
+// is returned because it is likely the one intended, otherwise the error of the first.
+func validateAnyOf(alternatives []interface{}, value interface{}, path string) error {
+	var first, intended error
+	for _, alternative := range alternatives {
+		schema, _ := alternative.(map[string]interface{})
+		err := validate(schema, value, path)
+		if err == nil {
+			return nil
+		}
+		if first == nil {
+			first = err
+		}
+		if intended == nil && declaresField(resolve(schema), value) {
+			intended = err
+		}
+	}
+	if intended != nil {
+		return intended
+	}
+	return first
+}
+
+// declaresField returns true if the value is an object with a field declared by the schema.
+func declaresField(schema map[string]interface{}, value interface{}) bool {
+	object, _ := value.(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name := range object {
+		if _, exists := properties[name]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// validateObject checks the fields of the object.
+func validateObject(schema map[string]interface{}, object map[string]interface{}, path string) error {
+	required, _ := schema["required"].([]interface{})
+	for _, name := range required {
+		if _, exists := object[name.(string)]; !exists {
+			return errors.InvalidParam{Message: fmt.Sprintf("%s.%s is required", path, name)}
+		}
+	}
+
+	if min, exists := schema["minProperties"].(float64); exists && float64(len(object)) < min {
+		return errors.InvalidParam{Message: fmt.Sprintf("%s should have at least %d fields", path, int(min))}
+	}
+
+	// Check fields in order to make an error message deterministic.
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, exists := properties[name].(map[string]interface{})
+		if !exists {
+			continue
+		}
+		if err := validate(property, object[name], path+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateArray checks the items of the array.
+func validateArray(schema map[string]interface{}, array []interface{}, path string) error {
+	if min, exists := schema["minItems"].(float64); exists && float64(len(array)) < min {
+		return errors.InvalidParam{Message: fmt.Sprintf("%s should have at least %d items", path, int(min))}
+	}
+
+	items, exists := schema["items"].(map[string]interface{})
+	if !exists {
+		return nil
+	}
+	for i, item := range array {
+		if err := validate(items, item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateString checks the length and the pattern of the string.
+func validateString(schema map[string]interface{}, str string, path string) error {
+	if min, exists := schema["minLength"].(float64); exists && float64(len(str)) < min {
+		return errors.InvalidParam{Message: fmt.Sprintf("%s should have at least %d characters", path, int(min))}
+	}
+
+	if pattern, exists := schema["pattern"].(string); exists {
+		matched, err := regexp.MatchString(pattern, str)
+		if err != nil || !matched {
+			return errors.InvalidParam{Message: fmt.Sprintf("%s should match %s", path, pattern)}
+		}
+	}
+	return nil
+}
+
+// isType returns true if the value is of the type of the JSON schema.
+func isType(value interface{}, expected string) bool {
+	if expected == "integer" {
+		number, isNumber := value.(float64)
+		return isNumber && number == math.Trunc(number)
+	}
+	return typeOf(value) == expected
+}
+
+// typeOf returns the type of the JSON schema which the value is of.
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// contains returns true if the list has the value.
+func contains(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// convertYAML converts the value decoded from YAML into the types decoded from JSON,
+// so that it can be checked with the same schema.
+func convertYAML(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			object[fmt.Sprint(key)] = convertYAML(item)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, item := range value {
+			array[i] = convertYAML(item)
+		}
+		return array
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case uint64:
+		return float64(value)
+	case float32:
+		return float64(value)
+	}
+	return value
+}
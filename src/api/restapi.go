@@ -21,50 +21,111 @@ package api
 
 import (
 	"api/agent"
-	"api/common"
+	"api/catalog"
 	"api/group"
+	"api/key"
+	"api/openapi"
+	"api/router"
 	"commons/logger"
-	"commons/errors"
-	URL "commons/url"
+	"commons/requestid"
+	"context"
+	"crypto/tls"
 	"net/http"
 	"strconv"
-	"strings"
+	"sync"
+)
+
+var (
+	serverMutex sync.Mutex
+	server      *http.Server
 )
 
 // RunSDAMWebServer starts web server service with given address and port number.
-func RunSDAMWebServer(addr string, port int) {
-	http.ListenAndServe(addr+":"+strconv.Itoa(port), &_SDAMApis)
+// If tlsConfig is not nil, requests are served over HTTPS with the given settings.
+// This function blocks until the server stops and returns the reason.
+// After ShutdownSDAMWebServer is called, http.ErrServerClosed is returned.
+func RunSDAMWebServer(addr string, port int, tlsConfig *tls.Config) error {
+	webServer := &http.Server{
+		Addr:      addr + ":" + strconv.Itoa(port),
+		Handler:   &_SDAMApis,
+		TLSConfig: tlsConfig,
+	}
+
+	serverMutex.Lock()
+	server = webServer
+	serverMutex.Unlock()
+
+	if tlsConfig == nil {
+		logger.Logging(logger.INFO, "serving HTTP on", webServer.Addr)
+		return webServer.ListenAndServe()
+	}
+
+	// Certificates are already loaded in tlsConfig.
+	logger.Logging(logger.INFO, "serving HTTPS on", webServer.Addr)
+	return webServer.ListenAndServeTLS("", "")
+}
+
+// ShutdownSDAMWebServer stops accepting new requests and waits until
+// the requests being processed are completed or the given context is done.
+// If successful, this function returns an error as nil.
+// otherwise, the error of the context will be returned.
+func ShutdownSDAMWebServer(ctx context.Context) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
 }
 
 var _SDAMApis _SDAMApisHandler
 
 type _SDAMApisHandler struct{}
 
+var sdamRouter *router.Router
+
+func init() {
+	sdamRouter = router.New()
+	sdamRouter.AddRoutes(openapi.Validate(agent.Routes())...)
+	sdamRouter.AddRoutes(openapi.Validate(group.Routes())...)
+	sdamRouter.AddRoutes(openapi.Validate(key.Routes())...)
+	sdamRouter.AddRoutes(openapi.Validate(catalog.Routes())...)
+	sdamRouter.AddRoutes(openapi.Routes()...)
+}
+
 // ServeHTTP implements a http serve interface.
-// Check if the url contains a given string and call a proper function.
-//
-//    agents: agent.SdamAgentHandle.Handle will be called.
-//	  groups: group.SdamGroupHandle.Handle will be called.
-//    others: NotFoundURL error will be used to send an error message.
+// A request is dispatched by the route table which consists of
+// the routes of agent, group, API key and catalog APIs, and the OpenAPI document.
+// Bodies of requests are validated against the document before they are handled.
+// If no route matches the url, NotFoundURL error will be used to send an error message.
+// If the method is not supported by the url, InvalidMethod error will be used.
+// Every request is handled with a request id, which is returned in X-Request-ID header.
 func (_SDAMApis *_SDAMApisHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	logger.Logging(logger.DEBUG, "receive msg", req.Method, req.URL.Path)
-	defer logger.Logging(logger.DEBUG, "OUT")
-
-	switch url := req.URL.Path; {
-	default:
-		logger.Logging(logger.DEBUG, "Unknown URL")
-		common.WriteError(w, errors.NotFoundURL{})
+	req = withRequestId(w, req)
 
-	case !strings.Contains(url, URL.Base()):
-		logger.Logging(logger.DEBUG, "Unknown URL")
-		common.WriteError(w, errors.NotFoundURL{})
+	logger.LoggingContext(req.Context(), logger.DEBUG, "receive msg", req.Method, req.URL.Path)
+	defer logger.LoggingContext(req.Context(), logger.DEBUG, "OUT")
 
-	case strings.Contains(url, URL.Agents()):
-		logger.Logging(logger.DEBUG, "Request Agents APIs")
-		agent.SdamAgentHandle.Handle(w, req)
+	sdamRouter.ServeHTTP(w, req)
+}
 
-	case strings.Contains(url, URL.Groups()):
-		logger.Logging(logger.DEBUG, "Request Groups APIs")
-		group.SdamGroupHandle.Handle(w, req)
+// withRequestId returns a copy of the request whose context carries the request id.
+// The request id given in X-Request-ID header is used if it is valid,
+// otherwise a new request id is generated.
+// The request id is also set in X-Request-ID header of the response.
+func withRequestId(w http.ResponseWriter, req *http.Request) *http.Request {
+	id := req.Header.Get(requestid.HEADER)
+	if !requestid.Valid(id) {
+		id = requestid.New()
 	}
+	if id == "" {
+		return req
+	}
+
+	w.Header().Set(requestid.HEADER, id)
+	return req.WithContext(requestid.NewContext(req.Context(), id))
 }
@@ -17,60 +17,43 @@
 package api
 
 import (
-	"api/agent"
-	"api/group"
+	"commons/requestid"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
-type agentMock struct {
-	handlerCall bool
-}
-type groupMock struct {
-	handlerCall bool
-}
-
-var am agentMock
-var gm groupMock
-
-func setUp() func() {
-	am.handlerCall = false
-	gm.handlerCall = false
-	defaultSdamAgentHandle := agent.SdamAgentHandle
-	defaultSdamGroupHandle := group.SdamGroupHandle
-	agent.SdamAgentHandle = &am
-	group.SdamGroupHandle = &gm
-	return func() {
-		agent.SdamAgentHandle = defaultSdamAgentHandle
-		group.SdamGroupHandle = defaultSdamGroupHandle
-	}
-}
-
 func TestServeHTTPsendAgent(t *testing.T) {
-	tearDown := setUp()
-	defer tearDown()
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/api/v1/agents", nil)
+	req, _ := http.NewRequest("PUT", "/api/v1/agents", nil)
 	_SDAMApis.ServeHTTP(w, req)
 
-	if !am.handlerCall || gm.handlerCall {
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET" {
 		t.Error("ServeHTTPsendAgent is invalid")
 	}
 }
 
 func TestServeHTTPsendGroup(t *testing.T) {
-	tearDown := setUp()
-	defer tearDown()
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/api/v1/groups", nil)
+	req, _ := http.NewRequest("GET", "/api/v1/groups/create", nil)
 	_SDAMApis.ServeHTTP(w, req)
 
-	if !gm.handlerCall || am.handlerCall {
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "POST" {
 		t.Error("ServeHTTPsendGroup is invalid")
 	}
 }
 
+func TestServeHTTPsendAppWithAllowedMethods(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/v1/agents/agentID/apps/appID/", nil)
+	_SDAMApis.ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "DELETE, GET, POST" {
+		t.Error("ServeHTTPsendAppWithAllowedMethods is invalid")
+	}
+}
+
 func TestServeHTTPURLisEmpty(t *testing.T) {
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "", nil)
@@ -89,9 +72,38 @@ func TestServeHTTPinvalidURL(t *testing.T) {
 	}
 }
 
-func (am *agentMock) Handle(w http.ResponseWriter, req *http.Request) {
-	am.handlerCall = true
+func TestServeHTTPURLContainingBase(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/foo/api/v1/agentsX", nil)
+	_SDAMApis.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Error("ServeHTTPURLContainingBase is invalid")
+	}
 }
-func (gm *groupMock) Handle(w http.ResponseWriter, req *http.Request) {
-	gm.handlerCall = true
+
+func TestServeHTTPWithRequestID(t *testing.T) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/", nil)
+	req.Header.Set(requestid.HEADER, "request")
+	_SDAMApis.ServeHTTP(w, req)
+	if w.Header().Get(requestid.HEADER) != "request" {
+		t.Error("ServeHTTPWithRequestID is invalid")
+	}
+}
+
+func TestServeHTTPWithoutRequestID(t *testing.T) {
+	for _, id := range []string{"", "invalid id"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/v1/", nil)
+		req.Header.Set(requestid.HEADER, id)
+		_SDAMApis.ServeHTTP(w, req)
+
+		generated := w.Header().Get(requestid.HEADER)
+		if generated == "" || generated == id {
+			t.Error("ServeHTTPWithoutRequestID is invalid")
+		}
+		if !strings.Contains(w.Body.String(), `"requestId":"`+generated+`"`) {
+			t.Error("ServeHTTPWithoutRequestID is invalid")
+		}
+	}
 }
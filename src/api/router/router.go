@@ -0,0 +1,214 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package api/router provides a route table which dispatches requests
+// by an exact match of the method and the path.
+//
+// A pattern consists of literal segments and parameter segments enclosed in braces,
+// e.g. '/api/v1/agents/{agentID}/apps/{appID}'. A literal segment takes precedence
+// over a parameter segment, so '/agents/register' is matched before '/agents/{agentID}'.
+// A trailing slash of the request path is ignored.
+package router
+
+import (
+	"api/common"
+	"commons/errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Params holds the values of parameter segments keyed by their names.
+type Params map[string]string
+
+// Handler responds to a request matched with a route.
+type Handler func(w http.ResponseWriter, req *http.Request, params Params)
+
+// Middleware wraps a handler to run before and after it.
+type Middleware func(next Handler) Handler
+
+// Route represents a pair of the method and the pattern and its handler.
+type Route struct {
+	Method      string
+	Pattern     string
+	Handler     Handler
+	Middlewares []Middleware
+}
+
+type route struct {
+	Route
+	segments []string
+}
+
+// Router dispatches requests to the handler of the most specific route matching the path.
+// If no route matches the path, 404 (Not Found) is returned.
+// If routes match the path but not the method, 405 (Method Not Allowed) is returned
+// with the 'Allow' header listing the supported methods.
+type Router struct {
+	routes      []route
+	middlewares []Middleware
+}
+
+// New returns an empty route table.
+func New() *Router {
+	return &Router{}
+}
+
+// Use appends middlewares which are applied to every request in the given order.
+// Requests which do not match any route are also passed through them.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Handle adds a route with middlewares applied only to the route.
+func (r *Router) Handle(method string, pattern string, handler Handler, middlewares ...Middleware) {
+	r.AddRoutes(Route{method, pattern, handler, middlewares})
+}
+
+// AddRoutes adds the given routes to the route table.
+func (r *Router) AddRoutes(routes ...Route) {
+	for _, newRoute := range routes {
+		r.routes = append(r.routes, route{newRoute, split(newRoute.Pattern)})
+	}
+}
+
+// ServeHTTP implements a http serve interface.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	handler, params := r.lookup(req.Method, req.URL.Path)
+	chain(handler, r.middlewares)(w, req, params)
+}
+
+// lookup returns the handler to respond to the request, with the values of parameters.
+func (r *Router) lookup(method string, path string) (Handler, Params) {
+	segments := split(path)
+
+	var matched []route
+	var params Params
+	for _, candidate := range r.routes {
+		values, ok := candidate.match(segments)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case len(matched) == 0 || moreSpecific(candidate.segments, matched[0].segments):
+			matched = []route{candidate}
+			params = values
+		case samePattern(candidate.segments, matched[0].segments):
+			matched = append(matched, candidate)
+		}
+	}
+
+	if len(matched) == 0 {
+		return notFound, nil
+	}
+
+	allowed := make([]string, 0, len(matched))
+	for _, candidate := range matched {
+		if candidate.Method == method {
+			return chain(candidate.Handler, candidate.Middlewares), params
+		}
+		allowed = append(allowed, candidate.Method)
+	}
+	return methodNotAllowed(allowed), params
+}
+
+// match checks whether the route matches the path segments.
+// If it matches, the values of parameters are returned.
+func (r route) match(segments []string) (Params, bool) {
+	if len(r.segments) != len(segments) {
+		return nil, false
+	}
+
+	params := make(Params)
+	for i, segment := range r.segments {
+		name, isParam := paramName(segment)
+		switch {
+		case isParam && segments[i] != "":
+			params[name] = segments[i]
+		case segment != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// chain wraps the handler with middlewares.
+// The first middleware is the outermost one.
+func chain(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// notFound responds with NotFoundURL error.
+func notFound(w http.ResponseWriter, req *http.Request, _ Params) {
+	common.WriteError(w, errors.NotFoundURL{Message: req.URL.Path})
+}
+
+// methodNotAllowed returns a handler which responds with InvalidMethod error
+// and the list of allowed methods.
+func methodNotAllowed(allowed []string) Handler {
+	sort.Strings(allowed)
+	return func(w http.ResponseWriter, req *http.Request, _ Params) {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		common.WriteError(w, errors.InvalidMethod{Message: req.Method})
+	}
+}
+
+// split returns the segments of the path without a trailing slash.
+func split(path string) []string {
+	path = strings.TrimSuffix(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
+}
+
+// paramName returns the name of the parameter segment.
+func paramName(segment string) (string, bool) {
+	if len(segment) > 2 && strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+// moreSpecific returns true if a has a literal segment
+// at the first position where a and b are different.
+func moreSpecific(a []string, b []string) bool {
+	for i := range a {
+		_, aIsParam := paramName(a[i])
+		_, bIsParam := paramName(b[i])
+		if aIsParam != bIsParam {
+			return !aIsParam
+		}
+	}
+	return false
+}
+
+// samePattern returns true if a and b match the same paths.
+func samePattern(a []string, b []string) bool {
+	for i := range a {
+		_, aIsParam := paramName(a[i])
+		_, bIsParam := paramName(b[i])
+		if aIsParam != bIsParam || (!aIsParam && a[i] != b[i]) {
+			return false
+		}
+	}
+	return true
+}
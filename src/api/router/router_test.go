@@ -0,0 +1,159 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type handlerCall struct {
+	name   string
+	params Params
+}
+
+func (call *handlerCall) handler(name string) Handler {
+	return func(w http.ResponseWriter, req *http.Request, params Params) {
+		call.name = name
+		call.params = params
+	}
+}
+
+func setUp(call *handlerCall) *Router {
+	r := New()
+	r.Handle("GET", "/items", call.handler("items"))
+	r.Handle("POST", "/items/new", call.handler("newItem"))
+	r.Handle("GET", "/items/{itemID}", call.handler("getItem"))
+	r.Handle("DELETE", "/items/{itemID}", call.handler("deleteItem"))
+	r.Handle("POST", "/items/{itemID}/parts/{partID}", call.handler("part"))
+	return r
+}
+
+func TestCalledServeHTTPWithMatchedURL_ExpectHandlerCalledWithParams(t *testing.T) {
+	call := handlerCall{}
+	r := setUp(&call)
+
+	testList := []struct {
+		method string
+		url    string
+		name   string
+		params Params
+	}{
+		{"GET", "/items", "items", Params{}},
+		{"GET", "/items/", "items", Params{}},
+		{"POST", "/items/new", "newItem", Params{}},
+		{"GET", "/items/item1", "getItem", Params{"itemID": "item1"}},
+		{"DELETE", "/items/item1/", "deleteItem", Params{"itemID": "item1"}},
+		{"POST", "/items/item1/parts/part1", "part", Params{"itemID": "item1", "partID": "part1"}},
+	}
+
+	for _, test := range testList {
+		call.name, call.params = "", nil
+		req, _ := http.NewRequest(test.method, test.url, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		if call.name != test.name {
+			t.Errorf("Expected handler: %s, actual handler: %s", test.name, call.name)
+		}
+		if !reflect.DeepEqual(call.params, test.params) {
+			t.Errorf("Expected params: %v, actual params: %v", test.params, call.params)
+		}
+	}
+}
+
+func TestCalledServeHTTPWithUnknownURL_ExpectNotFound(t *testing.T) {
+	call := handlerCall{}
+	r := setUp(&call)
+
+	for _, url := range []string{"", "/", "/itemsX", "/prefix/items", "/items//parts/part1", "/items/item1/parts"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected code: %d, actual code: %d for %s", http.StatusNotFound, w.Code, url)
+		}
+		if call.name != "" {
+			t.Errorf("Unexpected handler: %s for %s", call.name, url)
+		}
+	}
+}
+
+func TestCalledServeHTTPWithUnsupportedMethod_ExpectMethodNotAllowed(t *testing.T) {
+	call := handlerCall{}
+	r := setUp(&call)
+
+	testList := []struct {
+		method string
+		url    string
+		allow  string
+	}{
+		{"POST", "/items", "GET"},
+		{"GET", "/items/new", "POST"},
+		{"PUT", "/items/item1", "DELETE, GET"},
+	}
+
+	for _, test := range testList {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(test.method, test.url, nil)
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected code: %d, actual code: %d", http.StatusMethodNotAllowed, w.Code)
+		}
+		if w.Header().Get("Allow") != test.allow {
+			t.Errorf("Expected allow: %s, actual allow: %s", test.allow, w.Header().Get("Allow"))
+		}
+	}
+}
+
+func TestCalledServeHTTPWithMiddlewares_ExpectCalledInOrder(t *testing.T) {
+	var calls []string
+	middleware := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(w http.ResponseWriter, req *http.Request, params Params) {
+				calls = append(calls, name)
+				next(w, req, params)
+			}
+		}
+	}
+
+	r := New()
+	r.Use(middleware("first"), middleware("second"))
+	r.Handle("GET", "/items", func(w http.ResponseWriter, req *http.Request, params Params) {
+		calls = append(calls, "handler")
+	}, middleware("route"))
+
+	req, _ := http.NewRequest("GET", "/items", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := []string{"first", "second", "route", "handler"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls: %v, actual calls: %v", expected, calls)
+	}
+
+	calls = nil
+	req, _ = http.NewRequest("GET", "/unknown", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected = []string{"first", "second"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls: %v, actual calls: %v", expected, calls)
+	}
+}
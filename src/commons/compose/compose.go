@@ -0,0 +1,381 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package commons/compose renders and validates docker compose files before they are deployed to agents,
+// so that values specific to each agent can be templated and an invalid file is rejected once
+// by the manager instead of by every agent.
+package compose
+
+import (
+	"commons/errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	VERSION  = "version"  // top-level key of the version of the compose file format.
+	SERVICES = "services" // top-level key of the services.
+	VOLUMES  = "volumes"  // top-level key of named volumes, and the key of volumes of a service.
+	PORTS    = "ports"    // key of ports of a service.
+	IMAGE    = "image"    // key of the image of a service.
+
+	EXTENSION_PREFIX = "x-" // prefix of extension fields, which are allowed at any level.
+	MAX_PORT         = 65535
+
+	// TEMPLATED is a value which replaces placeholders of variables while a template is validated.
+	TEMPLATED = "sdam-variable"
+)
+
+var (
+	// topLevelKeys is a set of keys which are allowed at the top level.
+	topLevelKeys = toSet(VERSION, SERVICES, VOLUMES, "networks", "secrets", "configs")
+
+	// serviceKeys is a set of keys of a service which are supported on agents.
+	// Building images and options of swarm mode are not supported.
+	serviceKeys = toSet(IMAGE, PORTS, VOLUMES, "cap_add", "cap_drop", "command", "container_name", "cpu_shares",
+		"cpus", "depends_on", "devices", "dns", "dns_search", "domainname", "entrypoint", "env_file", "environment",
+		"expose", "extra_hosts", "healthcheck", "hostname", "init", "ipc", "labels", "logging", "mac_address",
+		"mem_limit", "mem_reservation", "network_mode", "networks", "pid", "privileged", "read_only", "restart",
+		"security_opt", "shm_size", "stdin_open", "stop_grace_period", "stop_signal", "sysctls", "tmpfs", "tty",
+		"ulimits", "user", "volumes_from", "working_dir")
+
+	// portKeys and volumeKeys are sets of keys of the long syntax of a port and a volume of a service.
+	portKeys   = toSet("target", "published", "protocol", "mode")
+	volumeKeys = toSet("type", "source", "target", "read_only", "consistency", "bind", "volume", "tmpfs")
+
+	volumeTypes   = toSet("volume", "bind", "tmpfs", "npipe")
+	volumeModes   = toSet("ro", "rw", "z", "Z", "cached", "delegated", "consistent", "nocopy")
+	portProtocols = toSet("tcp", "udp", "sctp")
+
+	// portPattern is a pattern of the short syntax of a port, i.e. [[ip:][published]:]target[/protocol],
+	// where published and target are a port or a range of ports, e.g. '127.0.0.1:8080-8081:80-81/tcp'.
+	portPattern = regexp.MustCompile(`^(?:(?:\d{1,3}(?:\.\d{1,3}){3}|\[[0-9A-Fa-f:]+\]):)?(?:(\d+(?:-\d+)?)?:)?(\d+(?:-\d+)?)(?:/(\w+))?$`)
+
+	// namePattern is a pattern of names of services and named volumes.
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+// Validate parses the descriptor as a compose file and checks that every service has an image,
+// and that there are no unsupported keys and no invalid ports and volumes.
+// If versions is not empty, the compose file should declare one of them as its version.
+// Every problem found is described in the message of the error.
+// Placeholders of variables are regarded as valid values, so that a template
+// can be validated once before it is rendered for each agent.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func Validate(descriptor string, versions []string) error {
+	descriptor = placeholderPattern.ReplaceAllString(descriptor, TEMPLATED)
+
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(descriptor), &value); err != nil {
+		return errors.InvalidParam{Message: "compose file is not a valid yaml", Cause: err}
+	}
+
+	v := &validator{}
+	v.validateFile(value, versions)
+	if len(v.problems) > 0 {
+		return errors.InvalidParam{Message: "invalid compose file: " + strings.Join(v.problems, "; ")}
+	}
+	return nil
+}
+
+// validator collects problems found in a compose file.
+type validator struct {
+	problems []string
+}
+
+// report adds a problem of the value at the path.
+func (v *validator) report(path string, format string, args ...interface{}) {
+	v.problems = append(v.problems, path+" "+fmt.Sprintf(format, args...))
+}
+
+// validateFile checks the top level of a compose file.
+func (v *validator) validateFile(value interface{}, versions []string) {
+	file, ok := toMap(value)
+	if !ok {
+		v.report("compose file", "should be a mapping")
+		return
+	}
+	v.checkKeys("", file, topLevelKeys)
+
+	v.validateVersion(file[VERSION], versions)
+
+	named, ok := toMap(file[VOLUMES])
+	if file[VOLUMES] != nil && !ok {
+		v.report(VOLUMES, "should be a mapping")
+	}
+
+	services, ok := toMap(file[SERVICES])
+	switch {
+	case file[SERVICES] == nil:
+		v.report(SERVICES, "is required")
+	case !ok:
+		v.report(SERVICES, "should be a mapping")
+	case len(services) == 0:
+		v.report(SERVICES, "should have at least one service")
+	}
+	for _, name := range sortedKeys(services) {
+		v.validateService(name, services[name], named)
+	}
+}
+
+// validateVersion checks the version of the compose file format against the accepted versions.
+func (v *validator) validateVersion(value interface{}, versions []string) {
+	version := ""
+	switch value.(type) {
+	case nil:
+	case string, int, float64:
+		version = fmt.Sprint(value)
+	default:
+		v.report(VERSION, "should be a string")
+		return
+	}
+
+	if len(versions) == 0 || isTemplated(version) {
+		return
+	}
+	if version == "" {
+		v.report(VERSION, "is required, accepted versions are %s", strings.Join(versions, ", "))
+		return
+	}
+	for _, accepted := range versions {
+		if version == accepted {
+			return
+		}
+	}
+	v.report(VERSION, "'%s' is not accepted, accepted versions are %s", version, strings.Join(versions, ", "))
+}
+
+// validateService checks a service. named is the top-level volumes, which should declare
+// every named volume used by the service.
+func (v *validator) validateService(name string, value interface{}, named map[string]interface{}) {
+	path := SERVICES + "." + name
+	if !namePattern.MatchString(name) {
+		v.report(path, "is not a valid service name")
+	}
+
+	service, ok := toMap(value)
+	if !ok {
+		v.report(path, "should be a mapping")
+		return
+	}
+	v.checkKeys(path+".", service, serviceKeys)
+
+	image, _ := service[IMAGE].(string)
+	if strings.TrimSpace(image) == "" {
+		v.report(path+"."+IMAGE, "is required")
+	}
+
+	if service[PORTS] != nil {
+		ports, ok := service[PORTS].([]interface{})
+		if !ok {
+			v.report(path+"."+PORTS, "should be a list")
+		}
+		for i, port := range ports {
+			v.validatePort(path+"."+PORTS+"["+strconv.Itoa(i)+"]", port)
+		}
+	}
+
+	if service[VOLUMES] != nil {
+		volumes, ok := service[VOLUMES].([]interface{})
+		if !ok {
+			v.report(path+"."+VOLUMES, "should be a list")
+		}
+		for i, volume := range volumes {
+			v.validateVolume(path+"."+VOLUMES+"["+strconv.Itoa(i)+"]", volume, named)
+		}
+	}
+}
+
+// validatePort checks a port of a service in the short or the long syntax.
+func (v *validator) validatePort(path string, value interface{}) {
+	switch port := value.(type) {
+	case int:
+		if port < 1 || port > MAX_PORT {
+			v.report(path, "'%d' is not a valid port", port)
+		}
+	case string:
+		if isTemplated(port) {
+			return
+		}
+		match := portPattern.FindStringSubmatch(port)
+		if match == nil || !validPortRange(match[2]) || (match[1] != "" && !validPortRange(match[1])) {
+			v.report(path, "'%s' is not a valid port, e.g. '8080:80' or '127.0.0.1:8080:80/udp'", port)
+			return
+		}
+		if match[3] != "" && !portProtocols[match[3]] {
+			v.report(path, "'%s' has an unsupported protocol %s", port, match[3])
+		}
+	default:
+		long, ok := toMap(value)
+		if !ok {
+			v.report(path, "should be a string, a number or a mapping")
+			return
+		}
+		v.checkKeys(path+".", long, portKeys)
+
+		if target, ok := long["target"].(int); (!ok || target < 1 || target > MAX_PORT) && !isTemplated(long["target"]) {
+			v.report(path+".target", "should be a valid port")
+		}
+		switch published := long["published"].(type) {
+		case nil:
+		case int:
+			if published < 1 || published > MAX_PORT {
+				v.report(path+".published", "should be a valid port")
+			}
+		case string:
+			if !validPortRange(published) && !isTemplated(published) {
+				v.report(path+".published", "should be a valid port or range of ports")
+			}
+		default:
+			v.report(path+".published", "should be a valid port or range of ports")
+		}
+		if protocol, exists := long["protocol"]; exists && !portProtocols[fmt.Sprint(protocol)] {
+			v.report(path+".protocol", "'%v' is not a supported protocol", protocol)
+		}
+	}
+}
+
+// validateVolume checks a volume of a service in the short or the long syntax.
+// A named volume used in the short syntax should be declared in the top-level volumes.
+func (v *validator) validateVolume(path string, value interface{}, named map[string]interface{}) {
+	if volume, ok := value.(string); ok {
+		if isTemplated(volume) {
+			return
+		}
+		parts := strings.Split(volume, ":")
+		if len(parts) > 3 {
+			v.report(path, "'%s' is not a valid volume, e.g. 'data:/var/lib/data:ro'", volume)
+			return
+		}
+
+		target := parts[0]
+		if len(parts) > 1 {
+			source := parts[0]
+			target = parts[1]
+			if !isPath(source) {
+				if !namePattern.MatchString(source) {
+					v.report(path, "'%s' has an invalid source %s", volume, source)
+				} else if _, declared := named[source]; !declared {
+					v.report(path, "'%s' uses a named volume %s which is not declared in volumes", volume, source)
+				}
+			}
+		}
+		if !strings.HasPrefix(target, "/") {
+			v.report(path, "'%s' should have an absolute path in the container", volume)
+		}
+		if len(parts) == 3 {
+			for _, mode := range strings.Split(parts[2], ",") {
+				if !volumeModes[mode] {
+					v.report(path, "'%s' has an unsupported mode %s", volume, mode)
+				}
+			}
+		}
+		return
+	}
+
+	long, ok := toMap(value)
+	if !ok {
+		v.report(path, "should be a string or a mapping")
+		return
+	}
+	v.checkKeys(path+".", long, volumeKeys)
+
+	if volumeType, exists := long["type"]; exists && !volumeTypes[fmt.Sprint(volumeType)] {
+		v.report(path+".type", "'%v' is not a supported type of volume", volumeType)
+	}
+	if target, _ := long["target"].(string); !strings.HasPrefix(target, "/") && !isTemplated(target) {
+		v.report(path+".target", "should be an absolute path in the container")
+	}
+}
+
+// checkKeys reports keys of the mapping which are not in the allowed keys.
+// Extension fields are always allowed.
+func (v *validator) checkKeys(prefix string, mapping map[string]interface{}, allowed map[string]bool) {
+	for _, key := range sortedKeys(mapping) {
+		if !allowed[key] && !strings.HasPrefix(key, EXTENSION_PREFIX) {
+			v.report(prefix+key, "is not supported")
+		}
+	}
+}
+
+// validPortRange returns true if the value is a port or a range of ports, e.g. '8080-8081'.
+func validPortRange(value string) bool {
+	bounds := strings.Split(value, "-")
+	if len(bounds) > 2 {
+		return false
+	}
+
+	previous := 0
+	for _, bound := range bounds {
+		port, err := strconv.Atoi(bound)
+		if err != nil || port < 1 || port > MAX_PORT || port < previous {
+			return false
+		}
+		previous = port
+	}
+	return true
+}
+
+// isTemplated returns true if the value has a placeholder of a variable,
+// whose syntax cannot be checked until it is rendered.
+func isTemplated(value interface{}) bool {
+	str, ok := value.(string)
+	return ok && strings.Contains(str, TEMPLATED)
+}
+
+// isPath returns true if the source of a volume is a path on the host rather than a named volume.
+func isPath(source string) bool {
+	return strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~")
+}
+
+// toMap converts a mapping parsed from yaml into a map keyed by strings.
+func toMap(value interface{}) (map[string]interface{}, bool) {
+	mapping, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	result := make(map[string]interface{}, len(mapping))
+	for key, item := range mapping {
+		result[fmt.Sprint(key)] = item
+	}
+	return result, true
+}
+
+// sortedKeys returns the keys of the mapping in order, so that problems are reported in the same order.
+func sortedKeys(mapping map[string]interface{}) []string {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toSet returns a set of the keys.
+func toSet(keys ...string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
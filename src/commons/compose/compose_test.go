@@ -0,0 +1,131 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package compose
+
+import (
+	"commons/errors"
+	"strings"
+	"testing"
+)
+
+const validFile = `version: '3.7'
+services:
+  monitor:
+    image: monitor:1.4.0
+    restart: always
+    ports:
+    - 80
+    - 8080:80
+    - 127.0.0.1:9000-9001:9000-9001/udp
+    - target: 443
+      published: 8443
+      protocol: tcp
+    volumes:
+    - data:/var/lib/monitor
+    - ./config:/etc/monitor:ro,z
+    - /tmp
+    - type: bind
+      source: /dev/log
+      target: /dev/log
+    x-owner: line
+volumes:
+  data:
+`
+
+func TestCalledValidateWithValidFile_ExpectSuccess(t *testing.T) {
+	testList := map[string]struct {
+		descriptor string
+		versions   []string
+	}{
+		"AnyVersion":      {validFile, nil},
+		"AcceptedVersion": {validFile, []string{"3.6", "3.7"}},
+		"WithoutVersion":  {"services:\n  monitor:\n    image: monitor\n", nil},
+		"NumberVersion":   {"version: 2\nservices:\n  monitor:\n    image: monitor\n", []string{"2"}},
+		"ExtensionAtTop":  {"x-common: {}\nservices:\n  monitor:\n    image: monitor\n", nil},
+		"IPv6PortBinding": {"services:\n  monitor:\n    image: monitor\n    ports:\n    - '[::1]:8080:80'\n", nil},
+		"EmptyPublished":  {"services:\n  monitor:\n    image: monitor\n    ports:\n    - '127.0.0.1::80'\n", nil},
+	}
+
+	for name, test := range testList {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(test.descriptor, test.versions)
+
+			if err != nil {
+				t.Errorf("Unexpected err: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestCalledValidateWithInvalidFile_ExpectErrorReturn(t *testing.T) {
+	testList := map[string]struct {
+		descriptor string
+		versions   []string
+		message    string
+	}{
+		"MalformedYAML":      {"services: [", nil, "not a valid yaml"},
+		"NotMapping":         {"- monitor", nil, "compose file should be a mapping"},
+		"WithoutServices":    {"version: '3'", nil, "services is required"},
+		"EmptyServices":      {"services: {}", nil, "services should have at least one service"},
+		"WithoutImage":       {"services:\n  monitor:\n    restart: always\n", nil, "services.monitor.image is required"},
+		"UnsupportedKey":     {"services:\n  monitor:\n    image: monitor\n    build: .\n", nil, "services.monitor.build is not supported"},
+		"UnsupportedTopKey":  {"services:\n  monitor:\n    image: monitor\ndeploy: {}\n", nil, "deploy is not supported"},
+		"InvalidPort":        {"services:\n  monitor:\n    image: monitor\n    ports:\n    - '80:80:80'\n", nil, "services.monitor.ports[0] '80:80:80' is not a valid port"},
+		"OutOfRangePort":     {"services:\n  monitor:\n    image: monitor\n    ports:\n    - '70000:80'\n", nil, "is not a valid port"},
+		"UnknownProtocol":    {"services:\n  monitor:\n    image: monitor\n    ports:\n    - '80/icmp'\n", nil, "unsupported protocol icmp"},
+		"LongPortNoTarget":   {"services:\n  monitor:\n    image: monitor\n    ports:\n    - published: 80\n", nil, "services.monitor.ports[0].target should be a valid port"},
+		"RelativeTarget":     {"services:\n  monitor:\n    image: monitor\n    volumes:\n    - ./data:data\n", nil, "should have an absolute path in the container"},
+		"UndeclaredVolume":   {"services:\n  monitor:\n    image: monitor\n    volumes:\n    - data:/data\n", nil, "named volume data which is not declared"},
+		"UnknownVolumeMode":  {"services:\n  monitor:\n    image: monitor\n    volumes:\n    - /data:/data:rx\n", nil, "unsupported mode rx"},
+		"UnknownVolumeType":  {"services:\n  monitor:\n    image: monitor\n    volumes:\n    - type: cluster\n      target: /data\n", nil, "'cluster' is not a supported type"},
+		"VersionRequired":    {"services:\n  monitor:\n    image: monitor\n", []string{"3.7"}, "version is required"},
+		"VersionNotAccepted": {"version: '2.4'\nservices:\n  monitor:\n    image: monitor\n", []string{"3.7", "3.8"}, "version '2.4' is not accepted"},
+	}
+
+	for name, test := range testList {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(test.descriptor, test.versions)
+
+			switch err.(type) {
+			default:
+				t.Fatalf("Expected err: %s, actual err: %v", "InvalidParam", err)
+			case errors.InvalidParam:
+			}
+
+			if !strings.Contains(err.Error(), test.message) {
+				t.Errorf("Expected message: %s, actual message: %s", test.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestCalledValidateWithManyProblems_ExpectAllProblemsReported(t *testing.T) {
+	descriptor := "services:\n  monitor:\n    build: .\n    ports:\n    - 'http'\n"
+
+	err := Validate(descriptor, nil)
+
+	if err == nil {
+		t.Fatal("Expected err: InvalidParam, actual err: nil")
+	}
+
+	expected := "invalid compose file: services.monitor.build is not supported; services.monitor.image is required; " +
+		"services.monitor.ports[0] 'http' is not a valid port, e.g. '8080:80' or '127.0.0.1:8080:80/udp'"
+	if err.(errors.InvalidParam).Message != expected {
+		t.Errorf("Expected message: %s, actual message: %s", expected, err.(errors.InvalidParam).Message)
+	}
+}
@@ -0,0 +1,141 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package compose
+
+import (
+	"commons/errors"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	VARIABLES = "variables" // field of a deploy request which has variables of each agent.
+
+	AGENT_PREFIX  = "agent."  // prefix of variables which are fields of an agent.
+	LABELS_PREFIX = "labels." // prefix of variables which are labels of an agent.
+)
+
+// agentFields is a list of fields of an agent which are provided as variables.
+var agentFields = []string{"id", "host", "port", "deviceid"}
+
+// placeholderPattern is a pattern of a placeholder of a variable in a descriptor, e.g. '{{ agent.host }}'.
+// A name should not start with a dot, so that go templates of docker such as '{{.Name}}' are left as they are.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// Variables returns the variables of each agent given in the body of a deploy request,
+// i.e. a map of agent id to a map of variable names to values in the variables field.
+// If the body is not a JSON object or has no variables field, nil is returned.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func Variables(body string) (map[string]map[string]string, error) {
+	bodyMap := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(body), &bodyMap); err != nil {
+		return nil, nil
+	}
+	value, exists := bodyMap[VARIABLES]
+	if !exists {
+		return nil, nil
+	}
+
+	agents, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.InvalidParam{Message: "variables should be an object of agent ids to variables"}
+	}
+
+	variables := make(map[string]map[string]string)
+	for agentId, agentValue := range agents {
+		values, ok := agentValue.(map[string]interface{})
+		if !ok {
+			return nil, errors.InvalidParam{Message: "variables of an agent should be an object", Kind: errors.AGENT, ID: agentId}
+		}
+		variables[agentId] = make(map[string]string)
+		for name, value := range values {
+			str, ok := value.(string)
+			if !ok {
+				return nil, errors.InvalidParam{Message: "value of variable " + name + " should be a string", Kind: errors.AGENT, ID: agentId}
+			}
+			variables[agentId][name] = str
+		}
+	}
+	return variables, nil
+}
+
+// Render replaces placeholders of variables in the descriptor with the values for the agent.
+// Fields of the agent are provided as agent.id, agent.host, agent.port and agent.deviceid,
+// and labels of the agent as labels.<key>. Variables given for the agent id override them.
+// Values are substituted as they are, so placeholders should be quoted
+// when values may have special characters of yaml.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func Render(descriptor string, agent map[string]interface{}, variables map[string]map[string]string) (string, error) {
+	agentId, _ := agent["id"].(string)
+	values := agentValues(agent)
+	for name, value := range variables[agentId] {
+		values[name] = value
+	}
+
+	undefined := make(map[string]bool)
+	rendered := placeholderPattern.ReplaceAllStringFunc(descriptor, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		value, exists := values[name]
+		if !exists {
+			undefined[name] = true
+			return placeholder
+		}
+		return value
+	})
+
+	if len(undefined) != 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", errors.InvalidParam{
+			Message: "undefined variables in descriptor: " + strings.Join(names, ", "),
+			Kind:    errors.AGENT,
+			ID:      agentId,
+		}
+	}
+	return rendered, nil
+}
+
+// agentValues returns values of variables which are fields and labels of the agent.
+func agentValues(agent map[string]interface{}) map[string]string {
+	values := make(map[string]string)
+	for _, field := range agentFields {
+		if value, ok := agent[field].(string); ok {
+			values[AGENT_PREFIX+field] = value
+		}
+	}
+
+	switch labels := agent["labels"].(type) {
+	case map[string]string:
+		for key, value := range labels {
+			values[LABELS_PREFIX+key] = value
+		}
+	case map[string]interface{}:
+		for key, value := range labels {
+			if str, ok := value.(string); ok {
+				values[LABELS_PREFIX+key] = str
+			}
+		}
+	}
+	return values
+}
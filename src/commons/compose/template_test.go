@@ -0,0 +1,192 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package compose
+
+import (
+	"commons/errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const templateFile = `services:
+  monitor:
+    image: monitor:1.4.0
+    hostname: "{{ agent.id }}"
+    environment:
+    - STATION={{station}}
+    - SITE={{ labels.site }}
+    - MANAGER={{ agent.host }}:{{ agent.port }}
+    logging:
+      options:
+        tag: "{{.Name}}"
+`
+
+var templateAgent = map[string]interface{}{
+	"id":     "agentId",
+	"host":   "192.168.0.1",
+	"port":   "48098",
+	"labels": map[string]string{"site": "line-1"},
+}
+
+func TestCalledVariablesWithVariables_ExpectVariablesReturn(t *testing.T) {
+	body := `{"app":"monitor","version":"1.4.0","variables":{"agentId":{"station":"S-12"}}}`
+
+	variables, err := Variables(body)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expected := map[string]map[string]string{"agentId": {"station": "S-12"}}
+	if !reflect.DeepEqual(variables, expected) {
+		t.Errorf("Expected variables: %v, actual variables: %v", expected, variables)
+	}
+}
+
+func TestCalledVariablesWithoutVariables_ExpectNilReturn(t *testing.T) {
+	testList := map[string]string{
+		"ComposeFile":      "services:\n  monitor:\n    image: monitor\n",
+		"CatalogReference": `{"app":"monitor","version":"1.4.0"}`,
+	}
+
+	for name, body := range testList {
+		t.Run(name, func(t *testing.T) {
+			variables, err := Variables(body)
+			if err != nil {
+				t.Errorf("Unexpected err: %s", err.Error())
+			}
+			if variables != nil {
+				t.Errorf("Expected nil variables, actual variables: %v", variables)
+			}
+		})
+	}
+}
+
+func TestCalledVariablesWithInvalidVariables_ExpectErrorReturn(t *testing.T) {
+	testList := map[string]string{
+		"NotObject":      `{"variables":"station"}`,
+		"AgentNotObject": `{"variables":{"agentId":"station"}}`,
+		"ValueNotString": `{"variables":{"agentId":{"station":12}}}`,
+	}
+
+	for name, body := range testList {
+		t.Run(name, func(t *testing.T) {
+			_, err := Variables(body)
+			switch err.(type) {
+			default:
+				t.Errorf("Expected InvalidParam error, actual err: %v", err)
+			case errors.InvalidParam:
+			}
+		})
+	}
+}
+
+func TestCalledRender_ExpectRenderedDescriptorReturn(t *testing.T) {
+	variables := map[string]map[string]string{
+		"agentId": {"station": "S-12"},
+		"other":   {"station": "S-13"},
+	}
+
+	rendered, err := Render(templateFile, templateAgent, variables)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expected := []string{
+		`hostname: "agentId"`,
+		"STATION=S-12",
+		"SITE=line-1",
+		"MANAGER=192.168.0.1:48098",
+		`tag: "{{.Name}}"`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(rendered, line) {
+			t.Errorf("Expected %s in rendered descriptor: %s", line, rendered)
+		}
+	}
+	if err := Validate(rendered, nil); err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledRenderWithOverriddenVariable_ExpectOverriddenValueReturn(t *testing.T) {
+	variables := map[string]map[string]string{
+		"agentId": {"station": "S-12", "labels.site": "line-2"},
+	}
+
+	rendered, err := Render(templateFile, templateAgent, variables)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if !strings.Contains(rendered, "SITE=line-2") {
+		t.Errorf("Expected overridden label in rendered descriptor: %s", rendered)
+	}
+}
+
+func TestCalledRenderWithUndefinedVariables_ExpectErrorReturn(t *testing.T) {
+	agent := map[string]interface{}{"id": "agentId", "host": "192.168.0.1", "port": "48098"}
+
+	_, err := Render(templateFile, agent, nil)
+	switch err.(type) {
+	default:
+		t.Errorf("Expected InvalidParam error, actual err: %v", err)
+	case errors.InvalidParam:
+		expected := "invalid parameter: undefined variables in descriptor: labels.site, station (agent agentId)"
+		if err.Error() != expected {
+			t.Errorf("Expected err: %s, actual err: %s", expected, err.Error())
+		}
+	}
+}
+
+func TestCalledValidateWithTemplate_ExpectSuccess(t *testing.T) {
+	template := `version: '{{ version }}'
+services:
+  monitor:
+    image: monitor:{{ tag }}
+    ports:
+    - {{ agent.port }}:80
+    - target: 443
+      published: "{{ published }}"
+    volumes:
+    - {{ labels.data }}:/var/lib/monitor
+`
+
+	if err := Validate(template, []string{"3.7"}); err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledValidateWithInvalidTemplate_ExpectErrorReturn(t *testing.T) {
+	template := "services:\n  monitor:\n    image: monitor:{{ tag }}\n    build: .\n"
+
+	err := Validate(template, nil)
+	switch err.(type) {
+	default:
+		t.Errorf("Expected InvalidParam error, actual err: %v", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledRenderWithoutPlaceholders_ExpectDescriptorReturn(t *testing.T) {
+	rendered, err := Render(validFile, templateAgent, nil)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if rendered != validFile {
+		t.Errorf("Expected descriptor: %s, actual descriptor: %s", validFile, rendered)
+	}
+}
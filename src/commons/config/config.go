@@ -0,0 +1,613 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package commons/config provides the settings of Service Deployment Agent Manager.
+// Settings are resolved in the following order, where later sources override
+// earlier ones:
+//
+//  1. built-in defaults
+//  2. configuration file (YAML or JSON), given by -config or SDAM_CONFIG_FILE
+//  3. environment variables prefixed with SDAM_
+//  4. command-line flags
+package config
+
+import (
+	"bytes"
+	"commons/errors"
+	"encoding/json"
+	"flag"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	ENV_PREFIX      = "SDAM_"            // prefix of environment variables.
+	CONFIG_FILE_ENV = "SDAM_CONFIG_FILE" // environment variable used to indicate a configuration file.
+	CONFIG_FILE     = "config"           // command-line flag used to indicate a configuration file.
+)
+
+const (
+	CLIENT_AUTH_NONE               = "none"               // client certificates are not requested.
+	CLIENT_AUTH_REQUEST            = "request"            // client certificates are requested but not required.
+	CLIENT_AUTH_REQUIRE            = "require"            // client certificates are required but not verified.
+	CLIENT_AUTH_VERIFY_IF_GIVEN    = "verify_if_given"    // client certificates are verified if given.
+	CLIENT_AUTH_REQUIRE_AND_VERIFY = "require_and_verify" // client certificates are required and verified.
+)
+
+const (
+	HEALTH_PUSH = "push" // liveness of agents is decided by their pings.
+	HEALTH_PULL = "pull" // liveness of agents is decided by health probes of the manager.
+	HEALTH_BOTH = "both" // agents are alive while either pings or health probes succeed.
+)
+
+type (
+	// Config represents all settings of Service Deployment Agent Manager.
+	Config struct {
+		Server    ServerConfig    `yaml:"server" json:"server"`
+		DB        DBConfig        `yaml:"db" json:"db"`
+		Agent     AgentConfig     `yaml:"agent" json:"agent"`
+		Group     GroupConfig     `yaml:"group" json:"group"`
+		Health    HealthConfig    `yaml:"health" json:"health"`
+		Retention RetentionConfig `yaml:"retention" json:"retention"`
+		Auth      AuthConfig      `yaml:"auth" json:"auth"`
+	}
+
+	// ServerConfig represents settings of the REST server.
+	// ShutdownTimeout is the number of seconds to wait for running requests
+	// and health checks to finish when the server is terminated.
+	// TrustedProxies is a list of addresses or CIDR networks of proxies
+	// whose X-Forwarded-For header is used to find the address of agents.
+	ServerConfig struct {
+		Address         string          `yaml:"address" json:"address"`
+		Port            int             `yaml:"port" json:"port"`
+		TLS             ServerTLSConfig `yaml:"tls" json:"tls"`
+		ShutdownTimeout int             `yaml:"shutdown_timeout_sec" json:"shutdown_timeout_sec"`
+		TrustedProxies  []string        `yaml:"trusted_proxies" json:"trusted_proxies"`
+	}
+
+	// ServerTLSConfig represents settings used to serve HTTPS.
+	// HTTPS is enabled when both CertFile and KeyFile are given.
+	// ClientAuth is one of 'none', 'request', 'require', 'verify_if_given'
+	// and 'require_and_verify', and client certificates are verified with ClientCAFile.
+	ServerTLSConfig struct {
+		CertFile     string `yaml:"cert_file" json:"cert_file"`
+		KeyFile      string `yaml:"key_file" json:"key_file"`
+		ClientCAFile string `yaml:"client_ca_file" json:"client_ca_file"`
+		ClientAuth   string `yaml:"client_auth" json:"client_auth"`
+	}
+
+	// DBConfig represents settings used to connect to the database.
+	DBConfig struct {
+		URL        string `yaml:"url" json:"url"`
+		Name       string `yaml:"name" json:"name"`
+		Username   string `yaml:"username" json:"username"`
+		Password   string `yaml:"password" json:"password"`
+		AuthSource string `yaml:"auth_source" json:"auth_source"`
+	}
+
+	// AgentConfig represents settings used to communicate with agents.
+	// Apps recorded for connected agents are reconciled with the apps which the agents report
+	// every ReconcileInterval seconds, with at most ReconcileConcurrency agents at a time.
+	// Reconciliation is disabled when ReconcileInterval is 0.
+	// ComposeVersions is a list of versions of the compose file format which can be deployed.
+	// If it is empty, compose files of any version, or without a version, can be deployed.
+	AgentConfig struct {
+		DefaultPort          string         `yaml:"default_port" json:"default_port"`
+		TLS                  AgentTLSConfig `yaml:"tls" json:"tls"`
+		ReconcileInterval    int            `yaml:"reconcile_interval_sec" json:"reconcile_interval_sec"`
+		ReconcileConcurrency int            `yaml:"reconcile_concurrency" json:"reconcile_concurrency"`
+		ComposeVersions      []string       `yaml:"compose_versions" json:"compose_versions"`
+	}
+
+	// GroupConfig represents settings of operations on groups.
+	// Members of groups which declare a desired state are converged toward it every ConvergeInterval
+	// seconds, with at most ConvergeConcurrency members at a time.
+	// Periodic convergence is disabled when ConvergeInterval is 0.
+	GroupConfig struct {
+		ConvergeInterval    int `yaml:"converge_interval_sec" json:"converge_interval_sec"`
+		ConvergeConcurrency int `yaml:"converge_concurrency" json:"converge_concurrency"`
+	}
+
+	// AgentTLSConfig represents settings used to send HTTPS requests to agents.
+	// Server certificates of agents are verified with CAFile, or with the system
+	// roots if it is not given. CertFile and KeyFile are presented to agents
+	// as a client certificate of the manager.
+	AgentTLSConfig struct {
+		Enabled  bool   `yaml:"enabled" json:"enabled"`
+		CAFile   string `yaml:"ca_file" json:"ca_file"`
+		CertFile string `yaml:"cert_file" json:"cert_file"`
+		KeyFile  string `yaml:"key_file" json:"key_file"`
+	}
+
+	// HealthConfig represents settings of the agent health check.
+	// Mode is one of 'push', 'pull' and 'both', and is used for agents which do not have their own.
+	// Agents in 'pull' or 'both' mode are probed every ProbeInterval seconds, with at most
+	// ProbeConcurrency probes at a time, and a probe fails after ProbeTimeout seconds.
+	// Probing is disabled when ProbeInterval is 0.
+	HealthConfig struct {
+		MaxNetworkLatency int    `yaml:"max_network_latency_sec" json:"max_network_latency_sec"`
+		Mode              string `yaml:"mode" json:"mode"`
+		ProbeInterval     int    `yaml:"probe_interval_sec" json:"probe_interval_sec"`
+		ProbeTimeout      int    `yaml:"probe_timeout_sec" json:"probe_timeout_sec"`
+		ProbeConcurrency  int    `yaml:"probe_concurrency" json:"probe_concurrency"`
+	}
+
+	// RetentionConfig represents the retention policy of disconnected agents.
+	// An agent which has been disconnected for StaleAfter days becomes stale, and one which has been
+	// disconnected for DeleteAfter days is removed from all groups and deleted. Either is disabled when it is 0.
+	// The policy is applied every Interval seconds. Stale agents are excluded from operations on groups
+	// and selected agents unless IncludeStale is true.
+	RetentionConfig struct {
+		StaleAfter   int  `yaml:"stale_after_days" json:"stale_after_days"`
+		DeleteAfter  int  `yaml:"delete_after_days" json:"delete_after_days"`
+		Interval     int  `yaml:"interval_sec" json:"interval_sec"`
+		IncludeStale bool `yaml:"include_stale" json:"include_stale"`
+	}
+
+	// AuthConfig represents settings used to authenticate operators.
+	// When it is enabled, requests are accepted with AdminKey, an API key
+	// stored in the database or a bearer token signed with TokenSecret.
+	AuthConfig struct {
+		Enabled     bool   `yaml:"enabled" json:"enabled"`
+		AdminKey    string `yaml:"admin_key" json:"admin_key"`
+		TokenSecret string `yaml:"token_secret" json:"token_secret"`
+	}
+)
+
+// setting binds a command-line flag and an environment variable to a field of Config.
+type setting struct {
+	flag  string
+	usage string
+	apply func(cfg *Config, value string) bool
+}
+
+var settings = []setting{
+	{"address", "address of the REST server",
+		func(cfg *Config, value string) bool {
+			cfg.Server.Address = value
+			return true
+		}},
+	{"port", "port number of the REST server",
+		func(cfg *Config, value string) bool {
+			return parsePort(value, &cfg.Server.Port)
+		}},
+	{"shutdown-timeout", "seconds to wait for running requests when the server is terminated",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Server.ShutdownTimeout)
+		}},
+	{"trusted-proxies", "comma separated addresses or CIDR networks of trusted proxies",
+		func(cfg *Config, value string) bool {
+			cfg.Server.TrustedProxies = splitList(value)
+			return true
+		}},
+	{"tls-cert-file", "certificate file used to serve HTTPS",
+		func(cfg *Config, value string) bool {
+			cfg.Server.TLS.CertFile = value
+			return true
+		}},
+	{"tls-key-file", "private key file used to serve HTTPS",
+		func(cfg *Config, value string) bool {
+			cfg.Server.TLS.KeyFile = value
+			return true
+		}},
+	{"tls-client-ca-file", "CA bundle used to verify client certificates",
+		func(cfg *Config, value string) bool {
+			cfg.Server.TLS.ClientCAFile = value
+			return true
+		}},
+	{"tls-client-auth", "client certificate policy (none, request, require, verify_if_given, require_and_verify)",
+		func(cfg *Config, value string) bool {
+			cfg.Server.TLS.ClientAuth = value
+			return true
+		}},
+	{"db-url", "address of the database server (host:port)",
+		func(cfg *Config, value string) bool {
+			cfg.DB.URL = value
+			return true
+		}},
+	{"db-name", "name of the database",
+		func(cfg *Config, value string) bool {
+			cfg.DB.Name = value
+			return true
+		}},
+	{"db-username", "username used to authenticate to the database",
+		func(cfg *Config, value string) bool {
+			cfg.DB.Username = value
+			return true
+		}},
+	{"db-password", "password used to authenticate to the database",
+		func(cfg *Config, value string) bool {
+			cfg.DB.Password = value
+			return true
+		}},
+	{"db-auth-source", "database holding the credentials of the user",
+		func(cfg *Config, value string) bool {
+			cfg.DB.AuthSource = value
+			return true
+		}},
+	{"agent-port", "default port number of service deployment agents",
+		func(cfg *Config, value string) bool {
+			var port int
+			if !parsePort(value, &port) {
+				return false
+			}
+			cfg.Agent.DefaultPort = value
+			return true
+		}},
+	{"agent-tls", "send requests to agents over HTTPS (true or false)",
+		func(cfg *Config, value string) bool {
+			enabled, err := strconv.ParseBool(value)
+			cfg.Agent.TLS.Enabled = enabled
+			return err == nil
+		}},
+	{"agent-tls-ca-file", "CA bundle used to verify certificates of agents",
+		func(cfg *Config, value string) bool {
+			cfg.Agent.TLS.CAFile = value
+			return true
+		}},
+	{"agent-tls-cert-file", "client certificate file presented to agents",
+		func(cfg *Config, value string) bool {
+			cfg.Agent.TLS.CertFile = value
+			return true
+		}},
+	{"agent-tls-key-file", "private key file of the client certificate presented to agents",
+		func(cfg *Config, value string) bool {
+			cfg.Agent.TLS.KeyFile = value
+			return true
+		}},
+	{"agent-reconcile-interval", "seconds between reconciliations of apps on agents, or 0 to disable them",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Agent.ReconcileInterval)
+		}},
+	{"agent-reconcile-concurrency", "maximum number of agents reconciled at a time",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Agent.ReconcileConcurrency)
+		}},
+	{"agent-compose-versions", "comma separated versions of the compose file format which can be deployed",
+		func(cfg *Config, value string) bool {
+			cfg.Agent.ComposeVersions = splitList(value)
+			return true
+		}},
+	{"group-converge-interval", "seconds between convergences of groups toward their desired state, or 0 to disable them",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Group.ConvergeInterval)
+		}},
+	{"group-converge-concurrency", "maximum number of members converged at a time",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Group.ConvergeConcurrency)
+		}},
+	{"health-max-network-latency", "seconds added to the ping interval before an agent is disconnected",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Health.MaxNetworkLatency)
+		}},
+	{"health-mode", "default health check mode of agents (push, pull, both)",
+		func(cfg *Config, value string) bool {
+			cfg.Health.Mode = value
+			return true
+		}},
+	{"health-probe-interval", "seconds between health probes of agents, or 0 to disable probes",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Health.ProbeInterval)
+		}},
+	{"health-probe-timeout", "seconds to wait for the response of a health probe",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Health.ProbeTimeout)
+		}},
+	{"health-probe-concurrency", "maximum number of health probes sent at a time",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Health.ProbeConcurrency)
+		}},
+	{"retention-stale-after", "days after which a disconnected agent becomes stale, or 0 to disable",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Retention.StaleAfter)
+		}},
+	{"retention-delete-after", "days after which a disconnected agent is deleted, or 0 to disable",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Retention.DeleteAfter)
+		}},
+	{"retention-interval", "seconds between applications of the retention policy",
+		func(cfg *Config, value string) bool {
+			return parseNonNegative(value, &cfg.Retention.Interval)
+		}},
+	{"retention-include-stale", "include stale agents in operations on groups (true or false)",
+		func(cfg *Config, value string) bool {
+			include, err := strconv.ParseBool(value)
+			cfg.Retention.IncludeStale = include
+			return err == nil
+		}},
+	{"auth", "require operators to be authenticated (true or false)",
+		func(cfg *Config, value string) bool {
+			enabled, err := strconv.ParseBool(value)
+			cfg.Auth.Enabled = enabled
+			return err == nil
+		}},
+	{"auth-admin-key", "static API key which is always accepted",
+		func(cfg *Config, value string) bool {
+			cfg.Auth.AdminKey = value
+			return true
+		}},
+	{"auth-token-secret", "secret used to verify HMAC-signed bearer tokens",
+		func(cfg *Config, value string) bool {
+			cfg.Auth.TokenSecret = value
+			return true
+		}},
+}
+
+var current Config
+var lookupEnv func(key string) (string, bool)
+
+func init() {
+	current = Default()
+	lookupEnv = os.LookupEnv
+}
+
+// Default returns the built-in settings.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:         "0.0.0.0",
+			Port:            48099,
+			ShutdownTimeout: 30,
+		},
+		DB: DBConfig{
+			URL:  "127.0.0.1:27017",
+			Name: "DeploymentManagerDB",
+		},
+		Agent: AgentConfig{
+			DefaultPort:          "48098",
+			ReconcileInterval:    300,
+			ReconcileConcurrency: 10,
+		},
+		Group: GroupConfig{
+			ConvergeInterval:    60,
+			ConvergeConcurrency: 10,
+		},
+		Health: HealthConfig{
+			MaxNetworkLatency: 3,
+			Mode:              HEALTH_PUSH,
+			ProbeInterval:     30,
+			ProbeTimeout:      5,
+			ProbeConcurrency:  10,
+		},
+		Retention: RetentionConfig{
+			Interval: 3600,
+		},
+	}
+}
+
+// Get returns the settings currently in use.
+func Get() Config {
+	return current
+}
+
+// Set replaces the settings currently in use.
+func Set(cfg Config) {
+	current = cfg
+}
+
+// Load resolves the settings from defaults, a configuration file,
+// environment variables and the given command-line arguments.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	file := flags.String(CONFIG_FILE, "", "path of a configuration file (YAML or JSON)")
+	for _, s := range settings {
+		flags.String(s.flag, "", s.usage+" (env: "+envName(s.flag)+")")
+	}
+	if err := flags.Parse(args); err != nil {
+		return cfg, errors.InvalidParam{Cause: err}
+	}
+
+	path := *file
+	if path == "" {
+		path, _ = lookupEnv(CONFIG_FILE_ENV)
+	}
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	for _, s := range settings {
+		value, exists := lookupEnv(envName(s.flag))
+		if !exists {
+			continue
+		}
+		if !s.apply(&cfg, value) {
+			return cfg, errors.InvalidParam{Message: envName(s.flag) + "=" + value}
+		}
+	}
+
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		if err != nil || f.Name == CONFIG_FILE {
+			return
+		}
+		for _, s := range settings {
+			if s.flag == f.Name {
+				if !s.apply(&cfg, f.Value.String()) {
+					err = errors.InvalidParam{Message: "-" + f.Name + "=" + f.Value.String()}
+				}
+			}
+		}
+	})
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg, validate(cfg)
+}
+
+// loadFile reads a configuration file and overrides the given settings with its contents.
+// A file with '.json' extension is decoded as JSON, and others are decoded as YAML.
+// Unknown keys are rejected in both formats, so that a typo does not leave a setting unchanged.
+func loadFile(path string, cfg *Config) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.IOError{Cause: err}
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+	} else {
+		err = yaml.UnmarshalStrict(data, cfg)
+	}
+	if err != nil {
+		return errors.InvalidParam{Message: path, Cause: err}
+	}
+	return nil
+}
+
+// validate checks whether the resolved settings can be used.
+func validate(cfg Config) error {
+	switch {
+	case cfg.Server.Port <= 0 || cfg.Server.Port > 65535:
+		return errors.InvalidParam{Message: "server port is out of range: " + strconv.Itoa(cfg.Server.Port)}
+	case cfg.Server.ShutdownTimeout < 0:
+		return errors.InvalidParam{Message: "shutdown timeout must not be negative"}
+	case !isProxyList(cfg.Server.TrustedProxies):
+		return errors.InvalidParam{Message: "trusted proxies should be addresses or CIDR networks"}
+	case cfg.DB.URL == "":
+		return errors.InvalidParam{Message: "db url is required"}
+	case cfg.DB.Name == "":
+		return errors.InvalidParam{Message: "db name is required"}
+	case cfg.DB.Password != "" && cfg.DB.Username == "":
+		return errors.InvalidParam{Message: "db password is given without username"}
+	case (cfg.Server.TLS.CertFile == "") != (cfg.Server.TLS.KeyFile == ""):
+		return errors.InvalidParam{Message: "both tls cert file and key file are required"}
+	case !isClientAuth(cfg.Server.TLS.ClientAuth):
+		return errors.InvalidParam{Message: "unknown tls client auth: " + cfg.Server.TLS.ClientAuth}
+	case cfg.Server.TLS.CertFile == "" && (cfg.Server.TLS.ClientCAFile != "" || cfg.Server.TLS.ClientAuth != ""):
+		return errors.InvalidParam{Message: "tls client authentication requires tls cert file and key file"}
+	case cfg.Agent.DefaultPort == "":
+		return errors.InvalidParam{Message: "default agent port is required"}
+	case (cfg.Agent.TLS.CertFile == "") != (cfg.Agent.TLS.KeyFile == ""):
+		return errors.InvalidParam{Message: "both agent tls cert file and key file are required"}
+	case !cfg.Agent.TLS.Enabled && (cfg.Agent.TLS.CAFile != "" || cfg.Agent.TLS.CertFile != ""):
+		return errors.InvalidParam{Message: "agent tls files are given but agent tls is not enabled"}
+	case cfg.Agent.ReconcileInterval < 0:
+		return errors.InvalidParam{Message: "agent reconcile interval must not be negative"}
+	case cfg.Agent.ReconcileInterval > 0 && cfg.Agent.ReconcileConcurrency <= 0:
+		return errors.InvalidParam{Message: "agent reconcile concurrency should be positive"}
+	case cfg.Group.ConvergeInterval < 0:
+		return errors.InvalidParam{Message: "group converge interval must not be negative"}
+	case cfg.Group.ConvergeConcurrency <= 0:
+		return errors.InvalidParam{Message: "group converge concurrency should be positive"}
+	case cfg.Health.MaxNetworkLatency < 0:
+		return errors.InvalidParam{Message: "max network latency must not be negative"}
+	case !IsHealthMode(cfg.Health.Mode):
+		return errors.InvalidParam{Message: "unknown health mode: " + cfg.Health.Mode}
+	case cfg.Health.ProbeInterval < 0:
+		return errors.InvalidParam{Message: "health probe interval must not be negative"}
+	case cfg.Health.ProbeInterval > 0 && (cfg.Health.ProbeTimeout <= 0 || cfg.Health.ProbeTimeout > cfg.Health.ProbeInterval):
+		return errors.InvalidParam{Message: "health probe timeout should be between 1 and the probe interval"}
+	case cfg.Health.ProbeInterval > 0 && cfg.Health.ProbeConcurrency <= 0:
+		return errors.InvalidParam{Message: "health probe concurrency should be positive"}
+	case cfg.Retention.StaleAfter < 0 || cfg.Retention.DeleteAfter < 0:
+		return errors.InvalidParam{Message: "retention days must not be negative"}
+	case cfg.Retention.StaleAfter > 0 && cfg.Retention.DeleteAfter > 0 && cfg.Retention.DeleteAfter < cfg.Retention.StaleAfter:
+		return errors.InvalidParam{Message: "retention delete days should not be less than stale days"}
+	case (cfg.Retention.StaleAfter > 0 || cfg.Retention.DeleteAfter > 0) && cfg.Retention.Interval <= 0:
+		return errors.InvalidParam{Message: "retention interval should be positive"}
+	case cfg.Auth.Enabled && cfg.Auth.AdminKey == "" && cfg.Auth.TokenSecret == "":
+		return errors.InvalidParam{Message: "auth requires admin key or token secret"}
+	case !cfg.Auth.Enabled && (cfg.Auth.AdminKey != "" || cfg.Auth.TokenSecret != ""):
+		return errors.InvalidParam{Message: "auth keys are given but auth is not enabled"}
+	}
+	return nil
+}
+
+// isClientAuth returns true if the value is a supported client certificate policy.
+func isClientAuth(value string) bool {
+	switch value {
+	case "", CLIENT_AUTH_NONE, CLIENT_AUTH_REQUEST, CLIENT_AUTH_REQUIRE,
+		CLIENT_AUTH_VERIFY_IF_GIVEN, CLIENT_AUTH_REQUIRE_AND_VERIFY:
+		return true
+	}
+	return false
+}
+
+// IsHealthMode returns true if the value is a supported health check mode.
+func IsHealthMode(value string) bool {
+	switch value {
+	case HEALTH_PUSH, HEALTH_PULL, HEALTH_BOTH:
+		return true
+	}
+	return false
+}
+
+// isProxyList returns true if every proxy is an IP address or a CIDR network.
+func isProxyList(proxies []string) bool {
+	for _, proxy := range proxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// splitList returns the non-empty items of a comma separated list without surrounding spaces.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envName returns the environment variable bound to the given flag name.
+// e.g., 'db-url' is bound to 'SDAM_DB_URL'.
+func envName(flagName string) string {
+	return ENV_PREFIX + strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+}
+
+// parsePort converts the value to a port number.
+// This function returns false if the value is not a valid port number.
+func parsePort(value string, port *int) bool {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 || n > 65535 {
+		return false
+	}
+	*port = n
+	return true
+}
+
+// parseNonNegative converts the value to a non-negative integer.
+// This function returns false if the value is not a non-negative integer.
+func parseNonNegative(value string, dst *int) bool {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return false
+	}
+	*dst = n
+	return true
+}
@@ -0,0 +1,370 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package config
+
+import (
+	"commons/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type tearDown func()
+
+func setUpEnv(env map[string]string) tearDown {
+	lookupEnv = func(key string) (string, bool) {
+		value, exists := env[key]
+		return value, exists
+	}
+	return func() {
+		lookupEnv = os.LookupEnv
+	}
+}
+
+func writeFile(t *testing.T, name string, contents string) (string, tearDown) {
+	dir, err := ioutil.TempDir("", "sdam-config")
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	path := filepath.Join(dir, name)
+	if err = ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCalledLoadWithoutAnySource_ExpectDefaultReturn(t *testing.T) {
+	tearDown := setUpEnv(nil)
+	defer tearDown()
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Expected config: %v, actual config: %v", Default(), cfg)
+	}
+}
+
+func TestCalledLoadWithYAMLFile_ExpectFileValuesReturn(t *testing.T) {
+	tearDown := setUpEnv(nil)
+	defer tearDown()
+
+	path, removeFile := writeFile(t, "sdam.yaml", `
+server:
+  port: 8080
+  shutdown_timeout_sec: 5
+db:
+  url: mongo:27017
+  name: SDAM
+agent:
+  default_port: "58098"
+health:
+  max_network_latency_sec: 10
+`)
+	defer removeFile()
+
+	cfg, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	expected := Default()
+	expected.Server.Port = 8080
+	expected.Server.ShutdownTimeout = 5
+	expected.DB.URL = "mongo:27017"
+	expected.DB.Name = "SDAM"
+	expected.Agent.DefaultPort = "58098"
+	expected.Health.MaxNetworkLatency = 10
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("Expected config: %v, actual config: %v", expected, cfg)
+	}
+}
+
+func TestCalledLoadWithJSONFileFromEnv_ExpectFileValuesReturn(t *testing.T) {
+	path, removeFile := writeFile(t, "sdam.json", `{"server":{"address":"127.0.0.1"}}`)
+	defer removeFile()
+
+	tearDown := setUpEnv(map[string]string{CONFIG_FILE_ENV: path})
+	defer tearDown()
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	if cfg.Server.Address != "127.0.0.1" {
+		t.Errorf("Expected address: %s, actual address: %s", "127.0.0.1", cfg.Server.Address)
+	}
+}
+
+func TestCalledLoadWithAllSources_ExpectFlagsOverrideEnvOverrideFile(t *testing.T) {
+	path, removeFile := writeFile(t, "sdam.yaml", "server:\n  port: 1000\ndb:\n  name: FILE\n  url: file:27017\n")
+	defer removeFile()
+
+	tearDown := setUpEnv(map[string]string{
+		"SDAM_PORT":    "2000",
+		"SDAM_DB_NAME": "ENV",
+	})
+	defer tearDown()
+
+	cfg, err := Load([]string{"-config", path, "-port", "3000"})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	if cfg.Server.Port != 3000 {
+		t.Errorf("Expected port: %d, actual port: %d", 3000, cfg.Server.Port)
+	}
+	if cfg.DB.Name != "ENV" {
+		t.Errorf("Expected db name: %s, actual db name: %s", "ENV", cfg.DB.Name)
+	}
+	if cfg.DB.URL != "file:27017" {
+		t.Errorf("Expected db url: %s, actual db url: %s", "file:27017", cfg.DB.URL)
+	}
+}
+
+func TestCalledLoadWithTLSSettings_ExpectTLSValuesReturn(t *testing.T) {
+	tearDown := setUpEnv(map[string]string{
+		"SDAM_AGENT_TLS":         "true",
+		"SDAM_AGENT_TLS_CA_FILE": "agent-ca.pem",
+	})
+	defer tearDown()
+
+	cfg, err := Load([]string{"-tls-cert-file", "cert.pem", "-tls-key-file", "key.pem",
+		"-tls-client-ca-file", "ca.pem", "-tls-client-auth", CLIENT_AUTH_VERIFY_IF_GIVEN})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	expectedServer := ServerTLSConfig{"cert.pem", "key.pem", "ca.pem", CLIENT_AUTH_VERIFY_IF_GIVEN}
+	if cfg.Server.TLS != expectedServer {
+		t.Errorf("Expected server tls: %v, actual server tls: %v", expectedServer, cfg.Server.TLS)
+	}
+	expectedAgent := AgentTLSConfig{Enabled: true, CAFile: "agent-ca.pem"}
+	if cfg.Agent.TLS != expectedAgent {
+		t.Errorf("Expected agent tls: %v, actual agent tls: %v", expectedAgent, cfg.Agent.TLS)
+	}
+}
+
+func TestCalledLoadWithTrustedProxies_ExpectProxiesReturn(t *testing.T) {
+	path, removeFile := writeFile(t, "sdam.yaml", "server:\n  trusted_proxies:\n  - 10.0.0.1\n")
+	defer removeFile()
+
+	tearDown := setUpEnv(nil)
+	defer tearDown()
+
+	cfg, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	expected := []string{"10.0.0.1"}
+	if !reflect.DeepEqual(cfg.Server.TrustedProxies, expected) {
+		t.Errorf("Expected proxies: %v, actual proxies: %v", expected, cfg.Server.TrustedProxies)
+	}
+
+	cfg, err = Load([]string{"-config", path, "-trusted-proxies", "10.0.0.1, 192.168.0.0/16"})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	expected = []string{"10.0.0.1", "192.168.0.0/16"}
+	if !reflect.DeepEqual(cfg.Server.TrustedProxies, expected) {
+		t.Errorf("Expected proxies: %v, actual proxies: %v", expected, cfg.Server.TrustedProxies)
+	}
+}
+
+func TestCalledLoadWithComposeVersions_ExpectVersionsReturn(t *testing.T) {
+	tearDown := setUpEnv(map[string]string{
+		"SDAM_AGENT_COMPOSE_VERSIONS": "3.7, 3.8,",
+	})
+	defer tearDown()
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	expected := []string{"3.7", "3.8"}
+	if !reflect.DeepEqual(cfg.Agent.ComposeVersions, expected) {
+		t.Errorf("Expected compose versions: %v, actual compose versions: %v", expected, cfg.Agent.ComposeVersions)
+	}
+}
+
+func TestCalledLoadWithHealthSettings_ExpectHealthValuesReturn(t *testing.T) {
+	tearDown := setUpEnv(map[string]string{
+		"SDAM_HEALTH_MODE": HEALTH_BOTH,
+	})
+	defer tearDown()
+
+	cfg, err := Load([]string{"-health-probe-interval", "60", "-health-probe-timeout", "10", "-health-probe-concurrency", "4"})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	expected := HealthConfig{MaxNetworkLatency: 3, Mode: HEALTH_BOTH, ProbeInterval: 60, ProbeTimeout: 10, ProbeConcurrency: 4}
+	if cfg.Health != expected {
+		t.Errorf("Expected health: %v, actual health: %v", expected, cfg.Health)
+	}
+
+	// Probes are disabled, so the other probe settings are not used.
+	_, err = Load([]string{"-health-probe-interval", "0", "-health-probe-timeout", "0"})
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledLoadWithReconcileSettings_ExpectReconcileValuesReturn(t *testing.T) {
+	tearDown := setUpEnv(map[string]string{
+		"SDAM_AGENT_RECONCILE_INTERVAL": "60",
+	})
+	defer tearDown()
+
+	cfg, err := Load([]string{"-agent-reconcile-concurrency", "4"})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	if cfg.Agent.ReconcileInterval != 60 || cfg.Agent.ReconcileConcurrency != 4 {
+		t.Errorf("Expected reconcile interval and concurrency: 60 4, actual: %d %d",
+			cfg.Agent.ReconcileInterval, cfg.Agent.ReconcileConcurrency)
+	}
+
+	// Reconciliation is disabled, so the concurrency is not used.
+	_, err = Load([]string{"-agent-reconcile-interval", "0", "-agent-reconcile-concurrency", "0"})
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledLoadWithConvergeSettings_ExpectConvergeValuesReturn(t *testing.T) {
+	tearDown := setUpEnv(map[string]string{
+		"SDAM_GROUP_CONVERGE_INTERVAL": "120",
+	})
+	defer tearDown()
+
+	cfg, err := Load([]string{"-group-converge-concurrency", "4"})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	if cfg.Group.ConvergeInterval != 120 || cfg.Group.ConvergeConcurrency != 4 {
+		t.Errorf("Expected converge interval and concurrency: 120 4, actual: %d %d",
+			cfg.Group.ConvergeInterval, cfg.Group.ConvergeConcurrency)
+	}
+}
+
+func TestCalledLoadWithRetentionSettings_ExpectRetentionValuesReturn(t *testing.T) {
+	tearDown := setUpEnv(map[string]string{
+		"SDAM_RETENTION_STALE_AFTER": "7",
+	})
+	defer tearDown()
+
+	cfg, err := Load([]string{"-retention-delete-after", "30", "-retention-include-stale", "true"})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	expected := RetentionConfig{StaleAfter: 7, DeleteAfter: 30, Interval: 3600, IncludeStale: true}
+	if cfg.Retention != expected {
+		t.Errorf("Expected retention: %v, actual retention: %v", expected, cfg.Retention)
+	}
+}
+
+func TestCalledLoadWithAuthSettings_ExpectAuthValuesReturn(t *testing.T) {
+	tearDown := setUpEnv(map[string]string{
+		"SDAM_AUTH_TOKEN_SECRET": "secret",
+	})
+	defer tearDown()
+
+	cfg, err := Load([]string{"-auth", "true", "-auth-admin-key", "admin"})
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	expected := AuthConfig{Enabled: true, AdminKey: "admin", TokenSecret: "secret"}
+	if cfg.Auth != expected {
+		t.Errorf("Expected auth: %v, actual auth: %v", expected, cfg.Auth)
+	}
+}
+
+func TestCalledLoadWithInvalidValues_ExpectErrorReturn(t *testing.T) {
+	path, removeFile := writeFile(t, "sdam.yaml", "server:\n  unknown: 1\n")
+	defer removeFile()
+	jsonPath, removeJSONFile := writeFile(t, "sdam.json", `{"auth":{"enabeld":true}}`)
+	defer removeJSONFile()
+
+	testList := []struct {
+		name string
+		env  map[string]string
+		args []string
+	}{
+		{"InvalidEnvPort", map[string]string{"SDAM_PORT": "port"}, nil},
+		{"InvalidFlagPort", nil, []string{"-port", "70000"}},
+		{"InvalidAgentPort", nil, []string{"-agent-port", "0"}},
+		{"NegativeLatency", nil, []string{"-health-max-network-latency", "-1"}},
+		{"UnknownHealthMode", map[string]string{"SDAM_HEALTH_MODE": "poll"}, nil},
+		{"ProbeTimeoutOverInterval", nil, []string{"-health-probe-interval", "5", "-health-probe-timeout", "10"}},
+		{"ZeroProbeConcurrency", nil, []string{"-health-probe-concurrency", "0"}},
+		{"ZeroReconcileConcurrency", map[string]string{"SDAM_AGENT_RECONCILE_CONCURRENCY": "0"}, nil},
+		{"ZeroConvergeConcurrency", nil, []string{"-group-converge-concurrency", "0"}},
+		{"DeleteBeforeStale", nil, []string{"-retention-stale-after", "30", "-retention-delete-after", "7"}},
+		{"ZeroRetentionInterval", nil, []string{"-retention-delete-after", "7", "-retention-interval", "0"}},
+		{"InvalidTrustedProxy", nil, []string{"-trusted-proxies", "10.0.0.1,proxy.local"}},
+		{"NegativeShutdownTimeout", map[string]string{"SDAM_SHUTDOWN_TIMEOUT": "-5"}, nil},
+		{"PasswordWithoutUsername", map[string]string{"SDAM_DB_PASSWORD": "secret"}, nil},
+		{"CertWithoutKey", nil, []string{"-tls-cert-file", "cert.pem"}},
+		{"UnknownClientAuth", nil, []string{"-tls-cert-file", "cert.pem", "-tls-key-file", "key.pem", "-tls-client-auth", "always"}},
+		{"ClientAuthWithoutCert", map[string]string{"SDAM_TLS_CLIENT_AUTH": "require"}, nil},
+		{"InvalidAgentTLS", map[string]string{"SDAM_AGENT_TLS": "yes please"}, nil},
+		{"AgentCAWithoutTLS", nil, []string{"-agent-tls-ca-file", "ca.pem"}},
+		{"AuthWithoutCredentials", map[string]string{"SDAM_AUTH": "true"}, nil},
+		{"AdminKeyWithoutAuth", nil, []string{"-auth-admin-key", "secret"}},
+		{"UnknownFlag", nil, []string{"-unknown", "value"}},
+		{"UnknownFileKey", nil, []string{"-config", path}},
+		{"UnknownJSONFileKey", nil, []string{"-config", jsonPath}},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			tearDown := setUpEnv(test.env)
+			defer tearDown()
+
+			_, err := Load(test.args)
+			switch err.(type) {
+			default:
+				t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+			case errors.InvalidParam:
+			}
+		})
+	}
+}
+
+func TestCalledLoadWithNotExistingFile_ExpectErrorReturn(t *testing.T) {
+	tearDown := setUpEnv(nil)
+	defer tearDown()
+
+	_, err := Load([]string{"-config", "/not/existing/sdam.yaml"})
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "IOError", err)
+	case errors.IOError:
+	}
+}
@@ -16,105 +16,474 @@
  *******************************************************************************/
 
 // Package commons/errors defines error structs of Service Deployment Agent Manager.
+//
+// Every error has a stable code to be checked by clients, the kind and the id of
+// the resource which caused the error, and an optional cause wrapped by the error.
+// The cause can be inspected with Is and As.
 package errors
 
+import goerrors "errors"
+
+const (
+	NOT_FOUND_URL         = "not_found_url"
+	INVALID_METHOD        = "invalid_method"
+	UNAUTHORIZED          = "unauthorized"
+	FORBIDDEN             = "forbidden"
+	INVALID_PARAM         = "invalid_param"
+	INVALID_JSON          = "invalid_json"
+	INVALID_OBJECT_ID     = "invalid_object_id"
+	NOT_FOUND             = "not_found"
+	DB_CONNECTION_ERROR   = "db_connection_error"
+	DB_OPERATION_ERROR    = "db_operation_error"
+	IO_ERROR              = "io_error"
+	INTERNAL_SERVER_ERROR = "internal_server_error"
+)
+
+const (
+	AGENT    = "agent"    // used to indicate the kind of agent resources.
+	GROUP    = "group"    // used to indicate the kind of group resources.
+	APP      = "app"      // used to indicate the kind of app resources.
+	KEY      = "key"      // used to indicate the kind of api key resources.
+	EVENT    = "event"    // used to indicate the kind of agent event resources.
+	CATALOG  = "catalog"  // used to indicate the kind of catalog app resources.
+	REVISION = "revision" // used to indicate the kind of app revision resources.
+)
+
+// Error is implemented by all errors of this package.
+type Error interface {
+	error
+
+	// Code returns a stable code of the error.
+	Code() string
+
+	// Resource returns the kind and the id of the resource which caused the error.
+	Resource() (kind string, id string)
+
+	// Unwrap returns the cause of the error.
+	Unwrap() error
+}
+
+// Is reports whether any error in the chain of err matches target.
+// An error of this package matches target of the same type if the kind and the id
+// of target are empty or equal, e.g. Is(err, NotFound{Kind: AGENT}).
+func Is(err error, target error) bool {
+	return goerrors.Is(err, target)
+}
+
+// As finds the first error in the chain of err that matches target,
+// and if so, sets target to that error value and returns true.
+func As(err error, target interface{}) bool {
+	return goerrors.As(err, target)
+}
+
+// describe returns a message followed by the resource and the cause of an error.
+func describe(message string, kind string, id string, cause error) string {
+	resource := kind
+	if id != "" {
+		if resource != "" {
+			resource += " "
+		}
+		resource += id
+	}
+	if message == "" {
+		message = resource
+	} else if resource != "" {
+		message += " (" + resource + ")"
+	}
+
+	if cause != nil {
+		if message == "" {
+			return cause.Error()
+		}
+		message += ": " + cause.Error()
+	}
+	return message
+}
+
+// matches returns true if target has the same code and matching resource.
+func matches(err Error, target error) bool {
+	other, ok := target.(Error)
+	if !ok || other.Code() != err.Code() {
+		return false
+	}
+
+	kind, id := err.Resource()
+	otherKind, otherId := other.Resource()
+	return (otherKind == "" || otherKind == kind) && (otherId == "" || otherId == id)
+}
+
 // Struct NotFoundURL will be used for return case of error
 // which value of unknown or invalid url.
 type NotFoundURL struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of NotFoundURL.
 func (e NotFoundURL) Error() string {
-	return "unsupported url: " + e.Message
+	return "unsupported url: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns NOT_FOUND_URL as a code of NotFoundURL.
+func (e NotFoundURL) Code() string {
+	return NOT_FOUND_URL
+}
+
+// Resource returns the kind and the id of the resource.
+func (e NotFoundURL) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of NotFoundURL.
+func (e NotFoundURL) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is NotFoundURL with matching resource.
+func (e NotFoundURL) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct InvalidMethod will be used for return case of error
 // which method of request is not provide.
 type InvalidMethod struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of InvalidMethod.
 func (e InvalidMethod) Error() string {
-	return "invalid method: " + e.Message
+	return "invalid method: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns INVALID_METHOD as a code of InvalidMethod.
+func (e InvalidMethod) Code() string {
+	return INVALID_METHOD
+}
+
+// Resource returns the kind and the id of the resource.
+func (e InvalidMethod) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of InvalidMethod.
+func (e InvalidMethod) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is InvalidMethod with matching resource.
+func (e InvalidMethod) Is(target error) bool {
+	return matches(e, target)
+}
+
+// Struct Unauthorized will be used for return case of error
+// which a request does not carry valid credentials.
+type Unauthorized struct {
+	Message string
+	Kind    string
+	ID      string
+	Cause   error
+}
+
+// Error sets an error message of Unauthorized.
+func (e Unauthorized) Error() string {
+	return "unauthorized: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns UNAUTHORIZED as a code of Unauthorized.
+func (e Unauthorized) Code() string {
+	return UNAUTHORIZED
+}
+
+// Resource returns the kind and the id of the resource.
+func (e Unauthorized) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of Unauthorized.
+func (e Unauthorized) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is Unauthorized with matching resource.
+func (e Unauthorized) Is(target error) bool {
+	return matches(e, target)
+}
+
+// Struct Forbidden will be used for return case of error
+// which an authenticated caller is not permitted to perform a request.
+type Forbidden struct {
+	Message string
+	Kind    string
+	ID      string
+	Cause   error
+}
+
+// Error sets an error message of Forbidden.
+func (e Forbidden) Error() string {
+	return "forbidden: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns FORBIDDEN as a code of Forbidden.
+func (e Forbidden) Code() string {
+	return FORBIDDEN
+}
+
+// Resource returns the kind and the id of the resource.
+func (e Forbidden) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of Forbidden.
+func (e Forbidden) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is Forbidden with matching resource.
+func (e Forbidden) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct InvalidParam will be used for return case of error
 // which value of unknown or invalid type, range in the parameters.
 type InvalidParam struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of InvalidParam.
 func (e InvalidParam) Error() string {
-	return "invalid parameter: " + e.Message
+	return "invalid parameter: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns INVALID_PARAM as a code of InvalidParam.
+func (e InvalidParam) Code() string {
+	return INVALID_PARAM
+}
+
+// Resource returns the kind and the id of the resource.
+func (e InvalidParam) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of InvalidParam.
+func (e InvalidParam) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is InvalidParam with matching resource.
+func (e InvalidParam) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct InvalidJSON will be used for return case of error
 // which value of malformed json format.
 type InvalidJSON struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of InvalidJSON.
 func (e InvalidJSON) Error() string {
-	return "invalid json format: " + e.Message
+	return "invalid json format: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns INVALID_JSON as a code of InvalidJSON.
+func (e InvalidJSON) Code() string {
+	return INVALID_JSON
+}
+
+// Resource returns the kind and the id of the resource.
+func (e InvalidJSON) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of InvalidJSON.
+func (e InvalidJSON) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is InvalidJSON with matching resource.
+func (e InvalidJSON) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct InvalidJSON will be used for return case of error
 // which value of invalid ObjectId.
 type InvalidObjectId struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of InvalidObjectId.
 func (e InvalidObjectId) Error() string {
-	return "invalid objectId: " + e.Message
+	return "invalid objectId: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns INVALID_OBJECT_ID as a code of InvalidObjectId.
+func (e InvalidObjectId) Code() string {
+	return INVALID_OBJECT_ID
+}
+
+// Resource returns the kind and the id of the resource.
+func (e InvalidObjectId) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of InvalidObjectId.
+func (e InvalidObjectId) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is InvalidObjectId with matching resource.
+func (e InvalidObjectId) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct NotFound will be used for return case of error
 // which object or target can not found.
 type NotFound struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of NotFound.
 func (e NotFound) Error() string {
-	return "not found target: " + e.Message
+	return "not found target: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns NOT_FOUND as a code of NotFound.
+func (e NotFound) Code() string {
+	return NOT_FOUND
+}
+
+// Resource returns the kind and the id of the resource.
+func (e NotFound) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of NotFound.
+func (e NotFound) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is NotFound with matching resource.
+func (e NotFound) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct DBConnectionError will be used for return case of error
 // which connection failed with db server.
 type DBConnectionError struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of DBConnectionError.
 func (e DBConnectionError) Error() string {
-	return "db connection failed: " + e.Message
+	return "db connection failed: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns DB_CONNECTION_ERROR as a code of DBConnectionError.
+func (e DBConnectionError) Code() string {
+	return DB_CONNECTION_ERROR
+}
+
+// Resource returns the kind and the id of the resource.
+func (e DBConnectionError) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of DBConnectionError.
+func (e DBConnectionError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is DBConnectionError with matching resource.
+func (e DBConnectionError) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct DBOperationError will be used for return case of error
 // which db operation failed(e.g., insert, update, delete).
 type DBOperationError struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of DBOperationError.
 func (e DBOperationError) Error() string {
-	return "db operation failed: " + e.Message
+	return "db operation failed: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns DB_OPERATION_ERROR as a code of DBOperationError.
+func (e DBOperationError) Code() string {
+	return DB_OPERATION_ERROR
+}
+
+// Resource returns the kind and the id of the resource.
+func (e DBOperationError) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of DBOperationError.
+func (e DBOperationError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is DBOperationError with matching resource.
+func (e DBOperationError) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct IOError will be used for return case of error
 // which IO operaion fail like file operation failed or json marshalling failed.
 type IOError struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of IOError.
 func (e IOError) Error() string {
-	return "io error: " + e.Message
+	return "io error: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns IO_ERROR as a code of IOError.
+func (e IOError) Code() string {
+	return IO_ERROR
+}
+
+// Resource returns the kind and the id of the resource.
+func (e IOError) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of IOError.
+func (e IOError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is IOError with matching resource.
+func (e IOError) Is(target error) bool {
+	return matches(e, target)
 }
 
 // Struct InternalServerError will be used for return case of error
@@ -122,9 +491,32 @@ func (e IOError) Error() string {
 // and no more specific message is suitable.
 type InternalServerError struct {
 	Message string
+	Kind    string
+	ID      string
+	Cause   error
 }
 
 // Error sets an error message of InternalServerError.
 func (e InternalServerError) Error() string {
-	return "internal server error: " + e.Message
+	return "internal server error: " + describe(e.Message, e.Kind, e.ID, e.Cause)
+}
+
+// Code returns INTERNAL_SERVER_ERROR as a code of InternalServerError.
+func (e InternalServerError) Code() string {
+	return INTERNAL_SERVER_ERROR
+}
+
+// Resource returns the kind and the id of the resource.
+func (e InternalServerError) Resource() (string, string) {
+	return e.Kind, e.ID
+}
+
+// Unwrap returns the cause of InternalServerError.
+func (e InternalServerError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is InternalServerError with matching resource.
+func (e InternalServerError) Is(target error) bool {
+	return matches(e, target)
 }
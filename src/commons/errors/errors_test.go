@@ -17,6 +17,8 @@
 package errors
 
 import (
+	goerrors "errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -36,25 +38,29 @@ func TestTError(t *testing.T) {
 
 	testList := []testObj{
 		{testName: "NotFoundURL", testPrefix: "unsupported url",
-			testError: &NotFoundURL{msg}},
+			testError: &NotFoundURL{Message: msg}},
 		{testName: "InvalidMethod", testPrefix: "invalid method",
-			testError: &InvalidMethod{msg}},
+			testError: &InvalidMethod{Message: msg}},
+		{testName: "Unauthorized", testPrefix: "unauthorized",
+			testError: &Unauthorized{Message: msg}},
+		{testName: "Forbidden", testPrefix: "forbidden",
+			testError: &Forbidden{Message: msg}},
 		{testName: "InvalidParam", testPrefix: "invalid parameter",
-			testError: &InvalidParam{msg}},
+			testError: &InvalidParam{Message: msg}},
 		{testName: "InvalidJSON", testPrefix: "invalid json format",
-			testError: &InvalidJSON{msg}},
+			testError: &InvalidJSON{Message: msg}},
 		{testName: "InvalidObjectId", testPrefix: "invalid objectId",
-			testError: &InvalidObjectId{msg}},
+			testError: &InvalidObjectId{Message: msg}},
 		{testName: "NotFound", testPrefix: "not found target",
-			testError: &NotFound{msg}},
+			testError: &NotFound{Message: msg}},
 		{testName: "DBConnectionError", testPrefix: "db connection failed",
-			testError: &DBConnectionError{msg}},
+			testError: &DBConnectionError{Message: msg}},
 		{testName: "DBOperationError", testPrefix: "db operation failed",
-			testError: &DBOperationError{msg}},
+			testError: &DBOperationError{Message: msg}},
 		{testName: "IOError", testPrefix: "io error",
-			testError: &IOError{msg}},
+			testError: &IOError{Message: msg}},
 		{testName: "InternalServerError", testPrefix: "internal server error",
-			testError: &InternalServerError{msg}},
+			testError: &InternalServerError{Message: msg}},
 	}
 
 	testFunc := func(err commonsError, prefix string) {
@@ -72,3 +78,56 @@ func TestTError(t *testing.T) {
 		})
 	}
 }
+
+func TestCalledErrorWithResourceAndCause_ExpectDetailsReturn(t *testing.T) {
+	cause := goerrors.New("not found")
+	err := NotFound{Kind: AGENT, ID: "agent", Cause: cause}
+
+	if err.Error() != "not found target: agent agent: not found" {
+		t.Errorf("Unexpected message: %s", err.Error())
+	}
+
+	if err.Code() != NOT_FOUND {
+		t.Errorf("Expected code: %s, actual code: %s", NOT_FOUND, err.Code())
+	}
+
+	if kind, id := err.Resource(); kind != AGENT || id != "agent" {
+		t.Errorf("Unexpected resource: %s %s", kind, id)
+	}
+
+	if !Is(err, cause) {
+		t.Error("Expected the cause is unwrapped")
+	}
+
+	message := InvalidParam{Message: "body is not a valid json", Cause: cause}.Error()
+	if message != "invalid parameter: body is not a valid json: not found" {
+		t.Errorf("Unexpected message: %s", message)
+	}
+}
+
+func TestCalledIs_ExpectMatchedByCodeAndResource(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", NotFound{Kind: GROUP, ID: "group"})
+
+	testList := []struct {
+		target  error
+		matched bool
+	}{
+		{NotFound{}, true},
+		{NotFound{Kind: GROUP}, true},
+		{NotFound{Kind: GROUP, ID: "group"}, true},
+		{NotFound{Kind: AGENT}, false},
+		{NotFound{Kind: GROUP, ID: "other"}, false},
+		{InvalidParam{}, false},
+	}
+
+	for _, test := range testList {
+		if matched := Is(err, test.target); matched != test.matched {
+			t.Errorf("Expected matched: %t, actual matched: %t for %v", test.matched, matched, test.target)
+		}
+	}
+
+	var notFound NotFound
+	if !As(err, &notFound) || notFound.ID != "group" {
+		t.Errorf("Unexpected error: %v", notFound)
+	}
+}
@@ -0,0 +1,100 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+// Package commons/labels validates labels of agents and parses label selectors.
+// A selector is a comma separated list of key=value pairs, e.g. 'site=plant3,arch=arm64',
+// which matches agents having all of the labels.
+package labels
+
+import (
+	"commons/errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	FILTER_PREFIX = "label." // prefix of a filter of agents by a label, e.g. 'label.site'.
+	MAX_LENGTH    = 63       // the maximum length of a key or a value of a label.
+)
+
+var (
+	keyPattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_-]*[A-Za-z0-9])?$`)
+	valuePattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9_.-]*[A-Za-z0-9])?)?$`)
+)
+
+// ValidKey returns true if the key can be used as a key of a label.
+// A key consists of alphanumerics, '-' and '_', and begins and ends with an alphanumeric.
+func ValidKey(key string) bool {
+	return len(key) <= MAX_LENGTH && keyPattern.MatchString(key)
+}
+
+// ValidValue returns true if the value can be used as a value of a label.
+// A value may be empty, and '.' is also allowed in addition to the characters of a key.
+func ValidValue(value string) bool {
+	return len(value) <= MAX_LENGTH && valuePattern.MatchString(value)
+}
+
+// Validate checks keys and values of the labels.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func Validate(labels map[string]string) error {
+	for key, value := range labels {
+		if !ValidKey(key) {
+			return errors.InvalidParam{Message: "invalid label key: " + key}
+		}
+		if !ValidValue(value) {
+			return errors.InvalidParam{Message: "invalid label value: " + key + "=" + value}
+		}
+	}
+	return nil
+}
+
+// ParseSelector returns the labels required by the selector.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func ParseSelector(selector string) (map[string]string, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, errors.InvalidParam{Message: "selector should not be empty"}
+	}
+
+	required := make(map[string]string)
+	for _, requirement := range strings.Split(selector, ",") {
+		pair := strings.SplitN(requirement, "=", 2)
+		if len(pair) != 2 {
+			return nil, errors.InvalidParam{Message: "invalid selector: " + requirement + " should be key=value"}
+		}
+
+		key, value := strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1])
+		if _, exists := required[key]; exists {
+			return nil, errors.InvalidParam{Message: "invalid selector: duplicated key " + key}
+		}
+		required[key] = value
+	}
+
+	if err := Validate(required); err != nil {
+		return nil, err
+	}
+	return required, nil
+}
+
+// ToFilter returns the filter of agents having all of the labels.
+func ToFilter(labels map[string]string) map[string]string {
+	filter := make(map[string]string, len(labels))
+	for key, value := range labels {
+		filter[FILTER_PREFIX+key] = value
+	}
+	return filter
+}
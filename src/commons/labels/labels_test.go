@@ -0,0 +1,110 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package labels
+
+import (
+	"commons/errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCalledValidateWithValidLabels_ExpectSuccess(t *testing.T) {
+	testList := []map[string]string{
+		nil,
+		{"site": "plant3"},
+		{"arch": "arm64", "sda-version": "1.0.0", "rack_no": ""},
+		{strings.Repeat("k", MAX_LENGTH): strings.Repeat("v", MAX_LENGTH)},
+	}
+
+	for _, labels := range testList {
+		err := Validate(labels)
+
+		if err != nil {
+			t.Errorf("Unexpected err: %s", err.Error())
+		}
+	}
+}
+
+func TestCalledValidateWithInvalidLabels_ExpectErrorReturn(t *testing.T) {
+	testList := []map[string]string{
+		{"": "plant3"},
+		{"site.name": "plant3"},
+		{"$site": "plant3"},
+		{"-site": "plant3"},
+		{"site": "plant 3"},
+		{"site": "plant3."},
+		{"site": "plant=3"},
+		{strings.Repeat("k", MAX_LENGTH+1): "v"},
+		{"k": strings.Repeat("v", MAX_LENGTH+1)},
+	}
+
+	for _, labels := range testList {
+		err := Validate(labels)
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %v", "InvalidParam", err, labels)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestCalledParseSelector_ExpectRequiredLabelsReturn(t *testing.T) {
+	testList := []struct {
+		selector string
+		expected map[string]string
+	}{
+		{"site=plant3", map[string]string{"site": "plant3"}},
+		{"site=plant3,arch=arm64", map[string]string{"site": "plant3", "arch": "arm64"}},
+		{" site = plant3 , arch=arm64 ", map[string]string{"site": "plant3", "arch": "arm64"}},
+		{"rack=", map[string]string{"rack": ""}},
+	}
+
+	for _, test := range testList {
+		required, err := ParseSelector(test.selector)
+
+		if err != nil {
+			t.Errorf("Unexpected err: %s", err.Error())
+		}
+
+		if !reflect.DeepEqual(required, test.expected) {
+			t.Errorf("Expected labels: %v, actual labels: %v", test.expected, required)
+		}
+	}
+}
+
+func TestCalledParseSelectorWithInvalidSelector_ExpectErrorReturn(t *testing.T) {
+	for _, selector := range []string{"", " ", "site", "site=plant3,", "=plant3", "site=plant3,site=plant4", "site!=plant3", "site=plant=3"} {
+		_, err := ParseSelector(selector)
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, selector)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestCalledToFilter_ExpectPrefixedKeysReturn(t *testing.T) {
+	filter := ToFilter(map[string]string{"site": "plant3", "arch": "arm64"})
+
+	expected := map[string]string{"label.site": "plant3", "label.arch": "arm64"}
+	if !reflect.DeepEqual(filter, expected) {
+		t.Errorf("Expected filter: %v, actual filter: %v", expected, filter)
+	}
+}
@@ -19,6 +19,8 @@
 package logger
 
 import (
+	"commons/requestid"
+	"context"
 	"log"
 	"os"
 	"path"
@@ -46,7 +48,19 @@ const (
 
 // Logging prints log stream on standard output with file name and function name, line.
 func Logging(level int, msgs ...string) {
-	pc, file, line, _ := runtime.Caller(1)
+	output(nil, level, msgs)
+}
+
+// LoggingContext prints log stream like Logging, with the request id carried by ctx.
+// It should be used while handling a request, so that all logs of the request
+// can be correlated by the request id.
+func LoggingContext(ctx context.Context, level int, msgs ...string) {
+	output(ctx, level, msgs)
+}
+
+// output prints log stream with the caller of Logging or LoggingContext.
+func output(ctx context.Context, level int, msgs []string) {
+	pc, file, line, _ := runtime.Caller(2)
 	_, fileName := path.Split(file)
 	parts := strings.Split(runtime.FuncForPC(pc).Name(), ".")
 	pl := len(parts)
@@ -60,5 +74,9 @@ func Logging(level int, msgs ...string) {
 		packageName = strings.Join(parts[0:pl-1], ".")
 	}
 
+	if id := requestid.FromContext(ctx); id != "" {
+		loggers[level].Println("["+id+"]", packageName, fileName, funcName, ":", strconv.Itoa(line), msgs)
+		return
+	}
 	loggers[level].Println(packageName, fileName, funcName, ":", strconv.Itoa(line), msgs)
 }
@@ -17,6 +17,8 @@
 package logger
 
 import (
+	"commons/requestid"
+	"context"
 	"io/ioutil"
 	"log"
 	"os"
@@ -79,3 +81,19 @@ func TestLogger(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggingContext(t *testing.T) {
+	tearDown, r, w := setUpLogging()
+	defer tearDown()
+
+	ctx := requestid.NewContext(context.Background(), "request")
+	LoggingContext(ctx, DEBUG, "test")
+	str := getPrintString(r, w)
+
+	if !strings.HasPrefix(str, "[DEBUG][SDAM]") || !strings.Contains(str, "[request] commons/logger logger_test.go TestLoggingContext") {
+		t.Errorf("Unexpected log: %s", str)
+	}
+	if !strings.HasSuffix(str, "[test]\n") {
+		t.Errorf("Unexpected log: %s", str)
+	}
+}
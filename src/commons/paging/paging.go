@@ -0,0 +1,85 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package commons/paging parses query parameters used to filter, sort and paginate
+// a list of resources, e.g. '/api/v1/agents?status=connected&sort=-host&limit=20'.
+package paging
+
+import (
+	"commons/errors"
+	"net/url"
+	"strconv"
+)
+
+const (
+	SORT   = "sort"   // used to indicate a field to sort by. A leading '-' means descending.
+	LIMIT  = "limit"  // used to indicate the maximum number of items in a page.
+	CURSOR = "cursor" // used to indicate the position following the last item of a previous page.
+	NEXT   = "next"   // used to indicate the cursor of a next page in responses.
+)
+
+// Options represents a filter and a page of a list.
+type Options struct {
+	Filter map[string]string
+	Sort   string
+	Limit  int
+	Cursor string
+}
+
+// Parse returns options parsed from query parameters.
+// Only the names in filters are accepted as filter parameters,
+// and each parameter must be given at most once.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func Parse(query url.Values, filters ...string) (Options, error) {
+	options := Options{Filter: make(map[string]string)}
+
+	for name, values := range query {
+		if len(values) != 1 {
+			return Options{}, errors.InvalidParam{Message: "duplicated query parameter: " + name}
+		}
+		value := values[0]
+
+		switch {
+		case name == SORT:
+			options.Sort = value
+		case name == LIMIT:
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				return Options{}, errors.InvalidParam{Message: "limit should be a non-negative integer"}
+			}
+			options.Limit = limit
+		case name == CURSOR:
+			options.Cursor = value
+		case contains(filters, name):
+			options.Filter[name] = value
+		default:
+			return Options{}, errors.InvalidParam{Message: "unsupported query parameter: " + name}
+		}
+	}
+	return options, nil
+}
+
+// contains returns true if the list includes the name.
+func contains(list []string, name string) bool {
+	for _, item := range list {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
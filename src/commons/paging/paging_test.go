@@ -0,0 +1,56 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package paging
+
+import (
+	"commons/errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCalledParse_ExpectOptionsReturn(t *testing.T) {
+	query, _ := url.ParseQuery("status=connected&sort=-host&limit=20&cursor=abc")
+
+	options, err := Parse(query, "status", "host")
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	expected := Options{
+		Filter: map[string]string{"status": "connected"},
+		Sort:   "-host",
+		Limit:  20,
+		Cursor: "abc",
+	}
+	if !reflect.DeepEqual(options, expected) {
+		t.Errorf("Expected options: %v, actual options: %v", expected, options)
+	}
+}
+
+func TestCalledParseWithInvalidQuery_ExpectErrorReturn(t *testing.T) {
+	for _, rawQuery := range []string{"limit=-1", "limit=ten", "status=a&status=b", "port=8888"} {
+		query, _ := url.ParseQuery(rawQuery)
+
+		_, err := Parse(query, "status")
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, rawQuery)
+		case errors.InvalidParam:
+		}
+	}
+}
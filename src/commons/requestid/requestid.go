@@ -0,0 +1,81 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package commons/requestid carries the id of a request, which is used to correlate
+// logs of Service Deployment Agent Manager and agents handling the same request.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+)
+
+const (
+	HEADER     = "X-Request-ID" // used to indicate the header of a request id.
+	MAX_LENGTH = 128            // the maximum length of a request id accepted from clients.
+	ID_BYTES   = 16             // the number of random bytes of a generated request id.
+)
+
+type contextKey struct{}
+
+var randReader io.Reader
+
+func init() {
+	randReader = rand.Reader
+}
+
+// New generates a new random request id.
+// If random bytes are not available, an empty string will be returned.
+func New() string {
+	random := make([]byte, ID_BYTES)
+	if _, err := io.ReadFull(randReader, random); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(random)
+}
+
+// Valid returns true if the id can be used as a request id.
+// A request id should consist of up to MAX_LENGTH printable ASCII characters
+// without spaces, so that it is safely written to logs and headers.
+func Valid(id string) bool {
+	if len(id) == 0 || len(id) > MAX_LENGTH {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx which carries the request id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id carried by ctx.
+// If ctx is nil or does not carry a request id, an empty string will be returned.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
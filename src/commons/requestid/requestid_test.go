@@ -0,0 +1,82 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestCalledNew_ExpectRandomIdReturn(t *testing.T) {
+	first, second := New(), New()
+
+	if len(first) != 2*ID_BYTES || !Valid(first) {
+		t.Errorf("Unexpected id: %s", first)
+	}
+
+	if first == second {
+		t.Errorf("Expected different ids, actual ids: %s, %s", first, second)
+	}
+}
+
+func TestCalledNewWhenRandomIsNotAvailable_ExpectEmptyIdReturn(t *testing.T) {
+	randReader = bytes.NewReader(nil)
+	defer func() { randReader = rand.Reader }()
+
+	if id := New(); id != "" {
+		t.Errorf("Unexpected id: %s", id)
+	}
+}
+
+func TestCalledValid_ExpectPrintableIdAccepted(t *testing.T) {
+	testList := []struct {
+		id    string
+		valid bool
+	}{
+		{"6f1c1a2e-8d3b-4c5f", true},
+		{strings.Repeat("a", MAX_LENGTH), true},
+		{strings.Repeat("a", MAX_LENGTH+1), false},
+		{"", false},
+		{"with space", false},
+		{"line\nbreak", false},
+	}
+
+	for _, test := range testList {
+		if valid := Valid(test.id); valid != test.valid {
+			t.Errorf("Expected valid: %t, actual valid: %t for %q", test.valid, valid, test.id)
+		}
+	}
+}
+
+func TestCalledFromContext_ExpectIdCarriedByContextReturn(t *testing.T) {
+	ctx := NewContext(context.Background(), "request")
+
+	if id := FromContext(ctx); id != "request" {
+		t.Errorf("Expected id: %s, actual id: %s", "request", id)
+	}
+
+	if id := FromContext(context.Background()); id != "" {
+		t.Errorf("Unexpected id: %s", id)
+	}
+
+	if id := FromContext(nil); id != "" {
+		t.Errorf("Unexpected id: %s", id)
+	}
+}
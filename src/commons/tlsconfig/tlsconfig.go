@@ -0,0 +1,118 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package commons/tlsconfig builds TLS settings of the REST server
+// and of the requests sent to agents.
+package tlsconfig
+
+import (
+	"commons/config"
+	"commons/errors"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+)
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"":                                    tls.NoClientCert,
+	config.CLIENT_AUTH_NONE:               tls.NoClientCert,
+	config.CLIENT_AUTH_REQUEST:            tls.RequestClientCert,
+	config.CLIENT_AUTH_REQUIRE:            tls.RequireAnyClientCert,
+	config.CLIENT_AUTH_VERIFY_IF_GIVEN:    tls.VerifyClientCertIfGiven,
+	config.CLIENT_AUTH_REQUIRE_AND_VERIFY: tls.RequireAndVerifyClientCert,
+}
+
+// NewServerConfig returns TLS settings of the REST server.
+// If HTTPS is not configured, this function returns nil without an error.
+// otherwise, an appropriate error will be returned on failure.
+func NewServerConfig(cfg config.ServerTLSConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.IOError{Cause: err}
+	}
+
+	clientAuth, exists := clientAuthTypes[cfg.ClientAuth]
+	if !exists {
+		return nil, errors.InvalidParam{Message: "unknown tls client auth: " + cfg.ClientAuth}
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   clientAuth,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile != "" {
+		tlsConfig.ClientCAs, err = loadCertPool(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+	} else if clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert {
+		return nil, errors.InvalidParam{Message: "tls client ca file is required to verify client certificates"}
+	}
+
+	return tlsConfig, nil
+}
+
+// NewClientConfig returns TLS settings of the requests sent to agents.
+// If HTTPS is not enabled, this function returns nil without an error.
+// otherwise, an appropriate error will be returned on failure.
+func NewClientConfig(cfg config.AgentTLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	var err error
+	if cfg.CAFile != "" {
+		tlsConfig.RootCAs, err = loadCertPool(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, errors.IOError{Cause: err}
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// loadCertPool reads PEM encoded certificates from the given file.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.IOError{Cause: err}
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.InvalidParam{Message: "no certificate is found in " + path}
+	}
+	return pool, nil
+}
@@ -0,0 +1,197 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package tlsconfig
+
+import (
+	"commons/config"
+	"commons/errors"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testFiles struct {
+	dir      string
+	certFile string
+	keyFile  string
+}
+
+// setUp writes a self-signed certificate and its private key to a temporary directory.
+func setUp(t *testing.T) (testFiles, func()) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sdam"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	dir, err := ioutil.TempDir("", "sdam-tls")
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	files := testFiles{
+		dir:      dir,
+		certFile: filepath.Join(dir, "cert.pem"),
+		keyFile:  filepath.Join(dir, "key.pem"),
+	}
+	ioutil.WriteFile(files.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(files.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+
+	return files, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCalledNewServerConfigWithoutCert_ExpectNilReturn(t *testing.T) {
+	tlsConfig, err := NewServerConfig(config.ServerTLSConfig{})
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if tlsConfig != nil {
+		t.Error("Expected nil tls config")
+	}
+}
+
+func TestCalledNewServerConfigWithClientCA_ExpectVerifyingConfigReturn(t *testing.T) {
+	files, tearDown := setUp(t)
+	defer tearDown()
+
+	tlsConfig, err := NewServerConfig(config.ServerTLSConfig{
+		CertFile:     files.certFile,
+		KeyFile:      files.keyFile,
+		ClientCAFile: files.certFile,
+		ClientAuth:   config.CLIENT_AUTH_REQUIRE_AND_VERIFY,
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Errorf("Expected certificates: %d, actual certificates: %d", 1, len(tlsConfig.Certificates))
+	}
+	if tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("Expected client auth: %v, actual client auth: %v", tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	}
+	if tlsConfig.ClientCAs == nil {
+		t.Error("Expected client CAs")
+	}
+}
+
+func TestCalledNewServerConfigWithVerifyWithoutClientCA_ExpectErrorReturn(t *testing.T) {
+	files, tearDown := setUp(t)
+	defer tearDown()
+
+	_, err := NewServerConfig(config.ServerTLSConfig{
+		CertFile:   files.certFile,
+		KeyFile:    files.keyFile,
+		ClientAuth: config.CLIENT_AUTH_VERIFY_IF_GIVEN,
+	})
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledNewServerConfigWithNotExistingFile_ExpectErrorReturn(t *testing.T) {
+	_, err := NewServerConfig(config.ServerTLSConfig{
+		CertFile: "/not/existing/cert.pem",
+		KeyFile:  "/not/existing/key.pem",
+	})
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "IOError", err)
+	case errors.IOError:
+	}
+}
+
+func TestCalledNewClientConfigWhenDisabled_ExpectNilReturn(t *testing.T) {
+	tlsConfig, err := NewClientConfig(config.AgentTLSConfig{})
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	if tlsConfig != nil {
+		t.Error("Expected nil tls config")
+	}
+}
+
+func TestCalledNewClientConfigWithCAAndCert_ExpectConfigReturn(t *testing.T) {
+	files, tearDown := setUp(t)
+	defer tearDown()
+
+	tlsConfig, err := NewClientConfig(config.AgentTLSConfig{
+		Enabled:  true,
+		CAFile:   files.certFile,
+		CertFile: files.certFile,
+		KeyFile:  files.keyFile,
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+	if tlsConfig.RootCAs == nil {
+		t.Error("Expected root CAs")
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Errorf("Expected certificates: %d, actual certificates: %d", 1, len(tlsConfig.Certificates))
+	}
+}
+
+func TestCalledNewClientConfigWithInvalidCAFile_ExpectErrorReturn(t *testing.T) {
+	files, tearDown := setUp(t)
+	defer tearDown()
+
+	_, err := NewClientConfig(config.AgentTLSConfig{
+		Enabled: true,
+		CAFile:  files.keyFile,
+	})
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
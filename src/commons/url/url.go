@@ -58,4 +58,55 @@ func Register() string { return "/register" }
 func Unregister() string { return "/unregister" }
 
 // Base returns the ping url as a type of string.
-func Ping() string { return "/ping" }
\ No newline at end of file
+func Ping() string { return "/ping" }
+
+// Health returns the health url of agents as a type of string.
+func Health() string { return "/health" }
+
+// HealthCheck returns the healthcheck url as a type of string.
+func HealthCheck() string { return "/healthcheck" }
+
+// Secret returns the secret url of agents as a type of string.
+func Secret() string { return "/secret" }
+
+// Decommission returns the decommission url as a type of string.
+func Decommission() string { return "/decommission" }
+
+// Reconcile returns the reconcile url as a type of string.
+func Reconcile() string { return "/reconcile" }
+
+// Retention returns the retention url as a type of string.
+func Retention() string { return "/retention" }
+
+// Admin returns the admin url as a type of string.
+func Admin() string { return "/admin" }
+
+// Labels returns the labels url as a type of string.
+func Labels() string { return "/labels" }
+
+// Events returns the events url as a type of string.
+func Events() string { return "/events" }
+
+// Availability returns the availability url as a type of string.
+func Availability() string { return "/availability" }
+
+// Keys returns the keys url as a type of string.
+func Keys() string { return "/keys" }
+
+// Catalog returns the catalog url as a type of string.
+func Catalog() string { return "/catalog" }
+
+// Versions returns the versions url as a type of string.
+func Versions() string { return "/versions" }
+
+// Rollback returns the rollback url as a type of string.
+func Rollback() string { return "/rollback" }
+
+// Desired returns the desired state url as a type of string.
+func Desired() string { return "/desired" }
+
+// Converge returns the converge url as a type of string.
+func Converge() string { return "/converge" }
+
+// OpenAPI returns the openapi document url as a type of string.
+func OpenAPI() string { return "/openapi.json" }
@@ -17,29 +17,62 @@
 package db
 
 import (
+	"commons/config"
 	"commons/logger"
+	"context"
 	"db/mongo"
+	"net/url"
 )
 
 type Command interface {
 	// AddAgent insert new Agent.
 	AddAgent(host string, port string, status string) (map[string]interface{}, error)
 
-	// UpdateAgentAddress updates ip,port of agent from db related to agent.
-	UpdateAgentAddress(agent_id string, host string, port string) error
+	// UpdateAgentAddress updates ip,port of agent from db related to agent, and records the change.
+	UpdateAgentAddress(agent_id string, host string, port string, changed_at int64) error
+
+	// UpdateAgentDeviceID updates the identity reported by agent.
+	UpdateAgentDeviceID(agent_id string, device_id string) error
+
+	// UpdateAgentSecret updates the hash of the secret issued to agent.
+	UpdateAgentSecret(agent_id string, hash string) error
+
+	// UpdateAgentHealthCheck updates the health check mode of agent.
+	UpdateAgentHealthCheck(agent_id string, mode string) error
+
+	// UpdateAgentExempt updates whether agent is exempt from the retention policy.
+	UpdateAgentExempt(agent_id string, exempt bool) error
 
 	// UpdateAgentStatus updates status of agent from db related to agent.
 	UpdateAgentStatus(agent_id string, status string) error
 
+	// UpdateAgentLastSeen updates the time of the last ping and the interval of pings of agent.
+	UpdateAgentLastSeen(agent_id string, last_seen int64, interval int) error
+
+	// UpdateAgentMetadata updates the given fields of metadata of agent.
+	UpdateAgentMetadata(agent_id string, metadata map[string]interface{}) error
+
+	// UpdateAgentLabels sets the given labels and removes labels with the given keys of agent.
+	UpdateAgentLabels(agent_id string, labels map[string]string, removed []string) error
+
 	// GetAgent returns single document from db related to agent.
 	GetAgent(agent_id string) (map[string]interface{}, error)
 
 	// GetAgentByIP returns single document from db related to agent.
 	GetAgentByIP(ip string) (map[string]interface{}, error)
 
+	// GetAgentByDeviceID returns single document from db related to agent with the identity.
+	GetAgentByDeviceID(device_id string) (map[string]interface{}, error)
+
+	// GetAgentSecret returns the hash of the secret issued to agent.
+	GetAgentSecret(agent_id string) (string, error)
+
 	// GetAllAgents returns all documents from db related to agent.
 	GetAllAgents() ([]map[string]interface{}, error)
 
+	// GetAgentsByQuery returns a page of documents from db related to agent matching the filter.
+	GetAgentsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error)
+
 	// GetAgentByAppID returns single document including specific app.
 	GetAgentByAppID(agent_id string, app_id string) (map[string]interface{}, error)
 
@@ -49,6 +82,15 @@ type Command interface {
 	// DeleteAppFromAgent delete specific app from the target agent.
 	DeleteAppFromAgent(agent_id string, app_id string) error
 
+	// UpdateAgentCatalog records the catalog app and version of the app on agent.
+	UpdateAgentCatalog(agent_id string, app_id string, name string, version string) error
+
+	// AddAgentRevision add new revision of the descriptor applied to the app on agent, and returns its number.
+	AddAgentRevision(agent_id string, app_id string, descriptor string, checksum string, name string, version string, applied_at int64) (int, error)
+
+	// GetAgentRevisions returns revisions of the descriptor applied to the app on agent.
+	GetAgentRevisions(agent_id string, app_id string) ([]map[string]interface{}, error)
+
 	// DeleteAgent delete single document from db related to agent.
 	DeleteAgent(agent_id string) error
 
@@ -61,6 +103,9 @@ type Command interface {
 	// GetAllGroups returns all documents from db related to group.
 	GetAllGroups() ([]map[string]interface{}, error)
 
+	// GetGroupsByQuery returns a page of documents from db related to group matching the filter.
+	GetGroupsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error)
+
 	// GetGroupMembers returns all agents who belong to the target group.
 	GetGroupMembers(group_id string) ([]map[string]interface{}, error)
 
@@ -75,6 +120,57 @@ type Command interface {
 
 	// DeleteGroup delete single document from db related to group.
 	DeleteGroup(group_id string) error
+
+	// SetGroupDesiredState declares the apps which members of group should run.
+	SetGroupDesiredState(group_id string, apps []map[string]string, prune bool) error
+
+	// UnsetGroupDesiredState removes the desired state and the convergence of members of group.
+	UnsetGroupDesiredState(group_id string) error
+
+	// GetGroupDesiredState returns the desired state of group with the convergence of members.
+	GetGroupDesiredState(group_id string) (map[string]interface{}, error)
+
+	// GetAllGroupDesiredStates returns the desired state of all groups which declare one.
+	GetAllGroupDesiredStates() ([]map[string]interface{}, error)
+
+	// UpdateGroupConvergence records the result of the last convergence of agent toward the desired state of group.
+	UpdateGroupConvergence(group_id string, agent_id string, status string, actions []string, failures []string, converged_at int64) error
+
+	// AddKey insert new API key with the role, the groups and the hash of the key.
+	AddKey(name string, role string, groups []string, hash string) (map[string]interface{}, error)
+
+	// GetKeyByHash returns single document from db related to API key.
+	GetKeyByHash(hash string) (map[string]interface{}, error)
+
+	// GetAllKeys returns all documents from db related to API key.
+	GetAllKeys() ([]map[string]interface{}, error)
+
+	// DeleteKey delete single document from db related to API key.
+	DeleteKey(key_id string) error
+
+	// AddCatalogApp insert new app of the catalog with the name and the description.
+	AddCatalogApp(name string, description string) (map[string]interface{}, error)
+
+	// GetCatalogApp returns single document from db related to catalog app.
+	GetCatalogApp(name string) (map[string]interface{}, error)
+
+	// GetAllCatalogApps returns all documents from db related to catalog app.
+	GetAllCatalogApps() ([]map[string]interface{}, error)
+
+	// DeleteCatalogApp delete single document from db related to catalog app.
+	DeleteCatalogApp(name string) error
+
+	// AddCatalogVersion add new version of the descriptor to the catalog app.
+	AddCatalogVersion(name string, version string, description string, descriptor string, checksum string, created_at int64) error
+
+	// GetCatalogVersion returns the version of the catalog app including the descriptor.
+	GetCatalogVersion(name string, version string) (map[string]interface{}, error)
+
+	// AddEvent insert new event of agent which happened at the time.
+	AddEvent(agent_id string, event_type string, time int64, detail string) error
+
+	// GetEventsByQuery returns a page of documents from db related to event matching the filter.
+	GetEventsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error)
 }
 
 type Closer interface {
@@ -89,7 +185,7 @@ type DBManager interface {
 
 type (
 	DBConnection interface {
-		Connect() (DBManager, error)
+		Connect(ctx context.Context) (DBManager, error)
 	}
 
 	DBConnector struct{}
@@ -101,23 +197,50 @@ func init() {
 	mgoBuilder = &mongo.MongoBuilder{}
 }
 
-// Connect establishes a new session to the database identified by the given url.
-func (DBConnector) Connect() (DBManager, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
-
-	// TODO: Should be updated to support different types of databases.
-	url := "127.0.0.1:27017"
+// Connect establishes a new session to the database identified by the configured url.
+// Logs of the returned DBManager include the request id carried by ctx.
+func (DBConnector) Connect(ctx context.Context) (DBManager, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
-	err := mgoBuilder.Connect(url)
+	err := mgoBuilder.Connect(makeDialURL(config.Get().DB))
 	if err != nil {
 		return nil, err
 	}
 
-	dbManager, err := mgoBuilder.CreateDB()
+	dbManager, err := mgoBuilder.CreateDB(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	return dbManager, err
 }
+
+// Close closes the session to the database shared by all connections.
+// It should be called once when the service is terminated.
+func Close() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	mgoBuilder.Close()
+}
+
+// makeDialURL returns the url used to dial the database.
+// If a username is configured, the credentials are included in the url and
+// authenticated against the configured database or auth source.
+func makeDialURL(cfg config.DBConfig) string {
+	if cfg.Username == "" {
+		return cfg.URL
+	}
+
+	dialURL := url.URL{
+		Scheme: "mongodb",
+		User:   url.UserPassword(cfg.Username, cfg.Password),
+		Host:   cfg.URL,
+		Path:   "/" + cfg.Name,
+	}
+	if cfg.AuthSource != "" {
+		dialURL.RawQuery = url.Values{"authSource": {cfg.AuthSource}}.Encode()
+	}
+	return dialURL.String()
+}
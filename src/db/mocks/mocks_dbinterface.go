@@ -21,6 +21,7 @@
 package mocks
 
 import (
+	context "context"
 	"db"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -63,15 +64,39 @@ func (mr *MockCommandMockRecorder) AddAgent(host, port, status interface{}) *gom
 }
 
 // UpdateAgentAddress mocks base method
-func (m *MockCommand) UpdateAgentAddress(agent_id, host, port string) error {
-	ret := m.ctrl.Call(m, "UpdateAgentAddress", agent_id, host, port)
+func (m *MockCommand) UpdateAgentAddress(agent_id, host, port string, changed_at int64) error {
+	ret := m.ctrl.Call(m, "UpdateAgentAddress", agent_id, host, port, changed_at)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateAgentAddress indicates an expected call of UpdateAgentAddress
-func (mr *MockCommandMockRecorder) UpdateAgentAddress(agent_id, host, port interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentAddress", reflect.TypeOf((*MockCommand)(nil).UpdateAgentAddress), agent_id, host, port)
+func (mr *MockCommandMockRecorder) UpdateAgentAddress(agent_id, host, port, changed_at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentAddress", reflect.TypeOf((*MockCommand)(nil).UpdateAgentAddress), agent_id, host, port, changed_at)
+}
+
+// UpdateAgentDeviceID mocks base method
+func (m *MockCommand) UpdateAgentDeviceID(agent_id, device_id string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentDeviceID", agent_id, device_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentDeviceID indicates an expected call of UpdateAgentDeviceID
+func (mr *MockCommandMockRecorder) UpdateAgentDeviceID(agent_id, device_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentDeviceID", reflect.TypeOf((*MockCommand)(nil).UpdateAgentDeviceID), agent_id, device_id)
+}
+
+// UpdateAgentSecret mocks base method
+func (m *MockCommand) UpdateAgentSecret(agent_id, hash string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentSecret", agent_id, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentSecret indicates an expected call of UpdateAgentSecret
+func (mr *MockCommandMockRecorder) UpdateAgentSecret(agent_id, hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentSecret", reflect.TypeOf((*MockCommand)(nil).UpdateAgentSecret), agent_id, hash)
 }
 
 // UpdateAgentStatus mocks base method
@@ -86,6 +111,42 @@ func (mr *MockCommandMockRecorder) UpdateAgentStatus(agent_id, status interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentStatus", reflect.TypeOf((*MockCommand)(nil).UpdateAgentStatus), agent_id, status)
 }
 
+// UpdateAgentLastSeen mocks base method
+func (m *MockCommand) UpdateAgentLastSeen(agent_id string, last_seen int64, interval int) error {
+	ret := m.ctrl.Call(m, "UpdateAgentLastSeen", agent_id, last_seen, interval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentLastSeen indicates an expected call of UpdateAgentLastSeen
+func (mr *MockCommandMockRecorder) UpdateAgentLastSeen(agent_id, last_seen, interval interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentLastSeen", reflect.TypeOf((*MockCommand)(nil).UpdateAgentLastSeen), agent_id, last_seen, interval)
+}
+
+// UpdateAgentMetadata mocks base method
+func (m *MockCommand) UpdateAgentMetadata(agent_id string, metadata map[string]interface{}) error {
+	ret := m.ctrl.Call(m, "UpdateAgentMetadata", agent_id, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentMetadata indicates an expected call of UpdateAgentMetadata
+func (mr *MockCommandMockRecorder) UpdateAgentMetadata(agent_id, metadata interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentMetadata", reflect.TypeOf((*MockCommand)(nil).UpdateAgentMetadata), agent_id, metadata)
+}
+
+// UpdateAgentLabels mocks base method
+func (m *MockCommand) UpdateAgentLabels(agent_id string, labels map[string]string, removed []string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentLabels", agent_id, labels, removed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentLabels indicates an expected call of UpdateAgentLabels
+func (mr *MockCommandMockRecorder) UpdateAgentLabels(agent_id, labels, removed interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentLabels", reflect.TypeOf((*MockCommand)(nil).UpdateAgentLabels), agent_id, labels, removed)
+}
+
 // GetAgent mocks base method
 func (m *MockCommand) GetAgent(agent_id string) (map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetAgent", agent_id)
@@ -112,6 +173,32 @@ func (mr *MockCommandMockRecorder) GetAgentByIP(ip interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentByIP", reflect.TypeOf((*MockCommand)(nil).GetAgentByIP), ip)
 }
 
+// GetAgentByDeviceID mocks base method
+func (m *MockCommand) GetAgentByDeviceID(device_id string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAgentByDeviceID", device_id)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentByDeviceID indicates an expected call of GetAgentByDeviceID
+func (mr *MockCommandMockRecorder) GetAgentByDeviceID(device_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentByDeviceID", reflect.TypeOf((*MockCommand)(nil).GetAgentByDeviceID), device_id)
+}
+
+// GetAgentSecret mocks base method
+func (m *MockCommand) GetAgentSecret(agent_id string) (string, error) {
+	ret := m.ctrl.Call(m, "GetAgentSecret", agent_id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentSecret indicates an expected call of GetAgentSecret
+func (mr *MockCommandMockRecorder) GetAgentSecret(agent_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentSecret", reflect.TypeOf((*MockCommand)(nil).GetAgentSecret), agent_id)
+}
+
 // GetAllAgents mocks base method
 func (m *MockCommand) GetAllAgents() ([]map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetAllAgents")
@@ -125,6 +212,20 @@ func (mr *MockCommandMockRecorder) GetAllAgents() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAgents", reflect.TypeOf((*MockCommand)(nil).GetAllAgents))
 }
 
+// GetAgentsByQuery mocks base method
+func (m *MockCommand) GetAgentsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	ret := m.ctrl.Call(m, "GetAgentsByQuery", filter, sort, limit, cursor)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAgentsByQuery indicates an expected call of GetAgentsByQuery
+func (mr *MockCommandMockRecorder) GetAgentsByQuery(filter, sort, limit, cursor interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentsByQuery", reflect.TypeOf((*MockCommand)(nil).GetAgentsByQuery), filter, sort, limit, cursor)
+}
+
 // GetAgentByAppID mocks base method
 func (m *MockCommand) GetAgentByAppID(agent_id, app_id string) (map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetAgentByAppID", agent_id, app_id)
@@ -213,6 +314,20 @@ func (mr *MockCommandMockRecorder) GetAllGroups() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGroups", reflect.TypeOf((*MockCommand)(nil).GetAllGroups))
 }
 
+// GetGroupsByQuery mocks base method
+func (m *MockCommand) GetGroupsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	ret := m.ctrl.Call(m, "GetGroupsByQuery", filter, sort, limit, cursor)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetGroupsByQuery indicates an expected call of GetGroupsByQuery
+func (mr *MockCommandMockRecorder) GetGroupsByQuery(filter, sort, limit, cursor interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsByQuery", reflect.TypeOf((*MockCommand)(nil).GetGroupsByQuery), filter, sort, limit, cursor)
+}
+
 // GetGroupMembers mocks base method
 func (m *MockCommand) GetGroupMembers(group_id string) ([]map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetGroupMembers", group_id)
@@ -275,6 +390,283 @@ func (mr *MockCommandMockRecorder) DeleteGroup(group_id interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockCommand)(nil).DeleteGroup), group_id)
 }
 
+// AddKey mocks base method
+func (m *MockCommand) AddKey(name, role string, groups []string, hash string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "AddKey", name, role, groups, hash)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddKey indicates an expected call of AddKey
+func (mr *MockCommandMockRecorder) AddKey(name, role, groups, hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddKey", reflect.TypeOf((*MockCommand)(nil).AddKey), name, role, groups, hash)
+}
+
+// GetKeyByHash mocks base method
+func (m *MockCommand) GetKeyByHash(hash string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetKeyByHash", hash)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyByHash indicates an expected call of GetKeyByHash
+func (mr *MockCommandMockRecorder) GetKeyByHash(hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyByHash", reflect.TypeOf((*MockCommand)(nil).GetKeyByHash), hash)
+}
+
+// GetAllKeys mocks base method
+func (m *MockCommand) GetAllKeys() ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAllKeys")
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllKeys indicates an expected call of GetAllKeys
+func (mr *MockCommandMockRecorder) GetAllKeys() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllKeys", reflect.TypeOf((*MockCommand)(nil).GetAllKeys))
+}
+
+// DeleteKey mocks base method
+func (m *MockCommand) DeleteKey(key_id string) error {
+	ret := m.ctrl.Call(m, "DeleteKey", key_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKey indicates an expected call of DeleteKey
+func (mr *MockCommandMockRecorder) DeleteKey(key_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKey", reflect.TypeOf((*MockCommand)(nil).DeleteKey), key_id)
+}
+
+// AddEvent mocks base method
+func (m *MockCommand) AddEvent(agent_id, event_type string, time int64, detail string) error {
+	ret := m.ctrl.Call(m, "AddEvent", agent_id, event_type, time, detail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvent indicates an expected call of AddEvent
+func (mr *MockCommandMockRecorder) AddEvent(agent_id, event_type, time, detail interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*MockCommand)(nil).AddEvent), agent_id, event_type, time, detail)
+}
+
+// GetEventsByQuery mocks base method
+func (m *MockCommand) GetEventsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	ret := m.ctrl.Call(m, "GetEventsByQuery", filter, sort, limit, cursor)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetEventsByQuery indicates an expected call of GetEventsByQuery
+func (mr *MockCommandMockRecorder) GetEventsByQuery(filter, sort, limit, cursor interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsByQuery", reflect.TypeOf((*MockCommand)(nil).GetEventsByQuery), filter, sort, limit, cursor)
+}
+
+// UpdateAgentHealthCheck mocks base method
+func (m *MockCommand) UpdateAgentHealthCheck(agent_id, mode string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentHealthCheck", agent_id, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentHealthCheck indicates an expected call of UpdateAgentHealthCheck
+func (mr *MockCommandMockRecorder) UpdateAgentHealthCheck(agent_id, mode interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentHealthCheck", reflect.TypeOf((*MockCommand)(nil).UpdateAgentHealthCheck), agent_id, mode)
+}
+
+// UpdateAgentExempt mocks base method
+func (m *MockCommand) UpdateAgentExempt(agent_id string, exempt bool) error {
+	ret := m.ctrl.Call(m, "UpdateAgentExempt", agent_id, exempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentExempt indicates an expected call of UpdateAgentExempt
+func (mr *MockCommandMockRecorder) UpdateAgentExempt(agent_id, exempt interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentExempt", reflect.TypeOf((*MockCommand)(nil).UpdateAgentExempt), agent_id, exempt)
+}
+
+// UpdateAgentCatalog mocks base method
+func (m *MockCommand) UpdateAgentCatalog(agent_id, app_id, name, version string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentCatalog", agent_id, app_id, name, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentCatalog indicates an expected call of UpdateAgentCatalog
+func (mr *MockCommandMockRecorder) UpdateAgentCatalog(agent_id, app_id, name, version interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentCatalog", reflect.TypeOf((*MockCommand)(nil).UpdateAgentCatalog), agent_id, app_id, name, version)
+}
+
+// AddCatalogApp mocks base method
+func (m *MockCommand) AddCatalogApp(name, description string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "AddCatalogApp", name, description)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCatalogApp indicates an expected call of AddCatalogApp
+func (mr *MockCommandMockRecorder) AddCatalogApp(name, description interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCatalogApp", reflect.TypeOf((*MockCommand)(nil).AddCatalogApp), name, description)
+}
+
+// GetCatalogApp mocks base method
+func (m *MockCommand) GetCatalogApp(name string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetCatalogApp", name)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogApp indicates an expected call of GetCatalogApp
+func (mr *MockCommandMockRecorder) GetCatalogApp(name interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogApp", reflect.TypeOf((*MockCommand)(nil).GetCatalogApp), name)
+}
+
+// GetAllCatalogApps mocks base method
+func (m *MockCommand) GetAllCatalogApps() ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAllCatalogApps")
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllCatalogApps indicates an expected call of GetAllCatalogApps
+func (mr *MockCommandMockRecorder) GetAllCatalogApps() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCatalogApps", reflect.TypeOf((*MockCommand)(nil).GetAllCatalogApps))
+}
+
+// DeleteCatalogApp mocks base method
+func (m *MockCommand) DeleteCatalogApp(name string) error {
+	ret := m.ctrl.Call(m, "DeleteCatalogApp", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCatalogApp indicates an expected call of DeleteCatalogApp
+func (mr *MockCommandMockRecorder) DeleteCatalogApp(name interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCatalogApp", reflect.TypeOf((*MockCommand)(nil).DeleteCatalogApp), name)
+}
+
+// AddCatalogVersion mocks base method
+func (m *MockCommand) AddCatalogVersion(name, version, description, descriptor, checksum string, created_at int64) error {
+	ret := m.ctrl.Call(m, "AddCatalogVersion", name, version, description, descriptor, checksum, created_at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCatalogVersion indicates an expected call of AddCatalogVersion
+func (mr *MockCommandMockRecorder) AddCatalogVersion(name, version, description, descriptor, checksum, created_at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCatalogVersion", reflect.TypeOf((*MockCommand)(nil).AddCatalogVersion), name, version, description, descriptor, checksum, created_at)
+}
+
+// GetCatalogVersion mocks base method
+func (m *MockCommand) GetCatalogVersion(name, version string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetCatalogVersion", name, version)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogVersion indicates an expected call of GetCatalogVersion
+func (mr *MockCommandMockRecorder) GetCatalogVersion(name, version interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogVersion", reflect.TypeOf((*MockCommand)(nil).GetCatalogVersion), name, version)
+}
+
+// AddAgentRevision mocks base method
+func (m *MockCommand) AddAgentRevision(agent_id, app_id, descriptor, checksum, name, version string, applied_at int64) (int, error) {
+	ret := m.ctrl.Call(m, "AddAgentRevision", agent_id, app_id, descriptor, checksum, name, version, applied_at)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAgentRevision indicates an expected call of AddAgentRevision
+func (mr *MockCommandMockRecorder) AddAgentRevision(agent_id, app_id, descriptor, checksum, name, version, applied_at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAgentRevision", reflect.TypeOf((*MockCommand)(nil).AddAgentRevision), agent_id, app_id, descriptor, checksum, name, version, applied_at)
+}
+
+// GetAgentRevisions mocks base method
+func (m *MockCommand) GetAgentRevisions(agent_id, app_id string) ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAgentRevisions", agent_id, app_id)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentRevisions indicates an expected call of GetAgentRevisions
+func (mr *MockCommandMockRecorder) GetAgentRevisions(agent_id, app_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentRevisions", reflect.TypeOf((*MockCommand)(nil).GetAgentRevisions), agent_id, app_id)
+}
+
+// SetGroupDesiredState mocks base method
+func (m *MockCommand) SetGroupDesiredState(group_id string, apps []map[string]string, prune bool) error {
+	ret := m.ctrl.Call(m, "SetGroupDesiredState", group_id, apps, prune)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGroupDesiredState indicates an expected call of SetGroupDesiredState
+func (mr *MockCommandMockRecorder) SetGroupDesiredState(group_id, apps, prune interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGroupDesiredState", reflect.TypeOf((*MockCommand)(nil).SetGroupDesiredState), group_id, apps, prune)
+}
+
+// UnsetGroupDesiredState mocks base method
+func (m *MockCommand) UnsetGroupDesiredState(group_id string) error {
+	ret := m.ctrl.Call(m, "UnsetGroupDesiredState", group_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsetGroupDesiredState indicates an expected call of UnsetGroupDesiredState
+func (mr *MockCommandMockRecorder) UnsetGroupDesiredState(group_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetGroupDesiredState", reflect.TypeOf((*MockCommand)(nil).UnsetGroupDesiredState), group_id)
+}
+
+// GetGroupDesiredState mocks base method
+func (m *MockCommand) GetGroupDesiredState(group_id string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetGroupDesiredState", group_id)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupDesiredState indicates an expected call of GetGroupDesiredState
+func (mr *MockCommandMockRecorder) GetGroupDesiredState(group_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupDesiredState", reflect.TypeOf((*MockCommand)(nil).GetGroupDesiredState), group_id)
+}
+
+// GetAllGroupDesiredStates mocks base method
+func (m *MockCommand) GetAllGroupDesiredStates() ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAllGroupDesiredStates")
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllGroupDesiredStates indicates an expected call of GetAllGroupDesiredStates
+func (mr *MockCommandMockRecorder) GetAllGroupDesiredStates() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGroupDesiredStates", reflect.TypeOf((*MockCommand)(nil).GetAllGroupDesiredStates))
+}
+
+// UpdateGroupConvergence mocks base method
+func (m *MockCommand) UpdateGroupConvergence(group_id, agent_id, status string, actions, failures []string, converged_at int64) error {
+	ret := m.ctrl.Call(m, "UpdateGroupConvergence", group_id, agent_id, status, actions, failures, converged_at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGroupConvergence indicates an expected call of UpdateGroupConvergence
+func (mr *MockCommandMockRecorder) UpdateGroupConvergence(group_id, agent_id, status, actions, failures, converged_at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupConvergence", reflect.TypeOf((*MockCommand)(nil).UpdateGroupConvergence), group_id, agent_id, status, actions, failures, converged_at)
+}
+
 // MockCloser is a mock of Closer interface
 type MockCloser struct {
 	ctrl     *gomock.Controller
@@ -345,15 +737,39 @@ func (mr *MockDBManagerMockRecorder) AddAgent(host, port, status interface{}) *g
 }
 
 // UpdateAgentAddress mocks base method
-func (m *MockDBManager) UpdateAgentAddress(agent_id, host, port string) error {
-	ret := m.ctrl.Call(m, "UpdateAgentAddress", agent_id, host, port)
+func (m *MockDBManager) UpdateAgentAddress(agent_id, host, port string, changed_at int64) error {
+	ret := m.ctrl.Call(m, "UpdateAgentAddress", agent_id, host, port, changed_at)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateAgentAddress indicates an expected call of UpdateAgentAddress
-func (mr *MockDBManagerMockRecorder) UpdateAgentAddress(agent_id, host, port interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentAddress", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentAddress), agent_id, host, port)
+func (mr *MockDBManagerMockRecorder) UpdateAgentAddress(agent_id, host, port, changed_at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentAddress", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentAddress), agent_id, host, port, changed_at)
+}
+
+// UpdateAgentDeviceID mocks base method
+func (m *MockDBManager) UpdateAgentDeviceID(agent_id, device_id string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentDeviceID", agent_id, device_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentDeviceID indicates an expected call of UpdateAgentDeviceID
+func (mr *MockDBManagerMockRecorder) UpdateAgentDeviceID(agent_id, device_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentDeviceID", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentDeviceID), agent_id, device_id)
+}
+
+// UpdateAgentSecret mocks base method
+func (m *MockDBManager) UpdateAgentSecret(agent_id, hash string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentSecret", agent_id, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentSecret indicates an expected call of UpdateAgentSecret
+func (mr *MockDBManagerMockRecorder) UpdateAgentSecret(agent_id, hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentSecret", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentSecret), agent_id, hash)
 }
 
 // UpdateAgentStatus mocks base method
@@ -368,6 +784,42 @@ func (mr *MockDBManagerMockRecorder) UpdateAgentStatus(agent_id, status interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentStatus", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentStatus), agent_id, status)
 }
 
+// UpdateAgentLastSeen mocks base method
+func (m *MockDBManager) UpdateAgentLastSeen(agent_id string, last_seen int64, interval int) error {
+	ret := m.ctrl.Call(m, "UpdateAgentLastSeen", agent_id, last_seen, interval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentLastSeen indicates an expected call of UpdateAgentLastSeen
+func (mr *MockDBManagerMockRecorder) UpdateAgentLastSeen(agent_id, last_seen, interval interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentLastSeen", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentLastSeen), agent_id, last_seen, interval)
+}
+
+// UpdateAgentMetadata mocks base method
+func (m *MockDBManager) UpdateAgentMetadata(agent_id string, metadata map[string]interface{}) error {
+	ret := m.ctrl.Call(m, "UpdateAgentMetadata", agent_id, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentMetadata indicates an expected call of UpdateAgentMetadata
+func (mr *MockDBManagerMockRecorder) UpdateAgentMetadata(agent_id, metadata interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentMetadata", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentMetadata), agent_id, metadata)
+}
+
+// UpdateAgentLabels mocks base method
+func (m *MockDBManager) UpdateAgentLabels(agent_id string, labels map[string]string, removed []string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentLabels", agent_id, labels, removed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentLabels indicates an expected call of UpdateAgentLabels
+func (mr *MockDBManagerMockRecorder) UpdateAgentLabels(agent_id, labels, removed interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentLabels", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentLabels), agent_id, labels, removed)
+}
+
 // GetAgent mocks base method
 func (m *MockDBManager) GetAgent(agent_id string) (map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetAgent", agent_id)
@@ -394,6 +846,32 @@ func (mr *MockDBManagerMockRecorder) GetAgentByIP(ip interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentByIP", reflect.TypeOf((*MockDBManager)(nil).GetAgentByIP), ip)
 }
 
+// GetAgentByDeviceID mocks base method
+func (m *MockDBManager) GetAgentByDeviceID(device_id string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAgentByDeviceID", device_id)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentByDeviceID indicates an expected call of GetAgentByDeviceID
+func (mr *MockDBManagerMockRecorder) GetAgentByDeviceID(device_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentByDeviceID", reflect.TypeOf((*MockDBManager)(nil).GetAgentByDeviceID), device_id)
+}
+
+// GetAgentSecret mocks base method
+func (m *MockDBManager) GetAgentSecret(agent_id string) (string, error) {
+	ret := m.ctrl.Call(m, "GetAgentSecret", agent_id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentSecret indicates an expected call of GetAgentSecret
+func (mr *MockDBManagerMockRecorder) GetAgentSecret(agent_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentSecret", reflect.TypeOf((*MockDBManager)(nil).GetAgentSecret), agent_id)
+}
+
 // GetAllAgents mocks base method
 func (m *MockDBManager) GetAllAgents() ([]map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetAllAgents")
@@ -407,6 +885,20 @@ func (mr *MockDBManagerMockRecorder) GetAllAgents() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAgents", reflect.TypeOf((*MockDBManager)(nil).GetAllAgents))
 }
 
+// GetAgentsByQuery mocks base method
+func (m *MockDBManager) GetAgentsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	ret := m.ctrl.Call(m, "GetAgentsByQuery", filter, sort, limit, cursor)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAgentsByQuery indicates an expected call of GetAgentsByQuery
+func (mr *MockDBManagerMockRecorder) GetAgentsByQuery(filter, sort, limit, cursor interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentsByQuery", reflect.TypeOf((*MockDBManager)(nil).GetAgentsByQuery), filter, sort, limit, cursor)
+}
+
 // GetAgentByAppID mocks base method
 func (m *MockDBManager) GetAgentByAppID(agent_id, app_id string) (map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetAgentByAppID", agent_id, app_id)
@@ -495,6 +987,20 @@ func (mr *MockDBManagerMockRecorder) GetAllGroups() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGroups", reflect.TypeOf((*MockDBManager)(nil).GetAllGroups))
 }
 
+// GetGroupsByQuery mocks base method
+func (m *MockDBManager) GetGroupsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	ret := m.ctrl.Call(m, "GetGroupsByQuery", filter, sort, limit, cursor)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetGroupsByQuery indicates an expected call of GetGroupsByQuery
+func (mr *MockDBManagerMockRecorder) GetGroupsByQuery(filter, sort, limit, cursor interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsByQuery", reflect.TypeOf((*MockDBManager)(nil).GetGroupsByQuery), filter, sort, limit, cursor)
+}
+
 // GetGroupMembers mocks base method
 func (m *MockDBManager) GetGroupMembers(group_id string) ([]map[string]interface{}, error) {
 	ret := m.ctrl.Call(m, "GetGroupMembers", group_id)
@@ -557,6 +1063,57 @@ func (mr *MockDBManagerMockRecorder) DeleteGroup(group_id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockDBManager)(nil).DeleteGroup), group_id)
 }
 
+// AddKey mocks base method
+func (m *MockDBManager) AddKey(name, role string, groups []string, hash string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "AddKey", name, role, groups, hash)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddKey indicates an expected call of AddKey
+func (mr *MockDBManagerMockRecorder) AddKey(name, role, groups, hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddKey", reflect.TypeOf((*MockDBManager)(nil).AddKey), name, role, groups, hash)
+}
+
+// GetKeyByHash mocks base method
+func (m *MockDBManager) GetKeyByHash(hash string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetKeyByHash", hash)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeyByHash indicates an expected call of GetKeyByHash
+func (mr *MockDBManagerMockRecorder) GetKeyByHash(hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeyByHash", reflect.TypeOf((*MockDBManager)(nil).GetKeyByHash), hash)
+}
+
+// GetAllKeys mocks base method
+func (m *MockDBManager) GetAllKeys() ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAllKeys")
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllKeys indicates an expected call of GetAllKeys
+func (mr *MockDBManagerMockRecorder) GetAllKeys() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllKeys", reflect.TypeOf((*MockDBManager)(nil).GetAllKeys))
+}
+
+// DeleteKey mocks base method
+func (m *MockDBManager) DeleteKey(key_id string) error {
+	ret := m.ctrl.Call(m, "DeleteKey", key_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKey indicates an expected call of DeleteKey
+func (mr *MockDBManagerMockRecorder) DeleteKey(key_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKey", reflect.TypeOf((*MockDBManager)(nil).DeleteKey), key_id)
+}
+
 // Close mocks base method
 func (m *MockDBManager) Close() {
 	m.ctrl.Call(m, "Close")
@@ -567,6 +1124,232 @@ func (mr *MockDBManagerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDBManager)(nil).Close))
 }
 
+// AddEvent mocks base method
+func (m *MockDBManager) AddEvent(agent_id, event_type string, time int64, detail string) error {
+	ret := m.ctrl.Call(m, "AddEvent", agent_id, event_type, time, detail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvent indicates an expected call of AddEvent
+func (mr *MockDBManagerMockRecorder) AddEvent(agent_id, event_type, time, detail interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*MockDBManager)(nil).AddEvent), agent_id, event_type, time, detail)
+}
+
+// GetEventsByQuery mocks base method
+func (m *MockDBManager) GetEventsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	ret := m.ctrl.Call(m, "GetEventsByQuery", filter, sort, limit, cursor)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetEventsByQuery indicates an expected call of GetEventsByQuery
+func (mr *MockDBManagerMockRecorder) GetEventsByQuery(filter, sort, limit, cursor interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsByQuery", reflect.TypeOf((*MockDBManager)(nil).GetEventsByQuery), filter, sort, limit, cursor)
+}
+
+// UpdateAgentHealthCheck mocks base method
+func (m *MockDBManager) UpdateAgentHealthCheck(agent_id, mode string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentHealthCheck", agent_id, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentHealthCheck indicates an expected call of UpdateAgentHealthCheck
+func (mr *MockDBManagerMockRecorder) UpdateAgentHealthCheck(agent_id, mode interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentHealthCheck", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentHealthCheck), agent_id, mode)
+}
+
+// UpdateAgentExempt mocks base method
+func (m *MockDBManager) UpdateAgentExempt(agent_id string, exempt bool) error {
+	ret := m.ctrl.Call(m, "UpdateAgentExempt", agent_id, exempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentExempt indicates an expected call of UpdateAgentExempt
+func (mr *MockDBManagerMockRecorder) UpdateAgentExempt(agent_id, exempt interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentExempt", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentExempt), agent_id, exempt)
+}
+
+// UpdateAgentCatalog mocks base method
+func (m *MockDBManager) UpdateAgentCatalog(agent_id, app_id, name, version string) error {
+	ret := m.ctrl.Call(m, "UpdateAgentCatalog", agent_id, app_id, name, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAgentCatalog indicates an expected call of UpdateAgentCatalog
+func (mr *MockDBManagerMockRecorder) UpdateAgentCatalog(agent_id, app_id, name, version interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAgentCatalog", reflect.TypeOf((*MockDBManager)(nil).UpdateAgentCatalog), agent_id, app_id, name, version)
+}
+
+// AddCatalogApp mocks base method
+func (m *MockDBManager) AddCatalogApp(name, description string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "AddCatalogApp", name, description)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCatalogApp indicates an expected call of AddCatalogApp
+func (mr *MockDBManagerMockRecorder) AddCatalogApp(name, description interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCatalogApp", reflect.TypeOf((*MockDBManager)(nil).AddCatalogApp), name, description)
+}
+
+// GetCatalogApp mocks base method
+func (m *MockDBManager) GetCatalogApp(name string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetCatalogApp", name)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogApp indicates an expected call of GetCatalogApp
+func (mr *MockDBManagerMockRecorder) GetCatalogApp(name interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogApp", reflect.TypeOf((*MockDBManager)(nil).GetCatalogApp), name)
+}
+
+// GetAllCatalogApps mocks base method
+func (m *MockDBManager) GetAllCatalogApps() ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAllCatalogApps")
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllCatalogApps indicates an expected call of GetAllCatalogApps
+func (mr *MockDBManagerMockRecorder) GetAllCatalogApps() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCatalogApps", reflect.TypeOf((*MockDBManager)(nil).GetAllCatalogApps))
+}
+
+// DeleteCatalogApp mocks base method
+func (m *MockDBManager) DeleteCatalogApp(name string) error {
+	ret := m.ctrl.Call(m, "DeleteCatalogApp", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCatalogApp indicates an expected call of DeleteCatalogApp
+func (mr *MockDBManagerMockRecorder) DeleteCatalogApp(name interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCatalogApp", reflect.TypeOf((*MockDBManager)(nil).DeleteCatalogApp), name)
+}
+
+// AddCatalogVersion mocks base method
+func (m *MockDBManager) AddCatalogVersion(name, version, description, descriptor, checksum string, created_at int64) error {
+	ret := m.ctrl.Call(m, "AddCatalogVersion", name, version, description, descriptor, checksum, created_at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCatalogVersion indicates an expected call of AddCatalogVersion
+func (mr *MockDBManagerMockRecorder) AddCatalogVersion(name, version, description, descriptor, checksum, created_at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCatalogVersion", reflect.TypeOf((*MockDBManager)(nil).AddCatalogVersion), name, version, description, descriptor, checksum, created_at)
+}
+
+// GetCatalogVersion mocks base method
+func (m *MockDBManager) GetCatalogVersion(name, version string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetCatalogVersion", name, version)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogVersion indicates an expected call of GetCatalogVersion
+func (mr *MockDBManagerMockRecorder) GetCatalogVersion(name, version interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogVersion", reflect.TypeOf((*MockDBManager)(nil).GetCatalogVersion), name, version)
+}
+
+// AddAgentRevision mocks base method
+func (m *MockDBManager) AddAgentRevision(agent_id, app_id, descriptor, checksum, name, version string, applied_at int64) (int, error) {
+	ret := m.ctrl.Call(m, "AddAgentRevision", agent_id, app_id, descriptor, checksum, name, version, applied_at)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAgentRevision indicates an expected call of AddAgentRevision
+func (mr *MockDBManagerMockRecorder) AddAgentRevision(agent_id, app_id, descriptor, checksum, name, version, applied_at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAgentRevision", reflect.TypeOf((*MockDBManager)(nil).AddAgentRevision), agent_id, app_id, descriptor, checksum, name, version, applied_at)
+}
+
+// GetAgentRevisions mocks base method
+func (m *MockDBManager) GetAgentRevisions(agent_id, app_id string) ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAgentRevisions", agent_id, app_id)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentRevisions indicates an expected call of GetAgentRevisions
+func (mr *MockDBManagerMockRecorder) GetAgentRevisions(agent_id, app_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentRevisions", reflect.TypeOf((*MockDBManager)(nil).GetAgentRevisions), agent_id, app_id)
+}
+
+// SetGroupDesiredState mocks base method
+func (m *MockDBManager) SetGroupDesiredState(group_id string, apps []map[string]string, prune bool) error {
+	ret := m.ctrl.Call(m, "SetGroupDesiredState", group_id, apps, prune)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetGroupDesiredState indicates an expected call of SetGroupDesiredState
+func (mr *MockDBManagerMockRecorder) SetGroupDesiredState(group_id, apps, prune interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGroupDesiredState", reflect.TypeOf((*MockDBManager)(nil).SetGroupDesiredState), group_id, apps, prune)
+}
+
+// UnsetGroupDesiredState mocks base method
+func (m *MockDBManager) UnsetGroupDesiredState(group_id string) error {
+	ret := m.ctrl.Call(m, "UnsetGroupDesiredState", group_id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsetGroupDesiredState indicates an expected call of UnsetGroupDesiredState
+func (mr *MockDBManagerMockRecorder) UnsetGroupDesiredState(group_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetGroupDesiredState", reflect.TypeOf((*MockDBManager)(nil).UnsetGroupDesiredState), group_id)
+}
+
+// GetGroupDesiredState mocks base method
+func (m *MockDBManager) GetGroupDesiredState(group_id string) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetGroupDesiredState", group_id)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupDesiredState indicates an expected call of GetGroupDesiredState
+func (mr *MockDBManagerMockRecorder) GetGroupDesiredState(group_id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupDesiredState", reflect.TypeOf((*MockDBManager)(nil).GetGroupDesiredState), group_id)
+}
+
+// GetAllGroupDesiredStates mocks base method
+func (m *MockDBManager) GetAllGroupDesiredStates() ([]map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetAllGroupDesiredStates")
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllGroupDesiredStates indicates an expected call of GetAllGroupDesiredStates
+func (mr *MockDBManagerMockRecorder) GetAllGroupDesiredStates() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGroupDesiredStates", reflect.TypeOf((*MockDBManager)(nil).GetAllGroupDesiredStates))
+}
+
+// UpdateGroupConvergence mocks base method
+func (m *MockDBManager) UpdateGroupConvergence(group_id, agent_id, status string, actions, failures []string, converged_at int64) error {
+	ret := m.ctrl.Call(m, "UpdateGroupConvergence", group_id, agent_id, status, actions, failures, converged_at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGroupConvergence indicates an expected call of UpdateGroupConvergence
+func (mr *MockDBManagerMockRecorder) UpdateGroupConvergence(group_id, agent_id, status, actions, failures, converged_at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupConvergence", reflect.TypeOf((*MockDBManager)(nil).UpdateGroupConvergence), group_id, agent_id, status, actions, failures, converged_at)
+}
+
 // MockDBConnection is a mock of DBConnection interface
 type MockDBConnection struct {
 	ctrl     *gomock.Controller
@@ -591,14 +1374,14 @@ func (m *MockDBConnection) EXPECT() *MockDBConnectionMockRecorder {
 }
 
 // Connect mocks base method
-func (m *MockDBConnection) Connect() (db.DBManager, error) {
-	ret := m.ctrl.Call(m, "Connect")
+func (m *MockDBConnection) Connect(ctx context.Context) (db.DBManager, error) {
+	ret := m.ctrl.Call(m, "Connect", ctx)
 	ret0, _ := ret[0].(db.DBManager)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Connect indicates an expected call of Connect
-func (mr *MockDBConnectionMockRecorder) Connect() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockDBConnection)(nil).Connect))
+func (mr *MockDBConnectionMockRecorder) Connect(ctx interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockDBConnection)(nil).Connect), ctx)
 }
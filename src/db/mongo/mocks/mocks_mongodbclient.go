@@ -21,6 +21,7 @@
 package mocks
 
 import (
+	context "context"
 	. "db/mongo"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -62,15 +63,25 @@ func (mr *MockBuilderMockRecorder) Connect(url interface{}) *gomock.Call {
 }
 
 // CreateDB mocks base method
-func (m *MockBuilder) CreateDB() (*MongoDBManager, error) {
-	ret := m.ctrl.Call(m, "CreateDB")
+func (m *MockBuilder) CreateDB(ctx context.Context) (*MongoDBManager, error) {
+	ret := m.ctrl.Call(m, "CreateDB", ctx)
 	ret0, _ := ret[0].(*MongoDBManager)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateDB indicates an expected call of CreateDB
-func (mr *MockBuilderMockRecorder) CreateDB() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDB", reflect.TypeOf((*MockBuilder)(nil).CreateDB))
+func (mr *MockBuilderMockRecorder) CreateDB(ctx interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDB", reflect.TypeOf((*MockBuilder)(nil).CreateDB), ctx)
+}
+
+// Close mocks base method
+func (m *MockBuilder) Close() {
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockBuilderMockRecorder) Close() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBuilder)(nil).Close))
 }
 
@@ -16,46 +16,225 @@
  *******************************************************************************/
 
 // Package db/mongo implements some functions to use mgo which is MongoDB driver for Go.
-// Service Deployment Agent Manager creates two collections.
-// The first is used for managing a list of agents and second is used for managing a list of group.
+// Service Deployment Agent Manager creates five collections.
+// The first is used for managing a list of agents, second is used for managing a list of group,
+// third is used for managing API keys of operators, fourth is used for recording events of agents
+// and fifth is used for managing the catalog of apps.
 package mongo
 
 import (
+	"commons/config"
 	"commons/errors"
+	"commons/labels"
 	"commons/logger"
+	"context"
 	. "db/mongo/wrapper"
 	"gopkg.in/mgo.v2/bson"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 const (
-	DB_NAME          = "DeploymentManagerDB"
-	AGENT_COLLECTION = "AGENT"
-	GROUP_COLLECTION = "GROUP"
+	AGENT_COLLECTION   = "AGENT"
+	GROUP_COLLECTION   = "GROUP"
+	KEY_COLLECTION     = "KEY"
+	EVENT_COLLECTION   = "EVENT"
+	CATALOG_COLLECTION = "CATALOG"
+
+	MAX_ADDRESS_HISTORY = 10 // number of address changes kept for each agent.
+	MAX_REVISIONS       = 10 // number of revisions of descriptors kept for each app of agent.
+	MAX_REVISION_TRIES  = 5  // number of tries to append a revision when other revisions are appended concurrently.
 )
 
+// appIdPattern is a pattern of app ids which are used as keys of fields of an agent.
+// A dot or a leading dollar sign in an app id would change the path of the field.
+var appIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 type (
 	Agent struct {
-		ID     bson.ObjectId `bson:"_id,omitempty"`
-		Host   string
-		Port   string
-		Apps   []string
-		Status string
+		ID          bson.ObjectId `bson:"_id,omitempty"`
+		Host        string
+		Port        string
+		Apps        []string
+		Status      string
+		LastSeen    int64
+		Interval    int
+		Metadata    Metadata
+		Labels      map[string]string
+		DeviceID    string
+		SecretHash  string
+		Addresses   []Address
+		HealthCheck string
+		Exempt      bool
+		Catalog     map[string]CatalogRef
+		Revisions   map[string][]Revision
+	}
+	CatalogRef struct {
+		App     string
+		Version string
+	}
+	Revision struct {
+		Number     int
+		Descriptor string
+		Checksum   string
+		Catalog    CatalogRef
+		AppliedAt  int64
+	}
+	Address struct {
+		Host      string
+		Port      string
+		ChangedAt int64
+	}
+	Metadata struct {
+		Hostname      string
+		OS            string
+		Arch          string
+		Kernel        string
+		DockerVersion string
+		SDAVersion    string
+		FreeCPU       float64
+		FreeMemory    int64
+		FreeDisk      int64
 	}
 	Group struct {
+		ID          bson.ObjectId `bson:"_id,omitempty"`
+		Members     []string
+		Desired     *DesiredState          `bson:",omitempty"`
+		Convergence map[string]Convergence `bson:",omitempty"`
+	}
+	DesiredState struct {
+		Apps  []DesiredApp
+		Prune bool
+	}
+	DesiredApp struct {
+		App     string
+		Version string
+		State   string
+	}
+	Convergence struct {
+		Status   string
+		Actions  []string
+		Failures []string
+		Time     int64
+	}
+	Key struct {
+		ID     bson.ObjectId `bson:"_id,omitempty"`
+		Name   string
+		Role   string
+		Groups []string
+		Hash   string
+	}
+	Event struct {
 		ID      bson.ObjectId `bson:"_id,omitempty"`
-		Members []string
+		AgentID string
+		Type    string
+		Time    int64
+		Detail  string
+	}
+	CatalogApp struct {
+		Name        string `bson:"_id"`
+		Description string
+		Versions    []CatalogVersion
+	}
+	CatalogVersion struct {
+		Version     string
+		Description string
+		Descriptor  string
+		Checksum    string
+		CreatedAt   int64
 	}
 )
 
 // convertToMap converts Agent object into a map.
 func (agent Agent) convertToMap() map[string]interface{} {
 	return map[string]interface{}{
-		"id":     agent.ID.Hex(),
-		"host":   agent.Host,
-		"port":   agent.Port,
-		"apps":   agent.Apps,
-		"status": agent.Status,
+		"id":          agent.ID.Hex(),
+		"host":        agent.Host,
+		"port":        agent.Port,
+		"apps":        agent.Apps,
+		"status":      agent.Status,
+		"lastseen":    agent.LastSeen,
+		"interval":    agent.Interval,
+		"metadata":    agent.Metadata.convertToMap(),
+		"labels":      agent.Labels,
+		"deviceid":    agent.DeviceID,
+		"addresses":   convertAddressesToMap(agent.Addresses),
+		"healthcheck": agent.HealthCheck,
+		"exempt":      agent.Exempt,
+		"catalog":     convertCatalogRefsToMap(agent.Catalog),
+	}
+}
+
+// convertCatalogRefsToMap converts catalog versions of apps into a map keyed by app id.
+func convertCatalogRefsToMap(refs map[string]CatalogRef) map[string]interface{} {
+	result := make(map[string]interface{}, len(refs))
+	for appId, ref := range refs {
+		result[appId] = map[string]interface{}{
+			"app":     ref.App,
+			"version": ref.Version,
+		}
+	}
+	return result
+}
+
+// convertAddressesToMap converts a list of Address objects into a list of maps.
+func convertAddressesToMap(addresses []Address) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(addresses))
+	for i, address := range addresses {
+		result[i] = map[string]interface{}{
+			"host":      address.Host,
+			"port":      address.Port,
+			"changedat": address.ChangedAt,
+		}
+	}
+	return result
+}
+
+// convertToMap converts Revision object into a map.
+// The catalog version is included only if the descriptor is from the catalog.
+func (revision Revision) convertToMap() map[string]interface{} {
+	result := map[string]interface{}{
+		"revision":   revision.Number,
+		"descriptor": revision.Descriptor,
+		"checksum":   revision.Checksum,
+		"appliedat":  revision.AppliedAt,
+	}
+	if revision.Catalog.App != "" {
+		result["catalog"] = map[string]interface{}{
+			"app":     revision.Catalog.App,
+			"version": revision.Catalog.Version,
+		}
+	}
+	return result
+}
+
+// convertToMap converts Metadata object into a map.
+func (metadata Metadata) convertToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"hostname":      metadata.Hostname,
+		"os":            metadata.OS,
+		"arch":          metadata.Arch,
+		"kernel":        metadata.Kernel,
+		"dockerversion": metadata.DockerVersion,
+		"sdaversion":    metadata.SDAVersion,
+		"freecpu":       metadata.FreeCPU,
+		"freememory":    metadata.FreeMemory,
+		"freedisk":      metadata.FreeDisk,
+	}
+}
+
+// sortValue returns the value of the field used to sort agents.
+// If the field is not a sortable field, an empty string is returned.
+func (agent Agent) sortValue(field string) string {
+	switch strings.TrimPrefix(field, DESCENDING) {
+	case "host":
+		return agent.Host
+	case "status":
+		return agent.Status
 	}
+	return ""
 }
 
 // convertToMap converts Group object into a map.
@@ -66,19 +245,100 @@ func (group Group) convertToMap() map[string]interface{} {
 	}
 }
 
-// MongoDBManager provides persistence logic for "agent" and "group" collection.
+// convertDesiredStateToMap converts the desired state of Group object into a map,
+// with the convergence of each member keyed by agent id.
+func (group Group) convertDesiredStateToMap() map[string]interface{} {
+	apps := make([]map[string]interface{}, len(group.Desired.Apps))
+	for i, app := range group.Desired.Apps {
+		apps[i] = map[string]interface{}{
+			"app":     app.App,
+			"version": app.Version,
+			"state":   app.State,
+		}
+	}
+
+	convergence := make(map[string]interface{}, len(group.Convergence))
+	for agentId, member := range group.Convergence {
+		convergence[agentId] = map[string]interface{}{
+			"status":   member.Status,
+			"actions":  member.Actions,
+			"failures": member.Failures,
+			"time":     member.Time,
+		}
+	}
+
+	return map[string]interface{}{
+		"id":          group.ID.Hex(),
+		"apps":        apps,
+		"prune":       group.Desired.Prune,
+		"convergence": convergence,
+	}
+}
+
+// convertToMap converts Key object into a map.
+// The hash of the key is not included.
+func (key Key) convertToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":     key.ID.Hex(),
+		"name":   key.Name,
+		"role":   key.Role,
+		"groups": key.Groups,
+	}
+}
+
+// convertToMap converts Event object into a map.
+func (event Event) convertToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":      event.ID.Hex(),
+		"agentid": event.AgentID,
+		"type":    event.Type,
+		"time":    event.Time,
+		"detail":  event.Detail,
+	}
+}
+
+// convertToMap converts CatalogApp object into a map.
+// Descriptors of versions are not included.
+func (app CatalogApp) convertToMap() map[string]interface{} {
+	versions := make([]map[string]interface{}, len(app.Versions))
+	for i, version := range app.Versions {
+		versions[i] = version.convertToMap()
+		delete(versions[i], "descriptor")
+	}
+	return map[string]interface{}{
+		"name":        app.Name,
+		"description": app.Description,
+		"versions":    versions,
+	}
+}
+
+// convertToMap converts CatalogVersion object into a map.
+func (version CatalogVersion) convertToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"version":     version.Version,
+		"description": version.Description,
+		"descriptor":  version.Descriptor,
+		"checksum":    version.Checksum,
+		"createdat":   version.CreatedAt,
+	}
+}
+
+// MongoDBManager provides persistence logic for "agent", "group", "key", "event" and "catalog" collection.
 type (
 	Builder interface {
 		Connect(url string) error
-		CreateDB() (*MongoDBManager, error)
+		CreateDB(ctx context.Context) (*MongoDBManager, error)
+		Close()
 	}
 
 	MongoBuilder struct {
+		mutex   sync.Mutex
 		session Session
 	}
 
 	MongoDBManager struct {
 		mgoSession Session
+		ctx        context.Context
 	}
 )
 
@@ -88,16 +348,24 @@ func init() {
 	mgoDial = MongoDial{}
 }
 
-// Connect establishes a new session to the database identified by the given url.
+// Connect establishes a session to the database identified by the given url.
+// The session is established once and shared by all MongoDBManager objects.
 // If the connection is unsuccessful, this function returns DBConnectionError object.
 func (builder *MongoBuilder) Connect(url string) error {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
+	builder.mutex.Lock()
+	defer builder.mutex.Unlock()
+
+	if builder.session != nil {
+		return nil
+	}
+
 	// Create a MongoDB Session.
 	session, err := mgoDial.Dial(url)
 	if err != nil {
-		return errors.DBConnectionError{err.Error()}
+		return errors.DBConnectionError{Cause: err}
 	}
 
 	builder.session = session
@@ -105,40 +373,71 @@ func (builder *MongoBuilder) Connect(url string) error {
 }
 
 // CreateDB returns the MongoDBManager object used to interact with databases.
+// Logs of the MongoDBManager include the request id carried by ctx.
 // If the session is nil, this function returns DBOperationError object.
 // otherwise, this function returns an error as nil.
-func (builder *MongoBuilder) CreateDB() (*MongoDBManager, error) {
+func (builder *MongoBuilder) CreateDB(ctx context.Context) (*MongoDBManager, error) {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
+	builder.mutex.Lock()
+	defer builder.mutex.Unlock()
+
 	if builder.session == nil {
 		return nil, errors.DBOperationError{}
 	}
 
+	// Each MongoDBManager owns a copy of the shared session,
+	// so that closing it releases only its own socket.
 	return &MongoDBManager{
-		mgoSession: builder.session,
+		mgoSession: builder.session.Copy(),
+		ctx:        ctx,
 	}, nil
 }
 
-// Close terminates the session.
-func (client *MongoDBManager) Close() {
+// Close closes the shared session established by Connect.
+// MongoDBManager objects created before remain usable until they are closed.
+func (builder *MongoBuilder) Close() {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
+	builder.mutex.Lock()
+	defer builder.mutex.Unlock()
+
+	if builder.session != nil {
+		builder.session.Close()
+		builder.session = nil
+	}
+}
+
+// Close terminates the session.
+func (client *MongoDBManager) Close() {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
 	client.mgoSession.Close()
 }
 
-// getCollection returns a value representing the named collection.
+// getCollection returns a value representing the named collection
+// of the configured database.
 func (client *MongoDBManager) getCollection(collectionName string) Collection {
-	return client.mgoSession.DB(DB_NAME).C(collectionName)
+	return client.mgoSession.DB(config.Get().DB.Name).C(collectionName)
+}
+
+// validateAppId returns InvalidParam error if the app id cannot be used as a key of a field.
+func validateAppId(app_id string) error {
+	if !appIdPattern.MatchString(app_id) {
+		return errors.InvalidParam{Message: "app id should consist of alphanumerics, '-' and '_'", Kind: errors.APP, ID: app_id}
+	}
+	return nil
 }
 
 // AddAgent inserts new agent to 'agent' collection.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
 func (client *MongoDBManager) AddAgent(host string, port string, status string) (map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	agent := Agent{
 		ID:     bson.NewObjectId(),
@@ -149,7 +448,7 @@ func (client *MongoDBManager) AddAgent(host string, port string, status string)
 
 	err := client.getCollection(AGENT_COLLECTION).Insert(agent)
 	if err != nil {
-		return nil, ConvertMongoError(err)
+		return nil, ConvertMongoError(err, errors.AGENT, "")
 	}
 
 	result := agent.convertToMap()
@@ -157,312 +456,797 @@ func (client *MongoDBManager) AddAgent(host string, port string, status string)
 }
 
 // UpdateAgentAddress updates ip,port of agent specified by agent_id parameter.
+// The change is also recorded with changed_at, in seconds since the epoch,
+// and only the latest MAX_ADDRESS_HISTORY changes are kept.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) UpdateAgentAddress(agent_id string, host string, port string) error {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) UpdateAgentAddress(agent_id string, host string, port string, changed_at int64) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
 		return err
 	}
 
 	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
-	update := bson.M{"$set": bson.M{"host": host, "port": port}}
+	address := Address{Host: host, Port: port, ChangedAt: changed_at}
+	update := bson.M{
+		"$set":  bson.M{"host": host, "port": port},
+		"$push": bson.M{"addresses": bson.M{"$each": []Address{address}, "$slice": -MAX_ADDRESS_HISTORY}},
+	}
 	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
 	if err != nil {
-		return ConvertMongoError(err, "Failed to update address")
+		return ConvertMongoError(err, errors.AGENT, agent_id)
 	}
 	return err
 }
 
-// UpdateAgentStatus updates status of agent specified by agent_id parameter.
+// UpdateAgentDeviceID updates the identity reported by agent specified by agent_id parameter.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) UpdateAgentStatus(agent_id string, status string) error {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) UpdateAgentDeviceID(agent_id string, device_id string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
 		return err
 	}
 
 	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
-	update := bson.M{"$set": bson.M{"status": status}}
+	update := bson.M{"$set": bson.M{"deviceid": device_id}}
 	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
 	if err != nil {
-		return ConvertMongoError(err, "Failed to update status")
+		return ConvertMongoError(err, errors.AGENT, agent_id)
 	}
 	return err
 }
 
-// GetAgent returns single document specified by agent_id parameter.
+// UpdateAgentSecret updates the hash of the secret issued to agent specified by agent_id parameter.
+// The hash is not included in documents returned for agents.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) GetAgent(agent_id string) (map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) UpdateAgentSecret(agent_id string, hash string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
-		return nil, err
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
 	}
 
-	agent := Agent{}
 	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
-	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
+	update := bson.M{"$set": bson.M{"secrethash": hash}}
+	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
 	if err != nil {
-		return nil, ConvertMongoError(err, agent_id)
+		return ConvertMongoError(err, errors.AGENT, agent_id)
 	}
-
-	result := agent.convertToMap()
-	return result, err
+	return err
 }
 
-// GetAgentByIP returns single document specified by ip parameter.
+// UpdateAgentHealthCheck updates the health check mode of agent specified by agent_id parameter.
 // If successful, this function returns an error as nil.
-func (client *MongoDBManager) GetAgentByIP(ip string) (map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) UpdateAgentHealthCheck(agent_id string, mode string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
-	agent := Agent{}
-	query := bson.M{"host": ip}
-	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
-	if err != nil {
-		return nil, ConvertMongoError(err, ip)
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
 	}
 
-	result := agent.convertToMap()
-	return result, err
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	update := bson.M{"$set": bson.M{"healthcheck": mode}}
+	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.AGENT, agent_id)
+	}
+	return err
 }
 
-// GetAllAgents returns all documents from 'agent' collection.
+// UpdateAgentExempt updates whether agent specified by agent_id parameter is exempt from the retention policy.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) GetAllAgents() ([]map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) UpdateAgentExempt(agent_id string, exempt bool) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
-	agents := []Agent{}
-	err := client.getCollection(AGENT_COLLECTION).Find(nil).All(&agents)
-	if err != nil {
-		return nil, ConvertMongoError(err)
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
 	}
 
-	result := make([]map[string]interface{}, len(agents))
-	for i, agent := range agents {
-		result[i] = agent.convertToMap()
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	update := bson.M{"$set": bson.M{"exempt": exempt}}
+	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.AGENT, agent_id)
 	}
-	return result, err
+	return err
 }
 
-// GetAgentByAppID returns single document specified by agent_id parameter.
+// UpdateAgentStatus updates status of agent specified by agent_id parameter.
 // If successful, this function returns an error as nil.
-// But if the target agent does not include the given app_id,
-// an appropriate error will be returned.
-func (client *MongoDBManager) GetAgentByAppID(agent_id string, app_id string) (map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) UpdateAgentStatus(agent_id string, status string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
-		return nil, err
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
 	}
 
-	agent := Agent{}
-	query := bson.M{"_id": bson.ObjectIdHex(agent_id), "apps": bson.M{"$in": []string{app_id}}}
-	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	update := bson.M{"$set": bson.M{"status": status}}
+	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
 	if err != nil {
-		return nil, ConvertMongoError(err, agent_id)
+		return ConvertMongoError(err, errors.AGENT, agent_id)
 	}
-
-	result := agent.convertToMap()
-	return result, err
+	return err
 }
 
-// AddAppToAgent adds the specific app to the target agent.
+// UpdateAgentLastSeen updates the time of the last ping, in seconds since the epoch,
+// and the interval of pings of agent specified by agent_id parameter.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) AddAppToAgent(agent_id string, app_id string) error {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) UpdateAgentLastSeen(agent_id string, last_seen int64, interval int) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
 		return err
 	}
 
 	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
-	update := bson.M{"$addToSet": bson.M{"apps": app_id}}
+	update := bson.M{"$set": bson.M{"lastseen": last_seen, "interval": interval}}
 	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
 	if err != nil {
-		return ConvertMongoError(err, agent_id)
+		return ConvertMongoError(err, errors.AGENT, agent_id)
 	}
 	return err
 }
 
-// DeleteAppFromAgent deletes the specific app from the target agent.
+// UpdateAgentMetadata updates metadata of agent specified by agent_id parameter.
+// Only fields included in metadata parameter are updated, and the others are left as they are.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) DeleteAppFromAgent(agent_id string, app_id string) error {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) UpdateAgentMetadata(agent_id string, metadata map[string]interface{}) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
 		return err
 	}
 
+	fields := bson.M{}
+	for key, value := range metadata {
+		fields["metadata."+key] = value
+	}
+
 	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
-	update := bson.M{"$pull": bson.M{"apps": app_id}}
+	update := bson.M{"$set": fields}
 	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
 	if err != nil {
-		return ConvertMongoError(err, agent_id)
+		return ConvertMongoError(err, errors.AGENT, agent_id)
 	}
 	return err
 }
 
-// DeleteAgent deletes single document from 'agent' collection.
+// UpdateAgentLabels sets labels and removes labels whose keys are in removed parameter
+// of agent specified by agent_id parameter. The other labels are left as they are.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) DeleteAgent(agent_id string) error {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) UpdateAgentLabels(agent_id string, labels map[string]string, removed []string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
 		return err
 	}
 
+	update := bson.M{}
+	if len(labels) != 0 {
+		fields := bson.M{}
+		for key, value := range labels {
+			fields["labels."+key] = value
+		}
+		update["$set"] = fields
+	}
+	if len(removed) != 0 {
+		fields := bson.M{}
+		for _, key := range removed {
+			fields["labels."+key] = ""
+		}
+		update["$unset"] = fields
+	}
+	if len(update) == 0 {
+		return nil
+	}
+
 	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
-	err := client.getCollection(AGENT_COLLECTION).Remove(query)
+	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
 	if err != nil {
-		return ConvertMongoError(err, agent_id)
+		return ConvertMongoError(err, errors.AGENT, agent_id)
 	}
 	return err
 }
 
-// CreateGroup inserts new Group to 'group' collection.
+// GetAgent returns single document specified by agent_id parameter.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) CreateGroup() (map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) GetAgent(agent_id string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
-	group := Group{
-		ID: bson.NewObjectId(),
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return nil, err
 	}
 
-	err := client.getCollection(GROUP_COLLECTION).Insert(group)
+	agent := Agent{}
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
 	if err != nil {
-		return nil, ConvertMongoError(err)
+		return nil, ConvertMongoError(err, errors.AGENT, agent_id)
 	}
 
-	result := group.convertToMap()
+	result := agent.convertToMap()
 	return result, err
 }
 
-// GetGroup returns single document specified by group_id parameter.
+// GetAgentByIP returns single document specified by ip parameter.
 // If successful, this function returns an error as nil.
-// otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) GetGroup(group_id string) (map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
-
-	// Verify id is ObjectId, otherwise fail
-	if !bson.IsObjectIdHex(group_id) {
-		err := errors.InvalidObjectId{group_id}
-		return nil, err
-	}
+func (client *MongoDBManager) GetAgentByIP(ip string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
-	group := Group{}
-	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
-	err := client.getCollection(GROUP_COLLECTION).Find(query).One(&group)
+	agent := Agent{}
+	query := bson.M{"host": ip}
+	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
 	if err != nil {
-		return nil, ConvertMongoError(err, group_id)
+		return nil, ConvertMongoError(err, errors.AGENT, ip)
 	}
 
-	result := group.convertToMap()
+	result := agent.convertToMap()
 	return result, err
 }
 
-// GetAllGroups returns all documents from 'group' collection.
+// GetAgentByDeviceID returns single document specified by device_id parameter,
+// which is the identity reported by the agent.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) GetAllGroups() ([]map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) GetAgentByDeviceID(device_id string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
-	groups := []Group{}
-	err := client.getCollection(GROUP_COLLECTION).Find(nil).All(&groups)
+	agent := Agent{}
+	query := bson.M{"deviceid": device_id}
+	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
 	if err != nil {
-		return nil, ConvertMongoError(err)
+		return nil, ConvertMongoError(err, errors.AGENT, device_id)
 	}
 
-	result := make([]map[string]interface{}, len(groups))
-	for i, group := range groups {
-		result[i] = group.convertToMap()
-	}
+	result := agent.convertToMap()
 	return result, err
 }
 
-// JoinGroup adds the specific agent to a list of group members.
+// GetAgentSecret returns the hash of the secret issued to agent specified by agent_id parameter,
+// or an empty string if no secret has been issued to the agent.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) JoinGroup(group_id string, agent_id string) error {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) GetAgentSecret(agent_id string) (string, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
-	if !bson.IsObjectIdHex(group_id) {
-		err := errors.InvalidObjectId{group_id}
-		return err
-	}
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
-		return err
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return "", err
 	}
 
-	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
-	update := bson.M{"$addToSet": bson.M{"members": agent_id}}
-	err := client.getCollection(GROUP_COLLECTION).Update(query, update)
+	agent := Agent{}
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
 	if err != nil {
-		return ConvertMongoError(err, group_id)
+		return "", ConvertMongoError(err, errors.AGENT, agent_id)
 	}
-	return err
+	return agent.SecretHash, err
 }
 
-// LeaveGroup deletes the specific agent from a list of group members.
+// GetAllAgents returns all documents from 'agent' collection.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (client *MongoDBManager) LeaveGroup(group_id string, agent_id string) error {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (client *MongoDBManager) GetAllAgents() ([]map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
-	// Verify id is ObjectId, otherwise fail
-	if !bson.IsObjectIdHex(group_id) {
-		err := errors.InvalidObjectId{group_id}
-		return err
-	}
+	agents := []Agent{}
+	err := client.getCollection(AGENT_COLLECTION).Find(nil).All(&agents)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.AGENT, "")
+	}
+
+	result := make([]map[string]interface{}, len(agents))
+	for i, agent := range agents {
+		result[i] = agent.convertToMap()
+	}
+	return result, err
+}
+
+// GetAgentsByQuery returns a page of documents from 'agent' collection matching the filter.
+// The filter may have the following conditions.
+//
+//    status: status of agents.
+//    host: glob pattern of hosts of agents, e.g. '10.0.*'.
+//    app: id of an app installed on agents.
+//    group: id of a group which agents belong to.
+//    label.<key>: value of the label of agents with the key.
+//
+// Documents are sorted by the field given by sort, i.e. 'id', 'host' or 'status',
+// and the field prefixed by '-' sorts documents in descending order.
+// If limit is positive, at most limit documents following cursor are returned
+// with the cursor of the next page, which is empty if there are no more documents.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetAgentsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	query := bson.M{}
+	for key, value := range filter {
+		switch key {
+		case "status":
+			query["status"] = value
+		case "host":
+			query["host"] = globToRegex(value)
+		case "app":
+			query["apps"] = value
+		case "group":
+			group, err := client.GetGroup(value)
+			if err != nil {
+				return nil, "", err
+			}
+			members := []bson.ObjectId{}
+			for _, agent_id := range group["members"].([]string) {
+				if bson.IsObjectIdHex(agent_id) {
+					members = append(members, bson.ObjectIdHex(agent_id))
+				}
+			}
+			query[ID_FIELD] = bson.M{"$in": members}
+		default:
+			if strings.HasPrefix(key, labels.FILTER_PREFIX) {
+				query["labels."+strings.TrimPrefix(key, labels.FILTER_PREFIX)] = value
+				continue
+			}
+			return nil, "", errors.InvalidParam{Message: "unsupported filter: " + key}
+		}
+	}
+
+	query, order, err := makePageQuery(query, sort, agentSortFields, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	found := client.getCollection(AGENT_COLLECTION).Find(query).Sort(order...)
+	if limit > 0 {
+		// Get one more document to know whether the next page exists.
+		found = found.Limit(limit + 1)
+	}
+
+	agents := []Agent{}
+	err = found.All(&agents)
+	if err != nil {
+		return nil, "", ConvertMongoError(err, errors.AGENT, "")
+	}
+
+	next := ""
+	if limit > 0 && len(agents) > limit {
+		agents = agents[:limit]
+		last := agents[limit-1]
+		next = encodeCursor(last.ID, last.sortValue(order[0]))
+	}
+
+	result := make([]map[string]interface{}, len(agents))
+	for i, agent := range agents {
+		result[i] = agent.convertToMap()
+	}
+	return result, next, err
+}
+
+// GetAgentByAppID returns single document specified by agent_id parameter.
+// If successful, this function returns an error as nil.
+// But if the target agent does not include the given app_id,
+// an appropriate error will be returned.
+func (client *MongoDBManager) GetAgentByAppID(agent_id string, app_id string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return nil, err
+	}
+
+	agent := Agent{}
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id), "apps": bson.M{"$in": []string{app_id}}}
+	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.APP, app_id)
+	}
+
+	result := agent.convertToMap()
+	return result, err
+}
+
+// AddAppToAgent adds the specific app to the target agent.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) AddAppToAgent(agent_id string, app_id string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	update := bson.M{"$addToSet": bson.M{"apps": app_id}}
+	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.AGENT, agent_id)
+	}
+	return err
+}
+
+// DeleteAppFromAgent deletes the specific app from the target agent.
+// The catalog version and the revisions recorded for the app are also removed.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) DeleteAppFromAgent(agent_id string, app_id string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	update := bson.M{"$pull": bson.M{"apps": app_id}}
+	// Nothing can be recorded for an app whose id cannot be a key of a field.
+	if validateAppId(app_id) == nil {
+		update["$unset"] = bson.M{"catalog." + app_id: "", "revisions." + app_id: ""}
+	}
+	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.AGENT, agent_id)
+	}
+	return err
+}
+
+// UpdateAgentCatalog records the catalog app and version of the app specified by app_id
+// on agent specified by agent_id parameter.
+// If name is empty, the recorded catalog version of the app is removed.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) UpdateAgentCatalog(agent_id string, app_id string, name string, version string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
+	}
+
+	if err := validateAppId(app_id); err != nil {
+		return err
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	update := bson.M{"$unset": bson.M{"catalog." + app_id: ""}}
+	if name != "" {
+		update = bson.M{"$set": bson.M{"catalog." + app_id: CatalogRef{App: name, Version: version}}}
+	}
+	err := client.getCollection(AGENT_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.AGENT, agent_id)
+	}
+	return err
+}
+
+// AddAgentRevision appends new revision of the descriptor applied to the app specified by app_id
+// on agent specified by agent_id parameter, and returns the number of the revision.
+// Revisions are numbered from 1, and only the latest MAX_REVISIONS revisions are kept.
+// The revision is appended only if no revision has taken its number in the meantime,
+// otherwise the number is allocated again, so concurrent revisions never share a number.
+// The catalog app and version are empty if the descriptor is not from the catalog.
+// The time of the application is given in seconds since the epoch.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) AddAgentRevision(agent_id string, app_id string, descriptor string, checksum string, name string, version string, applied_at int64) (int, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return 0, err
+	}
+	if err := validateAppId(app_id); err != nil {
+		return 0, err
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	for try := 0; try < MAX_REVISION_TRIES; try++ {
+		agent := Agent{}
+		err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
+		if err != nil {
+			return 0, ConvertMongoError(err, errors.AGENT, agent_id)
+		}
+
+		number := 1
+		if revisions := agent.Revisions[app_id]; len(revisions) != 0 {
+			number = revisions[len(revisions)-1].Number + 1
+		}
+
+		revision := Revision{
+			Number:     number,
+			Descriptor: descriptor,
+			Checksum:   checksum,
+			Catalog:    CatalogRef{App: name, Version: version},
+			AppliedAt:  applied_at,
+		}
+		// The agent does not match if a concurrent revision has taken the number.
+		selector := bson.M{"_id": bson.ObjectIdHex(agent_id), "revisions." + app_id + ".number": bson.M{"$ne": number}}
+		update := bson.M{
+			"$push": bson.M{"revisions." + app_id: bson.M{"$each": []Revision{revision}, "$slice": -MAX_REVISIONS}},
+		}
+		err = client.getCollection(AGENT_COLLECTION).Update(selector, update)
+		if err == nil {
+			return number, nil
+		}
+		err = ConvertMongoError(err, errors.AGENT, agent_id)
+		if _, taken := err.(errors.NotFound); !taken {
+			return 0, err
+		}
+	}
+	return 0, errors.DBOperationError{Message: "revision number is taken by concurrent revisions", Kind: errors.APP, ID: app_id}
+}
+
+// GetAgentRevisions returns revisions of the descriptor applied to the app specified by app_id
+// on agent specified by agent_id parameter, oldest first.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetAgentRevisions(agent_id string, app_id string) ([]map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return nil, err
+	}
+
+	agent := Agent{}
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	err := client.getCollection(AGENT_COLLECTION).Find(query).One(&agent)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.AGENT, agent_id)
+	}
+
+	revisions := agent.Revisions[app_id]
+	result := make([]map[string]interface{}, len(revisions))
+	for i, revision := range revisions {
+		result[i] = revision.convertToMap()
+	}
+	return result, err
+}
+
+// DeleteAgent deletes single document from 'agent' collection.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) DeleteAgent(agent_id string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(agent_id)}
+	err := client.getCollection(AGENT_COLLECTION).Remove(query)
+	if err != nil {
+		return ConvertMongoError(err, errors.AGENT, agent_id)
+	}
+	return err
+}
+
+// CreateGroup inserts new Group to 'group' collection.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) CreateGroup() (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	group := Group{
+		ID: bson.NewObjectId(),
+	}
+
+	err := client.getCollection(GROUP_COLLECTION).Insert(group)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.GROUP, "")
+	}
+
+	result := group.convertToMap()
+	return result, err
+}
+
+// GetGroup returns single document specified by group_id parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetGroup(group_id string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(group_id) {
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
+		return nil, err
+	}
+
+	group := Group{}
+	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
+	err := client.getCollection(GROUP_COLLECTION).Find(query).One(&group)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.GROUP, group_id)
+	}
+
+	result := group.convertToMap()
+	return result, err
+}
+
+// GetAllGroups returns all documents from 'group' collection.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetAllGroups() ([]map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	groups := []Group{}
+	err := client.getCollection(GROUP_COLLECTION).Find(nil).All(&groups)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.GROUP, "")
+	}
+
+	result := make([]map[string]interface{}, len(groups))
+	for i, group := range groups {
+		result[i] = group.convertToMap()
+	}
+	return result, err
+}
+
+// GetGroupsByQuery returns a page of documents from 'group' collection matching the filter.
+// The filter may have 'agent' condition which is id of an agent belonging to groups.
+// Documents are sorted by 'id', and '-id' sorts documents in descending order.
+// If limit is positive, at most limit documents following cursor are returned
+// with the cursor of the next page, which is empty if there are no more documents.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetGroupsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	query := bson.M{}
+	for key, value := range filter {
+		switch key {
+		case "agent":
+			query["members"] = value
+		default:
+			return nil, "", errors.InvalidParam{Message: "unsupported filter: " + key}
+		}
+	}
+
+	query, order, err := makePageQuery(query, sort, groupSortFields, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	found := client.getCollection(GROUP_COLLECTION).Find(query).Sort(order...)
+	if limit > 0 {
+		// Get one more document to know whether the next page exists.
+		found = found.Limit(limit + 1)
+	}
+
+	groups := []Group{}
+	err = found.All(&groups)
+	if err != nil {
+		return nil, "", ConvertMongoError(err, errors.GROUP, "")
+	}
+
+	next := ""
+	if limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+		next = encodeCursor(groups[limit-1].ID, "")
+	}
+
+	result := make([]map[string]interface{}, len(groups))
+	for i, group := range groups {
+		result[i] = group.convertToMap()
+	}
+	return result, next, err
+}
+
+// JoinGroup adds the specific agent to a list of group members.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) JoinGroup(group_id string, agent_id string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(group_id) {
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
+		return err
+	}
 	if !bson.IsObjectIdHex(agent_id) {
-		err := errors.InvalidObjectId{agent_id}
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
 		return err
 	}
 
 	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
-	update := bson.M{"$pull": bson.M{"members": agent_id}}
+	update := bson.M{"$addToSet": bson.M{"members": agent_id}}
 	err := client.getCollection(GROUP_COLLECTION).Update(query, update)
 	if err != nil {
-		return ConvertMongoError(err, group_id)
+		return ConvertMongoError(err, errors.GROUP, group_id)
+	}
+	return err
+}
+
+// LeaveGroup deletes the specific agent from a list of group members,
+// and removes the convergence of the agent.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) LeaveGroup(group_id string, agent_id string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(group_id) {
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
+		return err
+	}
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
+	update := bson.M{"$pull": bson.M{"members": agent_id}, "$unset": bson.M{"convergence." + agent_id: ""}}
+	err := client.getCollection(GROUP_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.GROUP, group_id)
 	}
 	return err
 }
@@ -471,12 +1255,12 @@ func (client *MongoDBManager) LeaveGroup(group_id string, agent_id string) error
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
 func (client *MongoDBManager) GetGroupMembers(group_id string) ([]map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(group_id) {
-		err := errors.InvalidObjectId{group_id}
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
 		return nil, err
 	}
 
@@ -502,12 +1286,12 @@ func (client *MongoDBManager) GetGroupMembers(group_id string) ([]map[string]int
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
 func (client *MongoDBManager) GetGroupMembersByAppID(group_id string, app_id string) ([]map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(group_id) {
-		err := errors.InvalidObjectId{group_id}
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
 		return nil, err
 	}
 
@@ -532,19 +1316,461 @@ func (client *MongoDBManager) GetGroupMembersByAppID(group_id string, app_id str
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
 func (client *MongoDBManager) DeleteGroup(group_id string) error {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
 
 	// Verify id is ObjectId, otherwise fail
 	if !bson.IsObjectIdHex(group_id) {
-		err := errors.InvalidObjectId{group_id}
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
 		return err
 	}
 
 	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
 	err := client.getCollection(GROUP_COLLECTION).Remove(query)
 	if err != nil {
-		return ConvertMongoError(err, group_id)
+		return ConvertMongoError(err, errors.GROUP, group_id)
+	}
+	return err
+}
+
+// SetGroupDesiredState declares the desired state of group specified by group_id parameter.
+// Each app is a map which has 'app', 'version' and 'state' of an app in the catalog.
+// If prune is true, apps which are not declared are removed from members.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) SetGroupDesiredState(group_id string, apps []map[string]string, prune bool) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(group_id) {
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
+		return err
+	}
+
+	desired := DesiredState{Apps: make([]DesiredApp, len(apps)), Prune: prune}
+	for i, app := range apps {
+		desired.Apps[i] = DesiredApp{App: app["app"], Version: app["version"], State: app["state"]}
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
+	update := bson.M{"$set": bson.M{"desired": desired}}
+	err := client.getCollection(GROUP_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.GROUP, group_id)
+	}
+	return err
+}
+
+// UnsetGroupDesiredState removes the desired state and the convergence of members
+// of group specified by group_id parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) UnsetGroupDesiredState(group_id string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(group_id) {
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
+		return err
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
+	update := bson.M{"$unset": bson.M{"desired": "", "convergence": ""}}
+	err := client.getCollection(GROUP_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.GROUP, group_id)
+	}
+	return err
+}
+
+// GetGroupDesiredState returns the desired state of group specified by group_id parameter
+// with the convergence of each member keyed by agent id.
+// If the group does not declare a desired state, NotFound error will be returned.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetGroupDesiredState(group_id string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(group_id) {
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
+		return nil, err
+	}
+
+	group := Group{}
+	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
+	err := client.getCollection(GROUP_COLLECTION).Find(query).One(&group)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.GROUP, group_id)
+	}
+	if group.Desired == nil {
+		return nil, errors.NotFound{Message: "no desired state", Kind: errors.GROUP, ID: group_id}
+	}
+
+	return group.convertDesiredStateToMap(), err
+}
+
+// GetAllGroupDesiredStates returns the desired state of all groups which declare one.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetAllGroupDesiredStates() ([]map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	groups := []Group{}
+	query := bson.M{"desired": bson.M{"$exists": true}}
+	err := client.getCollection(GROUP_COLLECTION).Find(query).All(&groups)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.GROUP, "")
+	}
+
+	result := make([]map[string]interface{}, len(groups))
+	for i, group := range groups {
+		result[i] = group.convertDesiredStateToMap()
+	}
+	return result, err
+}
+
+// UpdateGroupConvergence records the result of the last convergence of agent specified by agent_id
+// toward the desired state of group specified by group_id parameter.
+// The actions taken and the failures are given as descriptions, and the time in seconds since the epoch.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) UpdateGroupConvergence(group_id string, agent_id string, status string, actions []string, failures []string, converged_at int64) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(group_id) {
+		err := errors.InvalidObjectId{Kind: errors.GROUP, ID: group_id}
+		return err
+	}
+	if !bson.IsObjectIdHex(agent_id) {
+		err := errors.InvalidObjectId{Kind: errors.AGENT, ID: agent_id}
+		return err
+	}
+
+	convergence := Convergence{Status: status, Actions: actions, Failures: failures, Time: converged_at}
+	query := bson.M{"_id": bson.ObjectIdHex(group_id)}
+	update := bson.M{"$set": bson.M{"convergence." + agent_id: convergence}}
+	err := client.getCollection(GROUP_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.GROUP, group_id)
+	}
+	return err
+}
+
+// AddKey inserts new API key to 'key' collection.
+// The key is granted the role, and limited to the groups unless groups is empty.
+// Only the hash of the key is stored.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) AddKey(name string, role string, groups []string, hash string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	key := Key{
+		ID:     bson.NewObjectId(),
+		Name:   name,
+		Role:   role,
+		Groups: groups,
+		Hash:   hash,
+	}
+
+	err := client.getCollection(KEY_COLLECTION).Insert(key)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.KEY, "")
+	}
+
+	result := key.convertToMap()
+	return result, err
+}
+
+// GetKeyByHash returns single document specified by hash parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetKeyByHash(hash string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	key := Key{}
+	query := bson.M{"hash": hash}
+	err := client.getCollection(KEY_COLLECTION).Find(query).One(&key)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.KEY, "")
+	}
+
+	result := key.convertToMap()
+	return result, err
+}
+
+// GetAllKeys returns all documents from 'key' collection.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetAllKeys() ([]map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	keys := []Key{}
+	err := client.getCollection(KEY_COLLECTION).Find(nil).All(&keys)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.KEY, "")
+	}
+
+	result := make([]map[string]interface{}, len(keys))
+	for i, key := range keys {
+		result[i] = key.convertToMap()
+	}
+	return result, err
+}
+
+// DeleteKey deletes single document specified by key_id parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) DeleteKey(key_id string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	// Verify id is ObjectId, otherwise fail
+	if !bson.IsObjectIdHex(key_id) {
+		err := errors.InvalidObjectId{Kind: errors.KEY, ID: key_id}
+		return err
+	}
+
+	query := bson.M{"_id": bson.ObjectIdHex(key_id)}
+	err := client.getCollection(KEY_COLLECTION).Remove(query)
+	if err != nil {
+		return ConvertMongoError(err, errors.KEY, key_id)
+	}
+	return err
+}
+
+// AddEvent inserts new event of agent specified by agent_id parameter to 'event' collection.
+// The time of the event is given in seconds since the epoch.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) AddEvent(agent_id string, event_type string, time int64, detail string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	event := Event{
+		ID:      bson.NewObjectId(),
+		AgentID: agent_id,
+		Type:    event_type,
+		Time:    time,
+		Detail:  detail,
+	}
+
+	err := client.getCollection(EVENT_COLLECTION).Insert(event)
+	if err != nil {
+		return ConvertMongoError(err, errors.EVENT, "")
 	}
 	return err
 }
+
+// GetEventsByQuery returns a page of documents from 'event' collection matching the filter.
+// The following filters are supported.
+//
+//    agent: id of the agent of events.
+//    type: comma separated types of events.
+//    from: events at or after the time in seconds since the epoch.
+//    to: events at or before the time in seconds since the epoch.
+//
+// Documents are sorted by the field given by sort, i.e. 'id', which is the order of insertion.
+// If limit is positive, at most limit documents following cursor are returned
+// with the cursor of the next page, which is empty if there are no more documents.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetEventsByQuery(filter map[string]string, sort string, limit int, cursor string) ([]map[string]interface{}, string, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	query := bson.M{}
+	period := bson.M{}
+	for key, value := range filter {
+		switch key {
+		case "agent":
+			if !bson.IsObjectIdHex(value) {
+				return nil, "", errors.InvalidObjectId{Kind: errors.AGENT, ID: value}
+			}
+			query["agentid"] = value
+		case "type":
+			query["type"] = bson.M{"$in": strings.Split(value, ",")}
+		case "from", "to":
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, "", errors.InvalidParam{Message: key + " should be seconds since the epoch"}
+			}
+			operator := "$gte"
+			if key == "to" {
+				operator = "$lte"
+			}
+			period[operator] = seconds
+		default:
+			return nil, "", errors.InvalidParam{Message: "unsupported filter: " + key}
+		}
+	}
+	if len(period) != 0 {
+		query["time"] = period
+	}
+
+	query, order, err := makePageQuery(query, sort, eventSortFields, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	found := client.getCollection(EVENT_COLLECTION).Find(query).Sort(order...)
+	if limit > 0 {
+		// Get one more document to know whether the next page exists.
+		found = found.Limit(limit + 1)
+	}
+
+	events := []Event{}
+	err = found.All(&events)
+	if err != nil {
+		return nil, "", ConvertMongoError(err, errors.EVENT, "")
+	}
+
+	next := ""
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+		next = encodeCursor(events[limit-1].ID, "")
+	}
+
+	result := make([]map[string]interface{}, len(events))
+	for i, event := range events {
+		result[i] = event.convertToMap()
+	}
+	return result, next, err
+}
+
+// AddCatalogApp inserts new app with the name and the description to 'catalog' collection.
+// The name is the primary key of the app, so it should be unique.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) AddCatalogApp(name string, description string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	app := CatalogApp{
+		Name:        name,
+		Description: description,
+	}
+
+	err := client.getCollection(CATALOG_COLLECTION).Insert(app)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.CATALOG, name)
+	}
+
+	result := app.convertToMap()
+	return result, err
+}
+
+// GetCatalogApp returns single document specified by name parameter.
+// Descriptors of versions are not included.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetCatalogApp(name string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	app := CatalogApp{}
+	query := bson.M{"_id": name}
+	err := client.getCollection(CATALOG_COLLECTION).Find(query).One(&app)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.CATALOG, name)
+	}
+
+	result := app.convertToMap()
+	return result, err
+}
+
+// GetAllCatalogApps returns all documents from 'catalog' collection.
+// Descriptors of versions are not included.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetAllCatalogApps() ([]map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	apps := []CatalogApp{}
+	err := client.getCollection(CATALOG_COLLECTION).Find(nil).Sort("_id").All(&apps)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.CATALOG, "")
+	}
+
+	result := make([]map[string]interface{}, len(apps))
+	for i, app := range apps {
+		result[i] = app.convertToMap()
+	}
+	return result, err
+}
+
+// DeleteCatalogApp deletes single document specified by name parameter
+// with all versions of the app.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) DeleteCatalogApp(name string) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	query := bson.M{"_id": name}
+	err := client.getCollection(CATALOG_COLLECTION).Remove(query)
+	if err != nil {
+		return ConvertMongoError(err, errors.CATALOG, name)
+	}
+	return err
+}
+
+// AddCatalogVersion appends new version to the app specified by name parameter.
+// The time of creation is given in seconds since the epoch.
+// Versions are immutable, so NotFound error is returned if the app does not exist
+// or the version already exists.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) AddCatalogVersion(name string, version string, description string, descriptor string, checksum string, created_at int64) error {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	query := bson.M{"_id": name, "versions.version": bson.M{"$ne": version}}
+	update := bson.M{"$push": bson.M{"versions": CatalogVersion{
+		Version:     version,
+		Description: description,
+		Descriptor:  descriptor,
+		Checksum:    checksum,
+		CreatedAt:   created_at,
+	}}}
+	err := client.getCollection(CATALOG_COLLECTION).Update(query, update)
+	if err != nil {
+		return ConvertMongoError(err, errors.CATALOG, name)
+	}
+	return err
+}
+
+// GetCatalogVersion returns the version of the app specified by name parameter
+// including the descriptor.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (client *MongoDBManager) GetCatalogVersion(name string, version string) (map[string]interface{}, error) {
+	logger.LoggingContext(client.ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(client.ctx, logger.DEBUG, "OUT")
+
+	app := CatalogApp{}
+	query := bson.M{"_id": name}
+	err := client.getCollection(CATALOG_COLLECTION).Find(query).One(&app)
+	if err != nil {
+		return nil, ConvertMongoError(err, errors.CATALOG, name)
+	}
+
+	for _, item := range app.Versions {
+		if item.Version == version {
+			result := item.convertToMap()
+			result["name"] = app.Name
+			return result, err
+		}
+	}
+	return nil, errors.NotFound{Kind: errors.CATALOG, ID: name + ":" + version}
+}
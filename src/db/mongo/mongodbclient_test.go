@@ -18,6 +18,7 @@ package mongo
 
 import (
 	errors "commons/errors"
+	"context"
 	mgomocks "db/mongo/wrapper/mocks"
 	"github.com/golang/mock/gomock"
 	"gopkg.in/mgo.v2"
@@ -34,14 +35,19 @@ const (
 	appId           = "000000000000000000000000"
 	agentId         = "000000000000000000000001"
 	groupId         = "000000000000000000000002"
+	keyId           = "000000000000000000000003"
 	invalidObjectId = ""
 )
 
 var (
-	dummySession       = mgomocks.MockSession{}
-	connectionError    = errors.DBConnectionError{}
-	invalidObjectError = errors.InvalidObjectId{invalidObjectId}
-	notFoundError      = errors.NotFound{}
+	dummySession        = mgomocks.MockSession{}
+	connectionError     = errors.DBConnectionError{}
+	invalidAgentIdError = errors.InvalidObjectId{Kind: errors.AGENT, ID: invalidObjectId}
+	invalidGroupIdError = errors.InvalidObjectId{Kind: errors.GROUP, ID: invalidObjectId}
+	notFoundError       = errors.NotFound{}
+	emptyMetadata       = Metadata{}.convertToMap()
+	emptyAddresses      = []map[string]interface{}{}
+	emptyCatalog        = map[string]interface{}{}
 )
 
 func TestCalledConnectWithEmptyURL_ExpectErrorReturn(t *testing.T) {
@@ -92,7 +98,7 @@ func TestCalledConnectWithValidURL_ExpectSuccess(t *testing.T) {
 
 func TestCalledCreateDBWithInvalidSession_ExpectErrorReturn(t *testing.T) {
 	builder := MongoBuilder{}
-	_, err := builder.CreateDB()
+	_, err := builder.CreateDB(context.Background())
 
 	switch err.(type) {
 	default:
@@ -106,22 +112,70 @@ func TestCalledCreateDBWithValidSession_ExpectSuccess(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	connectionMockObj := mgomocks.NewMockConnection(mockCtrl)
+	sessionMockObj := mgomocks.NewMockSession(mockCtrl)
 
 	gomock.InOrder(
-		connectionMockObj.EXPECT().Dial(validUrl).Return(&dummySession, nil),
+		connectionMockObj.EXPECT().Dial(validUrl).Return(sessionMockObj, nil),
+		sessionMockObj.EXPECT().Copy().Return(&dummySession),
 	)
 	mgoDial = connectionMockObj
 
 	builder := MongoBuilder{}
 	_ = builder.Connect(validUrl)
 
-	_, err := builder.CreateDB()
+	_, err := builder.CreateDB(context.Background())
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledConnectTwice_ExpectSessionReused(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	connectionMockObj := mgomocks.NewMockConnection(mockCtrl)
+
+	gomock.InOrder(
+		connectionMockObj.EXPECT().Dial(validUrl).Return(&dummySession, nil).Times(1),
+	)
+	mgoDial = connectionMockObj
+
+	builder := MongoBuilder{}
+	_ = builder.Connect(validUrl)
+	err := builder.Connect(validUrl)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
 
+func TestCalledBuilderClose_ExpectSharedSessionClosed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	connectionMockObj := mgomocks.NewMockConnection(mockCtrl)
+	sessionMockObj := mgomocks.NewMockSession(mockCtrl)
+
+	gomock.InOrder(
+		connectionMockObj.EXPECT().Dial(validUrl).Return(sessionMockObj, nil),
+		sessionMockObj.EXPECT().Close(),
+	)
+	mgoDial = connectionMockObj
+
+	builder := MongoBuilder{}
+	_ = builder.Connect(validUrl)
+	builder.Close()
+
+	_, err := builder.CreateDB(context.Background())
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBOperationError", err)
+	case errors.DBOperationError:
+	}
+}
+
 func TestCalledClose_ExpectSessionClosed(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -220,7 +274,10 @@ func TestCalledUpdateAgentAddress_ExpectSuccess(t *testing.T) {
 	defer ctrl.Finish()
 
 	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
-	update := bson.M{"$set": bson.M{"host": "192.168.0.1", "port": "48098"}}
+	update := bson.M{
+		"$set":  bson.M{"host": "192.168.0.1", "port": "48098"},
+		"$push": bson.M{"addresses": bson.M{"$each": []Address{{Host: "192.168.0.1", Port: "48098", ChangedAt: 100}}, "$slice": -MAX_ADDRESS_HISTORY}},
+	}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -233,7 +290,7 @@ func TestCalledUpdateAgentAddress_ExpectSuccess(t *testing.T) {
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.UpdateAgentAddress(agentId, "192.168.0.1", "48098")
+	err := dbManager.UpdateAgentAddress(agentId, "192.168.0.1", "48098", 100)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -245,14 +302,14 @@ func TestCalledUpdateAgentAddressWithInvalidObjectId_ExpectErrorReturn(t *testin
 	defer ctrl.Finish()
 
 	dbManager := MongoDBManager{}
-	err := dbManager.UpdateAgentAddress(invalidObjectId, "192.168.0.1", "48098")
+	err := dbManager.UpdateAgentAddress(invalidObjectId, "192.168.0.1", "48098", 100)
 
 	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
 	}
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
 	}
 }
 
@@ -261,7 +318,10 @@ func TestCalledUpdateAgentAddressWhenDBReturnsError_ExpectErrorReturn(t *testing
 	defer ctrl.Finish()
 
 	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
-	update := bson.M{"$set": bson.M{"host": "192.168.0.1", "port": "48098"}}
+	update := bson.M{
+		"$set":  bson.M{"host": "192.168.0.1", "port": "48098"},
+		"$push": bson.M{"addresses": bson.M{"$each": []Address{{Host: "192.168.0.1", Port: "48098", ChangedAt: 100}}, "$slice": -MAX_ADDRESS_HISTORY}},
+	}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -274,7 +334,7 @@ func TestCalledUpdateAgentAddressWhenDBReturnsError_ExpectErrorReturn(t *testing
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.UpdateAgentAddress(agentId, "192.168.0.1", "48098")
+	err := dbManager.UpdateAgentAddress(agentId, "192.168.0.1", "48098", 100)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
@@ -320,11 +380,11 @@ func TestCalledUpdateAgentStatusWithInvalidObjectId_ExpectErrorReturn(t *testing
 	err := dbManager.UpdateAgentStatus(invalidObjectId, "connected")
 
 	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
 	}
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
 	}
 }
 
@@ -359,251 +419,207 @@ func TestCalledUpdateAgentStatusWhenDBReturnsError_ExpectErrorReturn(t *testing.
 	}
 }
 
-func TestCalledGetAgent_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAgentLastSeen_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
-	arg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{}, Status: status}
-	expectedRes := map[string]interface{}{
-		"id":     agentId,
-		"host":   "192.168.0.1",
-		"port":   "8888",
-		"apps":   []string{},
-		"status": status,
-	}
+	update := bson.M{"$set": bson.M{"lastseen": int64(1500000000), "interval": 10}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
-	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	res, err := dbManager.GetAgent(agentId)
+	err := dbManager.UpdateAgentLastSeen(agentId, 1500000000, 10)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
-
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
-	}
 }
 
-func TestCalledGetAgentWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAgentLastSeenWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbManager := MongoDBManager{}
-	_, err := dbManager.GetAgent(invalidObjectId)
+	err := dbManager.UpdateAgentLastSeen(invalidObjectId, 1500000000, 10)
 
 	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
 	}
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
 	}
 }
 
-func TestCalledGetAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAgentLastSeenWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$set": bson.M{"lastseen": int64(1500000000), "interval": 10}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
-	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).Return(mgo.ErrNotFound),
+		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	_, err := dbManager.GetAgent(agentId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
+	err := dbManager.UpdateAgentLastSeen(agentId, 1500000000, 10)
 
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
 	case errors.NotFound:
 	}
 }
 
-func TestCalledGetAllAgents_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAgentMetadata_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	args := []Agent{{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{}, Status: status}}
-	expectedRes := []map[string]interface{}{{
-		"id":     agentId,
-		"host":   "192.168.0.1",
-		"port":   "8888",
-		"apps":   []string{},
-		"status": status,
-	}}
+	metadata := map[string]interface{}{"hostname": "edge-01", "freememory": int64(1024)}
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$set": bson.M{"metadata.hostname": "edge-01", "metadata.freememory": int64(1024)}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
-	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(nil).Return(queryMockObj),
-		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	res, err := dbManager.GetAllAgents()
+	err := dbManager.UpdateAgentMetadata(agentId, metadata)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
+}
 
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+func TestCalledUpdateAgentMetadataWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	err := dbManager.UpdateAgentMetadata(invalidObjectId, map[string]interface{}{"hostname": "edge-01"})
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
 	}
 }
 
-func TestCalledGetAllAgentsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAgentMetadataWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$set": bson.M{"metadata.hostname": "edge-01"}}
+
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
-	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(nil).Return(queryMockObj),
-		queryMockObj.EXPECT().All(gomock.Any()).Return(mgo.ErrNotFound),
+		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	_, err := dbManager.GetAllAgents()
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
+	err := dbManager.UpdateAgentMetadata(agentId, map[string]interface{}{"hostname": "edge-01"})
 
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
 	case errors.NotFound:
 	}
 }
 
-func TestCalledGetAgentByAppID_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAgentLabels_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(agentId), "apps": bson.M{"$in": []string{appId}}}
-	arg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{}, Status: status}
-	expectedRes := map[string]interface{}{
-		"id":     agentId,
-		"host":   "192.168.0.1",
-		"port":   "8888",
-		"apps":   []string{},
-		"status": status,
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{
+		"$set":   bson.M{"labels.site": "plant3"},
+		"$unset": bson.M{"labels.arch": ""},
 	}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
-	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	res, err := dbManager.GetAgentByAppID(agentId, appId)
+	err := dbManager.UpdateAgentLabels(agentId, map[string]string{"site": "plant3"}, []string{"arch"})
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
-
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
-	}
 }
 
-func TestCalledGetAgentByAppIDWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAgentLabelsWithoutChanges_ExpectNoUpdate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(agentId), "apps": bson.M{"$in": []string{appId}}}
-
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
-	dbMockObj := mgomocks.NewMockDatabase(ctrl)
-	collectionMockObj := mgomocks.NewMockCollection(ctrl)
-	queryMockObj := mgomocks.NewMockQuery(ctrl)
-
-	gomock.InOrder(
-		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
-		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).Return(mgo.ErrNotFound),
-	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	_, err := dbManager.GetAgentByAppID(agentId, appId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
+	err := dbManager.UpdateAgentLabels(agentId, nil, nil)
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
 
-func TestCalledGetAgentByAppIDWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAgentLabelsWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbManager := MongoDBManager{}
-	_, err := dbManager.GetAgentByAppID(invalidObjectId, appId)
+	err := dbManager.UpdateAgentLabels(invalidObjectId, map[string]string{"site": "plant3"}, nil)
 
 	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
 	}
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
 	}
 }
 
-func TestCalledAddAppToAgent_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAgentLabelsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
-	update := bson.M{"$addToSet": bson.M{"apps": appId}}
+	update := bson.M{"$unset": bson.M{"labels.site": ""}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -612,141 +628,149 @@ func TestCalledAddAppToAgent_ExpectSuccess(t *testing.T) {
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.AddAppToAgent(agentId, appId)
+	err := dbManager.UpdateAgentLabels(agentId, nil, []string{"site"})
 
-	if err != nil {
-		t.Errorf("Unexpected err: %s", err.Error())
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
 	}
 }
 
-func TestCalledAddAppToAgentWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAgent_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	dbManager := MongoDBManager{}
-	err := dbManager.AddAppToAgent(invalidObjectId, appId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	arg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{}, Status: status,
+		Metadata: Metadata{Hostname: "edge-01", Arch: "arm64", FreeCPU: 87.5, FreeMemory: 1024}}
+	expectedMetadata := map[string]interface{}{
+		"hostname":      "edge-01",
+		"os":            "",
+		"arch":          "arm64",
+		"kernel":        "",
+		"dockerversion": "",
+		"sdaversion":    "",
+		"freecpu":       87.5,
+		"freememory":    int64(1024),
+		"freedisk":      int64(0),
 	}
-
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	expectedRes := map[string]interface{}{
+		"id":          agentId,
+		"host":        "192.168.0.1",
+		"port":        "8888",
+		"apps":        []string{},
+		"status":      status,
+		"lastseen":    int64(0),
+		"interval":    0,
+		"metadata":    expectedMetadata,
+		"labels":      map[string]string(nil),
+		"deviceid":    "",
+		"addresses":   emptyAddresses,
+		"healthcheck": "",
+		"exempt":      false,
+		"catalog":     emptyCatalog,
 	}
-}
-
-func TestCalledAddAppToAgentWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
-	update := bson.M{"$addToSet": bson.M{"apps": appId}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.AddAppToAgent(agentId, appId)
+	res, err := dbManager.GetAgent(agentId)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
 	}
 }
 
-func TestCalledDeleteAppFromAgent_ExpectSuccess(t *testing.T) {
+func TestCalledGetAgentByDeviceID_ExpectAddressesReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
-	update := bson.M{"$pull": bson.M{"apps": appId}}
+	query := bson.M{"deviceid": "device-01"}
+	arg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.2", Port: "8888", DeviceID: "device-01",
+		Addresses: []Address{{Host: "192.168.0.2", Port: "8888", ChangedAt: 100}}}
+	expectedAddresses := []map[string]interface{}{
+		{"host": "192.168.0.2", "port": "8888", "changedat": int64(100)},
+	}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.DeleteAppFromAgent(agentId, appId)
+	res, err := dbManager.GetAgentByDeviceID("device-01")
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
-}
-
-func TestCalledDeleteAppFromAgentWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	dbManager := MongoDBManager{}
-	err := dbManager.DeleteAppFromAgent(invalidObjectId, appId)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
+	if res["id"] != agentId || res["deviceid"] != "device-01" {
+		t.Errorf("Unexpected res: %v", res)
 	}
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	if !reflect.DeepEqual(res["addresses"], expectedAddresses) {
+		t.Errorf("Expected addresses: %v, actual addresses: %v", expectedAddresses, res["addresses"])
 	}
 }
 
-func TestCalledDeleteAppFromAgentWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAgentByDeviceIDWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
-	update := bson.M{"$pull": bson.M{"apps": appId}}
-
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
+		collectionMockObj.EXPECT().Find(bson.M{"deviceid": "device-01"}).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).Return(mgo.ErrNotFound),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.DeleteAppFromAgent(agentId, appId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
+	_, err := dbManager.GetAgentByDeviceID("device-01")
 
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
 	case errors.NotFound:
 	}
 }
 
-func TestCalledDeleteAgent_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAgentDeviceID_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$set": bson.M{"deviceid": "device-01"}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -755,38 +779,32 @@ func TestCalledDeleteAgent_ExpectSuccess(t *testing.T) {
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Remove(query).Return(nil),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.DeleteAgent(agentId)
+	err := dbManager.UpdateAgentDeviceID(agentId, "device-01")
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
 
-func TestCalledDeleteAgentWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
+func TestCalledUpdateAgentDeviceIDWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
 	dbManager := MongoDBManager{}
-	err := dbManager.DeleteAgent(invalidObjectId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
-	}
+	err := dbManager.UpdateAgentDeviceID(invalidObjectId, "device-01")
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	if err == nil || err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %v", invalidAgentIdError.Error(), err)
 	}
 }
 
-func TestCalledDeleteAgentWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAgentSecret_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$set": bson.M{"secrethash": "hash"}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -795,49 +813,71 @@ func TestCalledDeleteAgentWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Remove(query).Return(mgo.ErrNotFound),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.DeleteAgent(agentId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
+	err := dbManager.UpdateAgentSecret(agentId, "hash")
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
 
-func TestCalledCreateGroup_ExpectSuccess(t *testing.T) {
+func TestCalledGetAgentSecret_ExpectHashReturnWithoutAgentDocument(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	arg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.2", Port: "8888", SecretHash: "hash"}
+
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Insert(gomock.Any()).Return(nil),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	_, err := dbManager.CreateGroup()
+	hash, err := dbManager.GetAgentSecret(agentId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
+
+	if hash != "hash" {
+		t.Errorf("Expected hash: %s, actual hash: %s", "hash", hash)
+	}
+
+	// The hash is never returned with the agent.
+	for _, value := range arg.convertToMap() {
+		if value == "hash" {
+			t.Errorf("Unexpected hash in agent: %v", arg.convertToMap())
+		}
+	}
 }
 
-func TestCalledCreateGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAgentSecretWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+	_, err := dbManager.GetAgentSecret(invalidObjectId)
+
+	if err == nil || err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %v", invalidAgentIdError.Error(), err)
+	}
+}
+
+func TestCalledUpdateAgentHealthCheck_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$set": bson.M{"healthcheck": "pull"}}
+
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
@@ -845,79 +885,81 @@ func TestCalledCreateGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Insert(gomock.Any()).Return(mgo.ErrNotFound),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	_, err := dbManager.CreateGroup()
+	err := dbManager.UpdateAgentHealthCheck(agentId, "pull")
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
+}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+func TestCalledUpdateAgentHealthCheckWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+	err := dbManager.UpdateAgentHealthCheck(invalidObjectId, "pull")
+
+	if err == nil || err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %v", invalidAgentIdError.Error(), err)
 	}
 }
 
-func TestCalledGetGroup_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAgentExempt_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
-	arg := Group{ID: bson.ObjectIdHex(groupId), Members: []string{}}
-	expectedRes := map[string]interface{}{
-		"id":      groupId,
-		"members": []string{},
-	}
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$set": bson.M{"exempt": true}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
-	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	res, err := dbManager.GetGroup(groupId)
+	err := dbManager.UpdateAgentExempt(agentId, true)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
+}
 
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+func TestCalledUpdateAgentExemptWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+	err := dbManager.UpdateAgentExempt(invalidObjectId, true)
+
+	if err == nil || err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %v", invalidAgentIdError.Error(), err)
 	}
 }
 
-func TestCalledGetGroupWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAgentWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbManager := MongoDBManager{}
-	_, err := dbManager.GetGroup(invalidObjectId)
+	_, err := dbManager.GetAgent(invalidObjectId)
 
 	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
 	}
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
 	}
 }
 
-func TestCalledGetGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -932,7 +974,7 @@ func TestCalledGetGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	_, err := dbManager.GetGroup(groupId)
+	_, err := dbManager.GetAgent(agentId)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
@@ -943,16 +985,32 @@ func TestCalledGetGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
 	case errors.NotFound:
 	}
+
+	if !errors.Is(err, errors.NotFound{Kind: errors.AGENT, ID: agentId}) {
+		t.Errorf("Unexpected resource of err: %s", err.Error())
+	}
 }
 
-func TestCalledGetAllGroups_ExpectSuccess(t *testing.T) {
+func TestCalledGetAllAgents_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	args := []Group{{ID: bson.ObjectIdHex(groupId), Members: []string{}}}
+	args := []Agent{{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{}, Status: status}}
 	expectedRes := []map[string]interface{}{{
-		"id":      groupId,
-		"members": []string{},
+		"id":          agentId,
+		"host":        "192.168.0.1",
+		"port":        "8888",
+		"apps":        []string{},
+		"status":      status,
+		"lastseen":    int64(0),
+		"interval":    0,
+		"metadata":    emptyMetadata,
+		"labels":      map[string]string(nil),
+		"deviceid":    "",
+		"addresses":   emptyAddresses,
+		"healthcheck": "",
+		"exempt":      false,
+		"catalog":     emptyCatalog,
 	}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
@@ -968,7 +1026,7 @@ func TestCalledGetAllGroups_ExpectSuccess(t *testing.T) {
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	res, err := dbManager.GetAllGroups()
+	res, err := dbManager.GetAllAgents()
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -979,7 +1037,7 @@ func TestCalledGetAllGroups_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledGetAllGroupsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAllAgentsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -996,7 +1054,7 @@ func TestCalledGetAllGroupsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	_, err := dbManager.GetAllGroups()
+	_, err := dbManager.GetAllAgents()
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
@@ -1009,197 +1067,217 @@ func TestCalledGetAllGroupsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
-func TestCalledJoinGroup_ExpectSuccess(t *testing.T) {
+func TestCalledGetAgentsByQuery_ExpectPageReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
-	update := bson.M{"$addToSet": bson.M{"members": agentId}}
+	otherAgentId := "000000000000000000000004"
+	args := []Agent{
+		{ID: bson.ObjectIdHex(agentId), Host: "10.0.0.2", Port: "8888", Apps: []string{appId}, Status: status},
+		{ID: bson.ObjectIdHex(otherAgentId), Host: "10.0.0.1", Port: "8888", Apps: []string{appId}, Status: status},
+	}
+	expectedRes := []map[string]interface{}{{
+		"id":          agentId,
+		"host":        "10.0.0.2",
+		"port":        "8888",
+		"apps":        []string{appId},
+		"status":      status,
+		"lastseen":    int64(0),
+		"interval":    0,
+		"metadata":    emptyMetadata,
+		"labels":      map[string]string(nil),
+		"deviceid":    "",
+		"addresses":   emptyAddresses,
+		"healthcheck": "",
+		"exempt":      false,
+		"catalog":     emptyCatalog,
+	}}
+	query := bson.M{
+		"status": status,
+		"host":   bson.RegEx{Pattern: `^10\.0\..*$`},
+		"apps":   appId,
+	}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
-		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().Sort("-host", "-_id").Return(queryMockObj),
+		queryMockObj.EXPECT().Limit(2).Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.JoinGroup(groupId, agentId)
+	filter := map[string]string{"status": status, "host": "10.0.*", "app": appId}
+	res, next, err := dbManager.GetAgentsByQuery(filter, "-host", 1, "")
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
-}
-
-func TestCalledJoinGroupWithInvalidObjectIdAboutGroup_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	dbManager := MongoDBManager{}
-	err := dbManager.JoinGroup(invalidObjectId, agentId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
-	}
-
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
-	}
-}
-
-func TestCalledJoinGroupWithInvalidObjectIdAboutAgent_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
 
-	dbManager := MongoDBManager{}
-	err := dbManager.JoinGroup(groupId, invalidObjectId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
 	}
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	position, err := decodeCursor(next)
+	if err != nil || position.ID != agentId || position.Value != "10.0.0.2" {
+		t.Errorf("Unexpected cursor: %v", position)
 	}
 }
 
-func TestCalledJoinGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAgentsByQueryWithGroupAndCursor_ExpectMembersFollowingCursorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
-	update := bson.M{"$addToSet": bson.M{"members": agentId}}
+	groupArg := Group{ID: bson.ObjectIdHex(groupId), Members: []string{agentId}}
+	query := bson.M{"$and": []bson.M{
+		{"_id": bson.M{"$in": []bson.ObjectId{bson.ObjectIdHex(agentId)}}},
+		{"_id": bson.M{"$gt": bson.ObjectIdHex(appId)}},
+	}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
-		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
+		dbMockObj.EXPECT().C(GROUP_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(bson.M{"_id": bson.ObjectIdHex(groupId)}).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, groupArg).Return(nil),
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().Sort("_id").Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.JoinGroup(groupId, agentId)
+	res, next, err := dbManager.GetAgentsByQuery(map[string]string{"group": groupId}, "", 0, encodeCursor(bson.ObjectIdHex(appId), ""))
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+	if len(res) != 0 || next != "" {
+		t.Errorf("Unexpected res: %v, next: %s", res, next)
 	}
 }
 
-func TestCalledLeaveGroup_ExpectSuccess(t *testing.T) {
+func TestCalledGetAgentsByQueryWithLabels_ExpectAgentsHavingLabelsReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
-	update := bson.M{"$pull": bson.M{"members": agentId}}
+	args := []Agent{{ID: bson.ObjectIdHex(agentId), Host: "10.0.0.2", Port: "8888", Apps: []string{appId}, Status: status,
+		Labels: map[string]string{"site": "plant3", "arch": "arm64"}}}
+	query := bson.M{"labels.site": "plant3", "labels.arch": "arm64", "apps": appId}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
-		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().Sort("_id").Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.LeaveGroup(groupId, agentId)
+	filter := map[string]string{"label.site": "plant3", "label.arch": "arm64", "app": appId}
+	res, _, err := dbManager.GetAgentsByQuery(filter, "", 0, "")
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
-}
-
-func TestCalledLeaveGroupWithInvalidObjectIdAboutGroup_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	dbManager := MongoDBManager{}
-	err := dbManager.LeaveGroup(invalidObjectId, agentId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
-	}
 
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+	if len(res) != 1 || !reflect.DeepEqual(res[0]["labels"], args[0].Labels) {
+		t.Errorf("Unexpected res: %v", res)
 	}
 }
 
-func TestCalledLeaveGroupWithInvalidObjectIdAboutAgent_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	dbManager := MongoDBManager{}
-	err := dbManager.LeaveGroup(groupId, invalidObjectId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
-	}
-
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
+func TestCalledGetAgentsByQueryWithInvalidQuery_ExpectErrorReturn(t *testing.T) {
+	testList := []struct {
+		name   string
+		filter map[string]string
+		sort   string
+		cursor string
+	}{
+		{"UnknownFilter", map[string]string{"port": "8888"}, "", ""},
+		{"UnknownSortField", nil, "port", ""},
+		{"MalformedCursor", nil, "", "cursor"},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			dbManager := MongoDBManager{mgoSession: &dummySession}
+			_, _, err := dbManager.GetAgentsByQuery(test.filter, test.sort, 0, test.cursor)
+
+			switch err.(type) {
+			default:
+				t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+			case errors.InvalidParam:
+			}
+		})
 	}
 }
 
-func TestCalledLeaveGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAgentsByQueryWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
-	update := bson.M{"$pull": bson.M{"members": agentId}}
-
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
 	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
 
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
-		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(bson.M{}).Return(queryMockObj),
+		queryMockObj.EXPECT().Sort("_id").Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).Return(mgo.ErrNotFound),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.LeaveGroup(groupId, agentId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
+	_, _, err := dbManager.GetAgentsByQuery(nil, "", 0, "")
 
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
 	case errors.NotFound:
 	}
 }
 
-func TestCalledGetGroupMembers_ExpectSuccess(t *testing.T) {
+func TestCalledGetAgentByAppID_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	groupQuery := bson.M{"_id": bson.ObjectIdHex(groupId)}
-	agentQuery := bson.M{"_id": bson.ObjectIdHex(agentId)}
-	groupArg := Group{ID: bson.ObjectIdHex(groupId), Members: []string{agentId}}
-	agentArg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{}, Status: status}
-	expectedRes := []map[string]interface{}{{
-		"id":     agentId,
-		"host":   "192.168.0.1",
-		"port":   "8888",
-		"apps":   []string{},
-		"status": status,
-	}}
+	query := bson.M{"_id": bson.ObjectIdHex(agentId), "apps": bson.M{"$in": []string{appId}}}
+	arg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{}, Status: status}
+	expectedRes := map[string]interface{}{
+		"id":          agentId,
+		"host":        "192.168.0.1",
+		"port":        "8888",
+		"apps":        []string{},
+		"status":      status,
+		"lastseen":    int64(0),
+		"interval":    0,
+		"metadata":    emptyMetadata,
+		"labels":      map[string]string(nil),
+		"deviceid":    "",
+		"addresses":   emptyAddresses,
+		"healthcheck": "",
+		"exempt":      false,
+		"catalog":     emptyCatalog,
+	}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -1209,17 +1287,12 @@ func TestCalledGetGroupMembers_ExpectSuccess(t *testing.T) {
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(groupQuery).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, groupArg).Return(nil),
-
-		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
-		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(agentQuery).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, agentArg).Return(nil),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	res, err := dbManager.GetGroupMembers(groupId)
+	res, err := dbManager.GetAgentByAppID(agentId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1230,37 +1303,11 @@ func TestCalledGetGroupMembers_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledGetGroupMembersWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	dbManager := MongoDBManager{}
-	_, err := dbManager.GetGroupMembers(invalidObjectId)
-
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), "nil")
-	}
-
-	if err.Error() != invalidObjectError.Error() {
-		t.Errorf("Expected err: %s, actual err: %s", invalidObjectError.Error(), err.Error())
-	}
-}
-
-func TestCalledGetGroupMembersByAppID_ExpectSuccess(t *testing.T) {
+func TestCalledGetAgentByAppIDWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	groupQuery := bson.M{"_id": bson.ObjectIdHex(groupId)}
-	agentQuery := bson.M{"_id": bson.ObjectIdHex(agentId), "apps": bson.M{"$in": []string{appId}}}
-	groupArg := Group{ID: bson.ObjectIdHex(groupId), Members: []string{agentId}}
-	agentArg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{appId}, Status: status}
-	expectedRes := []map[string]interface{}{{
-		"id":     agentId,
-		"host":   "192.168.0.1",
-		"port":   "8888",
-		"apps":   []string{appId},
-		"status": status,
-	}}
+	query := bson.M{"_id": bson.ObjectIdHex(agentId), "apps": bson.M{"$in": []string{appId}}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -1270,50 +1317,50 @@ func TestCalledGetGroupMembersByAppID_ExpectSuccess(t *testing.T) {
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(groupQuery).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, groupArg).Return(nil),
-
-		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
-		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Find(agentQuery).Return(queryMockObj),
-		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, agentArg).Return(nil),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).Return(mgo.ErrNotFound),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	res, err := dbManager.GetGroupMembersByAppID(groupId, appId)
+	_, err := dbManager.GetAgentByAppID(agentId, appId)
 
-	if err != nil {
-		t.Errorf("Unexpected err: %s", err.Error())
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
 	}
 
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+
+	if !errors.Is(err, errors.NotFound{Kind: errors.APP, ID: appId}) {
+		t.Errorf("Unexpected resource of err: %s", err.Error())
 	}
 }
 
-func TestCalledGetGroupMembersByAppIDWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+func TestCalledGetAgentByAppIDWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbManager := MongoDBManager{}
-	_, err := dbManager.GetGroupMembersByAppID(invalidObjectId, appId)
+	_, err := dbManager.GetAgentByAppID(invalidObjectId, appId)
 
 	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "invalidObjectError", "nil")
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "invalidObjectError", err.Error())
-	case errors.InvalidObjectId:
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
 	}
 }
 
-func TestCalledDeleteGroup_ExpectSuccess(t *testing.T) {
+func TestCalledAddAppToAgent_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$addToSet": bson.M{"apps": appId}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -1322,40 +1369,39 @@ func TestCalledDeleteGroup_ExpectSuccess(t *testing.T) {
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Remove(query).Return(nil),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.DeleteGroup(groupId)
+	err := dbManager.AddAppToAgent(agentId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
 	}
 }
 
-func TestCalledDeleteGroupWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+func TestCalledAddAppToAgentWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbManager := MongoDBManager{}
-	err := dbManager.DeleteGroup(invalidObjectId)
+	err := dbManager.AddAppToAgent(invalidObjectId, appId)
 
 	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "invalidObjectError", "nil")
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "invalidObjectError", err.Error())
-	case errors.InvalidObjectId:
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
 	}
 }
 
-func TestCalledDeleteGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledAddAppToAgentWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$addToSet": bson.M{"apps": appId}}
 
 	sessionMockObj := mgomocks.NewMockSession(ctrl)
 	dbMockObj := mgomocks.NewMockDatabase(ctrl)
@@ -1364,11 +1410,89 @@ func TestCalledDeleteGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	gomock.InOrder(
 		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
 		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
-		collectionMockObj.EXPECT().Remove(query).Return(mgo.ErrNotFound),
+		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
 	)
 
 	dbManager := MongoDBManager{mgoSession: sessionMockObj}
-	err := dbManager.DeleteGroup(groupId)
+	err := dbManager.AddAppToAgent(agentId, appId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledDeleteAppFromAgent_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{
+		"$pull":  bson.M{"apps": appId},
+		"$unset": bson.M{"catalog." + appId: "", "revisions." + appId: ""},
+	}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteAppFromAgent(agentId, appId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteAppFromAgentWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	err := dbManager.DeleteAppFromAgent(invalidObjectId, appId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
+	}
+}
+
+func TestCalledDeleteAppFromAgentWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{
+		"$pull":  bson.M{"apps": appId},
+		"$unset": bson.M{"catalog." + appId: "", "revisions." + appId: ""},
+	}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteAppFromAgent(agentId, appId)
 
 	if err == nil {
 		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
@@ -1380,3 +1504,1737 @@ func TestCalledDeleteGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
 	case errors.NotFound:
 	}
 }
+
+func TestCalledUpdateAgentCatalog_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$set": bson.M{"catalog." + appId: CatalogRef{App: "line-monitor", Version: "1.4.0"}}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.UpdateAgentCatalog(agentId, appId, "line-monitor", "1.4.0")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledUpdateAgentCatalogWithEmptyName_ExpectCatalogVersionRemoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$unset": bson.M{"catalog." + appId: ""}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.UpdateAgentCatalog(agentId, appId, "", "")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledUpdateAgentCatalogWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+	err := dbManager.UpdateAgentCatalog(invalidObjectId, appId, "line-monitor", "1.4.0")
+
+	if err == nil || err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %v", invalidAgentIdError.Error(), err)
+	}
+}
+
+func TestCalledUpdateAgentCatalogWithInvalidAppId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+
+	for _, id := range []string{"", "app.version", "$app", "app id"} {
+		err := dbManager.UpdateAgentCatalog(agentId, id, "line-monitor", "1.4.0")
+
+		if !errors.Is(err, errors.InvalidParam{Kind: errors.APP, ID: id}) {
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+		}
+	}
+}
+
+func TestCalledDeleteAppFromAgentWithInvalidAppId_ExpectAppPulledOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	update := bson.M{"$pull": bson.M{"apps": "app.version"}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteAppFromAgent(agentId, "app.version")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledAddAgentRevision_ExpectNextNumberReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	arg := Agent{ID: bson.ObjectIdHex(agentId), Revisions: map[string][]Revision{appId: {{Number: 1}, {Number: 2}}}}
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	revision := Revision{Number: 3, Descriptor: "services:", Checksum: "sum", Catalog: CatalogRef{App: "line-monitor", Version: "1.4.0"}, AppliedAt: 10}
+	selector := bson.M{"_id": bson.ObjectIdHex(agentId), "revisions." + appId + ".number": bson.M{"$ne": 3}}
+	update := bson.M{"$push": bson.M{"revisions." + appId: bson.M{"$each": []Revision{revision}, "$slice": -MAX_REVISIONS}}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(selector, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	number, err := dbManager.AddAgentRevision(agentId, appId, "services:", "sum", "line-monitor", "1.4.0", 10)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if number != 3 {
+		t.Errorf("Expected number: %d, actual number: %d", 3, number)
+	}
+}
+
+func TestCalledAddAgentRevisionWhenNumberIsTakenConcurrently_ExpectNextNumberReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	before := Agent{ID: bson.ObjectIdHex(agentId), Revisions: map[string][]Revision{appId: {{Number: 1}, {Number: 2}}}}
+	after := Agent{ID: bson.ObjectIdHex(agentId), Revisions: map[string][]Revision{appId: {{Number: 1}, {Number: 2}, {Number: 3}}}}
+
+	taken := Revision{Number: 3, Descriptor: "services:", Checksum: "sum", AppliedAt: 10}
+	takenSelector := bson.M{"_id": bson.ObjectIdHex(agentId), "revisions." + appId + ".number": bson.M{"$ne": 3}}
+	takenUpdate := bson.M{"$push": bson.M{"revisions." + appId: bson.M{"$each": []Revision{taken}, "$slice": -MAX_REVISIONS}}}
+
+	revision := Revision{Number: 4, Descriptor: "services:", Checksum: "sum", AppliedAt: 10}
+	selector := bson.M{"_id": bson.ObjectIdHex(agentId), "revisions." + appId + ".number": bson.M{"$ne": 4}}
+	update := bson.M{"$push": bson.M{"revisions." + appId: bson.M{"$each": []Revision{revision}, "$slice": -MAX_REVISIONS}}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, before).Return(nil),
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(takenSelector, takenUpdate).Return(mgo.ErrNotFound),
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, after).Return(nil),
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(selector, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	number, err := dbManager.AddAgentRevision(agentId, appId, "services:", "sum", "", "", 10)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if number != 4 {
+		t.Errorf("Expected number: %d, actual number: %d", 4, number)
+	}
+}
+
+func TestCalledAddAgentRevisionWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+	_, err := dbManager.AddAgentRevision(invalidObjectId, appId, "services:", "sum", "", "", 10)
+
+	if err == nil || err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %v", invalidAgentIdError.Error(), err)
+	}
+}
+
+func TestCalledAddAgentRevisionWithInvalidAppId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+
+	for _, id := range []string{"", "app.version", "$app"} {
+		_, err := dbManager.AddAgentRevision(agentId, id, "services:", "sum", "", "", 10)
+
+		if !errors.Is(err, errors.InvalidParam{Kind: errors.APP, ID: id}) {
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+		}
+	}
+}
+
+func TestCalledGetAgentRevisions_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	arg := Agent{ID: bson.ObjectIdHex(agentId), Revisions: map[string][]Revision{appId: {
+		{Number: 1, Descriptor: "old", Checksum: "sum1", AppliedAt: 10},
+		{Number: 2, Descriptor: "new", Checksum: "sum2", Catalog: CatalogRef{App: "line-monitor", Version: "1.4.0"}, AppliedAt: 20},
+	}}}
+	expectedRes := []map[string]interface{}{{
+		"revision":   1,
+		"descriptor": "old",
+		"checksum":   "sum1",
+		"appliedat":  int64(10),
+	}, {
+		"revision":   2,
+		"descriptor": "new",
+		"checksum":   "sum2",
+		"appliedat":  int64(20),
+		"catalog":    map[string]interface{}{"app": "line-monitor", "version": "1.4.0"},
+	}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(AGENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(bson.M{"_id": bson.ObjectIdHex(agentId)}).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetAgentRevisions(agentId, appId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledDeleteAgent_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Remove(query).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteAgent(agentId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteAgentWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	err := dbManager.DeleteAgent(invalidObjectId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
+	}
+}
+
+func TestCalledDeleteAgentWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(agentId)}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Remove(query).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteAgent(agentId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledCreateGroup_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Insert(gomock.Any()).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	_, err := dbManager.CreateGroup()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledCreateGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Insert(gomock.Any()).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	_, err := dbManager.CreateGroup()
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetGroup_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	arg := Group{ID: bson.ObjectIdHex(groupId), Members: []string{}}
+	expectedRes := map[string]interface{}{
+		"id":      groupId,
+		"members": []string{},
+	}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetGroup(groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledGetGroupWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	_, err := dbManager.GetGroup(invalidObjectId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidGroupIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidGroupIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidGroupIdError.Error(), err.Error())
+	}
+}
+
+func TestCalledGetGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	_, err := dbManager.GetGroup(groupId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetAllGroups_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	args := []Group{{ID: bson.ObjectIdHex(groupId), Members: []string{}}}
+	expectedRes := []map[string]interface{}{{
+		"id":      groupId,
+		"members": []string{},
+	}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(nil).Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetAllGroups()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledGetAllGroupsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(nil).Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	_, err := dbManager.GetAllGroups()
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetGroupsByQuery_ExpectPageReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	otherGroupId := "000000000000000000000004"
+	args := []Group{
+		{ID: bson.ObjectIdHex(otherGroupId), Members: []string{agentId}},
+		{ID: bson.ObjectIdHex(groupId), Members: []string{agentId}},
+	}
+	expectedRes := []map[string]interface{}{{
+		"id":      otherGroupId,
+		"members": []string{agentId},
+	}}
+	query := bson.M{"$and": []bson.M{
+		{"members": agentId},
+		{"_id": bson.M{"$lt": bson.ObjectIdHex("000000000000000000000005")}},
+	}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(GROUP_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().Sort("-_id").Return(queryMockObj),
+		queryMockObj.EXPECT().Limit(2).Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	cursor := encodeCursor(bson.ObjectIdHex("000000000000000000000005"), "")
+	res, next, err := dbManager.GetGroupsByQuery(map[string]string{"agent": agentId}, "-id", 1, cursor)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+
+	if next != encodeCursor(bson.ObjectIdHex(otherGroupId), "") {
+		t.Errorf("Unexpected cursor: %s", next)
+	}
+}
+
+func TestCalledGetGroupsByQueryWithInvalidQuery_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{mgoSession: &dummySession}
+	for _, sort := range []string{"host", "status"} {
+		_, _, err := dbManager.GetGroupsByQuery(nil, sort, 0, "")
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+		case errors.InvalidParam:
+		}
+	}
+
+	_, _, err := dbManager.GetGroupsByQuery(map[string]string{"status": status}, "", 0, "")
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledJoinGroup_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	update := bson.M{"$addToSet": bson.M{"members": agentId}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.JoinGroup(groupId, agentId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledJoinGroupWithInvalidObjectIdAboutGroup_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	err := dbManager.JoinGroup(invalidObjectId, agentId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidGroupIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidGroupIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidGroupIdError.Error(), err.Error())
+	}
+}
+
+func TestCalledJoinGroupWithInvalidObjectIdAboutAgent_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	err := dbManager.JoinGroup(groupId, invalidObjectId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
+	}
+}
+
+func TestCalledJoinGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	update := bson.M{"$addToSet": bson.M{"members": agentId}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.JoinGroup(groupId, agentId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledLeaveGroup_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	update := bson.M{"$pull": bson.M{"members": agentId}, "$unset": bson.M{"convergence." + agentId: ""}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.LeaveGroup(groupId, agentId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledLeaveGroupWithInvalidObjectIdAboutGroup_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	err := dbManager.LeaveGroup(invalidObjectId, agentId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidGroupIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidGroupIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidGroupIdError.Error(), err.Error())
+	}
+}
+
+func TestCalledLeaveGroupWithInvalidObjectIdAboutAgent_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	err := dbManager.LeaveGroup(groupId, invalidObjectId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidAgentIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidAgentIdError.Error(), err.Error())
+	}
+}
+
+func TestCalledLeaveGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	update := bson.M{"$pull": bson.M{"members": agentId}, "$unset": bson.M{"convergence." + agentId: ""}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.LeaveGroup(groupId, agentId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetGroupMembers_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	groupQuery := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	agentQuery := bson.M{"_id": bson.ObjectIdHex(agentId)}
+	groupArg := Group{ID: bson.ObjectIdHex(groupId), Members: []string{agentId}}
+	agentArg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{}, Status: status}
+	expectedRes := []map[string]interface{}{{
+		"id":          agentId,
+		"host":        "192.168.0.1",
+		"port":        "8888",
+		"apps":        []string{},
+		"status":      status,
+		"lastseen":    int64(0),
+		"interval":    0,
+		"metadata":    emptyMetadata,
+		"labels":      map[string]string(nil),
+		"deviceid":    "",
+		"addresses":   emptyAddresses,
+		"healthcheck": "",
+		"exempt":      false,
+		"catalog":     emptyCatalog,
+	}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(groupQuery).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, groupArg).Return(nil),
+
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(agentQuery).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, agentArg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetGroupMembers(groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledGetGroupMembersWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	_, err := dbManager.GetGroupMembers(invalidObjectId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", invalidGroupIdError.Error(), "nil")
+	}
+
+	if err.Error() != invalidGroupIdError.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", invalidGroupIdError.Error(), err.Error())
+	}
+}
+
+func TestCalledGetGroupMembersByAppID_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	groupQuery := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	agentQuery := bson.M{"_id": bson.ObjectIdHex(agentId), "apps": bson.M{"$in": []string{appId}}}
+	groupArg := Group{ID: bson.ObjectIdHex(groupId), Members: []string{agentId}}
+	agentArg := Agent{ID: bson.ObjectIdHex(agentId), Host: "192.168.0.1", Port: "8888", Apps: []string{appId}, Status: status}
+	expectedRes := []map[string]interface{}{{
+		"id":          agentId,
+		"host":        "192.168.0.1",
+		"port":        "8888",
+		"apps":        []string{appId},
+		"status":      status,
+		"lastseen":    int64(0),
+		"interval":    0,
+		"metadata":    emptyMetadata,
+		"labels":      map[string]string(nil),
+		"deviceid":    "",
+		"addresses":   emptyAddresses,
+		"healthcheck": "",
+		"exempt":      false,
+		"catalog":     emptyCatalog,
+	}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(groupQuery).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, groupArg).Return(nil),
+
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(agentQuery).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, agentArg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetGroupMembersByAppID(groupId, appId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledGetGroupMembersByAppIDWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	_, err := dbManager.GetGroupMembersByAppID(invalidObjectId, appId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "invalidGroupIdError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "invalidGroupIdError", err.Error())
+	case errors.InvalidObjectId:
+	}
+}
+
+func TestCalledDeleteGroup_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Remove(query).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteGroup(groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteGroupWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManager := MongoDBManager{}
+	err := dbManager.DeleteGroup(invalidObjectId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "invalidGroupIdError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "invalidGroupIdError", err.Error())
+	case errors.InvalidObjectId:
+	}
+}
+
+func TestCalledDeleteGroupWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(gomock.Any()).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Remove(query).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteGroup(groupId)
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledSetGroupDesiredState_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	apps := []map[string]string{{"app": "line-monitor", "version": "1.4.0", "state": "running"}}
+	desired := DesiredState{Apps: []DesiredApp{{App: "line-monitor", Version: "1.4.0", State: "running"}}, Prune: true}
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	update := bson.M{"$set": bson.M{"desired": desired}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(GROUP_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.SetGroupDesiredState(groupId, apps, true)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledSetGroupDesiredStateWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+	err := dbManager.SetGroupDesiredState(invalidObjectId, nil, false)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidObjectId", err)
+	case errors.InvalidObjectId:
+	}
+}
+
+func TestCalledUnsetGroupDesiredState_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	update := bson.M{"$unset": bson.M{"desired": "", "convergence": ""}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(GROUP_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.UnsetGroupDesiredState(groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledGetGroupDesiredState_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	arg := Group{
+		ID:          bson.ObjectIdHex(groupId),
+		Members:     []string{agentId},
+		Desired:     &DesiredState{Apps: []DesiredApp{{App: "line-monitor", Version: "1.4.0", State: "running"}}},
+		Convergence: map[string]Convergence{agentId: {Status: "converged", Actions: []string{"deploy line-monitor 1.4.0"}, Time: 10}},
+	}
+	expectedRes := map[string]interface{}{
+		"id":    groupId,
+		"apps":  []map[string]interface{}{{"app": "line-monitor", "version": "1.4.0", "state": "running"}},
+		"prune": false,
+		"convergence": map[string]interface{}{
+			agentId: map[string]interface{}{
+				"status":   "converged",
+				"actions":  []string{"deploy line-monitor 1.4.0"},
+				"failures": []string(nil),
+				"time":     int64(10),
+			},
+		},
+	}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(GROUP_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(bson.M{"_id": bson.ObjectIdHex(groupId)}).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetGroupDesiredState(groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledGetGroupDesiredStateWithoutDesiredState_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	arg := Group{ID: bson.ObjectIdHex(groupId), Members: []string{agentId}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(GROUP_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(bson.M{"_id": bson.ObjectIdHex(groupId)}).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	_, err := dbManager.GetGroupDesiredState(groupId)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetAllGroupDesiredStates_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	args := []Group{{ID: bson.ObjectIdHex(groupId), Desired: &DesiredState{Apps: []DesiredApp{}, Prune: true}}}
+	expectedRes := []map[string]interface{}{{
+		"id":          groupId,
+		"apps":        []map[string]interface{}{},
+		"prune":       true,
+		"convergence": map[string]interface{}{},
+	}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(GROUP_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(bson.M{"desired": bson.M{"$exists": true}}).Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetAllGroupDesiredStates()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledUpdateGroupConvergence_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	convergence := Convergence{Status: "failed", Actions: []string{}, Failures: []string{"deploy line-monitor 1.4.0: 500"}, Time: 10}
+	query := bson.M{"_id": bson.ObjectIdHex(groupId)}
+	update := bson.M{"$set": bson.M{"convergence." + agentId: convergence}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(GROUP_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.UpdateGroupConvergence(groupId, agentId, "failed", []string{}, []string{"deploy line-monitor 1.4.0: 500"}, 10)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledUpdateGroupConvergenceWithInvalidObjectIdAboutAgent_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+	err := dbManager.UpdateGroupConvergence(groupId, invalidObjectId, "converged", nil, nil, 10)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidObjectId", err)
+	case errors.InvalidObjectId:
+	}
+}
+
+func TestCalledAddKey_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(KEY_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Insert(gomock.Any()).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.AddKey("operator", "viewer", []string{groupId}, "hash")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if _, exists := res["hash"]; exists || res["name"] != "operator" || res["role"] != "viewer" {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledAddKeyWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(KEY_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Insert(gomock.Any()).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	_, err := dbManager.AddKey("operator", "viewer", []string{groupId}, "hash")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetKeyByHash_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	arg := Key{ID: bson.ObjectIdHex(keyId), Name: "operator", Role: "viewer", Groups: []string{groupId}, Hash: "hash"}
+	expectedRes := map[string]interface{}{
+		"id":     keyId,
+		"name":   "operator",
+		"role":   "viewer",
+		"groups": []string{groupId},
+	}
+
+	query := bson.M{"hash": "hash"}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(KEY_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetKeyByHash("hash")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledGetKeyByHashWhenDBHasNotMatchedKey_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"hash": "hash"}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(KEY_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	_, err := dbManager.GetKeyByHash("hash")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetAllKeys_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	args := []Key{{ID: bson.ObjectIdHex(keyId), Name: "operator", Role: "admin", Hash: "hash"}}
+	expectedRes := []map[string]interface{}{{
+		"id":     keyId,
+		"name":   "operator",
+		"role":   "admin",
+		"groups": []string(nil),
+	}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(KEY_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(nil).Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetAllKeys()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledDeleteKey_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": bson.ObjectIdHex(keyId)}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(KEY_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Remove(query).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteKey(keyId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteKeyWithInvalidObjectId_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{}
+	err := dbManager.DeleteKey(invalidObjectId)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidObjectId", err)
+	case errors.InvalidObjectId:
+	}
+}
+
+func TestCalledAddEvent_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	matchEvent := gomock.AssignableToTypeOf(Event{})
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(EVENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Insert(matchEvent).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.AddEvent(agentId, "connected", 100, "")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledAddEventWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(EVENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Insert(gomock.Any()).Return(connectionError),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.AddEvent(agentId, "connected", 100, "")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBOperationError", err)
+	case errors.DBOperationError:
+	}
+}
+
+func TestCalledGetEventsByQuery_ExpectPageReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventId := "000000000000000000000006"
+	args := []Event{
+		{ID: bson.ObjectIdHex(eventId), AgentID: agentId, Type: "disconnected", Time: 200},
+		{ID: bson.ObjectIdHex("000000000000000000000007"), AgentID: agentId, Type: "connected", Time: 300},
+	}
+	expectedRes := []map[string]interface{}{{
+		"id":      eventId,
+		"agentid": agentId,
+		"type":    "disconnected",
+		"time":    int64(200),
+		"detail":  "",
+	}}
+	query := bson.M{
+		"agentid": agentId,
+		"type":    bson.M{"$in": []string{"connected", "disconnected"}},
+		"time":    bson.M{"$gte": int64(100), "$lte": int64(400)},
+	}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(EVENT_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().Sort("_id").Return(queryMockObj),
+		queryMockObj.EXPECT().Limit(2).Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	filter := map[string]string{"agent": agentId, "type": "connected,disconnected", "from": "100", "to": "400"}
+	res, next, err := dbManager.GetEventsByQuery(filter, "", 1, "")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+
+	if next != encodeCursor(bson.ObjectIdHex(eventId), "") {
+		t.Errorf("Unexpected cursor: %s", next)
+	}
+}
+
+func TestCalledGetEventsByQueryWithInvalidQuery_ExpectErrorReturn(t *testing.T) {
+	dbManager := MongoDBManager{mgoSession: &dummySession}
+	testList := []struct {
+		filter map[string]string
+		sort   string
+	}{
+		{map[string]string{"from": "yesterday"}, ""},
+		{map[string]string{"status": status}, ""},
+		{nil, "time"},
+	}
+
+	for _, test := range testList {
+		_, _, err := dbManager.GetEventsByQuery(test.filter, test.sort, 0, "")
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+		case errors.InvalidParam:
+		}
+	}
+
+	_, _, err := dbManager.GetEventsByQuery(map[string]string{"agent": invalidObjectId}, "", 0, "")
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidObjectId", err)
+	case errors.InvalidObjectId:
+	}
+}
+
+func TestCalledAddCatalogApp_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expectedRes := map[string]interface{}{
+		"name":        "line-monitor",
+		"description": "monitor",
+		"versions":    []map[string]interface{}{},
+	}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(CATALOG_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Insert(CatalogApp{Name: "line-monitor", Description: "monitor"}).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.AddCatalogApp("line-monitor", "monitor")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledGetCatalogApp_ExpectDescriptorsExcluded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	arg := CatalogApp{Name: "line-monitor", Versions: []CatalogVersion{
+		{Version: "1.4.0", Descriptor: "services:", Checksum: "sum", CreatedAt: 10},
+	}}
+	expectedRes := map[string]interface{}{
+		"name":        "line-monitor",
+		"description": "",
+		"versions": []map[string]interface{}{{
+			"version":     "1.4.0",
+			"description": "",
+			"checksum":    "sum",
+			"createdat":   int64(10),
+		}},
+	}
+
+	query := bson.M{"_id": "line-monitor"}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(CATALOG_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetCatalogApp("line-monitor")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledGetAllCatalogApps_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	args := []CatalogApp{{Name: "line-monitor"}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(CATALOG_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(nil).Return(queryMockObj),
+		queryMockObj.EXPECT().Sort("_id").Return(queryMockObj),
+		queryMockObj.EXPECT().All(gomock.Any()).SetArg(0, args).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetAllCatalogApps()
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if len(res) != 1 || res[0]["name"] != "line-monitor" {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledDeleteCatalogAppWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": "line-monitor"}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(CATALOG_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Remove(query).Return(mgo.ErrNotFound),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.DeleteCatalogApp("line-monitor")
+
+	if !errors.Is(err, errors.NotFound{Kind: errors.CATALOG}) {
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	}
+}
+
+func TestCalledAddCatalogVersion_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query := bson.M{"_id": "line-monitor", "versions.version": bson.M{"$ne": "1.4.0"}}
+	update := bson.M{"$push": bson.M{"versions": CatalogVersion{
+		Version:    "1.4.0",
+		Descriptor: "services:",
+		Checksum:   "sum",
+		CreatedAt:  10,
+	}}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(CATALOG_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Update(query, update).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	err := dbManager.AddCatalogVersion("line-monitor", "1.4.0", "", "services:", "sum", 10)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledGetCatalogVersion_ExpectDescriptorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	arg := CatalogApp{Name: "line-monitor", Versions: []CatalogVersion{
+		{Version: "1.3.0", Descriptor: "old"},
+		{Version: "1.4.0", Descriptor: "new", Checksum: "sum", CreatedAt: 10},
+	}}
+	expectedRes := map[string]interface{}{
+		"name":        "line-monitor",
+		"version":     "1.4.0",
+		"description": "",
+		"descriptor":  "new",
+		"checksum":    "sum",
+		"createdat":   int64(10),
+	}
+
+	query := bson.M{"_id": "line-monitor"}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(CATALOG_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(query).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	res, err := dbManager.GetCatalogVersion("line-monitor", "1.4.0")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledGetCatalogVersionWithUnknownVersion_ExpectNotFoundReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	arg := CatalogApp{Name: "line-monitor", Versions: []CatalogVersion{{Version: "1.3.0"}}}
+
+	sessionMockObj := mgomocks.NewMockSession(ctrl)
+	dbMockObj := mgomocks.NewMockDatabase(ctrl)
+	collectionMockObj := mgomocks.NewMockCollection(ctrl)
+	queryMockObj := mgomocks.NewMockQuery(ctrl)
+
+	gomock.InOrder(
+		sessionMockObj.EXPECT().DB(gomock.Any()).Return(dbMockObj),
+		dbMockObj.EXPECT().C(CATALOG_COLLECTION).Return(collectionMockObj),
+		collectionMockObj.EXPECT().Find(gomock.Any()).Return(queryMockObj),
+		queryMockObj.EXPECT().One(gomock.Any()).SetArg(0, arg).Return(nil),
+	)
+
+	dbManager := MongoDBManager{mgoSession: sessionMockObj}
+	_, err := dbManager.GetCatalogVersion("line-monitor", "1.4.0")
+
+	if !errors.Is(err, errors.NotFound{Kind: errors.CATALOG}) {
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	}
+}
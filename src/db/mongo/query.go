@@ -0,0 +1,125 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package mongo
+
+import (
+	"commons/errors"
+	"encoding/base64"
+	"encoding/json"
+	"gopkg.in/mgo.v2/bson"
+	"regexp"
+	"strings"
+)
+
+const (
+	ID_FIELD   = "_id" // name of the primary key field.
+	DESCENDING = "-"   // prefix of a sort field to sort documents in descending order.
+)
+
+var (
+	agentSortFields = map[string]string{"id": ID_FIELD, "host": "host", "status": "status"}
+	groupSortFields = map[string]string{"id": ID_FIELD}
+	eventSortFields = map[string]string{"id": ID_FIELD}
+)
+
+// cursor represents the position of the last document of a page.
+// Value is the value of the sort field, which is empty if documents are sorted by id.
+type cursor struct {
+	Value string `json:"value,omitempty"`
+	ID    string `json:"id"`
+}
+
+// makePageQuery returns the query and the sort order to get documents matching the filter
+// which follow the position given by cursorStr in the order given by sort.
+// sort is one of the keys of fields, optionally prefixed by '-' for descending order.
+// Documents with the same value of the sort field are ordered by id,
+// so that a page is not overlapped with the next page.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func makePageQuery(filter bson.M, sort string, fields map[string]string, cursorStr string) (bson.M, []string, error) {
+	descending := strings.HasPrefix(sort, DESCENDING)
+	key := strings.TrimPrefix(sort, DESCENDING)
+	if key == "" {
+		key = "id"
+	}
+
+	field, exists := fields[key]
+	if !exists {
+		return nil, nil, errors.InvalidParam{Message: "unsupported sort field: " + key}
+	}
+
+	order := []string{field}
+	if field != ID_FIELD {
+		order = append(order, ID_FIELD)
+	}
+	if descending {
+		for i := range order {
+			order[i] = DESCENDING + order[i]
+		}
+	}
+
+	if cursorStr == "" {
+		return filter, order, nil
+	}
+
+	position, err := decodeCursor(cursorStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	operator := "$gt"
+	if descending {
+		operator = "$lt"
+	}
+	after := bson.M{ID_FIELD: bson.M{operator: bson.ObjectIdHex(position.ID)}}
+	if field != ID_FIELD {
+		after = bson.M{"$or": []bson.M{
+			{field: bson.M{operator: position.Value}},
+			{field: position.Value, ID_FIELD: bson.M{operator: bson.ObjectIdHex(position.ID)}},
+		}}
+	}
+	return bson.M{"$and": []bson.M{filter, after}}, order, nil
+}
+
+// encodeCursor returns the cursor which points the document with the id and the value of the sort field.
+func encodeCursor(id bson.ObjectId, value string) string {
+	data, _ := json.Marshal(cursor{Value: value, ID: id.Hex()})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the position encoded by encodeCursor.
+// If the cursor is malformed, InvalidParam error will be returned.
+func decodeCursor(cursorStr string) (cursor, error) {
+	position := cursor{}
+	data, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err == nil {
+		err = json.Unmarshal(data, &position)
+	}
+	if err != nil || !bson.IsObjectIdHex(position.ID) {
+		return cursor{}, errors.InvalidParam{Message: "invalid cursor: " + cursorStr}
+	}
+	return position, nil
+}
+
+// globToRegex converts a glob pattern into a regular expression which matches the whole string.
+// '*' matches any sequence of characters and '?' matches any single character.
+func globToRegex(glob string) bson.RegEx {
+	pattern := regexp.QuoteMeta(glob)
+	pattern = strings.Replace(pattern, `\*`, ".*", -1)
+	pattern = strings.Replace(pattern, `\?`, ".", -1)
+	return bson.RegEx{Pattern: "^" + pattern + "$"}
+}
@@ -0,0 +1,67 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package mongo
+
+import (
+	"gopkg.in/mgo.v2/bson"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestCalledMakePageQueryWithSortAndCursor_ExpectQueryFollowingCursor(t *testing.T) {
+	filter := bson.M{"status": status}
+	cursor := encodeCursor(bson.ObjectIdHex(agentId), "10.0.0.1")
+
+	query, order, err := makePageQuery(filter, "host", agentSortFields, cursor)
+	if err != nil {
+		t.Fatalf("Unexpected err: %s", err.Error())
+	}
+
+	expectedQuery := bson.M{"$and": []bson.M{filter, {"$or": []bson.M{
+		{"host": bson.M{"$gt": "10.0.0.1"}},
+		{"host": "10.0.0.1", "_id": bson.M{"$gt": bson.ObjectIdHex(agentId)}},
+	}}}}
+	if !reflect.DeepEqual(query, expectedQuery) {
+		t.Errorf("Expected query: %v, actual query: %v", expectedQuery, query)
+	}
+	if !reflect.DeepEqual(order, []string{"host", "_id"}) {
+		t.Errorf("Unexpected order: %v", order)
+	}
+}
+
+func TestCalledGlobToRegex_ExpectWholeStringMatched(t *testing.T) {
+	testList := []struct {
+		glob    string
+		str     string
+		matched bool
+	}{
+		{"10.0.*", "10.0.1.2", true},
+		{"10.0.*", "10.01.2", false},
+		{"10.0.*", "110.0.1.2", false},
+		{"192.168.0.?", "192.168.0.1", true},
+		{"192.168.0.?", "192.168.0.10", false},
+		{"host(1)", "host(1)", true},
+	}
+
+	for _, test := range testList {
+		matched := regexp.MustCompile(globToRegex(test.glob).Pattern).MatchString(test.str)
+		if matched != test.matched {
+			t.Errorf("Expected matched: %t, actual matched: %t for %s and %s", test.matched, matched, test.glob, test.str)
+		}
+	}
+}
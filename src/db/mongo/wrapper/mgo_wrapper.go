@@ -24,6 +24,7 @@ import (
 type (
 	Session interface {
 		DB(name string) Database
+		Copy() Session
 		Close()
 	}
 
@@ -59,6 +60,8 @@ type (
 	Query interface {
 		All(result interface{}) error
 		One(result interface{}) error
+		Sort(fields ...string) Query
+		Limit(n int) Query
 	}
 
 	MongoQuery struct {
@@ -70,6 +73,10 @@ func (s MongoSession) DB(name string) Database {
 	return &MongoDatabase{Database: s.Session.DB(name)}
 }
 
+func (s MongoSession) Copy() Session {
+	return MongoSession{Session: s.Session.Copy()}
+}
+
 func (s MongoSession) Close() {
 	s.Session.Close()
 }
@@ -115,16 +122,24 @@ func (q MongoQuery) One(result interface{}) error {
 	return q.Query.One(result)
 }
 
+// Sort is a wrapper function used to abstract mgo Sort function.
+func (q MongoQuery) Sort(fields ...string) Query {
+	return MongoQuery{Query: q.Query.Sort(fields...)}
+}
+
+// Limit is a wrapper function used to abstract mgo Limit function.
+func (q MongoQuery) Limit(n int) Query {
+	return MongoQuery{Query: q.Query.Limit(n)}
+}
+
 // ConvertMongoError converts a mongo error into an error defined in errors package.
-func ConvertMongoError(mgoError error, message ...string) (err error) {
+// The kind and the id of the resource are included in the converted error,
+// and the mongo error is wrapped as its cause.
+func ConvertMongoError(mgoError error, kind string, id string) (err error) {
 	switch mgoError {
 	case mgo.ErrNotFound:
-		targetId := ""
-		if len(message) != 0 {
-			targetId = message[0]
-		}
-		return errors.NotFound{targetId}
+		return errors.NotFound{Kind: kind, ID: id, Cause: mgoError}
 	default:
-		return errors.DBOperationError{mgoError.Error()}
+		return errors.DBOperationError{Kind: kind, ID: id, Cause: mgoError}
 	}
 }
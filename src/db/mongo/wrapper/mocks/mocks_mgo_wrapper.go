@@ -61,6 +61,18 @@ func (mr *MockSessionMockRecorder) DB(name interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DB", reflect.TypeOf((*MockSession)(nil).DB), name)
 }
 
+// Copy mocks base method
+func (m *MockSession) Copy() Session {
+	ret := m.ctrl.Call(m, "Copy")
+	ret0, _ := ret[0].(Session)
+	return ret0
+}
+
+// Copy indicates an expected call of Copy
+func (mr *MockSessionMockRecorder) Copy() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Copy", reflect.TypeOf((*MockSession)(nil).Copy))
+}
+
 // Close mocks base method
 func (m *MockSession) Close() {
 	m.ctrl.Call(m, "Close")
@@ -263,3 +275,32 @@ func (m *MockQuery) One(result interface{}) error {
 func (mr *MockQueryMockRecorder) One(result interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "One", reflect.TypeOf((*MockQuery)(nil).One), result)
 }
+
+// Sort mocks base method
+func (m *MockQuery) Sort(fields ...string) Query {
+	varargs := []interface{}{}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Sort", varargs...)
+	ret0, _ := ret[0].(Query)
+	return ret0
+}
+
+// Sort indicates an expected call of Sort
+func (mr *MockQueryMockRecorder) Sort(fields ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sort", reflect.TypeOf((*MockQuery)(nil).Sort), varargs...)
+}
+
+// Limit mocks base method
+func (m *MockQuery) Limit(n int) Query {
+	ret := m.ctrl.Call(m, "Limit", n)
+	ret0, _ := ret[0].(Query)
+	return ret0
+}
+
+// Limit indicates an expected call of Limit
+func (mr *MockQueryMockRecorder) Limit(n interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockQuery)(nil).Limit), n)
+}
@@ -17,9 +17,110 @@
 package main
 
 import (
-    "api"
+	"api"
+	"commons/config"
+	"commons/logger"
+	"commons/tlsconfig"
+	"context"
+	"db"
+	"manager/agent"
+	"manager/group"
+	"messenger"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
-    api.RunSDAMWebServer("0.0.0.0", 48099)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		os.Exit(1)
+	}
+	config.Set(cfg)
+
+	serverTLS, err := tlsconfig.NewServerConfig(cfg.Server.TLS)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		os.Exit(1)
+	}
+
+	agentTLS, err := tlsconfig.NewClientConfig(cfg.Agent.TLS)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		os.Exit(1)
+	}
+	messenger.SetTLSConfig(agentTLS)
+
+	// Agents which stopped sending pings while the service was down are marked as disconnected.
+	if err = agent.RestoreHealthCheck(context.Background()); err != nil {
+		logger.Logging(logger.ERROR, "failed to restore healthchecks:", err.Error())
+	}
+
+	// Agents which cannot send pings are probed by the manager.
+	agent.StartHealthProbe()
+
+	// Apps recorded for agents are reconciled with the apps which the agents run.
+	agent.StartReconciler()
+
+	// Agents which have been disconnected for long become stale and are deleted.
+	agent.StartRetention()
+
+	// Members of groups which declare a desired state are converged toward it.
+	group.StartConverger()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- api.RunSDAMWebServer(cfg.Server.Address, cfg.Server.Port, serverTLS)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err = <-serverErr:
+		logger.Logging(logger.ERROR, err.Error())
+		os.Exit(1)
+	case sig := <-signals:
+		logger.Logging(logger.INFO, "shutting down by", sig.String())
+	}
+
+	if !shutdown(time.Duration(cfg.Server.ShutdownTimeout) * time.Second) {
+		os.Exit(1)
+	}
+}
+
+// shutdown stops accepting requests, waits for running requests and healthchecks
+// up to the given timeout, and then closes the database session.
+// This function returns false if anything is not finished in time.
+func shutdown(timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	completed := true
+	if err := api.ShutdownSDAMWebServer(ctx); err != nil {
+		logger.Logging(logger.ERROR, "failed to wait for running requests:", err.Error())
+		completed = false
+	}
+	if err := agent.StopRetention(ctx); err != nil {
+		logger.Logging(logger.ERROR, "failed to wait for the retention policy:", err.Error())
+		completed = false
+	}
+	if err := group.StopConverger(ctx); err != nil {
+		logger.Logging(logger.ERROR, "failed to wait for running convergences:", err.Error())
+		completed = false
+	}
+	if err := agent.StopReconciler(ctx); err != nil {
+		logger.Logging(logger.ERROR, "failed to wait for running reconciliations:", err.Error())
+		completed = false
+	}
+	if err := agent.StopHealthCheck(ctx); err != nil {
+		logger.Logging(logger.ERROR, "failed to wait for running healthchecks:", err.Error())
+		completed = false
+	}
+	db.Close()
+
+	logger.Logging(logger.INFO, "shutdown is completed")
+	return completed
 }
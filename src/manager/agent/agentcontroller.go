@@ -20,53 +20,187 @@
 package agent
 
 import (
+	"commons/compose"
+	"commons/config"
 	"commons/errors"
+	"commons/labels"
 	"commons/logger"
+	"commons/paging"
 	"commons/results"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"db"
+	"encoding/hex"
 	"encoding/json"
+	"io"
+	"manager/catalog"
+	"manager/event"
+	"manager/health"
+	"manager/revision"
 	"messenger"
+	"net/url"
 	"strconv"
 	"time"
 )
 
 const (
-	AGENTS                      = "agents"       // used to indicate a list of agents.
-	ID                          = "id"           // used to indicate an agent id.
-	HOST                        = "host"         // used to indicate an agent address.
-	PORT                        = "port"         // used to indicate an agent port.
-	DEFAULT_SDA_PORT            = "48098"        // default service deployment agent port.
-	STATUS_CONNECTED            = "connected"    // used to update agent status with connected.
-	STATUS_DISCONNECTED         = "disconnected" // used to update agent status with disconnected.
-	INTERVAL                    = "interval"     // a period between two healthcheck message.
-	MAXIMUM_NETWORK_LATENCY_SEC = 3              // the term used to indicate any kind of delay that happens in data communication over a network.
-	TIME_UNIT                   = time.Minute    // the minute is a unit of time for healthcheck.
+	AGENTS              = "agents"       // used to indicate a list of agents.
+	ID                  = "id"           // used to indicate an agent id.
+	HOST                = "host"         // used to indicate an agent address.
+	PORT                = "port"         // used to indicate an agent port.
+	STATUS              = "status"       // used to indicate an agent status.
+	APP                 = "app"          // used to indicate an app installed in agents.
+	GROUP               = "group"        // used to indicate a group including agents.
+	STATUS_CONNECTED    = "connected"    // used to update agent status with connected.
+	STATUS_DISCONNECTED = "disconnected" // used to update agent status with disconnected.
+	STATUS_STALE        = "stale"        // used to update agent status with stale.
+	INTERVAL            = "interval"     // a period between two healthcheck message, which is stored in seconds.
+	UNIT                = "unit"         // used to indicate the unit of the interval of a healthcheck message.
+	UNIT_MINUTE         = "min"          // the interval is given in minutes, which is the default unit.
+	UNIT_SECOND         = "sec"          // the interval is given in seconds.
+	LAST_SEEN           = "lastseen"     // the time of the last healthcheck message in seconds since the epoch.
+	METADATA            = "metadata"     // used to indicate metadata reported by an agent.
+	LABELS              = "labels"       // used to indicate labels of an agent.
+	SELECTOR            = "selector"     // used to indicate a label selector of agents.
+	DEVICE_ID           = "deviceid"     // used to indicate a stable identity of the device running an agent.
+	ADDRESSES           = "addresses"    // used to indicate the history of address changes of an agent.
+	AGENT               = "agent"        // used to indicate an agent of events.
+	TYPE                = "type"         // used to indicate a type of events.
+	HEALTH_CHECK        = "healthcheck"  // used to indicate the health check mode of an agent.
+	PROBE               = "probe"        // used to indicate events caused by health probes.
+	SECRET              = "secret"       // used to indicate a secret issued to an agent to prove its identity.
+	SECRET_BYTES        = 32             // the number of random bytes of a secret.
+)
+
+// Fields of metadata reported by agents.
+const (
+	HOSTNAME       = "hostname"      // the hostname of the device.
+	OS             = "os"            // the operating system of the device.
+	ARCH           = "arch"          // the CPU architecture of the device.
+	KERNEL         = "kernel"        // the kernel version of the device.
+	DOCKER_VERSION = "dockerversion" // the version of Docker engine.
+	SDA_VERSION    = "sdaversion"    // the version of Service Deployment Agent.
+	FREE_CPU       = "freecpu"       // the percentage of idle CPU.
+	FREE_MEMORY    = "freememory"    // the free memory in bytes.
+	FREE_DISK      = "freedisk"      // the free disk space in bytes.
 )
 
 type AgentController struct{}
 
 var dbConnector db.DBConnection
 var httpMessenger messenger.MessengerInterface
-var timers map[string]chan bool
+
+// monitor tracks the deadline of the next ping of each agent.
+var monitor *health.Monitor
+
+// prober sends health probes to agents periodically, and is nil if probes are not started.
+var prober *health.Prober
+
+// now returns the current time, and is replaced in tests.
+var now = time.Now
+
+// randReader is a source of secrets issued to agents, and is replaced in tests.
+var randReader io.Reader
 
 func init() {
 	dbConnector = db.DBConnector{}
 	httpMessenger = messenger.SdamMsgrImpl{}
+	randReader = rand.Reader
 
-	timers = make(map[string]chan bool)
+	monitor = health.NewMonitor(health.SystemClock{}, disconnectAgent)
 }
 
-// AddAgent inserts a new agent with ip which is passed in call to function.
-// If successful, a unique id that is created automatically will be returned.
-// otherwise, an appropriate error will be returned.
-func (AgentController) AddAgent(body string) (int, map[string]interface{}, error) {
+// StopHealthCheck stops health probes and tracking the deadlines of agents without changing
+// the status of agents. Running probes and an agent whose deadline has already passed are waited
+// for until its status is updated or the given context is done.
+// If successful, this function returns an error as nil.
+// otherwise, the error of the context will be returned.
+func StopHealthCheck(ctx context.Context) error {
 	logger.Logging(logger.DEBUG, "IN")
 	defer logger.Logging(logger.DEBUG, "OUT")
 
+	if prober != nil {
+		if err := prober.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	return monitor.Stop(ctx)
+}
+
+// RestoreHealthCheck rebuilds healthcheck deadlines of agents from the time of their last ping
+// and their interval, which are stored in the database.
+// Agents whose deadline passed while the service was not running are marked as disconnected
+// right away, and agents which have never sent a ping are left as they are.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func RestoreHealthCheck(ctx context.Context) error {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return err
+	}
+	defer db.Close()
+
+	agents, err := db.GetAllAgents()
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return err
+	}
+
+	for _, agent := range agents {
+		agentId, _ := agent[ID].(string)
+		lastSeen, _ := agent[LAST_SEEN].(int64)
+		interval, _ := agent[INTERVAL].(int)
+		if lastSeen == 0 {
+			continue
+		}
+
+		// If the deadline has passed, the next ping marks the agent as connected again.
+		// A stale agent is not marked as disconnected again.
+		deadline := time.Unix(lastSeen, 0).Add(healthCheckTimeout(interval))
+		if !monitor.Watch(agentId, deadline) || agent[STATUS] != STATUS_CONNECTED {
+			continue
+		}
+
+		logger.LoggingContext(ctx, logger.INFO, "ping request is not received in interval time before restart:", agentId)
+		err = db.UpdateAgentStatus(agentId, STATUS_DISCONNECTED)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return err
+		}
+
+		// The agent was disconnected at the deadline, not at the time of restart.
+		err = db.AddEvent(agentId, event.DISCONNECTED, deadline.Unix(), "")
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAgent inserts a new agent with ip which is passed in call to function.
+// If successful, a unique id that is created automatically will be returned with a secret
+// issued to the agent. The agent proves its identity with the secret when it registers again
+// or its address changes, and the secret is returned only once.
+// An agent registered before secrets were issued gets one when it registers again from its address.
+// A device which comes from the address of an agent without its secret is registered as a new agent,
+// since the address may have been leased to it, while a device id is never taken over without the secret.
+// otherwise, an appropriate error will be returned.
+func (AgentController) AddAgent(ctx context.Context, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -75,118 +209,262 @@ func (AgentController) AddAgent(body string) (int, map[string]interface{}, error
 	// This code will be used to update the information of agent without changing id.
 	bodyMap, err := convertJsonToMap(body)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Check whether 'ip' is included.
-	_, exists := bodyMap["ip"]
+	value, exists := bodyMap["ip"]
 	if !exists {
-		return results.ERROR, nil, errors.InvalidJSON{"ip field is required"}
+		return results.ERROR, nil, errors.InvalidJSON{Message: "ip field is required"}
+	}
+	ip, ok := value.(string)
+	if !ok {
+		return results.ERROR, nil, errors.InvalidParam{Message: "ip field should be a string"}
+	}
+
+	metadata, err := parseMetadata(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	agentLabels, err := parseLabels(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	port, err := parsePort(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	deviceId, err := parseDeviceId(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
 	}
 
-	// Get agent with given ip.
-	agent, err := db.GetAgentByIP(bodyMap["ip"].(string))
+	mode, err := parseHealthCheck(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	secret, err := parseSecret(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Get agent with given device id or ip.
+	// If the agent already exists in the database, its address, metadata and labels are updated
+	// only if the caller proves to be the agent with the secret issued to it.
+	hash := ""
+	agent, byDevice, err := findRegisteredAgent(db, deviceId, ip)
+	if err != nil && !errors.Is(err, errors.NotFound{}) {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
 	if err == nil {
-		// Agent with that ip address already exists in the database.
-		res := make(map[string]interface{})
-		res[ID] = agent[ID]
-		return results.OK, res, err
+		hash, err = verifySecret(db, agent, secret, ip, port)
+		if err != nil && (byDevice || !errors.Is(err, errors.Unauthorized{})) {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+		if err != nil {
+			// The address may have been leased to another device, which is registered as a new agent.
+			logger.LoggingContext(ctx, logger.INFO, "address of agent is taken by a new device:", agent[ID].(string), ip)
+			agent = nil
+		}
+	}
+
+	if agent == nil {
+		// Add new agent to database with given ip, port, status.
+		if port == "" {
+			port = config.Get().Agent.DefaultPort
+		}
+		agent, err = db.AddAgent(ip, port, STATUS_CONNECTED)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	} else {
+		err = updateAgentAddress(ctx, db, agent, ip, port)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+
+	// The device id is kept once given, so that the agent can be found after its address changes.
+	if deviceId != "" && agent[DEVICE_ID] != deviceId {
+		err = db.UpdateAgentDeviceID(agent[ID].(string), deviceId)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+
+	if len(metadata) != 0 {
+		err = db.UpdateAgentMetadata(agent[ID].(string), metadata)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+
+	if len(agentLabels) != 0 {
+		err = db.UpdateAgentLabels(agent[ID].(string), agentLabels, nil)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
 	}
 
-	// Add new agent to database with given ip, port, status.
-	agent, err = db.AddAgent(bodyMap["ip"].(string), DEFAULT_SDA_PORT, STATUS_CONNECTED)
+	// Agents which cannot send pings declare that they should be probed.
+	if mode != "" && agent[HEALTH_CHECK] != mode {
+		err = db.UpdateAgentHealthCheck(agent[ID].(string), mode)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+
+	err = db.AddEvent(agent[ID].(string), event.REGISTERED, now().Unix(), ip)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	res := make(map[string]interface{})
 	res[ID] = agent[ID]
+
+	// A secret is issued to a new agent, and to an agent which does not have one yet.
+	if hash == "" {
+		secret, err = issueSecret(db, agent[ID].(string))
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+		res[SECRET] = secret
+	}
 	return results.OK, res, err
 }
 
 // PingAgent starts timer with received interval.
 // If agent does not send next healthcheck message in interval time,
 // change the status of device from connected to disconnected.
+// A ping from a new address, or with a new port, is accepted only with the secret issued to the agent.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) PingAgent(agentId string, ip string, body string) (int, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) PingAgent(ctx context.Context, agentId string, ip string, body string) (int, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, err
 	}
 	defer db.Close()
 
 	// Get agent specified by agentId parameter.
-	_, err = db.GetAgent(agentId)
+	agent, err := db.GetAgent(agentId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, err
 	}
 
 	bodyMap, err := convertJsonToMap(body)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, err
 	}
 
-	_, exists := timers[agentId]
-	if !exists {
-		logger.Logging(logger.DEBUG, "first ping request is received from agent")
-	} else {
-		if timers[agentId] != nil {
-			// If ping request is received in interval time, send signal to stop timer.
-			timers[agentId] <- true
-			logger.Logging(logger.DEBUG, "ping request is received in interval time")
-		} else {
-			logger.Logging(logger.DEBUG, "ping request is received after interval time-out")
-			err = db.UpdateAgentStatus(agentId, STATUS_CONNECTED)
-			if err != nil {
-				logger.Logging(logger.ERROR, err.Error())
-			}
-		}
+	// Check whether 'interval' is included, and convert it into seconds.
+	interval, err := parseInterval(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
 	}
 
-	// Start timer with received interval time.
-	interval, err := strconv.Atoi(bodyMap[INTERVAL].(string))
-	timeDurationMin := time.Duration(interval+MAXIMUM_NETWORK_LATENCY_SEC) * TIME_UNIT
-	timer := time.NewTimer(timeDurationMin)
-	go func() {
-		quit := make(chan bool)
-		timers[agentId] = quit
+	// The agent may declare its port, and the ping may come from a new address.
+	port, err := parsePort(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
+	secret, err := parseSecret(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
+	if isAddressChanged(agent, ip, port) {
+		_, err = verifySecret(db, agent, secret, ip, port)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, err
+		}
+	}
+	err = updateAgentAddress(ctx, db, agent, ip, port)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
 
-		select {
-		// Block until timer finishes.
-		case <-timer.C:
-			logger.Logging(logger.ERROR, "ping request is not received in interval time")
+	// Free resources of the device may be reported with the ping.
+	metadata, err := parseMetadata(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
+	if len(metadata) != 0 {
+		err = db.UpdateAgentMetadata(agentId, metadata)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, err
+		}
+	}
 
-			// Connect to the database.
-			db, err := dbConnector.Connect()
-			if err != nil {
-				logger.Logging(logger.ERROR, err.Error())
-			}
-			defer db.Close()
+	// The liveness of an agent in pull mode is decided by health probes only.
+	mode := healthCheckMode(agent)
+	if mode == config.HEALTH_PULL {
+		return results.OK, err
+	}
 
-			// Status is updated with 'disconnected'.
-			err = db.UpdateAgentStatus(agentId, STATUS_DISCONNECTED)
-			if err != nil {
-				logger.Logging(logger.ERROR, err.Error())
-			}
+	// Store the time of this ping so that the deadline can be rebuilt after a restart.
+	err = db.UpdateAgentLastSeen(agentId, now().Unix(), interval)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
 
-		case <-quit:
-			timer.Stop()
-			return
+	// Reset the deadline of the agent with received interval time.
+	// If the agent is also probed, a later deadline given by a probe is kept.
+	var expired bool
+	if mode == config.HEALTH_BOTH {
+		expired = monitor.Extend(agentId, healthCheckTimeout(interval))
+	} else {
+		expired = monitor.Beat(agentId, healthCheckTimeout(interval))
+	}
+	if expired {
+		logger.LoggingContext(ctx, logger.DEBUG, "ping request is received after interval time-out")
+		err = db.UpdateAgentStatus(agentId, STATUS_CONNECTED)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, err
 		}
 
-		timers[agentId] = nil
-		close(quit)
-	}()
+		err = db.AddEvent(agentId, event.CONNECTED, now().Unix(), "")
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, err
+		}
+	}
 
 	return results.OK, err
 }
@@ -194,14 +472,14 @@ func (AgentController) PingAgent(agentId string, ip string, body string) (int, e
 // DeleteAgent deletes the agent with a primary key matching the agentId argument.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) DeleteAgent(agentId string) (int, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) DeleteAgent(ctx context.Context, agentId string) (int, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, err
 	}
 	defer db.Close()
@@ -209,28 +487,23 @@ func (AgentController) DeleteAgent(agentId string) (int, error) {
 	// Get agent specified by agentId parameter.
 	agent, err := db.GetAgent(agentId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, err
 	}
 
 	// Send request to unregister a specific agent.
 	address := getAgentAddress(agent)
-	codes, _ := httpMessenger.Unregister(address)
+	codes, _ := httpMessenger.Unregister(ctx, address)
 
 	result := codes[0]
 	if !isSuccessCode(result) {
 		return results.ERROR, err
 	}
 
-	if timers[agentId] != nil {
-		timers[agentId] <- true
-	}
-	delete(timers, agentId)
-
-	// Delete agent specified by agentId parameter.
-	err = db.DeleteAgent(agentId)
+	// Remove the agent from all groups and delete it.
+	_, err = removeAgent(db, agentId, "")
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, err
 	}
 
@@ -240,14 +513,14 @@ func (AgentController) DeleteAgent(agentId string) (int, error) {
 // GetAgent returns the agent with a primary key matching the agentId argument.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) GetAgent(agentId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) GetAgent(ctx context.Context, agentId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -255,52 +528,324 @@ func (AgentController) GetAgent(agentId string) (int, map[string]interface{}, er
 	// Get agent specified by agentId parameter.
 	agent, err := db.GetAgent(agentId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	return results.OK, agent, err
 }
 
-// GetAgents returns all agents in databases as an array.
+// GetAgents returns agents in databases as an array.
+// Agents can be filtered by status, host, app and group query parameters,
+// and a page of them is returned by sort, limit and cursor query parameters.
+// If more agents remain, a cursor of the next page is also returned.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) GetAgents() (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) GetAgents(ctx context.Context, query url.Values) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	options, err := paging.Parse(query, STATUS, HOST, APP, GROUP, SELECTOR)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// The selector is replaced with the labels required by it.
+	if selector, exists := options.Filter[SELECTOR]; exists {
+		required, err := labels.ParseSelector(selector)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+		delete(options.Filter, SELECTOR)
+		for key, value := range labels.ToFilter(required) {
+			options.Filter[key] = value
+		}
+	}
+
+	if status, exists := options.Filter[STATUS]; exists &&
+		status != STATUS_CONNECTED && status != STATUS_DISCONNECTED && status != STATUS_STALE {
+		err = errors.InvalidParam{Message: "status should be one of connected, disconnected or stale"}
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get a page of agents matching the filter.
+	agents, next, err := db.GetAgentsByQuery(options.Filter, options.Sort, options.Limit, options.Cursor)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[AGENTS] = agents
+	if next != "" {
+		res[paging.NEXT] = next
+	}
+
+	return results.OK, res, err
+}
+
+// GetEvents returns events of the agent specified by agentId parameter as an array.
+// Events can be filtered by type, from and to query parameters,
+// and a page of them is returned by sort, limit and cursor query parameters.
+// Events are kept after the agent is deleted, so the agent does not need to exist.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (AgentController) GetEvents(ctx context.Context, agentId string, query url.Values) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	options, err := paging.Parse(query, TYPE, event.FROM, event.TO)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	options.Filter[AGENT] = agentId
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get a page of events matching the filter.
+	events, next, err := db.GetEventsByQuery(options.Filter, options.Sort, options.Limit, options.Cursor)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[event.EVENTS] = events
+	if next != "" {
+		res[paging.NEXT] = next
+	}
+
+	return results.OK, res, err
+}
+
+// GetAvailability returns the availability of the agent specified by agentId parameter
+// in the period given by from and to query parameters, which is computed from its events.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (AgentController) GetAvailability(ctx context.Context, agentId string, query url.Values) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	from, to, err := event.ParsePeriod(query, now())
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	summary, err := event.GetAvailability(db, agentId, from, to)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res := summary.ToMap()
+	res[ID] = agentId
+	res[event.FROM] = from
+	res[event.TO] = to
+	return results.OK, res, err
+}
+
+// UpdateLabels changes labels of the agent specified by agentId parameter.
+// The body is a JSON object of labels to set, and a label whose value is null is removed.
+// Labels which are not included in the body are left as they are.
+// If successful, the labels of the agent after the change are returned.
+// otherwise, an appropriate error will be returned.
+func (AgentController) UpdateLabels(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	bodyMap, err := convertJsonToMap(body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	changed := make(map[string]string)
+	removed := make([]string, 0)
+	for key, value := range bodyMap {
+		switch value := value.(type) {
+		case string:
+			changed[key] = value
+		case nil:
+			if !labels.ValidKey(key) {
+				return results.ERROR, nil, errors.InvalidParam{Message: "invalid label key: " + key}
+			}
+			removed = append(removed, key)
+		default:
+			return results.ERROR, nil, errors.InvalidParam{Message: "value of label " + key + " should be a string or null"}
+		}
+	}
+	if err = labels.Validate(changed); err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get agent specified by agentId parameter.
+	agent, err := db.GetAgent(agentId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	err = db.UpdateAgentLabels(agentId, changed, removed)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Apply the change to the labels of the agent read before the update.
+	updated := make(map[string]string)
+	current, _ := agent[LABELS].(map[string]string)
+	for key, value := range current {
+		updated[key] = value
+	}
+	for key, value := range changed {
+		updated[key] = value
+	}
+	for _, key := range removed {
+		delete(updated, key)
+	}
+
+	res := make(map[string]interface{})
+	res[LABELS] = updated
+	return results.OK, res, err
+}
+
+// UpdateHealthCheck changes the health check mode of the agent specified by agentId parameter.
+// The body is a JSON object with 'healthcheck' field, which is one of 'push', 'pull' and 'both'.
+// If successful, the mode of the agent after the change is returned.
+// otherwise, an appropriate error will be returned.
+func (AgentController) UpdateHealthCheck(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	bodyMap, err := convertJsonToMap(body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	mode, err := parseHealthCheck(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	if mode == "" {
+		return results.ERROR, nil, errors.InvalidJSON{Message: "healthcheck field is required"}
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get agent specified by agentId parameter.
+	_, err = db.GetAgent(agentId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	err = db.UpdateAgentHealthCheck(agentId, mode)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[HEALTH_CHECK] = mode
+	return results.OK, res, err
+}
+
+// ResetSecret issues a new secret to the agent specified by agentId parameter,
+// which is used when the agent lost its secret, e.g. it is reinstalled.
+// The previous secret is no longer accepted, and the agent has to register with the new one.
+// If successful, the new secret is returned. otherwise, an appropriate error will be returned.
+func (AgentController) ResetSecret(ctx context.Context, agentId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
 
-	// Connect to the database.
-	db, err := dbConnector.Connect()
+	// Get agent specified by agentId parameter.
+	_, err = db.GetAgent(agentId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
-	defer db.Close()
 
-	// Get all agents stored in the database.
-	agents, err := db.GetAllAgents()
+	secret, err := issueSecret(db, agentId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	res := make(map[string]interface{})
-	res[AGENTS] = agents
-
+	res[ID] = agentId
+	res[SECRET] = secret
 	return results.OK, res, err
 }
 
 // DeployApp request an deployment of edge services to an agent specified by agentId parameter.
-// If response code represents success, add an app id to a list of installed app and returns it.
+// The body is a compose file or a reference to a version of an app in the catalog.
+// If response code represents success, add an app id to a list of installed app and returns it,
+// and the catalog version is recorded for the app if it is deployed by a reference.
+// Variables in the descriptor are rendered with the fields and labels of the agent
+// and variables given for the agent in the body.
+// The rendered descriptor is also recorded as the first revision of the app.
+// An invalid descriptor is rejected with InvalidParam error before the agent is contacted.
 // Otherwise, an appropriate error will be returned.
-func (AgentController) DeployApp(agentId string, body string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) DeployApp(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -308,26 +853,60 @@ func (AgentController) DeployApp(agentId string, body string) (int, map[string]i
 	// Get agent specified by agentId parameter.
 	agent, err := db.GetAgent(agentId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Resolve a catalog reference into the descriptor of the version.
+	descriptor, ref, err := catalog.Resolve(db, body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Reject an invalid descriptor before the agent is contacted.
+	err = compose.Validate(descriptor, config.Get().Agent.ComposeVersions)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Render variables in the descriptor for the agent.
+	descriptor, err = renderDescriptor(agent, descriptor, body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Request an deployment of edge services to a specific agent.
 	address := getAgentAddress(agent)
-	codes, respStr := httpMessenger.DeployApp(address, body)
+	codes, respStr := httpMessenger.DeployApp(ctx, address, descriptor)
 
-	respMap, err := convertRespToMap(respStr)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// if response code represents success, insert the installed appId into db.
 	result := codes[0]
 	if isSuccessCode(result) {
-		err = db.AddAppToAgent(agentId, respMap[ID].(string))
+		appId := respMap[ID].(string)
+		err = db.AddAppToAgent(agentId, appId)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+
+		err = catalog.Record(db, agent, appId, ref)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+
+		_, err = revision.Record(db, agentId, appId, descriptor, ref)
 		if err != nil {
-			logger.Logging(logger.ERROR, err.Error())
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
 			return results.ERROR, nil, err
 		}
 	}
@@ -339,14 +918,14 @@ func (AgentController) DeployApp(agentId string, body string) (int, map[string]i
 // specified by agentId parameter.
 // If response code represents success, returns a list of applications.
 // Otherwise, an appropriate error will be returned.
-func (AgentController) GetApps(agentId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) GetApps(ctx context.Context, agentId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -354,18 +933,18 @@ func (AgentController) GetApps(agentId string) (int, map[string]interface{}, err
 	// Get agent specified by agentId parameter.
 	agent, err := db.GetAgent(agentId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Request list of applications that is deployed to agent.
 	address := getAgentAddress(agent)
-	codes, respStr := httpMessenger.InfoApps(address)
+	codes, respStr := httpMessenger.InfoApps(ctx, address)
 
 	result := codes[0]
-	respMap, err := convertRespToMap(respStr)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -375,14 +954,14 @@ func (AgentController) GetApps(agentId string) (int, map[string]interface{}, err
 // GetApp gets the application's information of the agent specified by agentId parameter.
 // If response code represents success, returns information of application.
 // Otherwise, an appropriate error will be returned.
-func (AgentController) GetApp(agentId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) GetApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -390,18 +969,18 @@ func (AgentController) GetApp(agentId string, appId string) (int, map[string]int
 	// Get agent including app specified by appId parameter.
 	agent, err := db.GetAgentByAppID(agentId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Request get target application's information
 	address := getAgentAddress(agent)
-	codes, respStr := httpMessenger.InfoApp(address, appId)
+	codes, respStr := httpMessenger.InfoApp(ctx, address, appId)
 
 	result := codes[0]
-	respMap, err := convertRespToMap(respStr)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -409,16 +988,20 @@ func (AgentController) GetApp(agentId string, appId string) (int, map[string]int
 }
 
 // UpdateApp request to update an application specified by appId parameter.
+// The body is a compose file or a reference to a version of an app in the catalog,
+// and the catalog version recorded for the app follows the body.
+// Variables in the descriptor are rendered for the agent as DeployApp does.
+// An invalid descriptor is rejected with InvalidParam error before the agent is contacted.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) UpdateAppInfo(agentId string, appId string, body string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) UpdateAppInfo(ctx context.Context, agentId string, appId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -426,35 +1009,142 @@ func (AgentController) UpdateAppInfo(agentId string, appId string, body string)
 	// Get agent including app specified by appId parameter.
 	agent, err := db.GetAgentByAppID(agentId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Resolve a catalog reference into the descriptor of the version.
+	descriptor, ref, err := catalog.Resolve(db, body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Reject an invalid descriptor before the agent is contacted.
+	err = compose.Validate(descriptor, config.Get().Agent.ComposeVersions)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Render variables in the descriptor for the agent.
+	descriptor, err = renderDescriptor(agent, descriptor, body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Request update target application's information.
 	address := getAgentAddress(agent)
-	codes, respStr := httpMessenger.UpdateAppInfo(address, appId, body)
+	codes, respStr := httpMessenger.UpdateAppInfo(ctx, address, appId, descriptor)
 
 	result := codes[0]
-	respMap, err := convertRespToMap(respStr)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
+	// if response code represents success, record the catalog version and the revision of the app.
+	if isSuccessCode(result) {
+		err = catalog.Record(db, agent, appId, ref)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+
+		_, err = revision.Record(db, agentId, appId, descriptor, ref)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+
 	return result, respMap, err
 }
 
+// RollbackApp request to update an application specified by appId parameter with the descriptor
+// of an earlier revision, which is the previous one unless the body names a revision.
+// If response code represents success, the descriptor is recorded as a new revision and
+// the numbers of the new revision and the revision applied again are returned.
+// Otherwise, an appropriate error will be returned.
+func (AgentController) RollbackApp(ctx context.Context, agentId string, appId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	number, err := revision.ParseTarget(body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get agent including app specified by appId parameter.
+	agent, err := db.GetAgentByAppID(agentId, appId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	target, err := revision.Select(db, agentId, appId, number)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Request update target application's information with the descriptor of the revision.
+	descriptor := target[revision.DESCRIPTOR].(string)
+	address := getAgentAddress(agent)
+	codes, respStr := httpMessenger.UpdateAppInfo(ctx, address, appId, descriptor)
+
+	result := codes[0]
+	respMap, err := convertRespToMap(ctx, respStr)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	if !isSuccessCode(result) {
+		return result, respMap, err
+	}
+
+	// Record the catalog version of the revision and the descriptor as a new revision.
+	ref := revision.Reference(target)
+	err = catalog.Record(db, agent, appId, ref)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	recorded, err := revision.Record(db, agentId, appId, descriptor, ref)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[revision.REVISION] = recorded
+	res[revision.TARGET] = target[revision.REVISION]
+	return result, res, err
+}
+
 // DeleteApp request to delete an application specified by appId parameter.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) DeleteApp(agentId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) DeleteApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -462,19 +1152,19 @@ func (AgentController) DeleteApp(agentId string, appId string) (int, map[string]
 	// Get agent including app specified by appId parameter.
 	agent, err := db.GetAgentByAppID(agentId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Request delete target application
 	address := getAgentAddress(agent)
-	codes, respStr := httpMessenger.DeleteApp(address, appId)
+	codes, respStr := httpMessenger.DeleteApp(ctx, address, appId)
 
 	result := codes[0]
 	if !isSuccessCode(result) {
-		respMap, err := convertRespToMap(respStr)
+		respMap, err := convertRespToMap(ctx, respStr)
 		if err != nil {
-			logger.Logging(logger.ERROR, err.Error())
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
 			return results.ERROR, nil, err
 		}
 		return result, respMap, err
@@ -483,7 +1173,7 @@ func (AgentController) DeleteApp(agentId string, appId string) (int, map[string]
 	// if response code represents success, delete the appId from db.
 	err = db.DeleteAppFromAgent(agentId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -494,14 +1184,14 @@ func (AgentController) DeleteApp(agentId string, appId string) (int, map[string]
 // specified by appId parameter.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) UpdateApp(agentId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) UpdateApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -509,18 +1199,18 @@ func (AgentController) UpdateApp(agentId string, appId string) (int, map[string]
 	// Get agent including app specified by appId parameter.
 	agent, err := db.GetAgentByAppID(agentId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Request checking and updating all of images which is included target.
 	address := getAgentAddress(agent)
-	codes, respStr := httpMessenger.UpdateApp(address, appId)
+	codes, respStr := httpMessenger.UpdateApp(ctx, address, appId)
 
 	result := codes[0]
-	respMap, err := convertRespToMap(respStr)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -530,14 +1220,14 @@ func (AgentController) UpdateApp(agentId string, appId string) (int, map[string]
 // StartApp request to start an application specified by appId parameter.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) StartApp(agentId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) StartApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -545,18 +1235,18 @@ func (AgentController) StartApp(agentId string, appId string) (int, map[string]i
 	// Get agent including app specified by appId parameter.
 	agent, err := db.GetAgentByAppID(agentId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Request start target application.
 	address := getAgentAddress(agent)
-	codes, respStr := httpMessenger.StartApp(address, appId)
+	codes, respStr := httpMessenger.StartApp(ctx, address, appId)
 
 	result := codes[0]
-	respMap, err := convertRespToMap(respStr)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -566,14 +1256,14 @@ func (AgentController) StartApp(agentId string, appId string) (int, map[string]i
 // StopApp request to stop an application specified by appId parameter.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (AgentController) StopApp(agentId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (AgentController) StopApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -581,18 +1271,18 @@ func (AgentController) StopApp(agentId string, appId string) (int, map[string]in
 	// Get agent including app specified by appId parameter.
 	agent, err := db.GetAgentByAppID(agentId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	// Request stop target application.
 	address := getAgentAddress(agent)
-	codes, respStr := httpMessenger.StopApp(address, appId)
+	codes, respStr := httpMessenger.StopApp(ctx, address, appId)
 
 	result := codes[0]
-	respMap, err := convertRespToMap(respStr)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -606,11 +1296,29 @@ func convertJsonToMap(jsonStr string) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 	err := json.Unmarshal([]byte(jsonStr), &result)
 	if err != nil {
-		return nil, errors.InvalidJSON{"Unmarshalling Failed"}
+		return nil, errors.InvalidJSON{Message: "Unmarshalling Failed"}
 	}
 	return result, err
 }
 
+// renderDescriptor renders variables in the descriptor for the agent
+// with variables given for the agent in the body of a request.
+// The rendered descriptor is validated again, since values of variables may make it invalid.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func renderDescriptor(agent map[string]interface{}, descriptor string, body string) (string, error) {
+	variables, err := compose.Variables(body)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := compose.Render(descriptor, agent, variables)
+	if err != nil || rendered == descriptor {
+		return rendered, err
+	}
+	return rendered, compose.Validate(rendered, config.Get().Agent.ComposeVersions)
+}
+
 // getAgentAddress returns an address as an array.
 func getAgentAddress(agent map[string]interface{}) []map[string]interface{} {
 	result := make([]map[string]interface{}, 1)
@@ -624,15 +1332,285 @@ func getAgentAddress(agent map[string]interface{}) []map[string]interface{} {
 // convertRespToMap converts a response in the form of JSON data into a map.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func convertRespToMap(respStr []string) (map[string]interface{}, error) {
+func convertRespToMap(ctx context.Context, respStr []string) (map[string]interface{}, error) {
 	resp, err := convertJsonToMap(respStr[0])
 	if err != nil {
-		logger.Logging(logger.ERROR, "Failed to convert response from string to map")
-		return nil, errors.InternalServerError{"Json Converting Failed"}
+		logger.LoggingContext(ctx, logger.ERROR, "Failed to convert response from string to map")
+		return nil, errors.InternalServerError{Message: "Json Converting Failed"}
 	}
 	return resp, err
 }
 
+// parseMetadata returns the fields of metadata included in the body.
+// Fields which are not known are ignored, so that newer agents can report more.
+// If the type of a known field is not valid, an InvalidParam error will be returned.
+func parseMetadata(bodyMap map[string]interface{}) (map[string]interface{}, error) {
+	metadata := make(map[string]interface{})
+
+	value, exists := bodyMap[METADATA]
+	if !exists {
+		return metadata, nil
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.InvalidParam{Message: "metadata field should be an object"}
+	}
+
+	for _, key := range []string{HOSTNAME, OS, ARCH, KERNEL, DOCKER_VERSION, SDA_VERSION} {
+		if value, exists := fields[key]; exists {
+			text, ok := value.(string)
+			if !ok {
+				return nil, errors.InvalidParam{Message: "metadata." + key + " field should be a string"}
+			}
+			metadata[key] = text
+		}
+	}
+
+	if value, exists := fields[FREE_CPU]; exists {
+		percentage, ok := value.(float64)
+		if !ok || percentage < 0 || percentage > 100 {
+			return nil, errors.InvalidParam{Message: "metadata." + FREE_CPU + " field should be a percentage"}
+		}
+		metadata[FREE_CPU] = percentage
+	}
+
+	for _, key := range []string{FREE_MEMORY, FREE_DISK} {
+		if value, exists := fields[key]; exists {
+			bytes, ok := value.(float64)
+			if !ok || bytes < 0 || bytes != float64(int64(bytes)) {
+				return nil, errors.InvalidParam{Message: "metadata." + key + " field should be a number of bytes"}
+			}
+			metadata[key] = int64(bytes)
+		}
+	}
+	return metadata, nil
+}
+
+// parseLabels returns the labels included in the body.
+// If the labels are not an object of strings or invalid, an InvalidParam error will be returned.
+func parseLabels(bodyMap map[string]interface{}) (map[string]string, error) {
+	result := make(map[string]string)
+
+	value, exists := bodyMap[LABELS]
+	if !exists {
+		return result, nil
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.InvalidParam{Message: "labels field should be an object of strings"}
+	}
+
+	for key, value := range fields {
+		text, ok := value.(string)
+		if !ok {
+			return nil, errors.InvalidParam{Message: "labels field should be an object of strings"}
+		}
+		result[key] = text
+	}
+
+	if err := labels.Validate(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseInterval returns the interval of healthcheck messages in the body in seconds.
+// The interval is given in minutes unless the unit field of the body is 'sec'.
+// If the interval is not a string of a non-negative number or the unit is unknown,
+// an InvalidParam error is returned.
+func parseInterval(bodyMap map[string]interface{}) (int, error) {
+	value, _ := bodyMap[INTERVAL].(string)
+	interval, err := strconv.Atoi(value)
+	if err != nil || interval < 0 {
+		return 0, errors.InvalidParam{Message: "interval field should be a string of a non-negative number"}
+	}
+
+	unit := UNIT_MINUTE
+	if value, exists := bodyMap[UNIT]; exists {
+		unit, _ = value.(string)
+	}
+	switch unit {
+	case UNIT_MINUTE:
+		return interval * int(time.Minute/time.Second), nil
+	case UNIT_SECOND:
+		return interval, nil
+	default:
+		return 0, errors.InvalidParam{Message: "unit field should be one of " + UNIT_MINUTE + " and " + UNIT_SECOND}
+	}
+}
+
+// parsePort returns the port declared in the body, or an empty string if it is not given.
+// If the port is not a string of a number between 1 and 65535, an InvalidParam error is returned.
+func parsePort(bodyMap map[string]interface{}) (string, error) {
+	value, exists := bodyMap[PORT]
+	if !exists {
+		return "", nil
+	}
+	port, ok := value.(string)
+	if !ok {
+		return "", errors.InvalidParam{Message: "port field should be a string"}
+	}
+	number, err := strconv.Atoi(port)
+	if err != nil || number < 1 || number > 65535 || strconv.Itoa(number) != port {
+		return "", errors.InvalidParam{Message: "port field should be a number between 1 and 65535"}
+	}
+	return port, nil
+}
+
+// parseDeviceId returns the device id in the body, or an empty string if it is not given.
+func parseDeviceId(bodyMap map[string]interface{}) (string, error) {
+	value, exists := bodyMap[DEVICE_ID]
+	if !exists {
+		return "", nil
+	}
+	deviceId, ok := value.(string)
+	if !ok || deviceId == "" {
+		return "", errors.InvalidParam{Message: "deviceid field should be a non-empty string"}
+	}
+	return deviceId, nil
+}
+
+// parseSecret returns the secret in the body, or an empty string if it is not given.
+func parseSecret(bodyMap map[string]interface{}) (string, error) {
+	value, exists := bodyMap[SECRET]
+	if !exists {
+		return "", nil
+	}
+	secret, ok := value.(string)
+	if !ok || secret == "" {
+		return "", errors.InvalidParam{Message: "secret field should be a non-empty string"}
+	}
+	return secret, nil
+}
+
+// parseHealthCheck returns the health check mode in the body, or an empty string if it is not given.
+func parseHealthCheck(bodyMap map[string]interface{}) (string, error) {
+	value, exists := bodyMap[HEALTH_CHECK]
+	if !exists {
+		return "", nil
+	}
+	mode, ok := value.(string)
+	if !ok || !config.IsHealthMode(mode) {
+		return "", errors.InvalidParam{Message: "healthcheck field should be one of push, pull and both"}
+	}
+	return mode, nil
+}
+
+// healthCheckMode returns the health check mode of the agent,
+// or the configured mode if the agent does not have its own.
+func healthCheckMode(agent map[string]interface{}) string {
+	if mode, _ := agent[HEALTH_CHECK].(string); mode != "" {
+		return mode
+	}
+	return config.Get().Health.Mode
+}
+
+// findRegisteredAgent returns the agent registered with the given device id, and true.
+// If there is no such agent, the agent registered with the given ip is returned with false
+// unless it belongs to another device.
+// If there is no agent, NotFound error will be returned.
+// otherwise, an appropriate error will be returned.
+func findRegisteredAgent(dbManager db.DBManager, deviceId string, ip string) (map[string]interface{}, bool, error) {
+	if deviceId != "" {
+		agent, err := dbManager.GetAgentByDeviceID(deviceId)
+		if err == nil {
+			return agent, true, nil
+		}
+		if !errors.Is(err, errors.NotFound{}) {
+			return nil, false, err
+		}
+	}
+
+	agent, err := dbManager.GetAgentByIP(ip)
+	if err != nil {
+		return nil, false, err
+	}
+	if registered, _ := agent[DEVICE_ID].(string); registered != "" && registered != deviceId {
+		return nil, false, errors.NotFound{Message: "agent with ip " + ip + " belongs to another device"}
+	}
+	return agent, false, nil
+}
+
+// verifySecret checks whether the caller is the agent with the secret issued to the agent,
+// and returns the hash of the secret.
+// An agent registered before secrets were issued has no secret, and it is accepted without one
+// only if the given ip and port do not change its address. In that case, the hash is empty.
+// If the caller is not the agent, Unauthorized error will be returned.
+func verifySecret(dbManager db.DBManager, agent map[string]interface{}, secret string, ip string, port string) (string, error) {
+	agentId := agent[ID].(string)
+	hash, err := dbManager.GetAgentSecret(agentId)
+	if err != nil {
+		return "", err
+	}
+
+	if hash == "" {
+		if !isAddressChanged(agent, ip, port) {
+			return "", nil
+		}
+	} else if secret != "" && subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(hash)) == 1 {
+		return hash, nil
+	}
+	return "", errors.Unauthorized{Message: "secret issued to the agent is required", Kind: errors.AGENT, ID: agentId}
+}
+
+// issueSecret issues a new secret to the agent and stores the hash of it only.
+// If successful, this function returns the secret and an error as nil.
+// otherwise, an appropriate error will be returned.
+func issueSecret(dbManager db.DBManager, agentId string) (string, error) {
+	random := make([]byte, SECRET_BYTES)
+	if _, err := io.ReadFull(randReader, random); err != nil {
+		return "", errors.InternalServerError{Cause: err}
+	}
+
+	secret := hex.EncodeToString(random)
+	err := dbManager.UpdateAgentSecret(agentId, hashSecret(secret))
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// hashSecret returns the hex encoded SHA-256 hash of the secret.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// isAddressChanged returns true if the given ip or port differs from the stored address of the agent.
+// An empty ip or port keeps the stored one.
+func isAddressChanged(agent map[string]interface{}, ip string, port string) bool {
+	host, _ := agent[HOST].(string)
+	oldPort, _ := agent[PORT].(string)
+	return (ip != "" && ip != host) || (port != "" && port != oldPort)
+}
+
+// updateAgentAddress stores the given ip and port as the address of the agent
+// if they differ from the stored address. An empty ip or port keeps the stored one.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func updateAgentAddress(ctx context.Context, dbManager db.DBManager, agent map[string]interface{}, ip string, port string) error {
+	if !isAddressChanged(agent, ip, port) {
+		return nil
+	}
+
+	host, _ := agent[HOST].(string)
+	oldPort, _ := agent[PORT].(string)
+	if ip == "" {
+		ip = host
+	}
+	if port == "" {
+		port = oldPort
+	}
+
+	agentId := agent[ID].(string)
+	logger.LoggingContext(ctx, logger.INFO, "address of agent is changed:", agentId, host+":"+oldPort, "->", ip+":"+port)
+	err := dbManager.UpdateAgentAddress(agentId, ip, port, now().Unix())
+	if err != nil {
+		return err
+	}
+	return dbManager.AddEvent(agentId, event.ADDRESS_CHANGED, now().Unix(), host+":"+oldPort+" -> "+ip+":"+port)
+}
+
 // isSuccessCode returns true in case of success and false otherwise.
 func isSuccessCode(code int) bool {
 	if code >= 200 && code <= 299 {
@@ -640,3 +1618,37 @@ func isSuccessCode(code int) bool {
 	}
 	return false
 }
+
+// healthCheckTimeout returns the time to wait for the next ping of an agent with the interval.
+// The configured network latency is allowed in addition to the interval.
+func healthCheckTimeout(interval int) time.Duration {
+	latency := time.Duration(config.Get().Health.MaxNetworkLatency) * time.Second
+	return time.Duration(interval)*time.Second + latency
+}
+
+// disconnectAgent changes the status of the agent to disconnected.
+// It is called by the monitor when the agent does not send a ping in interval time.
+func disconnectAgent(agentId string) {
+	logger.Logging(logger.ERROR, "ping request is not received in interval time:", agentId)
+
+	// Connect to the database.
+	// The deadline outlives the ping request, so the request id is not carried.
+	db, err := dbConnector.Connect(context.Background())
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	defer db.Close()
+
+	// Status is updated with 'disconnected'.
+	err = db.UpdateAgentStatus(agentId, STATUS_DISCONNECTED)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	err = db.AddEvent(agentId, event.DISCONNECTED, now().Unix(), "")
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+}
@@ -17,21 +17,30 @@
 package agent
 
 import (
+	"bytes"
+	"commons/config"
 	"commons/errors"
 	"commons/results"
+	"context"
+	"crypto/rand"
 	dbmocks "db/mocks"
 	"github.com/golang/mock/gomock"
 	msgmocks "messenger/mocks"
+	"net/url"
 	"reflect"
 	"testing"
+	"time"
 )
 
 const (
-	status  = "connected"
-	appId   = "000000000000000000000000"
-	agentId = "000000000000000000000001"
-	host    = "127.0.0.1"
-	port    = "48098"
+	status           = "connected"
+	appId            = "000000000000000000000000"
+	agentId          = "000000000000000000000001"
+	otherAgentId     = "000000000000000000000002"
+	neverSeenAgentId = "000000000000000000000003"
+	groupId          = "000000000000000000000004"
+	host             = "127.0.0.1"
+	port             = "48098"
 )
 
 var (
@@ -46,7 +55,12 @@ var (
 			"host": host,
 			"port": port,
 		}}
-	body             = `{"description":"description"}`
+	groups = []map[string]interface{}{
+		map[string]interface{}{
+			"id":      groupId,
+			"members": []string{agentId},
+		}}
+	body             = "services:\n  monitor:\n    image: monitor:1.4.0\n"
 	respCode         = []int{results.OK}
 	errorRespCode    = []int{results.ERROR}
 	respStr          = []string{`{"response":"response"}`}
@@ -54,6 +68,8 @@ var (
 	notFoundError    = errors.NotFound{}
 	connectionError  = errors.DBConnectionError{}
 	invalidJsonError = errors.InvalidJSON{}
+	agentSecret      = "agent-secret"
+	agentSecretHash  = hashSecret(agentSecret)
 )
 
 var controller AgentInterface
@@ -66,24 +82,31 @@ func TestCalledAddAgentWithValidBody_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	randReader = bytes.NewReader(make([]byte, SECRET_BYTES))
+	defer func() { randReader = rand.Reader }()
+
 	body := `{"ip":"127.0.0.1"}`
+	secret := "0000000000000000000000000000000000000000000000000000000000000000"
 	expectedRes := map[string]interface{}{
-		"id": "000000000000000000000001",
+		"id":     "000000000000000000000001",
+		"secret": secret,
 	}
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().AddAgent(host, port, status).Return(agent, nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, hashSecret(secret)).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, res, err := controller.AddAgent(body)
+	code, res, err := controller.AddAgent(context.Background(), body)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -98,249 +121,548 @@ func TestCalledAddAgentWithValidBody_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledAddAgentWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledAddAgentWithMetadata_ExpectMetadataUpdated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	body := `{"ip":"127.0.0.1","metadata":{"hostname":"edge-01","arch":"arm64","freecpu":87.5,"freememory":1024,"unknown":true}}`
+	metadata := map[string]interface{}{
+		"hostname":   "edge-01",
+		"arch":       "arm64",
+		"freecpu":    87.5,
+		"freememory": int64(1024),
+	}
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().AddAgent(host, port, status).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentMetadata(agentId, metadata).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, gomock.Any()).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	body := `{"ip":"127.0.0.1"}`
-	code, _, err := controller.AddAgent(body)
+	code, res, err := controller.AddAgent(context.Background(), body)
 
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
-	case errors.DBConnectionError:
+	if res["id"] != agentId {
+		t.Errorf("Expected id: %s, actual id: %v", agentId, res["id"])
 	}
 }
 
-func TestCalledAddAgentWithInValidJsonFormatBody_ExpectSuccess(t *testing.T) {
+func TestCalledAddAgentWithMetadataWhenAgentExists_ExpectMetadataUpdated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	invalidBody := `{"ip"}`
+	body := `{"ip":"127.0.0.1","metadata":{"sdaversion":"1.1.0"}}`
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return("", nil),
+		dbManagerMockObj.EXPECT().UpdateAgentMetadata(agentId, map[string]interface{}{"sdaversion": "1.1.0"}).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, gomock.Any()).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.AddAgent(invalidBody)
+	code, _, err := controller.AddAgent(context.Background(), body)
 
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", "nil")
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
+}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", err.Error())
-	case errors.InvalidJSON:
+func TestCalledAddAgentWithPortAndDeviceID_ExpectNewAgentAdded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := `{"ip":"127.0.0.1","port":"58000","deviceid":"device-01"}`
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByDeviceID("device-01").Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().AddAgent(host, "58000", status).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentDeviceID(agentId, "device-01").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, gomock.Any()).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.AddAgent(context.Background(), body)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if res["id"] != agentId {
+		t.Errorf("Expected id: %s, actual id: %v", agentId, res["id"])
 	}
 }
 
-func TestCalledAddAgentWithInvalidBodyNotIncludingIDField_ExpectSuccess(t *testing.T) {
+func TestCalledAddAgentWhenDeviceMovedToNewAddress_ExpectAddressUpdated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	invalidBody := `{"key":"value"}`
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	body := `{"ip":"192.168.0.2","deviceid":"device-01","secret":"agent-secret"}`
+	registered := map[string]interface{}{
+		"id":       agentId,
+		"host":     host,
+		"port":     port,
+		"deviceid": "device-01",
+	}
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByDeviceID("device-01").Return(registered, nil),
+		dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return(agentSecretHash, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentAddress(agentId, "192.168.0.2", port, current.Unix()).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "address", gomock.Any(), gomock.Any()).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), "192.168.0.2").Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.AddAgent(invalidBody)
+	code, res, err := controller.AddAgent(context.Background(), body)
 
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", "nil")
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", err.Error())
-	case errors.InvalidJSON:
+	if res["id"] != agentId {
+		t.Errorf("Expected id: %s, actual id: %v", agentId, res["id"])
+	}
+
+	if _, exists := res["secret"]; exists {
+		t.Errorf("Unexpected secret: %v", res["secret"])
 	}
 }
 
-func TestCalledAddAgentWhenFailedToInsertNewAgentToDB_ExpectErrorReturn(t *testing.T) {
+func TestCalledAddAgentWithKnownDeviceIDWithoutItsSecret_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registered := map[string]interface{}{
+		"id":       agentId,
+		"host":     host,
+		"port":     port,
+		"deviceid": "device-01",
+	}
+
+	testList := []struct {
+		body string
+		hash string
+	}{
+		{`{"ip":"192.168.0.2","deviceid":"device-01"}`, agentSecretHash},
+		{`{"ip":"192.168.0.2","deviceid":"device-01","secret":"other-secret"}`, agentSecretHash},
+		{`{"ip":"127.0.0.1","deviceid":"device-01","secret":"other-secret"}`, agentSecretHash},
+		{`{"ip":"192.168.0.2","deviceid":"device-01","secret":"agent-secret"}`, ""},
+	}
+
+	for _, test := range testList {
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().GetAgentByDeviceID("device-01").Return(registered, nil),
+			dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return(test.hash, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, _, err := controller.AddAgent(context.Background(), test.body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d for %s", results.ERROR, code, test.body)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "Unauthorized", err, test.body)
+		case errors.Unauthorized:
+		}
+	}
+}
+
+func TestCalledAddAgentFromLeasedAddressWithoutItsSecret_ExpectNewAgentAdded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	leased := map[string]interface{}{
+		"id":   otherAgentId,
+		"host": host,
+		"port": port,
+	}
+
+	testList := []struct {
+		body     string
+		port     string
+		deviceId string
+		hash     string
+	}{
+		{`{"ip":"127.0.0.1"}`, port, "", agentSecretHash},
+		{`{"ip":"127.0.0.1","port":"58000","secret":"other-secret"}`, "58000", "", agentSecretHash},
+		{`{"ip":"127.0.0.1","deviceid":"device-02"}`, port, "device-02", agentSecretHash},
+		{`{"ip":"127.0.0.1","port":"58000"}`, "58000", "", ""},
+	}
+
+	for _, test := range testList {
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil)
+		if test.deviceId != "" {
+			dbManagerMockObj.EXPECT().GetAgentByDeviceID(test.deviceId).Return(nil, notFoundError)
+			dbManagerMockObj.EXPECT().UpdateAgentDeviceID(agentId, test.deviceId).Return(nil)
+		}
+		gomock.InOrder(
+			dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(leased, nil),
+			dbManagerMockObj.EXPECT().GetAgentSecret(otherAgentId).Return(test.hash, nil),
+			dbManagerMockObj.EXPECT().AddAgent(host, test.port, status).Return(agent, nil),
+			dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+			dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, gomock.Any()).Return(nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, res, err := controller.AddAgent(context.Background(), test.body)
+
+		if err != nil {
+			t.Errorf("Unexpected err: %s for %s", err.Error(), test.body)
+		}
+
+		if code != results.OK {
+			t.Errorf("Expected code: %d, actual code: %d for %s", results.OK, code, test.body)
+		}
+
+		if res["id"] != agentId || res["secret"] == nil {
+			t.Errorf("Expected id: %s with a secret, actual res: %v for %s", agentId, res, test.body)
+		}
+	}
+}
+
+func TestCalledAddAgentFromKnownAddressWithItsSecret_ExpectAgentKept(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	body := `{"ip":"127.0.0.1","port":"58000","secret":"agent-secret"}`
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(nil, notFoundError),
-		dbManagerMockObj.EXPECT().AddAgent(host, port, status).Return(nil, notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return(agentSecretHash, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentAddress(agentId, host, "58000", gomock.Any()).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "address", gomock.Any(), gomock.Any()).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	body := `{"ip":"127.0.0.1"}`
-	code, _, err := controller.AddAgent(body)
+	code, res, err := controller.AddAgent(context.Background(), body)
 
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+	expectedRes := map[string]interface{}{"id": agentId}
+	if !reflect.DeepEqual(res, expectedRes) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
 	}
 }
 
-func TestCalledPingAgentWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledAddAgentWhenDBFailedToFindDeviceID_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	body := `{"ip":"127.0.0.1","deviceid":"device-01"}`
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByDeviceID("device-01").Return(nil, connectionError),
+		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, err := controller.PingAgent(agentId, host, "")
+	code, _, err := controller.AddAgent(context.Background(), body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
-	}
-
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
+		t.Errorf("Expected err: %s, actual err: %v", "DBConnectionError", err)
 	case errors.DBConnectionError:
 	}
 }
 
-func TestCalledPingAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+func TestCalledAddAgentWithInvalidSecret_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testList := []string{
+		`{"ip":"127.0.0.1","secret":""}`,
+		`{"ip":"127.0.0.1","secret":1}`,
+	}
+
+	for _, body := range testList {
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, _, err := controller.AddAgent(context.Background(), body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, body)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestCalledAddAgentWhenAddressBelongsToAnotherDevice_ExpectNewAgentAdded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	body := `{"ip":"127.0.0.1","deviceid":"device-02"}`
+	registered := map[string]interface{}{
+		"id":       otherAgentId,
+		"host":     host,
+		"port":     port,
+		"deviceid": "device-01",
+	}
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByDeviceID("device-02").Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(registered, nil),
+		dbManagerMockObj.EXPECT().AddAgent(host, port, status).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentDeviceID(agentId, "device-02").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, gomock.Any()).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, err := controller.PingAgent(agentId, host, "")
+	_, res, err := controller.AddAgent(context.Background(), body)
 
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	if res["id"] != agentId {
+		t.Errorf("Expected id: %s, actual id: %v", agentId, res["id"])
 	}
+}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+func TestCalledAddAgentWithInvalidPortOrDeviceID_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testList := []string{
+		`{"ip":"127.0.0.1","port":48098}`,
+		`{"ip":"127.0.0.1","port":"0"}`,
+		`{"ip":"127.0.0.1","port":"65536"}`,
+		`{"ip":"127.0.0.1","port":"080"}`,
+		`{"ip":"127.0.0.1","deviceid":""}`,
+		`{"ip":"127.0.0.1","deviceid":1}`,
+	}
+
+	for _, body := range testList {
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, _, err := controller.AddAgent(context.Background(), body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, body)
+		case errors.InvalidParam:
+		}
 	}
 }
 
-func TestCalledPingAgentWithInvalidBody_ExpectErrorReturn(t *testing.T) {
+func TestCalledAddAgentWithInvalidMetadata_ExpectErrorReturn(t *testing.T) {
+	testList := []string{
+		`{"ip":"127.0.0.1","metadata":"edge-01"}`,
+		`{"ip":"127.0.0.1","metadata":{"hostname":1}}`,
+		`{"ip":"127.0.0.1","metadata":{"freecpu":"87.5"}}`,
+		`{"ip":"127.0.0.1","metadata":{"freecpu":101}}`,
+		`{"ip":"127.0.0.1","metadata":{"freememory":-1}}`,
+		`{"ip":"127.0.0.1","metadata":{"freedisk":1.5}}`,
+	}
+
+	for _, body := range testList {
+		ctrl := gomock.NewController(t)
+
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, _, err := controller.AddAgent(context.Background(), body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, body)
+		case errors.InvalidParam:
+		}
+		ctrl.Finish()
+	}
+}
+
+func TestCalledAddAgentWithLabels_ExpectLabelsUpdated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	body := `{"ip":"127.0.0.1","labels":{"site":"plant3","arch":"arm64"}}`
+	labels := map[string]string{"site": "plant3", "arch": "arm64"}
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().AddAgent(host, port, status).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLabels(agentId, labels, nil).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, gomock.Any()).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, err := controller.PingAgent(agentId, host, "")
+	code, res, err := controller.AddAgent(context.Background(), body)
 
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", "nil")
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", err.Error())
-	case errors.InvalidJSON:
+	if res["id"] != agentId {
+		t.Errorf("Expected id: %s, actual id: %v", agentId, res["id"])
 	}
 }
 
-func TestCalledDeleteAgent_ExpectSuccess(t *testing.T) {
+func TestCalledAddAgentWithHealthCheck_ExpectHealthCheckUpdated(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	body := `{"ip":"127.0.0.1","healthcheck":"pull"}`
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
-	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
-		msgMockObj.EXPECT().Unregister(address).Return(respCode, respStr),
-		dbManagerMockObj.EXPECT().DeleteAgent(agentId).Return(nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().AddAgent(host, port, status).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentHealthCheck(agentId, "pull").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, gomock.Any()).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
-	httpMessenger = msgMockObj
 
-	code, err := controller.DeleteAgent(agentId)
+	code, _, err := controller.AddAgent(context.Background(), body)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -351,36 +673,1429 @@ func TestCalledDeleteAgent_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledDeleteAgentWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledAddAgentWithInvalidLabels_ExpectErrorReturn(t *testing.T) {
+	testList := []string{
+		`{"ip":"127.0.0.1","labels":"site=plant3"}`,
+		`{"ip":"127.0.0.1","labels":{"site":3}}`,
+		`{"ip":"127.0.0.1","labels":{"site.name":"plant3"}}`,
+		`{"ip":"127.0.0.1","labels":{"site":"plant 3"}}`,
+	}
+
+	for _, body := range testList {
+		ctrl := gomock.NewController(t)
+
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, _, err := controller.AddAgent(context.Background(), body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, body)
+		case errors.InvalidParam:
+		}
+		ctrl.Finish()
+	}
+}
+
+func TestCalledAddAgentWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	body := `{"ip":"127.0.0.1"}`
+	code, _, err := controller.AddAgent(context.Background(), body)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledAddAgentWithInValidJsonFormatBody_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	invalidBody := `{"ip"}`
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, err := controller.DeleteAgent(agentId)
+	code, _, err := controller.AddAgent(context.Background(), invalidBody)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", err.Error())
+	case errors.InvalidJSON:
+	}
+}
+
+func TestCalledAddAgentWithInvalidBodyNotIncludingIDField_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invalidBody := `{"key":"value"}`
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.AddAgent(context.Background(), invalidBody)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", err.Error())
+	case errors.InvalidJSON:
+	}
+}
+
+func TestCalledAddAgentWhenFailedToInsertNewAgentToDB_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByIP(host).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().AddAgent(host, port, status).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	body := `{"ip":"127.0.0.1"}`
+	code, _, err := controller.AddAgent(context.Background(), body)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledPingAgentWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, "")
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledPingAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, "")
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledPingAgentWithInvalidBody_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, "")
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "InvalidJSON", err.Error())
+	case errors.InvalidJSON:
+	}
+}
+
+func TestCalledPingAgentWithInvalidInterval_ExpectErrorReturn(t *testing.T) {
+	for _, body := range []string{`{}`, `{"interval":10}`, `{"interval":"ten"}`, `{"interval":"-1"}`, `{"interval":"10","unit":"hour"}`} {
+		ctrl := gomock.NewController(t)
+
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, err := controller.PingAgent(context.Background(), agentId, host, body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+		case errors.InvalidParam:
+		}
+		ctrl.Finish()
+	}
+}
+
+func TestCalledPingAgentWhenDBFailedToUpdateLastSeen_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, current.Unix(), 60).Return(notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, `{"interval":"1"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledPingAgentWithMetadataWhenDBFailedToUpdate_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentMetadata(agentId, map[string]interface{}{"freedisk": int64(4096)}).Return(notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, `{"interval":"1","metadata":{"freedisk":4096}}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledPingAgentFromNewAddress_ExpectAddressUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return(agentSecretHash, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentAddress(agentId, "192.168.0.2", "58000", current.Unix()).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "address", gomock.Any(), gomock.Any()).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, current.Unix(), 60).Return(notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _ := controller.PingAgent(context.Background(), agentId, "192.168.0.2", `{"interval":"1","port":"58000","secret":"agent-secret"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+}
+
+func TestCalledPingAgentWhenDBFailedToUpdateAddress_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return(agentSecretHash, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentAddress(agentId, "192.168.0.2", port, gomock.Any()).Return(notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.PingAgent(context.Background(), agentId, "192.168.0.2", `{"interval":"1","secret":"agent-secret"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledPingAgentFromNewAddressWithoutItsSecret_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testList := []struct {
+		ip   string
+		body string
+		hash string
+	}{
+		{"192.168.0.2", `{"interval":"1"}`, agentSecretHash},
+		{"192.168.0.2", `{"interval":"1","secret":"other-secret"}`, agentSecretHash},
+		{host, `{"interval":"1","port":"58000","secret":"other-secret"}`, agentSecretHash},
+		{"192.168.0.2", `{"interval":"1","secret":"agent-secret"}`, ""},
+	}
+
+	for _, test := range testList {
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+			dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return(test.hash, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, err := controller.PingAgent(context.Background(), agentId, test.ip, test.body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d for %s", results.ERROR, code, test.body)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "Unauthorized", err, test.body)
+		case errors.Unauthorized:
+		}
+	}
+}
+
+func TestCalledPingAgentWithUnit_ExpectIntervalStoredInSeconds(t *testing.T) {
+	testList := map[string]struct {
+		body     string
+		interval int
+	}{
+		"DefaultUnit": {`{"interval":"2"}`, 120},
+		"Minutes":     {`{"interval":"2","unit":"min"}`, 120},
+		"Seconds":     {`{"interval":"10","unit":"sec"}`, 10},
+	}
+
+	for name, test := range testList {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+			dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+			gomock.InOrder(
+				dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+				dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+				dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, gomock.Any(), test.interval).Return(nil),
+				dbManagerMockObj.EXPECT().Close(),
+			)
+			// pass mockObj to a real object.
+			dbConnector = dbConnectionMockObj
+
+			monitor.Beat(agentId, time.Hour)
+			defer monitor.Remove(agentId)
+
+			code, err := controller.PingAgent(context.Background(), agentId, host, test.body)
+
+			if err != nil {
+				t.Errorf("Unexpected err: %s", err.Error())
+			}
+
+			if code != results.OK {
+				t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+			}
+		})
+	}
+}
+
+func TestCalledPingAgentAfterTimeout_ExpectConnectedEventRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, current.Unix(), 60).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentStatus(agentId, "connected").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "connected", current.Unix(), "").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	// The deadline of the agent has passed.
+	monitor.Watch(agentId, time.Now().Add(-time.Hour))
+	defer monitor.Remove(agentId)
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, `{"interval":"1"}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+}
+
+func TestCalledPingAgentInPullMode_ExpectLastSeenNotUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	probed := map[string]interface{}{"id": agentId, "host": host, "port": port, "healthcheck": "pull"}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(probed, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, `{"interval":"1"}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+}
+
+func TestCalledPingAgentInBothMode_ExpectLastSeenUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	probed := map[string]interface{}{"id": agentId, "host": host, "port": port, "healthcheck": "both"}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(probed, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, gomock.Any(), 60).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	// A probe has given a deadline later than the ping.
+	monitor.Beat(agentId, time.Hour)
+	defer monitor.Remove(agentId)
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, `{"interval":"1"}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+}
+
+func TestCalledDisconnectAgent_ExpectDisconnectedEventRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentStatus(agentId, "disconnected").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "disconnected", current.Unix(), "").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	disconnectAgent(agentId)
+}
+
+func TestCalledRestoreHealthCheckWithOverdueAgents_ExpectDisconnectedStatusUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lastSeen := time.Now().Add(-time.Hour).Unix()
+	agents := []map[string]interface{}{
+		{"id": agentId, "status": "connected", "lastseen": lastSeen, "interval": 1},
+		{"id": otherAgentId, "status": "disconnected", "lastseen": lastSeen, "interval": 1},
+		{"id": neverSeenAgentId, "status": "connected", "lastseen": int64(0), "interval": 0},
+	}
+	// The disconnection is recorded at the deadline of the overdue agent.
+	deadline := lastSeen + 1 + int64(config.Get().Health.MaxNetworkLatency)
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAllAgents().Return(agents, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentStatus(agentId, "disconnected").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "disconnected", deadline, "").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	defer func() {
+		monitor.Remove(agentId)
+		monitor.Remove(otherAgentId)
+		monitor.Remove(neverSeenAgentId)
+	}()
+
+	err := RestoreHealthCheck(context.Background())
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	// The next ping of an overdue agent is regarded as the one after interval time-out.
+	for _, id := range []string{agentId, otherAgentId} {
+		if !monitor.Beat(id, time.Hour) {
+			t.Errorf("Expected expired deadline of agent: %s", id)
+		}
+	}
+
+	if monitor.Beat(neverSeenAgentId, time.Hour) {
+		t.Errorf("Unexpected expired deadline of agent: %s", neverSeenAgentId)
+	}
+}
+
+func TestCalledRestoreHealthCheckWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	err := RestoreHealthCheck(context.Background())
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBConnectionError", err)
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledStopHealthCheckAfterPingAgent_ExpectTimerStoppedWithoutStatusUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, gomock.Any(), 60).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.PingAgent(context.Background(), agentId, host, `{"interval":"1"}`)
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err = StopHealthCheck(ctx)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteAgent_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		msgMockObj.EXPECT().Unregister(gomock.Any(), address).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().GetGroupsByQuery(map[string]string{"agent": agentId}, "", 0, "").Return(groups, "", nil),
+		dbManagerMockObj.EXPECT().LeaveGroup(groupId, agentId).Return(nil),
+		dbManagerMockObj.EXPECT().DeleteAgent(agentId).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "unregistered", gomock.Any(), "").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, err := controller.DeleteAgent(context.Background(), agentId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+}
+
+func TestCalledDeleteAgentWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.DeleteAgent(context.Background(), agentId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledDeleteAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.DeleteAgent(context.Background(), agentId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetAgent_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetAgent(context.Background(), agentId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(res, agent) {
+		t.Error()
+	}
+}
+
+func TestCalledGetAgentWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetAgent(context.Background(), agentId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledGetAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetAgent(context.Background(), agentId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetAgents_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agents := []map[string]interface{}{agent}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentsByQuery(map[string]string{}, "", 0, "").Return(agents, "", nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetAgents(context.Background(), nil)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(res["agents"].([]map[string]interface{}), agents) {
+		t.Error()
+	}
+}
+
+func TestCalledGetAgentsWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetAgents(context.Background(), nil)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledGetAgentsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentsByQuery(map[string]string{}, "", 0, "").Return(nil, "", notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetAgents(context.Background(), nil)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetAgentsWithQuery_ExpectFilteredPageReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agents := []map[string]interface{}{agent}
+	filter := map[string]string{"status": status, "host": "127.0.*"}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentsByQuery(filter, "-host", 1, "cursor").Return(agents, "next", nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	query, _ := url.ParseQuery("status=connected&host=127.0.*&sort=-host&limit=1&cursor=cursor")
+	code, res, err := controller.GetAgents(context.Background(), query)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(res["agents"].([]map[string]interface{}), agents) || res["next"] != "next" {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledGetEvents_ExpectEventsOfAgentReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query, _ := url.ParseQuery("type=connected,disconnected&from=100&sort=-id&limit=1")
+	filter := map[string]string{"agent": agentId, "type": "connected,disconnected", "from": "100"}
+	events := []map[string]interface{}{{"id": appId, "agentid": agentId, "type": "connected", "time": int64(200)}}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(filter, "-id", 1, "").Return(events, "next", nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetEvents(context.Background(), agentId, query)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedRes := map[string]interface{}{"events": events, "next": "next"}
+	if !reflect.DeepEqual(res, expectedRes) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledGetEventsWithInvalidQuery_ExpectErrorReturn(t *testing.T) {
+	query, _ := url.ParseQuery("status=connected")
+
+	code, _, err := controller.GetEvents(context.Background(), agentId, query)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledGetAvailability_ExpectSummaryReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	query, _ := url.ParseQuery("from=1000&to=2000")
+	disconnected := []map[string]interface{}{{"agentid": agentId, "type": "disconnected", "time": int64(1500)}}
+	registered := []map[string]interface{}{{"agentid": agentId, "type": "registered", "time": int64(500)}}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(gomock.Any(), "-id", 1, "").Return(registered, "", nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(gomock.Any(), "", 0, "").Return(disconnected, "", nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetAvailability(context.Background(), agentId, query)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedRes := map[string]interface{}{
+		"id": agentId, "from": int64(1000), "to": int64(2000),
+		"observed": int64(1000), "connected": int64(500), "uptime": 50.0, "disconnects": 1, "mtbd": int64(500),
+	}
+	if !reflect.DeepEqual(res, expectedRes) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledGetAvailabilityWithInvalidPeriod_ExpectErrorReturn(t *testing.T) {
+	query, _ := url.ParseQuery("from=2000&to=1000")
+
+	code, _, err := controller.GetAvailability(context.Background(), agentId, query)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
+func TestCalledGetAgentsWithSelector_ExpectLabelFilterUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agents := []map[string]interface{}{agent}
+	filter := map[string]string{"status": status, "label.site": "plant3", "label.arch": "arm64"}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentsByQuery(filter, "", 0, "").Return(agents, "", nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	query, _ := url.ParseQuery("status=connected&selector=site%3Dplant3,arch%3Darm64")
+	code, res, err := controller.GetAgents(context.Background(), query)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(res["agents"].([]map[string]interface{}), agents) {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledGetAgentsWithInvalidQuery_ExpectErrorReturn(t *testing.T) {
+	for _, rawQuery := range []string{"status=running", "port=8888", "limit=-1", "selector=site", "selector=site%3D1,site%3D2"} {
+		query, _ := url.ParseQuery(rawQuery)
+		code, _, err := controller.GetAgents(context.Background(), query)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestCalledUpdateLabels_ExpectMergedLabelsReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	labeledAgent := map[string]interface{}{
+		"id":     agentId,
+		"labels": map[string]string{"site": "plant2", "arch": "arm64", "rack": "7"},
+	}
+	body := `{"site":"plant3","rack":null,"zone":"north"}`
+	expectedLabels := map[string]string{"site": "plant3", "arch": "arm64", "zone": "north"}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(labeledAgent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLabels(agentId, map[string]string{"site": "plant3", "zone": "north"}, []string{"rack"}).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.UpdateLabels(context.Background(), agentId, body)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(res["labels"], expectedLabels) {
+		t.Errorf("Expected labels: %v, actual labels: %v", expectedLabels, res["labels"])
+	}
+}
+
+func TestCalledUpdateLabelsWithInvalidBody_ExpectErrorReturn(t *testing.T) {
+	testList := []string{
+		`{"site":3}`,
+		`{"site":["plant3"]}`,
+		`{"-site":"plant3"}`,
+		`{"site.name":null}`,
+		`{"site":"plant/3"}`,
+	}
+
+	for _, body := range testList {
+		code, _, err := controller.UpdateLabels(context.Background(), agentId, body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, body)
+		case errors.InvalidParam:
+		}
+	}
+}
+
+func TestCalledUpdateLabelsWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.UpdateLabels(context.Background(), agentId, `{"site":"plant3"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledUpdateHealthCheck_ExpectModeReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expectedRes := map[string]interface{}{
+		"healthcheck": "both",
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentHealthCheck(agentId, "both").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.UpdateHealthCheck(context.Background(), agentId, `{"healthcheck":"both"}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(res, expectedRes) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledUpdateHealthCheckWithInvalidBody_ExpectErrorReturn(t *testing.T) {
+	testList := []string{
+		`{"healthcheck":"poll"}`,
+		`{"healthcheck":1}`,
+	}
+
+	for _, body := range testList {
+		code, _, err := controller.UpdateHealthCheck(context.Background(), agentId, body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, body)
+		case errors.InvalidParam:
+		}
+	}
+
+	_, _, err := controller.UpdateHealthCheck(context.Background(), agentId, `{}`)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+	case errors.InvalidJSON:
+	}
+}
+
+func TestCalledResetSecretOfAgentWhichLostIt_ExpectAgentRegisteredWithNewSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	randReader = bytes.NewReader(make([]byte, SECRET_BYTES))
+	defer func() { randReader = rand.Reader }()
+
+	registered := map[string]interface{}{
+		"id":       agentId,
+		"host":     host,
+		"port":     port,
+		"deviceid": "device-01",
+	}
+	secret := "0000000000000000000000000000000000000000000000000000000000000000"
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(registered, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentSecret(agentId, hashSecret(secret)).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByDeviceID("device-01").Return(registered, nil),
+		dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return(hashSecret(secret), nil),
+		dbManagerMockObj.EXPECT().Close(),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByDeviceID("device-01").Return(registered, nil),
+		dbManagerMockObj.EXPECT().GetAgentSecret(agentId).Return(hashSecret(secret), nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "registered", gomock.Any(), host).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.ResetSecret(context.Background(), agentId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedRes := map[string]interface{}{"id": agentId, "secret": secret}
+	if !reflect.DeepEqual(res, expectedRes) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+
+	// The previous secret is revoked.
+	_, _, err = controller.AddAgent(context.Background(), `{"ip":"127.0.0.1","deviceid":"device-01","secret":"agent-secret"}`)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Unauthorized", err)
+	case errors.Unauthorized:
+	}
+
+	code, res, err = controller.AddAgent(context.Background(), `{"ip":"127.0.0.1","deviceid":"device-01","secret":"`+secret+`"}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
-	case errors.DBConnectionError:
+	expectedRes = map[string]interface{}{"id": agentId}
+	if !reflect.DeepEqual(res, expectedRes) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
 	}
 }
 
-func TestCalledDeleteAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+func TestCalledResetSecretWithNotExistAgent_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -388,46 +2103,52 @@ func TestCalledDeleteAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, err := controller.DeleteAgent(agentId)
+	code, _, err := controller.ResetSecret(context.Background(), agentId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
-
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
 	case errors.NotFound:
 	}
 }
 
-func TestCalledGetAgent_ExpectSuccess(t *testing.T) {
+func TestCalledDeployApp_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	respStr := []string{`{"id":"000000000000000000000000"}`}
+	expectedRes := map[string]interface{}{
+		"id": "000000000000000000000000",
+	}
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), address, body).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, res, err := controller.GetAgent(agentId)
+	code, res, err := controller.DeployApp(context.Background(), agentId, body)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -437,90 +2158,107 @@ func TestCalledGetAgent_ExpectSuccess(t *testing.T) {
 		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 
-	if !reflect.DeepEqual(res, agent) {
-		t.Error()
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
 	}
 }
 
-func TestCalledGetAgentWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWithVariables_ExpectRenderedDescriptorDeployed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	respStr := []string{`{"id":"000000000000000000000000"}`}
+	templated := `{"descriptor":"services:\n  monitor:\n    image: monitor:1.4.0\n    environment:\n` +
+		`    - MANAGER={{ agent.host }}:{{ agent.port }}\n    - STATION={{ station }}\n",` +
+		`"variables":{"000000000000000000000001":{"station":"S-1"}}}`
+	rendered := "services:\n  monitor:\n    image: monitor:1.4.0\n    environment:\n" +
+		"    - MANAGER=127.0.0.1:48098\n    - STATION=S-1\n"
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), address, rendered).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, rendered, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetAgent(agentId)
-
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
-	}
+	code, _, err := controller.DeployApp(context.Background(), agentId, templated)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
-	case errors.DBConnectionError:
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 }
 
-func TestCalledGetAgentWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWithUndefinedVariable_ExpectErrorReturnWithoutRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetAgent(agentId)
+	templated := "services:\n  monitor:\n    image: monitor:1.4.0\n    hostname: '{{ labels.station }}'\n"
+	code, _, err := controller.DeployApp(context.Background(), agentId, templated)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
-
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
 	}
 }
 
-func TestCalledGetAgents_ExpectSuccess(t *testing.T) {
+func TestCalledDeployAppWithCatalogReference_ExpectCatalogVersionRecorded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	agents := []map[string]interface{}{agent}
+	reference := `{"app":"line-monitor","version":"1.4.0"}`
+	version := map[string]interface{}{"name": "line-monitor", "version": "1.4.0", "descriptor": body, "checksum": "sum"}
+	respStr := []string{`{"id":"000000000000000000000000"}`}
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAllAgents().Return(agents, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("line-monitor", "1.4.0").Return(version, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), address, body).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, "line-monitor", "1.4.0").Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "line-monitor", "1.4.0", gomock.Any()).Return(1, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, res, err := controller.GetAgents()
+	code, _, err := controller.DeployApp(context.Background(), agentId, reference)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -529,109 +2267,136 @@ func TestCalledGetAgents_ExpectSuccess(t *testing.T) {
 	if code != results.OK {
 		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
-
-	if !reflect.DeepEqual(res["agents"].([]map[string]interface{}), agents) {
-		t.Error()
-	}
 }
 
-func TestCalledGetAgentsWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWithInvalidDescriptor_ExpectErrorReturnWithoutRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetAgents()
+	invalid := "services:\n  monitor:\n    ports:\n    - '80:80:80'\n"
+	code, _, err := controller.DeployApp(context.Background(), agentId, invalid)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
-	}
-
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
-	case errors.DBConnectionError:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
 	}
 }
 
-func TestCalledGetAgentsWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWithVariableRenderedInvalid_ExpectErrorReturnWithoutRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	templated := `{"descriptor":"services:\n  monitor:\n    image: monitor:1.4.0\n    ports:\n    - '{{ port }}'\n",` +
+		`"variables":{"000000000000000000000001":{"port":"80:80:80"}}}`
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAllAgents().Return(nil, notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetAgents()
+	code, _, err := controller.DeployApp(context.Background(), agentId, templated)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
-
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
 	}
 }
 
-func TestCalledDeployApp_ExpectSuccess(t *testing.T) {
+func TestCalledDeployAppWithNotAcceptedComposeVersion_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	respStr := []string{`{"id":"000000000000000000000000"}`}
-	expectedRes := map[string]interface{}{
-		"id": "000000000000000000000000",
-	}
+	old := config.Get()
+	cfg := old
+	cfg.Agent.ComposeVersions = []string{"3.7"}
+	config.Set(cfg)
+	defer config.Set(old)
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
-		msgMockObj.EXPECT().DeployApp(address, body).Return(respCode, respStr),
-		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.DeployApp(agentId, body)
+	code, _, err := controller.DeployApp(context.Background(), agentId, "version: '2.4'\n"+body)
 
-	if err != nil {
-		t.Errorf("Unexpected err: %s", err.Error())
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if code != results.OK {
-		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
 	}
+}
 
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+func TestCalledDeployAppWithUnknownCatalogVersion_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("line-monitor", "9.9.9").Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.DeployApp(context.Background(), agentId, `{"app":"line-monitor","version":"9.9.9"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
 	}
 }
 
@@ -642,12 +2407,12 @@ func TestCalledDeployAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.DeployApp(agentId, body)
+	code, _, err := controller.DeployApp(context.Background(), agentId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -673,7 +2438,7 @@ func TestCalledDeployAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -681,7 +2446,7 @@ func TestCalledDeployAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T)
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeployApp(agentId, body)
+	code, _, err := controller.DeployApp(context.Background(), agentId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -707,16 +2472,16 @@ func TestCalledDeployAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
-		msgMockObj.EXPECT().DeployApp(address, body).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), address, body).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeployApp(agentId, body)
+	code, _, err := controller.DeployApp(context.Background(), agentId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -744,9 +2509,9 @@ func TestCalledDeployAppWhenFailedToAddAppIdToDB_ExpectErrorReturn(t *testing.T)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
-		msgMockObj.EXPECT().DeployApp(address, body).Return(respCode, respStr),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), address, body).Return(respCode, respStr),
 		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -754,7 +2519,7 @@ func TestCalledDeployAppWhenFailedToAddAppIdToDB_ExpectErrorReturn(t *testing.T)
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeployApp(agentId, body)
+	code, _, err := controller.DeployApp(context.Background(), agentId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -785,16 +2550,16 @@ func TestCalledGetApps_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
-		msgMockObj.EXPECT().InfoApps(address).Return(respCode, respStr),
+		msgMockObj.EXPECT().InfoApps(gomock.Any(), address).Return(respCode, respStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.GetApps(agentId)
+	code, res, err := controller.GetApps(context.Background(), agentId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -816,12 +2581,12 @@ func TestCalledGetAppsWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetApps(agentId)
+	code, _, err := controller.GetApps(context.Background(), agentId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -847,16 +2612,16 @@ func TestCalledGetAppsWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *t
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agent, nil),
-		msgMockObj.EXPECT().InfoApps(address).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().InfoApps(gomock.Any(), address).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetApps(agentId)
+	code, _, err := controller.GetApps(context.Background(), agentId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -882,7 +2647,7 @@ func TestCalledGetAppsWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -890,7 +2655,7 @@ func TestCalledGetAppsWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetApps(agentId)
+	code, _, err := controller.GetApps(context.Background(), agentId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -921,16 +2686,16 @@ func TestCalledGetApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().InfoApp(address, appId).Return(respCode, respStr),
+		msgMockObj.EXPECT().InfoApp(gomock.Any(), address, appId).Return(respCode, respStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.GetApp(agentId, appId)
+	code, res, err := controller.GetApp(context.Background(), agentId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -952,12 +2717,12 @@ func TestCalledGetAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetApp(agentId, appId)
+	code, _, err := controller.GetApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -983,16 +2748,16 @@ func TestCalledGetAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *te
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().InfoApp(address, appId).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().InfoApp(gomock.Any(), address, appId).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetApp(agentId, appId)
+	code, _, err := controller.GetApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1018,7 +2783,7 @@ func TestCalledGetAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -1026,7 +2791,7 @@ func TestCalledGetAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetApp(agentId, appId)
+	code, _, err := controller.GetApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1052,16 +2817,17 @@ func TestCalledUpdateAppInfo_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().UpdateAppInfo(address, appId, body).Return(respCode, respStr),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), address, appId, body).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateAppInfo(agentId, appId, body)
+	code, _, err := controller.UpdateAppInfo(context.Background(), agentId, appId, body)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1072,6 +2838,37 @@ func TestCalledUpdateAppInfo_ExpectSuccess(t *testing.T) {
 	}
 }
 
+func TestCalledUpdateAppInfoWithInvalidDescriptor_ExpectErrorReturnWithoutRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	invalid := "services:\n  monitor:\n    ports:\n    - '80:80:80'\n"
+	code, _, err := controller.UpdateAppInfo(context.Background(), agentId, appId, invalid)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
 func TestCalledUpdateAppInfoWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1079,12 +2876,12 @@ func TestCalledUpdateAppInfoWhenDBConnectionFailed_ExpectErrorReturn(t *testing.
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.UpdateAppInfo(agentId, appId, body)
+	code, _, err := controller.UpdateAppInfo(context.Background(), agentId, appId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1110,7 +2907,7 @@ func TestCalledUpdateAppInfoWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testin
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -1118,7 +2915,7 @@ func TestCalledUpdateAppInfoWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testin
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateAppInfo(agentId, appId, body)
+	code, _, err := controller.UpdateAppInfo(context.Background(), agentId, appId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1144,16 +2941,16 @@ func TestCalledUpdateAppInfoWhenMessengerReturnsInvalidResponse_ExpectErrorRetur
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().UpdateAppInfo(address, appId, body).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), address, appId, body).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateAppInfo(agentId, appId, body)
+	code, _, err := controller.UpdateAppInfo(context.Background(), agentId, appId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1170,6 +2967,131 @@ func TestCalledUpdateAppInfoWhenMessengerReturnsInvalidResponse_ExpectErrorRetur
 	}
 }
 
+func TestCalledRollbackApp_ExpectPreviousRevisionApplied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revisions := []map[string]interface{}{
+		{"revision": 1, "descriptor": "services:", "checksum": "sum", "catalog": map[string]interface{}{"app": "line-monitor", "version": "1.3.0"}},
+		{"revision": 2, "descriptor": body, "checksum": "sum"},
+	}
+	expectedRes := map[string]interface{}{
+		"revision": 3,
+		"target":   1,
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(revisions, nil),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), address, appId, "services:").Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, "line-monitor", "1.3.0").Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, "services:", gomock.Any(), "line-monitor", "1.3.0", gomock.Any()).Return(3, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.RollbackApp(context.Background(), agentId, appId, "")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledRollbackAppWithoutPreviousRevision_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revisions := []map[string]interface{}{
+		{"revision": 1, "descriptor": body, "checksum": "sum"},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(revisions, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.RollbackApp(context.Background(), agentId, appId, "")
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if !errors.Is(err, errors.InvalidParam{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	}
+}
+
+func TestCalledRollbackAppWithInvalidRevision_ExpectErrorReturn(t *testing.T) {
+	code, _, err := controller.RollbackApp(context.Background(), agentId, appId, `{"revision":0}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if !errors.Is(err, errors.InvalidParam{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	}
+}
+
+func TestCalledRollbackAppWhenMessengerReturnsError_ExpectNothingRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revisions := []map[string]interface{}{
+		{"revision": 1, "descriptor": "services:", "checksum": "sum"},
+		{"revision": 2, "descriptor": body, "checksum": "sum"},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(revisions, nil),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), address, appId, "services:").Return(errorRespCode, respStr),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.RollbackApp(context.Background(), agentId, appId, `{"revision":1}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+}
+
 func TestCalledUpdateApp_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1179,16 +3101,16 @@ func TestCalledUpdateApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().UpdateApp(address, appId).Return(respCode, respStr),
+		msgMockObj.EXPECT().UpdateApp(gomock.Any(), address, appId).Return(respCode, respStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateApp(agentId, appId)
+	code, _, err := controller.UpdateApp(context.Background(), agentId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1206,12 +3128,12 @@ func TestCalledUpdateAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.UpdateApp(agentId, appId)
+	code, _, err := controller.UpdateApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1237,7 +3159,7 @@ func TestCalledUpdateAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -1245,7 +3167,7 @@ func TestCalledUpdateAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T)
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateApp(agentId, appId)
+	code, _, err := controller.UpdateApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1271,16 +3193,16 @@ func TestCalledUpdateAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().UpdateApp(address, appId).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().UpdateApp(gomock.Any(), address, appId).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateApp(agentId, appId)
+	code, _, err := controller.UpdateApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1306,16 +3228,16 @@ func TestCalledStartApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().StartApp(address, appId).Return(respCode, respStr),
+		msgMockObj.EXPECT().StartApp(gomock.Any(), address, appId).Return(respCode, respStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StartApp(agentId, appId)
+	code, _, err := controller.StartApp(context.Background(), agentId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1333,12 +3255,12 @@ func TestCalledStartAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.StartApp(agentId, appId)
+	code, _, err := controller.StartApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1364,7 +3286,7 @@ func TestCalledStartAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -1372,7 +3294,7 @@ func TestCalledStartAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T)
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StartApp(agentId, appId)
+	code, _, err := controller.StartApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1398,16 +3320,16 @@ func TestCalledStartAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().StartApp(address, appId).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().StartApp(gomock.Any(), address, appId).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StartApp(agentId, appId)
+	code, _, err := controller.StartApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1433,16 +3355,16 @@ func TestCalledStopApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().StopApp(address, appId).Return(respCode, respStr),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), address, appId).Return(respCode, respStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StopApp(agentId, appId)
+	code, _, err := controller.StopApp(context.Background(), agentId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1460,12 +3382,12 @@ func TestCalledStopAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.StopApp(agentId, appId)
+	code, _, err := controller.StopApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1491,7 +3413,7 @@ func TestCalledStopAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -1499,7 +3421,7 @@ func TestCalledStopAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T) {
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StopApp(agentId, appId)
+	code, _, err := controller.StopApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1525,16 +3447,16 @@ func TestCalledStopAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *t
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().StopApp(address, appId).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), address, appId).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StopApp(agentId, appId)
+	code, _, err := controller.StopApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1560,9 +3482,9 @@ func TestCalledDeleteApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().DeleteApp(address, appId).Return(respCode, respStr),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), address, appId).Return(respCode, respStr),
 		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -1570,7 +3492,7 @@ func TestCalledDeleteApp_ExpectSuccess(t *testing.T) {
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeleteApp(agentId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), agentId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1588,12 +3510,12 @@ func TestCalledDeleteAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.DeleteApp(agentId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1619,7 +3541,7 @@ func TestCalledDeleteAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -1627,7 +3549,7 @@ func TestCalledDeleteAppWhenDBHasNotMatchedAgent_ExpectErrorReturn(t *testing.T)
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeleteApp(agentId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1653,16 +3575,16 @@ func TestCalledDeleteAppWhenMessengerReturnsErrorCode_ExpectSuccess(t *testing.T
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().DeleteApp(address, appId).Return(errorRespCode, respStr),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), address, appId).Return(errorRespCode, respStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeleteApp(agentId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), agentId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1682,16 +3604,16 @@ func TestCalledDeleteAppWhenMessengerReturnsErrorCodeWithInvalidResponse_ExpectS
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().DeleteApp(address, appId).Return(errorRespCode, invalidRespStr),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), address, appId).Return(errorRespCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeleteApp(agentId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1717,9 +3639,9 @@ func TestCalledDeleteAppWhenFailedToDeleteAppIdFromDB_ExpectErrorReturn(t *testi
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetAgentByAppID(agentId, appId).Return(agent, nil),
-		msgMockObj.EXPECT().DeleteApp(address, appId).Return(respCode, nil),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), address, appId).Return(respCode, nil),
 		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -1727,7 +3649,7 @@ func TestCalledDeleteAppWhenFailedToDeleteAppIdFromDB_ExpectErrorReturn(t *testi
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeleteApp(agentId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), agentId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
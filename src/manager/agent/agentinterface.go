@@ -16,46 +16,82 @@
  *******************************************************************************/
 package agent
 
+import (
+	"context"
+	"net/url"
+)
+
 type AgentInterface interface {
 	// AddAgent add new agent to database.
-	AddAgent(body string) (int, map[string]interface{}, error)
+	AddAgent(ctx context.Context, body string) (int, map[string]interface{}, error)
 
 	// PingAgent check whether the agent is up and sending next ping request in interval time.
-	PingAgent(agentId string, ip string, body string) (int, error)
+	PingAgent(ctx context.Context, agentId string, ip string, body string) (int, error)
 
 	// DeleteAgent deletes the agent with a primary key matching the agentId argument.
-	DeleteAgent(agentId string) (int, error)
+	DeleteAgent(ctx context.Context, agentId string) (int, error)
+
+	// Decommission stops and deletes apps, unregisters and removes the agent from all groups and database.
+	Decommission(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error)
 
 	// GetAgent returns the agent with a primary key matching the agentId argument.
-	GetAgent(agentId string) (int, map[string]interface{}, error)
+	GetAgent(ctx context.Context, agentId string) (int, map[string]interface{}, error)
+
+	// GetAgents returns agents matching the filter of query parameters as an array.
+	GetAgents(ctx context.Context, query url.Values) (int, map[string]interface{}, error)
+
+	// GetEvents returns events of the agent matching the filter of query parameters as an array.
+	GetEvents(ctx context.Context, agentId string, query url.Values) (int, map[string]interface{}, error)
+
+	// GetAvailability returns the availability of the agent in the period of query parameters.
+	GetAvailability(ctx context.Context, agentId string, query url.Values) (int, map[string]interface{}, error)
+
+	// UpdateLabels changes labels of the agent specified by agentId parameter.
+	UpdateLabels(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error)
 
-	// GetAgents returns all agents in databases as an array.
-	GetAgents() (int, map[string]interface{}, error)
+	// Reconcile records the apps which the agent reports, and records the apps which are found or lost.
+	Reconcile(ctx context.Context, agentId string) (int, map[string]interface{}, error)
+
+	// UpdateExempt changes whether the agent is exempt from the retention policy.
+	UpdateExempt(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error)
+
+	// ApplyRetention applies the retention policy to disconnected agents, or reports it if it is a dry run.
+	ApplyRetention(ctx context.Context, body string) (int, map[string]interface{}, error)
+
+	// UpdateHealthCheck changes the health check mode of the agent specified by agentId parameter.
+	UpdateHealthCheck(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error)
+
+	// ResetSecret issues a new secret to the agent which lost its secret, and revokes the previous one.
+	ResetSecret(ctx context.Context, agentId string) (int, map[string]interface{}, error)
 
 	// DeployApp request an deployment of edge services to an agent specified by
 	// agentId parameter.
-	DeployApp(agentId string, body string) (int, map[string]interface{}, error)
+	DeployApp(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error)
 
 	// GetApps request a list of applications that is deployed to an agent specified
 	// by agentId parameter.
-	GetApps(agentId string) (int, map[string]interface{}, error)
+	GetApps(ctx context.Context, agentId string) (int, map[string]interface{}, error)
 
 	// GetApp gets the application's information of the agent specified by agentId parameter.
-	GetApp(agentId string, appId string) (int, map[string]interface{}, error)
+	GetApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error)
 
 	// UpdateApp request to update an application specified by appId parameter.
-	UpdateAppInfo(agentId string, appId string, body string) (int, map[string]interface{}, error)
+	UpdateAppInfo(ctx context.Context, agentId string, appId string, body string) (int, map[string]interface{}, error)
+
+	// RollbackApp request to update an application specified by appId parameter with the descriptor
+	// of an earlier revision.
+	RollbackApp(ctx context.Context, agentId string, appId string, body string) (int, map[string]interface{}, error)
 
 	// DeleteApp request to delete an application specified by appId parameter.
-	DeleteApp(agentId string, appId string) (int, map[string]interface{}, error)
+	DeleteApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error)
 
 	// UpdateAppInfo request to update all of images which is included an application
 	// specified by appId parameter.
-	UpdateApp(agentId string, appId string) (int, map[string]interface{}, error)
+	UpdateApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error)
 
 	// StartApp request to start an application specified by appId parameter.
-	StartApp(agentId string, appId string) (int, map[string]interface{}, error)
+	StartApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error)
 
 	// StopApp request to stop an application specified by appId parameter.
-	StopApp(agentId string, appId string) (int, map[string]interface{}, error)
+	StopApp(ctx context.Context, agentId string, appId string) (int, map[string]interface{}, error)
 }
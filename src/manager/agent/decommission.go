@@ -0,0 +1,252 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package agent
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"commons/results"
+	"context"
+	"db"
+	"manager/event"
+	"strings"
+)
+
+// Steps of decommissioning an agent, in order.
+const (
+	STEP_STOP_APP     = "stopapp"     // stops an app on the device.
+	STEP_DELETE_APP   = "deleteapp"   // deletes an app from the device.
+	STEP_UNREGISTER   = "unregister"  // unregisters the agent on the device.
+	STEP_LEAVE_GROUP  = "leavegroup"  // removes the agent from a group.
+	STEP_CLEAR_HEALTH = "clearhealth" // stops tracking the health of the agent.
+	STEP_DELETE_AGENT = "deleteagent" // deletes the agent from the database.
+)
+
+// Fields of requests and reports of decommissioning.
+const (
+	APPS           = "apps"           // used to indicate whether apps are stopped and deleted first.
+	FORCE          = "force"          // used to indicate whether the agent is deleted even if its device fails.
+	DECOMMISSIONED = "decommissioned" // used to indicate whether the agent is deleted.
+	STEPS          = "steps"          // used to indicate a list of steps.
+	STEP           = "step"           // used to indicate a step.
+	TARGET         = "target"         // used to indicate an app or a group of a step.
+	RESULT         = "result"         // used to indicate a result of a step.
+	CODE           = "code"           // used to indicate a response code of the device.
+	MESSAGE        = "message"        // used to indicate an error message of the device.
+	RESULT_OK      = "ok"             // the step succeeded.
+	RESULT_FAILED  = "failed"         // the step failed.
+	RESULT_SKIPPED = "skipped"        // the step was not done because an earlier step failed.
+	DECOMMISSION   = "decommission"   // used to indicate events caused by decommissioning.
+)
+
+// decommissionReport records the result of each step of decommissioning an agent.
+type decommissionReport struct {
+	steps  []map[string]interface{}
+	failed bool
+}
+
+// Decommission removes the agent specified by agentId parameter for good.
+// The body is optional, and may have 'apps' to stop and delete all apps on the agent first,
+// and 'force' to remove the agent from all groups and the database even if its device fails or is offline.
+// Otherwise, a failure of the device stops decommissioning and the agent is left as it is.
+// The result of each step is returned with 200 if all steps succeeded, 207 if the agent is removed
+// by force in spite of failures, or 500 if the agent is left.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (AgentController) Decommission(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	options := make(map[string]interface{})
+	if strings.TrimSpace(body) != "" {
+		var err error
+		options, err = convertJsonToMap(body)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+	withApps, err := parseOption(options, APPS)
+	if err != nil {
+		return results.ERROR, nil, err
+	}
+	force, err := parseOption(options, FORCE)
+	if err != nil {
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get agent specified by agentId parameter.
+	agent, err := db.GetAgent(agentId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	report := &decommissionReport{steps: make([]map[string]interface{}, 0)}
+	address := getAgentAddress(agent)
+
+	// Apps are stopped and deleted one by one, so that each failure is reported.
+	apps, _ := agent["apps"].([]string)
+	for i := 0; withApps && i < len(apps) && (force || !report.failed); i++ {
+		codes, respStr := httpMessenger.StopApp(ctx, address, apps[i])
+		if !report.device(STEP_STOP_APP, apps[i], codes, respStr) && !force {
+			break
+		}
+
+		codes, respStr = httpMessenger.DeleteApp(ctx, address, apps[i])
+		if !report.device(STEP_DELETE_APP, apps[i], codes, respStr) {
+			continue
+		}
+		err = db.DeleteAppFromAgent(agentId, apps[i])
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+
+	if force || !report.failed {
+		codes, respStr := httpMessenger.Unregister(ctx, address)
+		report.device(STEP_UNREGISTER, "", codes, respStr)
+	} else {
+		report.add(STEP_UNREGISTER, "", RESULT_SKIPPED)
+	}
+
+	// The agent is left as it is, so that decommissioning can be tried again.
+	if report.failed && !force {
+		logger.LoggingContext(ctx, logger.INFO, "decommissioning is stopped by a failure of the device:", agentId)
+		for _, step := range []string{STEP_LEAVE_GROUP, STEP_CLEAR_HEALTH, STEP_DELETE_AGENT} {
+			report.add(step, "", RESULT_SKIPPED)
+		}
+		return results.ERROR, report.toMap(agentId, force, false), nil
+	}
+
+	steps, err := removeAgent(db, agentId, DECOMMISSION)
+	report.steps = append(report.steps, steps...)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	result := results.OK
+	if report.failed {
+		logger.LoggingContext(ctx, logger.INFO, "agent is decommissioned by force in spite of failures of the device:", agentId)
+		result = results.MULTI_STATUS
+	}
+	return result, report.toMap(agentId, force, true), nil
+}
+
+// removeAgent removes the agent from all groups, stops tracking its health and deletes it from the database.
+// Events of the agent are kept, and an event of unregistration with the detail is recorded.
+// The steps which are done are returned.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func removeAgent(dbManager db.DBManager, agentId string, detail string) ([]map[string]interface{}, error) {
+	steps := make([]map[string]interface{}, 0)
+
+	groups, _, err := dbManager.GetGroupsByQuery(map[string]string{AGENT: agentId}, "", 0, "")
+	if err != nil {
+		return steps, err
+	}
+	for _, group := range groups {
+		groupId, _ := group[ID].(string)
+		err = dbManager.LeaveGroup(groupId, agentId)
+		if err != nil {
+			return steps, err
+		}
+		steps = append(steps, makeStep(STEP_LEAVE_GROUP, groupId, RESULT_OK))
+	}
+
+	monitor.Remove(agentId)
+	steps = append(steps, makeStep(STEP_CLEAR_HEALTH, "", RESULT_OK))
+
+	err = dbManager.DeleteAgent(agentId)
+	if err != nil {
+		return steps, err
+	}
+	steps = append(steps, makeStep(STEP_DELETE_AGENT, "", RESULT_OK))
+
+	return steps, dbManager.AddEvent(agentId, event.UNREGISTERED, now().Unix(), detail)
+}
+
+// parseOption returns the boolean option in the body, or false if it is not given.
+func parseOption(options map[string]interface{}, key string) (bool, error) {
+	value, exists := options[key]
+	if !exists {
+		return false, nil
+	}
+	option, ok := value.(bool)
+	if !ok {
+		return false, errors.InvalidParam{Message: key + " field should be a boolean"}
+	}
+	return option, nil
+}
+
+// makeStep returns a step of decommissioning with the target and the result.
+// The target is omitted if it is empty.
+func makeStep(step string, target string, result string) map[string]interface{} {
+	entry := map[string]interface{}{
+		STEP:   step,
+		RESULT: result,
+	}
+	if target != "" {
+		entry[TARGET] = target
+	}
+	return entry
+}
+
+// add records the step with the target and the result, and returns the entry of the step.
+func (report *decommissionReport) add(step string, target string, result string) map[string]interface{} {
+	entry := makeStep(step, target, result)
+	report.steps = append(report.steps, entry)
+	return entry
+}
+
+// device records the step with the response of the device.
+// This function returns true if the response code represents success.
+func (report *decommissionReport) device(step string, target string, codes []int, respStr []string) bool {
+	entry := report.add(step, target, RESULT_OK)
+	entry[CODE] = codes[0]
+	if isSuccessCode(codes[0]) {
+		return true
+	}
+
+	entry[RESULT] = RESULT_FAILED
+	if respMap, err := convertJsonToMap(respStr[0]); err == nil && respMap[MESSAGE] != nil {
+		entry[MESSAGE] = respMap[MESSAGE]
+	}
+	report.failed = true
+	return false
+}
+
+// toMap converts the report into a map.
+func (report *decommissionReport) toMap(agentId string, force bool, decommissioned bool) map[string]interface{} {
+	return map[string]interface{}{
+		ID:             agentId,
+		FORCE:          force,
+		DECOMMISSIONED: decommissioned,
+		STEPS:          report.steps,
+	}
+}
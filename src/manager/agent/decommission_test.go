@@ -0,0 +1,196 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package agent
+
+import (
+	"commons/errors"
+	"commons/results"
+	"context"
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	msgmocks "messenger/mocks"
+	"reflect"
+	"testing"
+)
+
+var agentWithApp = map[string]interface{}{
+	"id":   agentId,
+	"host": host,
+	"port": port,
+	"apps": []string{appId},
+}
+
+func TestCalledDecommissionWithApps_ExpectAllStepsDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agentWithApp, nil),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), address, appId).Return(respCode, respStr),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), address, appId).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(nil),
+		msgMockObj.EXPECT().Unregister(gomock.Any(), address).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().GetGroupsByQuery(map[string]string{"agent": agentId}, "", 0, "").Return(groups, "", nil),
+		dbManagerMockObj.EXPECT().LeaveGroup(groupId, agentId).Return(nil),
+		dbManagerMockObj.EXPECT().DeleteAgent(agentId).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "unregistered", gomock.Any(), "decommission").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.Decommission(context.Background(), agentId, `{"apps":true}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedSteps := []map[string]interface{}{
+		{"step": "stopapp", "target": appId, "result": "ok", "code": results.OK},
+		{"step": "deleteapp", "target": appId, "result": "ok", "code": results.OK},
+		{"step": "unregister", "result": "ok", "code": results.OK},
+		{"step": "leavegroup", "target": groupId, "result": "ok"},
+		{"step": "clearhealth", "result": "ok"},
+		{"step": "deleteagent", "result": "ok"},
+	}
+	if !reflect.DeepEqual(expectedSteps, res["steps"]) {
+		t.Errorf("Expected steps: %v, actual steps: %v", expectedSteps, res["steps"])
+	}
+
+	if res["decommissioned"] != true {
+		t.Errorf("Expected decommissioned: true, actual: %v", res["decommissioned"])
+	}
+}
+
+func TestCalledDecommissionWhenDeviceFailed_ExpectAgentLeft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agentWithApp, nil),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), address, appId).Return(errorRespCode, []string{`{"message":"unreachable"}`}),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.Decommission(context.Background(), agentId, `{"apps":true}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	expectedSteps := []map[string]interface{}{
+		{"step": "stopapp", "target": appId, "result": "failed", "code": results.ERROR, "message": "unreachable"},
+		{"step": "unregister", "result": "skipped"},
+		{"step": "leavegroup", "result": "skipped"},
+		{"step": "clearhealth", "result": "skipped"},
+		{"step": "deleteagent", "result": "skipped"},
+	}
+	if !reflect.DeepEqual(expectedSteps, res["steps"]) {
+		t.Errorf("Expected steps: %v, actual steps: %v", expectedSteps, res["steps"])
+	}
+
+	if res["decommissioned"] != false {
+		t.Errorf("Expected decommissioned: false, actual: %v", res["decommissioned"])
+	}
+}
+
+func TestCalledDecommissionByForceWhenDeviceFailed_ExpectAgentRemoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agentWithApp, nil),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), address, appId).Return(errorRespCode, invalidRespStr),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), address, appId).Return(errorRespCode, invalidRespStr),
+		msgMockObj.EXPECT().Unregister(gomock.Any(), address).Return(errorRespCode, invalidRespStr),
+		dbManagerMockObj.EXPECT().GetGroupsByQuery(map[string]string{"agent": agentId}, "", 0, "").Return(groups, "", nil),
+		dbManagerMockObj.EXPECT().LeaveGroup(groupId, agentId).Return(nil),
+		dbManagerMockObj.EXPECT().DeleteAgent(agentId).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "unregistered", gomock.Any(), "decommission").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.Decommission(context.Background(), agentId, `{"apps":true,"force":true}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.MULTI_STATUS {
+		t.Errorf("Expected code: %d, actual code: %d", results.MULTI_STATUS, code)
+	}
+
+	expectedSteps := []map[string]interface{}{
+		{"step": "stopapp", "target": appId, "result": "failed", "code": results.ERROR},
+		{"step": "deleteapp", "target": appId, "result": "failed", "code": results.ERROR},
+		{"step": "unregister", "result": "failed", "code": results.ERROR},
+		{"step": "leavegroup", "target": groupId, "result": "ok"},
+		{"step": "clearhealth", "result": "ok"},
+		{"step": "deleteagent", "result": "ok"},
+	}
+	if !reflect.DeepEqual(expectedSteps, res["steps"]) {
+		t.Errorf("Expected steps: %v, actual steps: %v", expectedSteps, res["steps"])
+	}
+
+	if res["decommissioned"] != true {
+		t.Errorf("Expected decommissioned: true, actual: %v", res["decommissioned"])
+	}
+}
+
+func TestCalledDecommissionWithInvalidOption_ExpectErrorReturn(t *testing.T) {
+	code, _, err := controller.Decommission(context.Background(), agentId, `{"force":"yes"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
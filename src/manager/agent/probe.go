@@ -0,0 +1,142 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package agent
+
+import (
+	"commons/config"
+	"commons/logger"
+	"context"
+	"manager/event"
+	"manager/health"
+	"time"
+)
+
+// StartHealthProbe starts probing agents whose health check mode is 'pull' or 'both'
+// every configured interval. Nothing is started if the interval is 0.
+func StartHealthProbe() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	interval := config.Get().Health.ProbeInterval
+	if interval == 0 {
+		return
+	}
+	prober = health.NewProber(health.SystemClock{}, time.Duration(interval)*time.Second, probeAgents)
+}
+
+// probeAgents sends a health probe to each agent whose health check mode is 'pull' or 'both',
+// with at most the configured number of probes at a time.
+func probeAgents() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(context.Background())
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	agents, err := db.GetAllAgents()
+	db.Close()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	var targets []map[string]interface{}
+	for _, agent := range agents {
+		if healthCheckMode(agent) != config.HEALTH_PUSH {
+			targets = append(targets, agent)
+		}
+	}
+
+	health.ProbeAll(len(targets), config.Get().Health.ProbeConcurrency, func(index int) {
+		probeAgent(targets[index])
+	})
+}
+
+// probeAgent sends a health probe to the agent and updates the status of the agent with the result.
+// A successful probe works as a ping whose interval is the probe interval and timeout.
+// When probes fail, the agent is disconnected at the deadline given by its last ping or probe,
+// or right away if it has never been seen.
+func probeAgent(agent map[string]interface{}) {
+	agentId, _ := agent[ID].(string)
+	cfg := config.Get().Health
+
+	// The probe is cancelled if the agent does not respond in time.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ProbeTimeout)*time.Second)
+	codes, _ := httpMessenger.Health(ctx, getAgentAddress(agent))
+	cancel()
+
+	alive := len(codes) == 1 && isSuccessCode(codes[0])
+	lastSeen, _ := agent[LAST_SEEN].(int64)
+	if !alive && (lastSeen != 0 || agent[STATUS] != STATUS_CONNECTED) {
+		logger.Logging(logger.DEBUG, "health probe failed:", agentId)
+		return
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(context.Background())
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	defer db.Close()
+
+	if !alive {
+		logger.Logging(logger.INFO, "health probe failed for agent which has never been seen:", agentId)
+		monitor.Watch(agentId, now())
+
+		err = db.UpdateAgentStatus(agentId, STATUS_DISCONNECTED)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return
+		}
+
+		err = db.AddEvent(agentId, event.DISCONNECTED, now().Unix(), PROBE)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+		return
+	}
+
+	// Store the time of this probe so that the deadline can be rebuilt after a restart.
+	interval := cfg.ProbeInterval + cfg.ProbeTimeout
+	err = db.UpdateAgentLastSeen(agentId, now().Unix(), interval)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	// A later deadline given by a ping is kept.
+	// An agent disconnected before a restart is not tracked, so its status is checked as well.
+	expired := monitor.Extend(agentId, healthCheckTimeout(interval))
+	if expired || agent[STATUS] != STATUS_CONNECTED {
+		logger.Logging(logger.INFO, "health probe succeeded after interval time-out:", agentId)
+		err = db.UpdateAgentStatus(agentId, STATUS_CONNECTED)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return
+		}
+
+		err = db.AddEvent(agentId, event.CONNECTED, now().Unix(), PROBE)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+	}
+}
@@ -0,0 +1,191 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package agent
+
+import (
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	msgmocks "messenger/mocks"
+	"testing"
+	"time"
+)
+
+func TestCalledProbeAgents_ExpectOnlyProbedAgentsProbed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lastSeen := time.Now().Unix()
+	agents := []map[string]interface{}{
+		{"id": agentId, "host": host, "port": port, "status": "connected", "lastseen": lastSeen, "healthcheck": "pull"},
+		{"id": otherAgentId, "host": "127.0.0.2", "port": port, "status": "connected", "lastseen": lastSeen, "healthcheck": ""},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAllAgents().Return(agents, nil),
+		dbManagerMockObj.EXPECT().Close(),
+		msgMockObj.EXPECT().Health(gomock.Any(), address).Return(errorRespCode, respStr),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	probeAgents()
+}
+
+func TestCalledProbeAgentWithHealthyAgent_ExpectLastSeenUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	probed := map[string]interface{}{"id": agentId, "host": host, "port": port, "status": "connected", "lastseen": int64(0)}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		msgMockObj.EXPECT().Health(gomock.Any(), address).Return(respCode, respStr),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, current.Unix(), 35).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+	defer monitor.Remove(agentId)
+
+	probeAgent(probed)
+}
+
+func TestCalledProbeAgentAfterTimeout_ExpectConnectedEventRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	probed := map[string]interface{}{"id": agentId, "host": host, "port": port, "status": "connected", "lastseen": int64(1)}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		msgMockObj.EXPECT().Health(gomock.Any(), address).Return(respCode, respStr),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, current.Unix(), 35).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentStatus(agentId, "connected").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "connected", current.Unix(), "probe").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	// The deadline of the agent has passed.
+	monitor.Watch(agentId, time.Now().Add(-time.Hour))
+	defer monitor.Remove(agentId)
+
+	probeAgent(probed)
+}
+
+func TestCalledProbeAgentWithDisconnectedAgent_ExpectConnectedEventRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The agent was disconnected before a restart, so it is not tracked.
+	probed := map[string]interface{}{"id": agentId, "host": host, "port": port, "status": "disconnected", "lastseen": int64(0)}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		msgMockObj.EXPECT().Health(gomock.Any(), address).Return(respCode, respStr),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentLastSeen(agentId, gomock.Any(), 35).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentStatus(agentId, "connected").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "connected", gomock.Any(), "probe").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+	defer monitor.Remove(agentId)
+
+	probeAgent(probed)
+}
+
+func TestCalledProbeAgentWithNeverSeenAgent_ExpectDisconnectedRightAway(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	probed := map[string]interface{}{"id": agentId, "host": host, "port": port, "status": "connected", "lastseen": int64(0)}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		msgMockObj.EXPECT().Health(gomock.Any(), address).Return(errorRespCode, respStr),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentStatus(agentId, "disconnected").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "disconnected", current.Unix(), "probe").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+	defer monitor.Remove(agentId)
+
+	probeAgent(probed)
+
+	// The next successful probe is regarded as the one after interval time-out.
+	if !monitor.Beat(agentId, time.Hour) {
+		t.Errorf("Expected expired deadline of agent: %s", agentId)
+	}
+}
+
+func TestCalledProbeAgentWithUnhealthySeenAgent_ExpectNothingUpdated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	probed := map[string]interface{}{"id": agentId, "host": host, "port": port, "status": "connected", "lastseen": time.Now().Unix()}
+
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		msgMockObj.EXPECT().Health(gomock.Any(), address).Return(errorRespCode, respStr),
+	)
+	// pass mockObj to a real object.
+	httpMessenger = msgMockObj
+
+	probeAgent(probed)
+}
@@ -0,0 +1,257 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package agent
+
+import (
+	"commons/config"
+	"commons/errors"
+	"commons/logger"
+	"commons/results"
+	"context"
+	"db"
+	"manager/event"
+	"manager/health"
+	"strings"
+	"time"
+)
+
+// Fields of reports of reconciliation.
+const (
+	FOUND = "found" // used to indicate apps which are found on the agent and recorded.
+	LOST  = "lost"  // used to indicate recorded apps which are not found on the agent.
+)
+
+var reconciler *health.Prober
+
+// StartReconciler starts reconciling apps recorded for connected agents with the apps
+// which the agents report every configured interval. Nothing is started if the interval is 0.
+func StartReconciler() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	interval := config.Get().Agent.ReconcileInterval
+	if interval == 0 {
+		return
+	}
+	reconciler = health.NewProber(health.SystemClock{}, time.Duration(interval)*time.Second, reconcileAgents)
+}
+
+// StopReconciler stops reconciling apps of agents.
+// Running reconciliations are waited for until they finish or the given context is done.
+// If successful, this function returns an error as nil.
+// otherwise, the error of the context will be returned.
+func StopReconciler(ctx context.Context) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if reconciler == nil {
+		return nil
+	}
+	return reconciler.Stop(ctx)
+}
+
+// Reconcile records the apps which the agent specified by agentId parameter reports,
+// and records an event for each app which is found or lost.
+// If the agent fails to report its apps, the response of the agent is returned.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (AgentController) Reconcile(ctx context.Context, agentId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get agent specified by agentId parameter.
+	agent, err := db.GetAgent(agentId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	return reconcile(ctx, db, agent)
+}
+
+// reconcileAgents reconciles apps of each connected agent,
+// with at most the configured number of agents at a time.
+func reconcileAgents() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(context.Background())
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	agents, err := db.GetAllAgents()
+	db.Close()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	var targets []map[string]interface{}
+	for _, agent := range agents {
+		if agent[STATUS] == STATUS_CONNECTED {
+			targets = append(targets, agent)
+		}
+	}
+
+	cfg := config.Get().Agent
+	health.ProbeAll(len(targets), cfg.ReconcileConcurrency, func(index int) {
+		// A reconciliation is cancelled if it does not finish until the next one.
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ReconcileInterval)*time.Second)
+		defer cancel()
+		reconcileAgent(ctx, targets[index])
+	})
+}
+
+// reconcileAgent reconciles apps of the agent, and logs the error if it fails.
+func reconcileAgent(ctx context.Context, agent map[string]interface{}) {
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	defer db.Close()
+
+	agentId, _ := agent[ID].(string)
+	code, _, err := reconcile(ctx, db, agent)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	if !isSuccessCode(code) {
+		logger.Logging(logger.DEBUG, "failed to get apps of agent:", agentId)
+	}
+}
+
+// reconcile requests a list of apps to the agent and changes the apps recorded for the agent to match it.
+// The agent is read before the request, so that an app recorded by a deployment during the request
+// is not regarded as lost.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func reconcile(ctx context.Context, dbManager db.DBManager, agent map[string]interface{}) (int, map[string]interface{}, error) {
+	agentId, _ := agent[ID].(string)
+
+	codes, respStr := httpMessenger.InfoApps(ctx, getAgentAddress(agent))
+	if !isSuccessCode(codes[0]) {
+		respMap, err := convertRespToMap(ctx, respStr)
+		if err != nil {
+			return results.ERROR, nil, err
+		}
+		return codes[0], respMap, nil
+	}
+
+	running, err := parseAppIds(respStr[0])
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	recorded, _ := agent[APPS].([]string)
+
+	found := difference(running, recorded)
+	for _, appId := range found {
+		err = dbManager.AddAppToAgent(agentId, appId)
+		if err != nil {
+			return results.ERROR, nil, err
+		}
+		err = dbManager.AddEvent(agentId, event.APP_FOUND, now().Unix(), appId)
+		if err != nil {
+			return results.ERROR, nil, err
+		}
+	}
+
+	lost := difference(recorded, running)
+	for _, appId := range lost {
+		err = dbManager.DeleteAppFromAgent(agentId, appId)
+		if err != nil {
+			return results.ERROR, nil, err
+		}
+		err = dbManager.AddEvent(agentId, event.APP_LOST, now().Unix(), appId)
+		if err != nil {
+			return results.ERROR, nil, err
+		}
+	}
+
+	if len(found) > 0 || len(lost) > 0 {
+		logger.LoggingContext(ctx, logger.INFO, "apps of agent are reconciled:", agentId,
+			"found:", strings.Join(found, ","), "lost:", strings.Join(lost, ","))
+	}
+
+	res := make(map[string]interface{})
+	res[ID] = agentId
+	res[APPS] = running
+	res[FOUND] = found
+	res[LOST] = lost
+	return results.OK, res, nil
+}
+
+// parseAppIds returns the ids of apps in a list of apps reported by an agent.
+// Each app is either an id or an object which has an id.
+// If the list is not valid, an InternalServerError is returned, so that recorded apps are not
+// regarded as lost because of an unexpected response.
+func parseAppIds(respStr string) ([]string, error) {
+	invalid := errors.InternalServerError{Message: "invalid list of apps from agent"}
+
+	respMap, err := convertJsonToMap(respStr)
+	if err != nil {
+		return nil, invalid
+	}
+	apps, ok := respMap[APPS].([]interface{})
+	if !ok {
+		return nil, invalid
+	}
+
+	appIds := make([]string, 0, len(apps))
+	for _, app := range apps {
+		if object, ok := app.(map[string]interface{}); ok {
+			app = object[ID]
+		}
+		appId, ok := app.(string)
+		if !ok || appId == "" {
+			return nil, invalid
+		}
+		appIds = append(appIds, appId)
+	}
+	return appIds, nil
+}
+
+// difference returns the ids in source which are not in target.
+func difference(source []string, target []string) []string {
+	targetSet := make(map[string]bool, len(target))
+	for _, id := range target {
+		targetSet[id] = true
+	}
+
+	result := make([]string, 0)
+	for _, id := range source {
+		if !targetSet[id] {
+			result = append(result, id)
+			targetSet[id] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package agent
+
+import (
+	"commons/errors"
+	"commons/results"
+	"context"
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	msgmocks "messenger/mocks"
+	"reflect"
+	"testing"
+)
+
+const foundAppId = "000000000000000000000005"
+
+func TestCalledReconcileWithDrift_ExpectAppsRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	infoAppsRespStr := []string{`{"apps":[{"id":"` + foundAppId + `","state":"running"}]}`}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agentWithApp, nil),
+		msgMockObj.EXPECT().InfoApps(gomock.Any(), address).Return(respCode, infoAppsRespStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, foundAppId).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "appfound", gomock.Any(), foundAppId).Return(nil),
+		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "applost", gomock.Any(), appId).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.Reconcile(context.Background(), agentId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedRes := map[string]interface{}{
+		"id":    agentId,
+		"apps":  []string{foundAppId},
+		"found": []string{foundAppId},
+		"lost":  []string{appId},
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledReconcileWhenAgentFailed_ExpectNothingChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agentWithApp, nil),
+		msgMockObj.EXPECT().InfoApps(gomock.Any(), address).Return(errorRespCode, respStr),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.Reconcile(context.Background(), agentId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+}
+
+func TestCalledReconcileWithInvalidListOfApps_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(agentWithApp, nil),
+		msgMockObj.EXPECT().InfoApps(gomock.Any(), address).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.Reconcile(context.Background(), agentId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InternalServerError", err)
+	case errors.InternalServerError:
+	}
+}
+
+func TestCalledReconcileAgents_ExpectOnlyConnectedAgentsReconciled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agents := []map[string]interface{}{
+		{"id": agentId, "host": host, "port": port, "status": "connected", "apps": []string{appId}},
+		{"id": otherAgentId, "host": "127.0.0.2", "port": port, "status": "disconnected", "apps": []string{}},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAllAgents().Return(agents, nil),
+		dbManagerMockObj.EXPECT().Close(),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		msgMockObj.EXPECT().InfoApps(gomock.Any(), address).Return(respCode, []string{`{"apps":["` + appId + `"]}`}),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	reconcileAgents()
+}
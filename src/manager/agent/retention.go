@@ -0,0 +1,272 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package agent
+
+import (
+	"commons/config"
+	"commons/errors"
+	"commons/logger"
+	"commons/results"
+	"context"
+	"db"
+	"manager/event"
+	"manager/health"
+	"strings"
+	"time"
+)
+
+// Fields of requests and reports of the retention policy.
+const (
+	EXEMPT    = "exempt"    // used to indicate whether an agent is exempt from the retention policy.
+	DRY_RUN   = "dryrun"    // used to indicate whether the retention policy is only reported.
+	STALE     = "stale"     // used to indicate agents which become stale.
+	DELETED   = "deleted"   // used to indicate agents which are deleted.
+	RETENTION = "retention" // used to indicate events caused by the retention policy.
+)
+
+var retainer *health.Prober
+
+// StartRetention starts applying the retention policy to disconnected agents every configured interval.
+// Nothing is started if neither stale days nor delete days are given.
+func StartRetention() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	cfg := config.Get().Retention
+	if cfg.StaleAfter == 0 && cfg.DeleteAfter == 0 {
+		return
+	}
+	retainer = health.NewProber(health.SystemClock{}, time.Duration(cfg.Interval)*time.Second, retainAgents)
+}
+
+// StopRetention stops applying the retention policy.
+// The running application is waited for until it finishes or the given context is done.
+// If successful, this function returns an error as nil.
+// otherwise, the error of the context will be returned.
+func StopRetention(ctx context.Context) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if retainer == nil {
+		return nil
+	}
+	return retainer.Stop(ctx)
+}
+
+// ApplyRetention applies the retention policy to disconnected agents right away.
+// The body is optional, and may have 'dryrun' to report agents which would become stale
+// or be deleted without changing them.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (AgentController) ApplyRetention(ctx context.Context, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	options := make(map[string]interface{})
+	if strings.TrimSpace(body) != "" {
+		var err error
+		options, err = convertJsonToMap(body)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+	dryRun, err := parseOption(options, DRY_RUN)
+	if err != nil {
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	res, err := applyRetention(db, dryRun)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	return results.OK, res, err
+}
+
+// UpdateExempt changes whether the agent specified by agentId parameter is exempt from the retention policy.
+// A stale agent which becomes exempt is disconnected again.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (AgentController) UpdateExempt(ctx context.Context, agentId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	bodyMap, err := convertJsonToMap(body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	if _, exists := bodyMap[EXEMPT]; !exists {
+		return results.ERROR, nil, errors.InvalidJSON{Message: "exempt field is required"}
+	}
+	exempt, err := parseOption(bodyMap, EXEMPT)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get agent specified by agentId parameter.
+	agent, err := db.GetAgent(agentId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	err = db.UpdateAgentExempt(agentId, exempt)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	if exempt && agent[STATUS] == STATUS_STALE {
+		err = db.UpdateAgentStatus(agentId, STATUS_DISCONNECTED)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+	}
+
+	res := make(map[string]interface{})
+	res[EXEMPT] = exempt
+	return results.OK, res, err
+}
+
+// retainAgents applies the retention policy, and logs the error if it fails.
+func retainAgents() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(context.Background())
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	defer db.Close()
+
+	_, err = applyRetention(db, false)
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+	}
+}
+
+// applyRetention makes agents which have been disconnected for the configured stale days stale,
+// and removes agents which have been disconnected for the configured delete days.
+// Connected agents and exempt agents are left as they are.
+// If dryRun is true, agents are only reported. Otherwise, an event with the detail 'retention'
+// is recorded for each agent which becomes stale or is deleted.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func applyRetention(dbManager db.DBManager, dryRun bool) (map[string]interface{}, error) {
+	cfg := config.Get().Retention
+
+	agents, err := dbManager.GetAllAgents()
+	if err != nil {
+		return nil, err
+	}
+
+	stale := make([]string, 0)
+	deleted := make([]string, 0)
+	for _, agent := range agents {
+		agentId, _ := agent[ID].(string)
+		exempt, _ := agent[EXEMPT].(bool)
+		if exempt || agent[STATUS] == STATUS_CONNECTED {
+			continue
+		}
+
+		since, err := disconnectedSince(dbManager, agent)
+		if err != nil {
+			return nil, err
+		}
+		if since == 0 {
+			continue
+		}
+		days := int(now().Sub(time.Unix(since, 0)) / (24 * time.Hour))
+
+		switch {
+		case cfg.DeleteAfter > 0 && days >= cfg.DeleteAfter:
+			deleted = append(deleted, agentId)
+			if dryRun {
+				continue
+			}
+			logger.Logging(logger.INFO, "agent is deleted by the retention policy:", agentId)
+			_, err = removeAgent(dbManager, agentId, RETENTION)
+			if err != nil {
+				return nil, err
+			}
+		case cfg.StaleAfter > 0 && days >= cfg.StaleAfter && agent[STATUS] != STATUS_STALE:
+			stale = append(stale, agentId)
+			if dryRun {
+				continue
+			}
+			logger.Logging(logger.INFO, "agent becomes stale by the retention policy:", agentId)
+			err = dbManager.UpdateAgentStatus(agentId, STATUS_STALE)
+			if err != nil {
+				return nil, err
+			}
+			err = dbManager.AddEvent(agentId, event.STALE, now().Unix(), RETENTION)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	res := make(map[string]interface{})
+	res[DRY_RUN] = dryRun
+	res[STALE] = stale
+	res[DELETED] = deleted
+	return res, nil
+}
+
+// disconnectedSince returns the time when the agent was disconnected last in seconds since the epoch.
+// If no disconnection is recorded, the time of its last ping is returned,
+// which is 0 if the agent has never been seen.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func disconnectedSince(dbManager db.DBManager, agent map[string]interface{}) (int64, error) {
+	agentId, _ := agent[ID].(string)
+	filter := map[string]string{AGENT: agentId, TYPE: event.DISCONNECTED}
+	events, _, err := dbManager.GetEventsByQuery(filter, "-id", 1, "")
+	if err != nil {
+		return 0, err
+	}
+	if len(events) != 0 {
+		since, _ := events[0]["time"].(int64)
+		return since, nil
+	}
+
+	lastSeen, _ := agent[LAST_SEEN].(int64)
+	return lastSeen, nil
+}
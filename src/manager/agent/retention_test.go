@@ -0,0 +1,192 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package agent
+
+import (
+	"commons/config"
+	"commons/errors"
+	"commons/results"
+	"context"
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const day = 24 * 60 * 60
+
+func setRetention(staleAfter int, deleteAfter int) func() {
+	cfg := config.Get()
+	old := cfg
+	cfg.Retention.StaleAfter = staleAfter
+	cfg.Retention.DeleteAfter = deleteAfter
+	config.Set(cfg)
+	return func() { config.Set(old) }
+}
+
+func TestCalledApplyRetention_ExpectAgentsStaleAndDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer setRetention(7, 30)()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	agents := []map[string]interface{}{
+		{"id": agentId, "status": "disconnected", "exempt": false},
+		{"id": otherAgentId, "status": "stale", "exempt": false},
+		{"id": neverSeenAgentId, "status": "disconnected", "exempt": true},
+		{"id": groupId, "status": "connected", "exempt": false},
+	}
+	disconnected := map[string]string{"agent": agentId, "type": "disconnected"}
+	otherDisconnected := map[string]string{"agent": otherAgentId, "type": "disconnected"}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAllAgents().Return(agents, nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(disconnected, "-id", 1, "").
+			Return([]map[string]interface{}{{"time": current.Unix() - 8*day}}, "", nil),
+		dbManagerMockObj.EXPECT().UpdateAgentStatus(agentId, "stale").Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(agentId, "stale", current.Unix(), "retention").Return(nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(otherDisconnected, "-id", 1, "").
+			Return([]map[string]interface{}{{"time": current.Unix() - 31*day}}, "", nil),
+		dbManagerMockObj.EXPECT().GetGroupsByQuery(map[string]string{"agent": otherAgentId}, "", 0, "").Return(nil, "", nil),
+		dbManagerMockObj.EXPECT().DeleteAgent(otherAgentId).Return(nil),
+		dbManagerMockObj.EXPECT().AddEvent(otherAgentId, "unregistered", current.Unix(), "retention").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.ApplyRetention(context.Background(), "")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedRes := map[string]interface{}{
+		"dryrun":  false,
+		"stale":   []string{agentId},
+		"deleted": []string{otherAgentId},
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledApplyRetentionWithDryRun_ExpectNothingChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer setRetention(7, 30)()
+
+	current := time.Unix(1500000000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	// The agent has no event of disconnection, so the time of its last ping is used.
+	agents := []map[string]interface{}{
+		{"id": agentId, "status": "disconnected", "lastseen": current.Unix() - 31*day},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAllAgents().Return(agents, nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(gomock.Any(), "-id", 1, "").Return(nil, "", nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.ApplyRetention(context.Background(), `{"dryrun":true}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedRes := map[string]interface{}{
+		"dryrun":  true,
+		"stale":   []string{},
+		"deleted": []string{agentId},
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledUpdateExemptForStaleAgent_ExpectAgentDisconnected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	staleAgent := map[string]interface{}{"id": agentId, "status": "stale"}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgent(agentId).Return(staleAgent, nil),
+		dbManagerMockObj.EXPECT().UpdateAgentExempt(agentId, true).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentStatus(agentId, "disconnected").Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.UpdateExempt(context.Background(), agentId, `{"exempt":true}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if res["exempt"] != true {
+		t.Errorf("Expected exempt: true, actual exempt: %v", res["exempt"])
+	}
+}
+
+func TestCalledUpdateExemptWithoutExempt_ExpectErrorReturn(t *testing.T) {
+	code, _, err := controller.UpdateExempt(context.Background(), agentId, `{}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+	case errors.InvalidJSON:
+	}
+}
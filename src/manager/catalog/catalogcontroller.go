@@ -0,0 +1,400 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package catalog provides operations to manage the catalog of apps
+// (e.g., create, get, delete apps and add versions...).
+// An app of the catalog has immutable versions of a compose descriptor with its checksum,
+// and is deployed to agents by a reference to one of the versions.
+package catalog
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"commons/results"
+	"context"
+	"crypto/sha256"
+	"db"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	APPS        = "apps"        // used to indicate a list of catalog apps.
+	NAME        = "name"        // used to indicate a name of catalog app.
+	APP         = "app"         // used to indicate a name of catalog app in a reference.
+	DESCRIPTION = "description" // used to indicate a description of app or version.
+	VERSION     = "version"     // used to indicate a version of catalog app.
+	VERSIONS    = "versions"    // used to indicate a list of versions of catalog app.
+	DESCRIPTOR  = "descriptor"  // used to indicate a compose descriptor of version.
+	CHECKSUM    = "checksum"    // used to indicate a SHA-256 checksum of descriptor.
+	CREATED_AT  = "createdat"   // used to indicate a time when version was added.
+	CATALOG     = "catalog"     // used to indicate catalog versions of apps recorded on agent.
+)
+
+var (
+	// namePattern is a pattern of names of apps, which are used in urls.
+	namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,62}$`)
+
+	// versionPattern is a pattern of versions of apps, e.g. 1.4.0 or 2.0.0-rc.1.
+	versionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]{0,62}$`)
+)
+
+// Reference identifies a version of an app in the catalog.
+type Reference struct {
+	App      string
+	Version  string
+	Checksum string
+}
+
+type CatalogController struct{}
+
+var dbConnector db.DBConnection
+var now = time.Now
+
+func init() {
+	dbConnector = db.DBConnector{}
+}
+
+// CreateApp adds a new app with the name and the description given in body to the catalog.
+// The app has no version until AddVersion is called.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (CatalogController) CreateApp(ctx context.Context, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	bodyMap, err := convertJsonToMap(body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	name, ok := bodyMap[NAME].(string)
+	if !ok {
+		return results.ERROR, nil, errors.InvalidJSON{Message: "name field is required"}
+	}
+	if !namePattern.MatchString(name) {
+		return results.ERROR, nil, errors.InvalidParam{Message: "name should consist of at most 63 lowercase alphanumerics, '.', '-' and '_'"}
+	}
+
+	description, err := getDescription(bodyMap)
+	if err != nil {
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	_, err = db.GetCatalogApp(name)
+	switch {
+	case err == nil:
+		return results.ERROR, nil, errors.InvalidParam{Message: "app already exists", Kind: errors.CATALOG, ID: name}
+	case !errors.Is(err, errors.NotFound{}):
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res, err := db.AddCatalogApp(name, description)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	return results.OK, res, err
+}
+
+// GetApps returns all apps in the catalog as an array.
+// Descriptors of versions are not included.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (CatalogController) GetApps(ctx context.Context) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	apps, err := db.GetAllCatalogApps()
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[APPS] = apps
+	return results.OK, res, err
+}
+
+// GetApp returns the app with the name and its versions.
+// Descriptors of versions are not included.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (CatalogController) GetApp(ctx context.Context, name string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	app, err := db.GetCatalogApp(name)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	return results.OK, app, err
+}
+
+// DeleteApp removes the app with the name and all its versions from the catalog.
+// Apps already deployed to agents are left as they are.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (CatalogController) DeleteApp(ctx context.Context, name string) (int, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
+	defer db.Close()
+
+	err = db.DeleteCatalogApp(name)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
+
+	return results.OK, err
+}
+
+// AddVersion adds a new version of the descriptor given in body to the app with the name.
+// Versions are immutable, so adding an existing version fails.
+// If successful, the version is returned with the checksum of the descriptor.
+// otherwise, an appropriate error will be returned.
+func (CatalogController) AddVersion(ctx context.Context, name string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	bodyMap, err := convertJsonToMap(body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	version, ok := bodyMap[VERSION].(string)
+	if !ok {
+		return results.ERROR, nil, errors.InvalidJSON{Message: "version field is required"}
+	}
+	if !versionPattern.MatchString(version) {
+		return results.ERROR, nil, errors.InvalidParam{Message: "version should consist of at most 63 alphanumerics, '.', '+', '-' and '_'"}
+	}
+
+	descriptor, ok := bodyMap[DESCRIPTOR].(string)
+	if !ok || strings.TrimSpace(descriptor) == "" {
+		return results.ERROR, nil, errors.InvalidJSON{Message: "descriptor field is required"}
+	}
+
+	description, err := getDescription(bodyMap)
+	if err != nil {
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	app, err := db.GetCatalogApp(name)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	versions, _ := app[VERSIONS].([]map[string]interface{})
+	for _, item := range versions {
+		if item[VERSION] == version {
+			return results.ERROR, nil, errors.InvalidParam{Message: "version already exists", Kind: errors.CATALOG, ID: name + ":" + version}
+		}
+	}
+
+	checksum := Checksum(descriptor)
+	createdAt := now().Unix()
+	err = db.AddCatalogVersion(name, version, description, descriptor, checksum, createdAt)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[NAME] = name
+	res[VERSION] = version
+	res[DESCRIPTION] = description
+	res[CHECKSUM] = checksum
+	res[CREATED_AT] = createdAt
+	return results.OK, res, err
+}
+
+// GetVersion returns the version of the app with the name including the descriptor.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (CatalogController) GetVersion(ctx context.Context, name string, version string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	res, err := db.GetCatalogVersion(name, version)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	return results.OK, res, err
+}
+
+// Resolve returns the descriptor to deploy for the body of a deploy request.
+// If the body is a catalog reference, i.e. a JSON object with app and version fields,
+// the descriptor of the version is returned with the reference.
+// If the body is a JSON object with descriptor field, the descriptor is returned with nil reference.
+// otherwise, the body itself is returned as the descriptor with nil reference.
+func Resolve(dbManager db.DBManager, body string) (string, *Reference, error) {
+	bodyMap := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(body), &bodyMap); err != nil {
+		return body, nil, nil
+	}
+	if _, exists := bodyMap[APP]; !exists {
+		if _, exists := bodyMap[DESCRIPTOR]; !exists {
+			return body, nil, nil
+		}
+		descriptor, ok := bodyMap[DESCRIPTOR].(string)
+		if !ok || descriptor == "" {
+			return "", nil, errors.InvalidParam{Message: "descriptor should be a compose file"}
+		}
+		return descriptor, nil, nil
+	}
+
+	name, ok := bodyMap[APP].(string)
+	if !ok || name == "" {
+		return "", nil, errors.InvalidParam{Message: "app of a catalog reference should be a name of catalog app"}
+	}
+	version, ok := bodyMap[VERSION].(string)
+	if !ok || version == "" {
+		return "", nil, errors.InvalidParam{Message: "version of a catalog reference is required"}
+	}
+
+	found, err := dbManager.GetCatalogVersion(name, version)
+	if err != nil {
+		return "", nil, err
+	}
+
+	descriptor, _ := found[DESCRIPTOR].(string)
+	checksum, _ := found[CHECKSUM].(string)
+	return descriptor, &Reference{App: name, Version: version, Checksum: checksum}, nil
+}
+
+// RecordedRef returns the catalog version recorded for the app on the agent.
+// If the app was not deployed from the catalog, nil is returned.
+func RecordedRef(agent map[string]interface{}, appId string) *Reference {
+	refs, _ := agent[CATALOG].(map[string]interface{})
+	ref, ok := refs[appId].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	name, _ := ref[APP].(string)
+	version, _ := ref[VERSION].(string)
+	return &Reference{App: name, Version: version}
+}
+
+// Record records the catalog version of the app which is deployed or updated on the agent
+// by the reference. If the reference is nil, i.e. the app is changed by a descriptor
+// which is not from the catalog, the catalog version recorded before is removed.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func Record(dbManager db.DBManager, agent map[string]interface{}, appId string, ref *Reference) error {
+	agentId, _ := agent["id"].(string)
+	if ref != nil {
+		return dbManager.UpdateAgentCatalog(agentId, appId, ref.App, ref.Version)
+	}
+	if RecordedRef(agent, appId) != nil {
+		return dbManager.UpdateAgentCatalog(agentId, appId, "", "")
+	}
+	return nil
+}
+
+// Checksum returns the hex encoded SHA-256 checksum of the descriptor.
+func Checksum(descriptor string) string {
+	sum := sha256.Sum256([]byte(descriptor))
+	return hex.EncodeToString(sum[:])
+}
+
+// getDescription returns the description field of body.
+// If the field is not given, an empty string will be returned.
+func getDescription(bodyMap map[string]interface{}) (string, error) {
+	value, exists := bodyMap[DESCRIPTION]
+	if !exists {
+		return "", nil
+	}
+
+	description, ok := value.(string)
+	if !ok {
+		return "", errors.InvalidParam{Message: "description should be a string"}
+	}
+	return description, nil
+}
+
+// convertJsonToMap converts JSON data into a map.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func convertJsonToMap(jsonStr string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	err := json.Unmarshal([]byte(jsonStr), &result)
+	if err != nil {
+		return nil, errors.InvalidJSON{Message: "Unmarshalling Failed"}
+	}
+	return result, err
+}
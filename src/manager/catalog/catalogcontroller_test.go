@@ -0,0 +1,354 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package catalog
+
+import (
+	"commons/errors"
+	"commons/results"
+	"context"
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	"testing"
+	"time"
+)
+
+const (
+	name       = "line-monitor"
+	version    = "1.4.0"
+	agentId    = "000000000000000000000001"
+	appId      = "000000000000000000000002"
+	descriptor = "services:\n  monitor:\n    image: monitor:1.4.0\n"
+)
+
+var (
+	appMap          = map[string]interface{}{"name": name, "description": "", "versions": []map[string]interface{}{}}
+	versionMap      = map[string]interface{}{"name": name, "version": version, "descriptor": descriptor, "checksum": Checksum(descriptor)}
+	notFoundError   = errors.NotFound{Kind: errors.CATALOG, ID: name}
+	connectionError = errors.DBConnectionError{}
+)
+
+var controller CatalogInterface
+
+func init() {
+	controller = CatalogController{}
+}
+
+func TestCalledCreateApp_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetCatalogApp(name).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().AddCatalogApp(name, "monitor").Return(appMap, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.CreateApp(context.Background(), `{"name":"line-monitor","description":"monitor"}`)
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res[NAME] != name {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledCreateAppWhenAppExists_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetCatalogApp(name).Return(appMap, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.CreateApp(context.Background(), `{"name":"line-monitor"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if !errors.Is(err, errors.InvalidParam{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	}
+}
+
+func TestCalledCreateAppWithInvalidParams_ExpectErrorReturn(t *testing.T) {
+	testList := []struct {
+		name string
+		body string
+	}{
+		{"UppercaseName", `{"name":"Line-Monitor"}`},
+		{"NameWithSlash", `{"name":"line/monitor"}`},
+		{"NumberDescription", `{"name":"line-monitor","description":1}`},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			code, _, err := controller.CreateApp(context.Background(), test.body)
+
+			if code != results.ERROR {
+				t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+			}
+
+			if !errors.Is(err, errors.InvalidParam{}) {
+				t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+			}
+		})
+	}
+}
+
+func TestCalledGetAppsWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetApps(context.Background())
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBConnectionError", err)
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledAddVersion_ExpectChecksumReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	now = func() time.Time { return time.Unix(100, 0) }
+	defer func() { now = time.Now }()
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetCatalogApp(name).Return(appMap, nil),
+		dbManagerMockObj.EXPECT().AddCatalogVersion(name, version, "", descriptor, Checksum(descriptor), int64(100)).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	body := `{"version":"1.4.0","descriptor":"services:\n  monitor:\n    image: monitor:1.4.0\n"}`
+	code, res, err := controller.AddVersion(context.Background(), name, body)
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res[CHECKSUM] != Checksum(descriptor) || res[CREATED_AT] != int64(100) {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledAddVersionWithExistingVersion_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	existing := map[string]interface{}{"name": name, "versions": []map[string]interface{}{{"version": version}}}
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetCatalogApp(name).Return(existing, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.AddVersion(context.Background(), name, `{"version":"1.4.0","descriptor":"services:"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if !errors.Is(err, errors.InvalidParam{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	}
+}
+
+func TestCalledAddVersionWithoutDescriptor_ExpectErrorReturn(t *testing.T) {
+	code, _, err := controller.AddVersion(context.Background(), name, `{"version":"1.4.0","descriptor":" "}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+	case errors.InvalidJSON:
+	}
+}
+
+func TestCalledResolveWithComposeFile_ExpectBodyReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	for _, body := range []string{descriptor, `{"services":{"monitor":{"image":"monitor"}}}`} {
+		resolved, ref, err := Resolve(dbManagerMockObj, body)
+
+		if err != nil {
+			t.Errorf("Unexpected err: %s", err.Error())
+		}
+
+		if resolved != body || ref != nil {
+			t.Errorf("Expected body: %s, actual body: %s, reference: %v", body, resolved, ref)
+		}
+	}
+}
+
+func TestCalledResolveWithReference_ExpectDescriptorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().GetCatalogVersion(name, version).Return(versionMap, nil),
+	)
+
+	resolved, ref, err := Resolve(dbManagerMockObj, `{"app":"line-monitor","version":"1.4.0"}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if resolved != descriptor {
+		t.Errorf("Expected descriptor: %s, actual descriptor: %s", descriptor, resolved)
+	}
+
+	expectedRef := Reference{App: name, Version: version, Checksum: Checksum(descriptor)}
+	if ref == nil || *ref != expectedRef {
+		t.Errorf("Expected reference: %v, actual reference: %v", expectedRef, ref)
+	}
+}
+
+func TestCalledResolveWithDescriptor_ExpectDescriptorReturn(t *testing.T) {
+	body := `{"descriptor":"services:\n  monitor:\n    image: monitor:1.4.0\n","variables":{"agentId":{"station":"S-12"}}}`
+
+	resolved, ref, err := Resolve(nil, body)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expected := "services:\n  monitor:\n    image: monitor:1.4.0\n"
+	if resolved != expected || ref != nil {
+		t.Errorf("Expected descriptor: %s, actual descriptor: %s, reference: %v", expected, resolved, ref)
+	}
+}
+
+func TestCalledResolveWithEmptyDescriptor_ExpectErrorReturn(t *testing.T) {
+	_, _, err := Resolve(nil, `{"descriptor":""}`)
+
+	if !errors.Is(err, errors.InvalidParam{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	}
+}
+
+func TestCalledResolveWithUnknownVersion_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().GetCatalogVersion(name, "9.9.9").Return(nil, notFoundError),
+	)
+
+	_, _, err := Resolve(dbManagerMockObj, `{"app":"line-monitor","version":"9.9.9"}`)
+
+	if !errors.Is(err, errors.NotFound{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	}
+}
+
+func TestCalledResolveWithoutVersion_ExpectErrorReturn(t *testing.T) {
+	_, _, err := Resolve(nil, `{"app":"line-monitor"}`)
+
+	if !errors.Is(err, errors.InvalidParam{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	}
+}
+
+func TestCalledRecord_ExpectCatalogVersionFollowsReference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	agent := map[string]interface{}{"id": agentId}
+	recorded := map[string]interface{}{"id": agentId, "catalog": map[string]interface{}{
+		appId: map[string]interface{}{"app": name, "version": "1.3.0"},
+	}}
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, name, version).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, "", "").Return(nil),
+	)
+
+	if err := Record(dbManagerMockObj, agent, appId, &Reference{App: name, Version: version}); err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	// A descriptor not from the catalog removes the recorded version.
+	if err := Record(dbManagerMockObj, recorded, appId, nil); err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+	// Nothing is recorded for an app which was not deployed from the catalog.
+	if err := Record(dbManagerMockObj, agent, appId, nil); err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
@@ -0,0 +1,39 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package catalog
+
+import "context"
+
+type CatalogInterface interface {
+	// CreateApp adds a new app with the name and the description given in body to the catalog.
+	CreateApp(ctx context.Context, body string) (int, map[string]interface{}, error)
+
+	// GetApps returns all apps in the catalog as an array.
+	GetApps(ctx context.Context) (int, map[string]interface{}, error)
+
+	// GetApp returns the app with the name and its versions.
+	GetApp(ctx context.Context, name string) (int, map[string]interface{}, error)
+
+	// DeleteApp removes the app with the name and all its versions from the catalog.
+	DeleteApp(ctx context.Context, name string) (int, error)
+
+	// AddVersion adds a new version of the descriptor given in body to the app with the name.
+	AddVersion(ctx context.Context, name string, body string) (int, map[string]interface{}, error)
+
+	// GetVersion returns the version of the app with the name including the descriptor.
+	GetVersion(ctx context.Context, name string, version string) (int, map[string]interface{}, error)
+}
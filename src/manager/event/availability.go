@@ -0,0 +1,173 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package event
+
+import (
+	"db"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	OBSERVED       = "observed"    // used to indicate the seconds in which agents were registered.
+	CONNECTED_TIME = "connected"   // used to indicate the seconds in which agents were connected.
+	UPTIME         = "uptime"      // used to indicate the percentage of connected time to observed time.
+	DISCONNECTS    = "disconnects" // used to indicate the number of disconnects.
+	MTBD           = "mtbd"        // used to indicate the mean time between disconnects in seconds.
+)
+
+// statusTypes are the types of events which change the status of agents.
+var statusTypes = []string{REGISTERED, UNREGISTERED, CONNECTED, DISCONNECTED}
+
+// Summary is the availability of agents in a period.
+type Summary struct {
+	Observed    int64 // seconds in which agents were registered.
+	Connected   int64 // seconds in which agents were connected.
+	Disconnects int   // number of changes from connected to disconnected.
+}
+
+// Add returns the availability of two sets of agents taken together.
+func (summary Summary) Add(other Summary) Summary {
+	return Summary{
+		Observed:    summary.Observed + other.Observed,
+		Connected:   summary.Connected + other.Connected,
+		Disconnects: summary.Disconnects + other.Disconnects,
+	}
+}
+
+// ToMap converts Summary object into a map.
+// Uptime is rounded to two decimal places, and is 0 if nothing was observed.
+// The mean time between disconnects is the connected time divided by the number of disconnects,
+// and is not included if there was no disconnect.
+func (summary Summary) ToMap() map[string]interface{} {
+	uptime := 0.0
+	if summary.Observed > 0 {
+		uptime = float64(summary.Connected) * 100 / float64(summary.Observed)
+		uptime = math.Round(uptime*100) / 100
+	}
+
+	result := map[string]interface{}{
+		OBSERVED:       summary.Observed,
+		CONNECTED_TIME: summary.Connected,
+		UPTIME:         uptime,
+		DISCONNECTS:    summary.Disconnects,
+	}
+	if summary.Disconnects > 0 {
+		result[MTBD] = summary.Connected / int64(summary.Disconnects)
+	}
+	return result
+}
+
+// Summarize returns the availability of an agent in the period from 'from' to 'to'
+// computed from the events of the agent in the period.
+// initial is the type of the last event which changed the status of the agent before the period,
+// or an empty string if there is no such event.
+// The time before an agent is registered, or after it is unregistered, is not observed.
+func Summarize(initial string, events []map[string]interface{}, from int64, to int64) Summary {
+	sorted := make([]map[string]interface{}, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return timeOf(sorted[i]) < timeOf(sorted[j])
+	})
+
+	summary := Summary{}
+	status := statusAfter(initial, "")
+	last := from
+	for _, event := range sorted {
+		at := timeOf(event)
+		if at < from {
+			at = from
+		}
+		if at > to {
+			break
+		}
+		summary.add(status, at-last)
+		last = at
+
+		eventType, _ := event["type"].(string)
+		next := statusAfter(eventType, status)
+		if status == CONNECTED && next == DISCONNECTED {
+			summary.Disconnects++
+		}
+		status = next
+	}
+	summary.add(status, to-last)
+	return summary
+}
+
+// GetAvailability returns the availability of the agent specified by agentId parameter
+// in the period from 'from' to 'to', using the events stored in the database.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func GetAvailability(dbManager db.Command, agentId string, from int64, to int64) (Summary, error) {
+	// Get the last event before the period to know the status at the start of the period.
+	filter := map[string]string{
+		"agent": agentId,
+		"type":  strings.Join(statusTypes, ","),
+		"to":    strconv.FormatInt(from-1, 10),
+	}
+	previous, _, err := dbManager.GetEventsByQuery(filter, "-id", 1, "")
+	if err != nil {
+		return Summary{}, err
+	}
+	initial := ""
+	if len(previous) != 0 {
+		initial, _ = previous[0]["type"].(string)
+	}
+
+	filter["from"] = strconv.FormatInt(from, 10)
+	filter["to"] = strconv.FormatInt(to, 10)
+	events, _, err := dbManager.GetEventsByQuery(filter, "", 0, "")
+	if err != nil {
+		return Summary{}, err
+	}
+	return Summarize(initial, events, from, to), nil
+}
+
+// add adds the duration in seconds to the summary according to the status.
+func (summary *Summary) add(status string, duration int64) {
+	switch status {
+	case CONNECTED:
+		summary.Observed += duration
+		summary.Connected += duration
+	case DISCONNECTED:
+		summary.Observed += duration
+	}
+}
+
+// statusAfter returns the status of an agent after the event of the given type.
+// Events which do not change the status keep the current status.
+func statusAfter(eventType string, current string) string {
+	switch eventType {
+	case REGISTERED, CONNECTED:
+		return CONNECTED
+	case DISCONNECTED:
+		return DISCONNECTED
+	case UNREGISTERED:
+		return ""
+	}
+	return current
+}
+
+// timeOf returns the time of the event in seconds since the epoch.
+func timeOf(event map[string]interface{}) int64 {
+	value, _ := event["time"].(int64)
+	return value
+}
@@ -0,0 +1,132 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package event
+
+import (
+	"commons/errors"
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	"reflect"
+	"testing"
+)
+
+const agentId = "000000000000000000000001"
+
+func newEvent(eventType string, time int64) map[string]interface{} {
+	return map[string]interface{}{"agentid": agentId, "type": eventType, "time": time}
+}
+
+func TestCalledSummarize_ExpectAvailabilityReturn(t *testing.T) {
+	testList := []struct {
+		name     string
+		initial  string
+		events   []map[string]interface{}
+		expected Summary
+	}{
+		{"NoEvent", "", nil, Summary{}},
+		{"ConnectedBefore", CONNECTED, nil, Summary{Observed: 1000, Connected: 1000}},
+		{"DisconnectedBefore", DISCONNECTED, nil, Summary{Observed: 1000}},
+		{"UnregisteredBefore", UNREGISTERED, nil, Summary{}},
+		{"RegisteredInPeriod", "", []map[string]interface{}{
+			newEvent(REGISTERED, 1400),
+		}, Summary{Observed: 600, Connected: 600}},
+		{"DisconnectedTwice", REGISTERED, []map[string]interface{}{
+			newEvent(DISCONNECTED, 1200),
+			newEvent(CONNECTED, 1300),
+			newEvent(ADDRESS_CHANGED, 1350),
+			newEvent(DISCONNECTED, 1500),
+			newEvent(DISCONNECTED, 1600),
+			newEvent(CONNECTED, 1900),
+		}, Summary{Observed: 1000, Connected: 500, Disconnects: 2}},
+		{"UnorderedEvents", CONNECTED, []map[string]interface{}{
+			newEvent(CONNECTED, 1300),
+			newEvent(DISCONNECTED, 1200),
+			newEvent(UNREGISTERED, 1800),
+		}, Summary{Observed: 800, Connected: 700, Disconnects: 1}},
+	}
+
+	for _, test := range testList {
+		summary := Summarize(test.initial, test.events, 1000, 2000)
+
+		if summary != test.expected {
+			t.Errorf("Expected summary: %v, actual summary: %v for %s", test.expected, summary, test.name)
+		}
+	}
+}
+
+func TestCalledToMap_ExpectUptimeAndMTBDReturn(t *testing.T) {
+	summary := Summary{Observed: 3000, Connected: 2000}.Add(Summary{Observed: 1000, Connected: 998, Disconnects: 2})
+
+	expected := map[string]interface{}{
+		OBSERVED:       int64(4000),
+		CONNECTED_TIME: int64(2998),
+		UPTIME:         74.95,
+		DISCONNECTS:    2,
+		MTBD:           int64(1499),
+	}
+	if res := summary.ToMap(); !reflect.DeepEqual(res, expected) {
+		t.Errorf("Expected res: %v, actual res: %v", expected, res)
+	}
+
+	res := Summary{}.ToMap()
+	if _, exists := res[MTBD]; exists || res[UPTIME] != 0.0 {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledGetAvailability_ExpectEventsInPeriodUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	types := "registered,unregistered,connected,disconnected"
+	previous := map[string]string{"agent": agentId, "type": types, "to": "999"}
+	period := map[string]string{"agent": agentId, "type": types, "from": "1000", "to": "2000"}
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().GetEventsByQuery(previous, "-id", 1, "").Return([]map[string]interface{}{newEvent(CONNECTED, 500)}, "", nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(period, "", 0, "").Return([]map[string]interface{}{newEvent(DISCONNECTED, 1500)}, "", nil),
+	)
+
+	summary, err := GetAvailability(dbManagerMockObj, agentId, 1000, 2000)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	expected := Summary{Observed: 1000, Connected: 500, Disconnects: 1}
+	if summary != expected {
+		t.Errorf("Expected summary: %v, actual summary: %v", expected, summary)
+	}
+}
+
+func TestCalledGetAvailabilityWhenDBReturnsError_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	dbManagerMockObj.EXPECT().GetEventsByQuery(gomock.Any(), "-id", 1, "").Return(nil, "", errors.DBOperationError{})
+
+	_, err := GetAvailability(dbManagerMockObj, agentId, 1000, 2000)
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBOperationError", err)
+	case errors.DBOperationError:
+	}
+}
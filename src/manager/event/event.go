@@ -0,0 +1,85 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package manager/event defines events recorded in the history of agents,
+// and computes the availability of agents from the history.
+package event
+
+import (
+	"commons/errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Types of events.
+const (
+	REGISTERED      = "registered"   // an agent is registered, and is regarded as connected.
+	UNREGISTERED    = "unregistered" // an agent is unregistered.
+	CONNECTED       = "connected"    // a ping is received from a disconnected agent.
+	DISCONNECTED    = "disconnected" // a ping is not received in interval time.
+	ADDRESS_CHANGED = "address"      // the address of an agent is changed.
+	APP_FOUND       = "appfound"     // an app which is not recorded is found on an agent.
+	APP_LOST        = "applost"      // a recorded app is not found on an agent.
+	STALE           = "stale"        // an agent has been disconnected for the retention period.
+)
+
+const (
+	EVENTS = "events" // used to indicate a list of events.
+	FROM   = "from"   // used to indicate the start of a period in seconds since the epoch.
+	TO     = "to"     // used to indicate the end of a period in seconds since the epoch.
+
+	// DEFAULT_PERIOD is the length of the period in seconds used when 'from' is not given.
+	DEFAULT_PERIOD = 7 * 24 * 60 * 60
+)
+
+// ParsePeriod returns the period given by 'from' and 'to' query parameters.
+// If 'to' is not given, the period ends at now, and if 'from' is not given,
+// the period starts DEFAULT_PERIOD seconds before the end.
+// If successful, this function returns an error as nil.
+// otherwise, InvalidParam error will be returned.
+func ParsePeriod(query url.Values, now time.Time) (int64, int64, error) {
+	to, err := parseTime(query, TO, now.Unix())
+	if err != nil {
+		return 0, 0, err
+	}
+	from, err := parseTime(query, FROM, to-DEFAULT_PERIOD)
+	if err != nil {
+		return 0, 0, err
+	}
+	if from >= to {
+		return 0, 0, errors.InvalidParam{Message: "from should be earlier than to"}
+	}
+	return from, to, nil
+}
+
+// parseTime returns the time in seconds since the epoch given by the named query parameter,
+// or the defaultTime if the parameter is not given.
+func parseTime(query url.Values, name string, defaultTime int64) (int64, error) {
+	values, exists := query[name]
+	if !exists {
+		return defaultTime, nil
+	}
+	if len(values) != 1 {
+		return 0, errors.InvalidParam{Message: "duplicated query parameter: " + name}
+	}
+	value, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || value < 0 {
+		return 0, errors.InvalidParam{Message: name + " should be seconds since the epoch"}
+	}
+	return value, nil
+}
@@ -0,0 +1,65 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package event
+
+import (
+	"commons/errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCalledParsePeriod_ExpectPeriodReturn(t *testing.T) {
+	now := time.Unix(1000000, 0)
+
+	testList := []struct {
+		query string
+		from  int64
+		to    int64
+	}{
+		{"", 1000000 - DEFAULT_PERIOD, 1000000},
+		{"to=900000", 900000 - DEFAULT_PERIOD, 900000},
+		{"from=100&to=200", 100, 200},
+		{"from=100", 100, 1000000},
+	}
+
+	for _, test := range testList {
+		query, _ := url.ParseQuery(test.query)
+		from, to, err := ParsePeriod(query, now)
+
+		if err != nil {
+			t.Errorf("Unexpected err: %s", err.Error())
+		}
+
+		if from != test.from || to != test.to {
+			t.Errorf("Expected period: %d-%d, actual period: %d-%d for %s", test.from, test.to, from, to, test.query)
+		}
+	}
+}
+
+func TestCalledParsePeriodWithInvalidQuery_ExpectErrorReturn(t *testing.T) {
+	for _, str := range []string{"from=yesterday", "to=-1", "from=200&to=100", "from=100&to=100", "to=1&to=2"} {
+		query, _ := url.ParseQuery(str)
+		_, _, err := ParsePeriod(query, time.Unix(1000000, 0))
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, str)
+		case errors.InvalidParam:
+		}
+	}
+}
@@ -0,0 +1,628 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package group
+
+import (
+	"commons/config"
+	"commons/errors"
+	"commons/logger"
+	"commons/results"
+	"context"
+	"db"
+	"encoding/json"
+	"manager/catalog"
+	"manager/health"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fields and values of desired states of groups and convergence of members.
+const (
+	PRUNE       = "prune"       // used to indicate whether apps which are not declared are removed.
+	VERSION     = "version"     // used to indicate a version of catalog app.
+	STATE       = "state"       // used to indicate a desired or reported state of an app.
+	CONVERGENCE = "convergence" // used to indicate convergence of members recorded for a group.
+	ACTIONS     = "actions"     // used to indicate actions taken on a member.
+	FAILURES    = "failures"    // used to indicate actions which failed on a member.
+	TIME        = "time"        // used to indicate a time when a member was converged.
+
+	STATE_RUNNING = "running" // used to declare apps which should be running.
+	STATE_STOPPED = "stopped" // used to declare apps which should be stopped.
+
+	STATUS_CONNECTED = "connected" // used to indicate agents which are connected.
+
+	CONVERGED   = "converged"   // used to indicate members which run apps as declared.
+	FAILED      = "failed"      // used to indicate members on which some actions failed.
+	UNREACHABLE = "unreachable" // used to indicate members which are not connected or do not report apps.
+	PENDING     = "pending"     // used to indicate members which are not converged yet.
+)
+
+var converger *health.Prober
+
+// convergences tracks convergences started in the background by changes of groups.
+var convergences sync.WaitGroup
+
+// groupLocks serializes convergences of the same group.
+var groupLocks = struct {
+	sync.Mutex
+	groups map[string]*sync.Mutex
+}{groups: make(map[string]*sync.Mutex)}
+
+// requestConvergence converges the group in the background, and is replaced in tests.
+var requestConvergence = convergeInBackground
+
+// StartConverger starts converging members of groups which declare a desired state
+// every configured interval. Nothing is started if the interval is 0.
+func StartConverger() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	interval := config.Get().Group.ConvergeInterval
+	if interval == 0 {
+		return
+	}
+	converger = health.NewProber(health.SystemClock{}, time.Duration(interval)*time.Second, convergeGroups)
+}
+
+// StopConverger stops converging groups.
+// Running convergences, including the ones started by changes of groups, are waited for
+// until they finish or the given context is done.
+// If successful, this function returns an error as nil.
+// otherwise, the error of the context will be returned.
+func StopConverger(ctx context.Context) error {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	if converger != nil {
+		if err := converger.Stop(ctx); err != nil {
+			return err
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		convergences.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetDesiredState declares the apps which members of the group should run,
+// and starts converging the members in the background.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) SetDesiredState(ctx context.Context, groupId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	bodyMap, err := convertJsonToMap(body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	apps, prune, err := parseDesiredState(db, bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	err = db.SetGroupDesiredState(groupId, apps, prune)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	requestConvergence(groupId)
+
+	res := make(map[string]interface{})
+	res[ID] = groupId
+	res[APPS] = apps
+	res[PRUNE] = prune
+	return results.OK, res, err
+}
+
+// GetDesiredState returns the desired state of the group with the status of the last
+// convergence of each member. Members which are not converged yet are pending.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) GetDesiredState(ctx context.Context, groupId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	desired, err := db.GetGroupDesiredState(groupId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	group, err := db.GetGroup(groupId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	memberIds, _ := group[MEMBERS].([]string)
+	convergence, _ := desired[CONVERGENCE].(map[string]interface{})
+
+	res := make(map[string]interface{})
+	res[ID] = groupId
+	res[APPS] = desired[APPS]
+	res[PRUNE] = desired[PRUNE]
+	res[MEMBERS] = makeConvergenceReports(memberIds, convergence)
+	return results.OK, res, err
+}
+
+// DeleteDesiredState removes the desired state of the group and the convergence of its members.
+// Apps running on the members are left as they are.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) DeleteDesiredState(ctx context.Context, groupId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	err = db.UnsetGroupDesiredState(groupId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	return results.OK, nil, err
+}
+
+// Converge converges members of the group toward its desired state right away,
+// and returns the result of the convergence of each member.
+// If the convergence fails on some members, MULTI_STATUS will be returned.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) Converge(ctx context.Context, groupId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	desired, reports, err := convergeGroup(ctx, db, groupId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	codes := make([]int, len(reports))
+	for i, report := range reports {
+		codes[i] = results.OK
+		if report[STATUS] != CONVERGED {
+			codes[i] = results.ERROR
+		}
+	}
+
+	res := make(map[string]interface{})
+	res[ID] = groupId
+	res[APPS] = desired[APPS]
+	res[PRUNE] = desired[PRUNE]
+	res[MEMBERS] = reports
+	return decideResultCode(codes), res, err
+}
+
+// convergeGroups converges members of each group which declares a desired state.
+func convergeGroups() {
+	logger.Logging(logger.DEBUG, "IN")
+	defer logger.Logging(logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(context.Background())
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+	defer db.Close()
+
+	states, err := db.GetAllGroupDesiredStates()
+	if err != nil {
+		logger.Logging(logger.ERROR, err.Error())
+		return
+	}
+
+	interval := time.Duration(config.Get().Group.ConvergeInterval) * time.Second
+	for _, desired := range states {
+		groupId, _ := desired[ID].(string)
+
+		// A convergence is cancelled if it does not finish until the next one.
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		_, _, err = convergeGroup(ctx, db, groupId)
+		cancel()
+		if err != nil && !errors.Is(err, errors.NotFound{}) {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+	}
+}
+
+// convergeInBackground converges members of the group in the background,
+// e.g. when the desired state or members of the group are changed.
+// Nothing is done if the group does not declare a desired state.
+func convergeInBackground(groupId string) {
+	convergences.Add(1)
+	go func() {
+		defer convergences.Done()
+
+		// Connect to the database.
+		ctx := context.Background()
+		db, err := dbConnector.Connect(ctx)
+		if err != nil {
+			logger.Logging(logger.ERROR, err.Error())
+			return
+		}
+		defer db.Close()
+
+		_, _, err = convergeGroup(ctx, db, groupId)
+		if err != nil && !errors.Is(err, errors.NotFound{}) {
+			logger.Logging(logger.ERROR, err.Error())
+		}
+	}()
+}
+
+// convergeGroup converges each member of the group toward the desired state of the group,
+// with at most the configured number of members at a time, and records the result for each member.
+// The desired state is read after convergences of the same group finish,
+// so that the latest one is always applied.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func convergeGroup(ctx context.Context, dbManager db.DBManager, groupId string) (map[string]interface{}, []map[string]interface{}, error) {
+	unlock := lockGroup(groupId)
+	defer unlock()
+
+	desired, err := dbManager.GetGroupDesiredState(groupId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members, err := dbManager.GetGroupMembers(groupId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apps, _ := desired[APPS].([]map[string]interface{})
+	prune, _ := desired[PRUNE].(bool)
+
+	reports := make([]map[string]interface{}, len(members))
+	health.ProbeAll(len(members), config.Get().Group.ConvergeConcurrency, func(index int) {
+		reports[index] = convergeMember(ctx, dbManager, members[index], apps, prune)
+	})
+
+	for _, report := range reports {
+		err = dbManager.UpdateGroupConvergence(groupId, report[ID].(string), report[STATUS].(string),
+			report[ACTIONS].([]string), report[FAILURES].([]string), report[TIME].(int64))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return desired, reports, err
+}
+
+// lockGroup locks convergences of the group, and returns a function to unlock them.
+func lockGroup(groupId string) func() {
+	groupLocks.Lock()
+	lock, exists := groupLocks.groups[groupId]
+	if !exists {
+		lock = &sync.Mutex{}
+		groupLocks.groups[groupId] = lock
+	}
+	groupLocks.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// convergeMember compares the apps which the member reports with the declared apps,
+// and deploys missing apps, updates apps of other versions, starts or stops apps
+// whose state differs and, if prune is true, deletes apps which are not declared.
+// An app is regarded as an instance of a declared app if it was deployed from the catalog app.
+// Apps whose state is not reported by the member are not started or stopped.
+// The result is returned as a report of the member.
+func convergeMember(ctx context.Context, dbManager db.DBManager, agent map[string]interface{},
+	apps []map[string]interface{}, prune bool) map[string]interface{} {
+	report := &memberReport{id: agent[ID].(string), actions: make([]string, 0), failures: make([]string, 0)}
+	if agent[STATUS] != STATUS_CONNECTED {
+		return report.make(UNREACHABLE)
+	}
+
+	members := []map[string]interface{}{agent}
+	codes, respStr := httpMessenger.InfoApps(ctx, getMemberAddress(members))
+	if !isSuccessCode(codes[0]) {
+		report.failures = append(report.failures, "list apps: "+describeResponse(codes[0], respStr[0]))
+		return report.make(UNREACHABLE)
+	}
+
+	appIds, states, err := parseAppStates(respStr[0])
+	if err != nil {
+		report.failures = append(report.failures, "list apps: "+err.Error())
+		return report.make(UNREACHABLE)
+	}
+
+	matched := make(map[string]bool)
+	for _, app := range apps {
+		name, _ := app[APP].(string)
+		version, _ := app[VERSION].(string)
+		state, _ := app[STATE].(string)
+
+		reference, _ := json.Marshal(map[string]string{APP: name, VERSION: version})
+		appId, recorded := findApp(agent, appIds, matched, name)
+		current := states[appId]
+		if appId != "" {
+			// The app is not deleted by pruning even if it fails to be updated.
+			matched[appId] = true
+		}
+
+		switch {
+		case appId == "":
+			code, resp, err := deployApp(ctx, dbManager, members, string(reference))
+			if !report.record("deploy "+name+" "+version, code, resp, err) {
+				continue
+			}
+			// Deployed apps are started by the agent.
+			appId, _ = resp[ID].(string)
+			current = STATE_RUNNING
+		case recorded != version:
+			code, resp, err := updateAppInfo(ctx, dbManager, members, appId, string(reference))
+			if !report.record("update "+name+" "+version, code, resp, err) {
+				continue
+			}
+		}
+
+		switch {
+		case state == STATE_RUNNING && current != "" && current != STATE_RUNNING:
+			code, resp, err := controlApp(ctx, members, func(address []map[string]interface{}) ([]int, []string) {
+				return httpMessenger.StartApp(ctx, address, appId)
+			})
+			report.record("start "+name, code, resp, err)
+		case state == STATE_STOPPED && current == STATE_RUNNING:
+			code, resp, err := controlApp(ctx, members, func(address []map[string]interface{}) ([]int, []string) {
+				return httpMessenger.StopApp(ctx, address, appId)
+			})
+			report.record("stop "+name, code, resp, err)
+		}
+	}
+
+	if prune {
+		for _, appId := range appIds {
+			if !matched[appId] {
+				code, resp, err := deleteApp(ctx, dbManager, members, appId)
+				report.record("delete "+appId, code, resp, err)
+			}
+		}
+	}
+
+	if len(report.failures) > 0 {
+		return report.make(FAILED)
+	}
+	return report.make(CONVERGED)
+}
+
+// memberReport collects actions taken to converge a member.
+type memberReport struct {
+	id       string
+	actions  []string
+	failures []string
+}
+
+// record appends the action to the actions taken if it succeeded, or to the failures otherwise.
+// It returns true if the action succeeded.
+func (report *memberReport) record(action string, code int, resp map[string]interface{}, err error) bool {
+	if err == nil && isSuccessCode(code) {
+		report.actions = append(report.actions, action)
+		return true
+	}
+
+	message := strconv.Itoa(code)
+	if err != nil {
+		message = err.Error()
+	} else if responses, ok := resp[RESPONSES].([]map[string]interface{}); ok && len(responses) > 0 {
+		if value, ok := responses[0][ERROR_MESSAGE].(string); ok && value != "" {
+			message = value
+		}
+	}
+	report.failures = append(report.failures, action+": "+message)
+	return false
+}
+
+// make returns the report of the member with the status as a map.
+func (report *memberReport) make(status string) map[string]interface{} {
+	return map[string]interface{}{
+		ID:       report.id,
+		STATUS:   status,
+		ACTIONS:  report.actions,
+		FAILURES: report.failures,
+		TIME:     now().Unix(),
+	}
+}
+
+// describeResponse returns the message of an error response of an agent,
+// or the code if the response has no message.
+func describeResponse(code int, respStr string) string {
+	respMap, err := convertJsonToMap(respStr)
+	if err == nil {
+		if message, ok := respMap[ERROR_MESSAGE].(string); ok && message != "" {
+			return message
+		}
+	}
+	return strconv.Itoa(code)
+}
+
+// findApp returns the id of an app reported by the agent which was deployed from the catalog app
+// specified by name parameter with the recorded version, skipping apps which are already matched.
+// If there is no such app, an empty id is returned.
+func findApp(agent map[string]interface{}, appIds []string, matched map[string]bool, name string) (string, string) {
+	for _, appId := range appIds {
+		if matched[appId] {
+			continue
+		}
+		ref := catalog.RecordedRef(agent, appId)
+		if ref != nil && ref.App == name {
+			return appId, ref.Version
+		}
+	}
+	return "", ""
+}
+
+// parseAppStates returns the ids of apps in a list of apps reported by an agent,
+// with the state of each app which reports it.
+// Each app is either an id or an object which has an id and optionally a state.
+// If the list is not valid, an InternalServerError is returned.
+func parseAppStates(respStr string) ([]string, map[string]string, error) {
+	invalid := errors.InternalServerError{Message: "invalid list of apps from agent"}
+
+	respMap, err := convertJsonToMap(respStr)
+	if err != nil {
+		return nil, nil, invalid
+	}
+	apps, ok := respMap[APPS].([]interface{})
+	if !ok {
+		return nil, nil, invalid
+	}
+
+	appIds := make([]string, 0, len(apps))
+	states := make(map[string]string)
+	for _, app := range apps {
+		state := ""
+		if object, ok := app.(map[string]interface{}); ok {
+			app = object[ID]
+			state, _ = object[STATE].(string)
+		}
+		appId, ok := app.(string)
+		if !ok || appId == "" {
+			return nil, nil, invalid
+		}
+		appIds = append(appIds, appId)
+		states[appId] = state
+	}
+	return appIds, states, nil
+}
+
+// parseDesiredState returns the declared apps and the prune field of the body of a desired state.
+// Each app has app, version and state fields. The state is running if it is not given.
+// Every declared version should exist in the catalog.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func parseDesiredState(dbManager db.DBManager, bodyMap map[string]interface{}) ([]map[string]string, bool, error) {
+	value, exists := bodyMap[APPS]
+	if !exists {
+		return nil, false, errors.InvalidJSON{Message: "apps field is required"}
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, false, errors.InvalidParam{Message: "apps field should be a list of apps"}
+	}
+
+	prune := false
+	if value, exists := bodyMap[PRUNE]; exists {
+		if prune, ok = value.(bool); !ok {
+			return nil, false, errors.InvalidParam{Message: "prune field should be a boolean"}
+		}
+	}
+
+	apps := make([]map[string]string, len(list))
+	declared := make(map[string]bool)
+	for i, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false, errors.InvalidParam{Message: "apps field should be a list of apps"}
+		}
+
+		name, _ := object[APP].(string)
+		version, _ := object[VERSION].(string)
+		if name == "" || version == "" {
+			return nil, false, errors.InvalidParam{Message: "app and version of a declared app are required"}
+		}
+		if declared[name] {
+			return nil, false, errors.InvalidParam{Message: "app is declared more than once", Kind: errors.CATALOG, ID: name}
+		}
+		declared[name] = true
+
+		state := STATE_RUNNING
+		if value, exists := object[STATE]; exists {
+			state, _ = value.(string)
+			if state != STATE_RUNNING && state != STATE_STOPPED {
+				return nil, false, errors.InvalidParam{Message: "state of a declared app should be running or stopped", Kind: errors.CATALOG, ID: name}
+			}
+		}
+
+		_, err := dbManager.GetCatalogVersion(name, version)
+		if err != nil {
+			return nil, false, err
+		}
+		apps[i] = map[string]string{APP: name, VERSION: version, STATE: state}
+	}
+	return apps, prune, nil
+}
+
+// makeConvergenceReports returns a report of the last convergence of each member.
+// Members which are not converged yet are pending.
+func makeConvergenceReports(memberIds []string, convergence map[string]interface{}) []map[string]interface{} {
+	reports := make([]map[string]interface{}, len(memberIds))
+	for i, agentId := range memberIds {
+		recorded, ok := convergence[agentId].(map[string]interface{})
+		if !ok {
+			reports[i] = map[string]interface{}{ID: agentId, STATUS: PENDING}
+			continue
+		}
+		reports[i] = map[string]interface{}{
+			ID:       agentId,
+			STATUS:   recorded[STATUS],
+			ACTIONS:  recorded[ACTIONS],
+			FAILURES: recorded[FAILURES],
+			TIME:     recorded[TIME],
+		}
+	}
+	return reports
+}
@@ -0,0 +1,428 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package group
+
+import (
+	"commons/errors"
+	"commons/results"
+	"context"
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	msgmocks "messenger/mocks"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	otherAppId      = "000000000000000000000003"
+	convergeAt      = int64(1000)
+	lineMonitorFile = "services:\n  monitor:\n    image: monitor:1.4.0\n"
+	gatewayFile     = "services:\n  gateway:\n    image: gateway:2.0.0\n"
+)
+
+var (
+	agentAddress = []map[string]interface{}{address}
+	declaredApps = []map[string]interface{}{
+		{"app": "line-monitor", "version": "1.4.0", "state": "running"},
+		{"app": "gateway", "version": "2.0.0", "state": "running"},
+	}
+)
+
+// stubConvergence replaces convergences in the background with recording the ids of groups.
+func stubConvergence() *[]string {
+	groupIds := make([]string, 0)
+	requestConvergence = func(groupId string) {
+		groupIds = append(groupIds, groupId)
+	}
+	return &groupIds
+}
+
+// stubNow fixes the time of convergences, and returns a function to restore it.
+func stubNow() func() {
+	now = func() time.Time { return time.Unix(convergeAt, 0) }
+	return func() { now = time.Now }
+}
+
+func TestCalledSetDesiredState_ExpectStateRecordedAndConverged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	apps := []map[string]string{
+		{"app": "line-monitor", "version": "1.4.0", "state": "running"},
+		{"app": "gateway", "version": "2.0.0", "state": "stopped"},
+	}
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("line-monitor", "1.4.0").Return(nil, nil),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("gateway", "2.0.0").Return(nil, nil),
+		dbManagerMockObj.EXPECT().SetGroupDesiredState(groupId, apps, true).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	converged := stubConvergence()
+	defer func() { requestConvergence = convergeInBackground }()
+
+	desired := `{"apps":[{"app":"line-monitor","version":"1.4.0"},{"app":"gateway","version":"2.0.0","state":"stopped"}],"prune":true}`
+	code, res, err := controller.SetDesiredState(context.Background(), groupId, desired)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedRes := map[string]interface{}{"id": groupId, "apps": apps, "prune": true}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+
+	if !reflect.DeepEqual([]string{groupId}, *converged) {
+		t.Errorf("Expected convergence of group: %s, actual: %v", groupId, *converged)
+	}
+}
+
+func TestCalledSetDesiredStateWithInvalidParams_ExpectErrorReturn(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected error
+	}{
+		"WithoutApps":     {`{"prune":true}`, errors.InvalidJSON{}},
+		"AppsNotList":     {`{"apps":"line-monitor"}`, errors.InvalidParam{}},
+		"WithoutVersion":  {`{"apps":[{"app":"line-monitor"}]}`, errors.InvalidParam{}},
+		"UnknownState":    {`{"apps":[{"app":"line-monitor","version":"1.4.0","state":"paused"}]}`, errors.InvalidParam{}},
+		"PruneNotBoolean": {`{"apps":[],"prune":"yes"}`, errors.InvalidParam{}},
+		"DuplicatedApp": {`{"apps":[{"app":"line-monitor","version":"1.4.0"},{"app":"line-monitor","version":"1.3.0"}]}`,
+			errors.InvalidParam{Kind: errors.CATALOG, ID: "line-monitor"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+			dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil)
+			dbManagerMockObj.EXPECT().GetCatalogVersion(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			dbManagerMockObj.EXPECT().Close()
+			// pass mockObj to a real object.
+			dbConnector = dbConnectionMockObj
+
+			code, _, err := controller.SetDesiredState(context.Background(), groupId, test.body)
+
+			if code != results.ERROR {
+				t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+			}
+
+			if !errors.Is(err, test.expected) {
+				t.Errorf("Expected err: %T, actual err: %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestCalledSetDesiredStateWithUnknownVersion_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	notFoundError := errors.NotFound{Kind: errors.CATALOG, ID: "line-monitor:9.9.9"}
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("line-monitor", "9.9.9").Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	desired := `{"apps":[{"app":"line-monitor","version":"9.9.9"}]}`
+	code, _, err := controller.SetDesiredState(context.Background(), groupId, desired)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if !errors.Is(err, notFoundError) {
+		t.Errorf("Expected err: %v, actual err: %v", notFoundError, err)
+	}
+}
+
+func TestCalledGetDesiredState_ExpectConvergenceOfMembersReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	convergence := map[string]interface{}{
+		"status":   "converged",
+		"actions":  []string{"deploy gateway 2.0.0"},
+		"failures": []string{},
+		"time":     convergeAt,
+	}
+	desired := map[string]interface{}{
+		"id":          groupId,
+		"apps":        declaredApps,
+		"prune":       false,
+		"convergence": map[string]interface{}{agentId: convergence},
+	}
+	groupWithMembers := map[string]interface{}{
+		"id":      groupId,
+		"members": []string{agentId, otherAppId},
+	}
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupDesiredState(groupId).Return(desired, nil),
+		dbManagerMockObj.EXPECT().GetGroup(groupId).Return(groupWithMembers, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetDesiredState(context.Background(), groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedMembers := []map[string]interface{}{
+		{"id": agentId, "status": "converged", "actions": []string{"deploy gateway 2.0.0"}, "failures": []string{}, "time": convergeAt},
+		{"id": otherAppId, "status": "pending"},
+	}
+	if !reflect.DeepEqual(expectedMembers, res["members"]) {
+		t.Errorf("Expected members: %v, actual members: %v", expectedMembers, res["members"])
+	}
+}
+
+func TestCalledDeleteDesiredState_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().UnsetGroupDesiredState(groupId).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.DeleteDesiredState(context.Background(), groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+}
+
+func TestCalledConverge_ExpectMemberConverged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer stubNow()()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	// The member runs an exited instance of an older version of line-monitor,
+	// and an app which is not deployed from the catalog.
+	member := map[string]interface{}{
+		"id":      agentId,
+		"host":    host,
+		"port":    port,
+		"status":  "connected",
+		"apps":    []string{appId, otherAppId},
+		"catalog": map[string]interface{}{appId: map[string]interface{}{"app": "line-monitor", "version": "1.3.0"}},
+	}
+	desired := map[string]interface{}{"id": groupId, "apps": declaredApps, "prune": true}
+	lineMonitor := map[string]interface{}{"descriptor": lineMonitorFile, "checksum": "1"}
+	gateway := map[string]interface{}{"descriptor": gatewayFile, "checksum": "2"}
+	reported := `{"apps":[{"id":"` + appId + `","state":"exited"},{"id":"` + otherAppId + `","state":"running"}]}`
+	deployed := []string{`{"id":"000000000000000000000004"}`}
+	actions := []string{"update line-monitor 1.4.0", "start line-monitor", "deploy gateway 2.0.0", "delete " + otherAppId}
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupDesiredState(groupId).Return(desired, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return([]map[string]interface{}{member}, nil),
+		msgMockObj.EXPECT().InfoApps(gomock.Any(), agentAddress).Return([]int{results.OK}, []string{reported}),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("line-monitor", "1.4.0").Return(lineMonitor, nil),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), agentAddress, appId, lineMonitorFile).Return([]int{results.OK}, []string{`{}`}),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, "line-monitor", "1.4.0").Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, lineMonitorFile, gomock.Any(), "line-monitor", "1.4.0", gomock.Any()).Return(2, nil),
+		msgMockObj.EXPECT().StartApp(gomock.Any(), agentAddress, appId).Return([]int{results.OK}, []string{`{}`}),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("gateway", "2.0.0").Return(gateway, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), agentAddress, gatewayFile).Return([]int{results.OK}, deployed),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, "000000000000000000000004").Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, "000000000000000000000004", "gateway", "2.0.0").Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, "000000000000000000000004", gatewayFile, gomock.Any(), "gateway", "2.0.0", gomock.Any()).Return(1, nil),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), agentAddress, otherAppId).Return([]int{results.OK}, []string{`{}`}),
+		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, otherAppId).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateGroupConvergence(groupId, agentId, "converged", actions, []string{}, convergeAt).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.Converge(context.Background(), groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	expectedMembers := []map[string]interface{}{
+		{"id": agentId, "status": "converged", "actions": actions, "failures": []string{}, "time": convergeAt},
+	}
+	if !reflect.DeepEqual(expectedMembers, res["members"]) {
+		t.Errorf("Expected members: %v, actual members: %v", expectedMembers, res["members"])
+	}
+}
+
+func TestCalledConvergeWhenActionsFailed_ExpectFailuresReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer stubNow()()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	// line-monitor runs the declared version but should be stopped, and the other member is disconnected.
+	member := map[string]interface{}{
+		"id":      agentId,
+		"host":    host,
+		"port":    port,
+		"status":  "connected",
+		"catalog": map[string]interface{}{appId: map[string]interface{}{"app": "line-monitor", "version": "1.4.0"}},
+	}
+	disconnected := map[string]interface{}{"id": otherAppId, "status": "disconnected"}
+	apps := []map[string]interface{}{
+		{"app": "line-monitor", "version": "1.4.0", "state": "stopped"},
+		{"app": "gateway", "version": "2.0.0", "state": "running"},
+	}
+	desired := map[string]interface{}{"id": groupId, "apps": apps, "prune": false}
+	gateway := map[string]interface{}{"descriptor": gatewayFile, "checksum": "2"}
+	reported := `{"apps":[{"id":"` + appId + `","state":"running"}]}`
+	failures := []string{"deploy gateway 2.0.0: no space left"}
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupDesiredState(groupId).Return(desired, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return([]map[string]interface{}{member, disconnected}, nil),
+		msgMockObj.EXPECT().InfoApps(gomock.Any(), agentAddress).Return([]int{results.OK}, []string{reported}),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), agentAddress, appId).Return([]int{results.OK}, []string{`{}`}),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("gateway", "2.0.0").Return(gateway, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), agentAddress, gatewayFile).Return([]int{results.ERROR}, []string{`{"message":"no space left"}`}),
+		dbManagerMockObj.EXPECT().UpdateGroupConvergence(groupId, agentId, "failed", []string{"stop line-monitor"}, failures, convergeAt).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateGroupConvergence(groupId, otherAppId, "unreachable", []string{}, []string{}, convergeAt).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.Converge(context.Background(), groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+}
+
+func TestCalledConvergeWithoutDesiredState_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	notFoundError := errors.NotFound{Message: "no desired state", Kind: errors.GROUP, ID: groupId}
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupDesiredState(groupId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.Converge(context.Background(), groupId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if !errors.Is(err, notFoundError) {
+		t.Errorf("Expected err: %v, actual err: %v", notFoundError, err)
+	}
+}
+
+func TestCalledStopConverger_ExpectBackgroundConvergencesWaited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupDesiredState(groupId).Return(nil, errors.NotFound{Kind: errors.GROUP, ID: groupId}),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	convergeInBackground(groupId)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := StopConverger(ctx); err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
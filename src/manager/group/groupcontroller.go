@@ -21,24 +21,38 @@
 package group
 
 import (
+	"commons/compose"
+	"commons/config"
 	"commons/errors"
+	"commons/labels"
 	"commons/logger"
+	"commons/paging"
 	"commons/results"
+	"context"
 	"db"
 	"encoding/json"
+	"manager/catalog"
+	"manager/event"
+	"manager/revision"
 	"messenger"
+	"net/url"
+	"time"
 )
 
 const (
 	AGENTS        = "agents"      // used to indicate a list of agents.
+	AGENT         = "agent"       // used to indicate an agent included in groups.
 	GROUPS        = "groups"      // used to indicate a list of groups.
 	MEMBERS       = "members"     // used to indicate a list of members.
 	APPS          = "apps"        // used to indicate a list of apps.
+	APP           = "app"         // used to indicate an app installed on agents.
 	ID            = "id"          // used to indicate an id.
 	RESPONSE_CODE = "code"        // used to indicate a code.
 	ERROR_MESSAGE = "message"     // used to indicate a message.
 	RESPONSES     = "responses"   // used to indicate a list of responses.
 	DESCRIPTION   = "description" // used to indicate a description.
+	STATUS        = "status"      // used to indicate a status of agents.
+	STATUS_STALE  = "stale"       // used to indicate agents excluded from operations by the retention policy.
 )
 
 type GroupController struct{}
@@ -46,6 +60,9 @@ type GroupController struct{}
 var dbConnector db.DBConnection
 var httpMessenger messenger.MessengerInterface
 
+// now returns the current time, and is replaced in tests.
+var now = time.Now
+
 func init() {
 	dbConnector = db.DBConnector{}
 	httpMessenger = messenger.SdamMsgrImpl{}
@@ -53,21 +70,21 @@ func init() {
 
 // CreateGroup inserts a new group to databases.
 // This function returns a unique id in case of success and an error otherwise.
-func (GroupController) CreateGroup() (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) CreateGroup(ctx context.Context) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
 
 	group, err := db.CreateGroup()
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -77,21 +94,21 @@ func (GroupController) CreateGroup() (int, map[string]interface{}, error) {
 // GetGroup returns the information of the group specified by groupId parameter.
 // If response code represents success, returns information about the group.
 // Otherwise, an appropriate error will be returned.
-func (GroupController) GetGroup(groupId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) GetGroup(ctx context.Context, groupId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
 
 	group, err := db.GetGroup(groupId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -99,101 +116,168 @@ func (GroupController) GetGroup(groupId string) (int, map[string]interface{}, er
 }
 
 // GetGroups returns a list of groups that is created on databases.
-// If response code represents success, returns a list of groups.
+// Groups can be filtered by agent query parameter, and a page of them is returned
+// by sort, limit and cursor query parameters.
+// If response code represents success, returns a list of groups
+// and a cursor of the next page if more groups remain.
 // Otherwise, an appropriate error will be returned.
-func (GroupController) GetGroups() (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) GetGroups(ctx context.Context, query url.Values) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	options, err := paging.Parse(query, AGENT)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
 
-	groups, err := db.GetAllGroups()
+	groups, next, err := db.GetGroupsByQuery(options.Filter, options.Sort, options.Limit, options.Cursor)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
 	res := make(map[string]interface{})
 	res[GROUPS] = groups
+	if next != "" {
+		res[paging.NEXT] = next
+	}
 
 	return results.OK, res, err
 }
 
+// GetAvailability returns the availability of members of the group specified by groupId parameter
+// in the period given by from and to query parameters, which is computed from events of the members.
+// The availability of the group is that of all members taken together.
+// If response code represents success, returns the availability of the group and each member.
+// Otherwise, an appropriate error will be returned.
+func (GroupController) GetAvailability(ctx context.Context, groupId string, query url.Values) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	from, to, err := event.ParsePeriod(query, now())
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	group, err := db.GetGroup(groupId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	total := event.Summary{}
+	members := make([]map[string]interface{}, 0)
+	for _, agentId := range group[MEMBERS].([]string) {
+		summary, err := event.GetAvailability(db, agentId, from, to)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+		total = total.Add(summary)
+
+		member := summary.ToMap()
+		member[ID] = agentId
+		members = append(members, member)
+	}
+
+	res := total.ToMap()
+	res[ID] = groupId
+	res[event.FROM] = from
+	res[event.TO] = to
+	res[MEMBERS] = members
+	return results.OK, res, err
+}
+
 // JoinGroup adds the agent to a list of members.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (GroupController) JoinGroup(groupId string, body string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) JoinGroup(ctx context.Context, groupId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
 
 	bodyMap, err := convertJsonToMap(body)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
-	// Check whether 'agents' is included.
-	_, exists := bodyMap[AGENTS]
-	if !exists {
-		return results.ERROR, nil, errors.InvalidJSON{"agents field is required"}
+	agentIds, err := getAgentIds(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
 	}
 
-	for _, agentId := range bodyMap[AGENTS].([]interface{}) {
-		err = db.JoinGroup(groupId, agentId.(string))
+	for _, agentId := range agentIds {
+		err = db.JoinGroup(groupId, agentId)
 		if err != nil {
-			logger.Logging(logger.ERROR, err.Error())
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
 			return results.ERROR, nil, err
 		}
 	}
 
+	// New members are converged toward the desired state of the group if it is declared.
+	requestConvergence(groupId)
+
 	return results.OK, nil, err
 }
 
 // LeaveGroup removes the agent from a list of members.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (GroupController) LeaveGroup(groupId string, body string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) LeaveGroup(ctx context.Context, groupId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
 
 	bodyMap, err := convertJsonToMap(body)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
-	// Check whether 'agents' is included.
-	_, exists := bodyMap[AGENTS]
-	if !exists {
-		return results.ERROR, nil, errors.InvalidJSON{"agents field is required"}
+	agentIds, err := getAgentIds(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
 	}
 
-	for _, agentId := range bodyMap[AGENTS].([]interface{}) {
-		err = db.LeaveGroup(groupId, agentId.(string))
+	for _, agentId := range agentIds {
+		err = db.LeaveGroup(groupId, agentId)
 		if err != nil {
-			logger.Logging(logger.ERROR, err.Error())
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
 			return results.ERROR, nil, err
 		}
 	}
@@ -204,21 +288,21 @@ func (GroupController) LeaveGroup(groupId string, body string) (int, map[string]
 // DeleteGroup deletes the group with a primary key matching the groupId argument.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (GroupController) DeleteGroup(groupId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) DeleteGroup(ctx context.Context, groupId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
 
 	err = db.DeleteGroup(groupId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -226,16 +310,17 @@ func (GroupController) DeleteGroup(groupId string) (int, map[string]interface{},
 }
 
 // DeployApp request an deployment of edge services to a group specified by groupId parameter.
+// The body is a compose file or a reference to a version of an app in the catalog.
 // If response code represents success, add an app id to a list of installed app and returns it.
 // Otherwise, an appropriate error will be returned.
-func (GroupController) DeployApp(groupId string, body string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) DeployApp(ctx context.Context, groupId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -243,61 +328,27 @@ func (GroupController) DeployApp(groupId string, body string) (int, map[string]i
 	// Get group members from the database.
 	members, err := db.GetGroupMembers(groupId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
+	members = excludeStale(members)
 
 	// Request an deployment of edge services to a specific group.
-	address := getMemberAddress(members)
-	codes, respStr := httpMessenger.DeployApp(address, body)
-	respMap, err := convertRespToMap(respStr)
-	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
-		return results.ERROR, nil, err
-	}
-
-	// if response code represents success, insert the installed appId into db.
-	installedAppId := ""
-	for i, agent := range members {
-		if isSuccessCode(codes[i]) {
-			err = db.AddAppToAgent(agent[ID].(string), respMap[i][ID].(string))
-			if err != nil {
-				logger.Logging(logger.ERROR, err.Error())
-				return results.ERROR, nil, err
-			}
-			installedAppId = respMap[i][ID].(string)
-		}
-	}
-
-	result := decideResultCode(codes)
-	if result != results.OK {
-		// Make separate responses to represent partial failure case.
-		resp := make(map[string]interface{})
-		resp[RESPONSES] = makeSeparateResponses(members, codes, respMap)
-		if installedAppId != "" {
-			resp[ID] = installedAppId
-		}
-		return result, resp, err
-	}
-
-	resp := make(map[string]interface{})
-	resp[ID] = installedAppId
-
-	return result, resp, err
+	return deployApp(ctx, db, members, body)
 }
 
 // GetApps request a list of applications that is deployed to a group
 // specified by groupId parameter.
 // If response code represents success, returns a list of applications.
 // Otherwise, an appropriate error will be returned.
-func (GroupController) GetApps(groupId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) GetApps(ctx context.Context, groupId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -305,7 +356,7 @@ func (GroupController) GetApps(groupId string) (int, map[string]interface{}, err
 	// Get group members from the database.
 	members, err := db.GetGroupMembers(groupId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -343,14 +394,14 @@ func (GroupController) GetApps(groupId string) (int, map[string]interface{}, err
 // GetApp gets the application's information of the group specified by groupId parameter.
 // If response code represents success, returns information of application.
 // Otherwise, an appropriate error will be returned.
-func (GroupController) GetApp(groupId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) GetApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -358,16 +409,17 @@ func (GroupController) GetApp(groupId string, appId string) (int, map[string]int
 	// Get group members including app specified by appId parameter.
 	members, err := db.GetGroupMembersByAppID(groupId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
+	members = excludeStale(members)
 
 	// Request get target application's information.
 	address := getMemberAddress(members)
-	codes, respStr := httpMessenger.InfoApp(address, appId)
-	respMap, err := convertRespToMap(respStr)
+	codes, respStr := httpMessenger.InfoApp(ctx, address, appId)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -405,16 +457,17 @@ func (GroupController) GetApp(groupId string, appId string) (int, map[string]int
 
 // UpdateApp request to update an application specified by appId parameter
 // to all members of the group.
+// The body is a compose file or a reference to a version of an app in the catalog.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (GroupController) UpdateAppInfo(groupId string, appId string, body string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) UpdateAppInfo(ctx context.Context, groupId string, appId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -422,42 +475,63 @@ func (GroupController) UpdateAppInfo(groupId string, appId string, body string)
 	// Get group members including app specified by appId parameter.
 	members, err := db.GetGroupMembersByAppID(groupId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
+	members = excludeStale(members)
 
 	// Request update target application's information.
-	address := getMemberAddress(members)
-	codes, respStr := httpMessenger.UpdateAppInfo(address, appId, body)
-	respMap, err := convertRespToMap(respStr)
+	return updateAppInfo(ctx, db, members, appId, body)
+}
+
+// RollbackApp request to update an application specified by appId parameter with the descriptor
+// of an earlier revision to all members of the group, which is the previous revision of each
+// member unless the body names a revision.
+// The result of each member is returned with the numbers of the new revision and the revision
+// applied again.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) RollbackApp(ctx context.Context, groupId string, appId string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	number, err := revision.ParseTarget(body)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
-	result := decideResultCode(codes)
-	if result != results.OK {
-		// Make separate responses to represent partial failure case.
-		resp := make(map[string]interface{})
-		resp[RESPONSES] = makeSeparateResponses(members, codes, respMap)
-		return result, resp, err
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
 	}
+	defer db.Close()
 
-	return result, nil, err
+	// Get group members including app specified by appId parameter.
+	members, err := db.GetGroupMembersByAppID(groupId, appId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	members = excludeStale(members)
+
+	return rollbackApp(ctx, db, members, appId, number)
 }
 
 // DeleteApp request to delete an application specified by appId parameter
 // to all members of the group.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (GroupController) DeleteApp(groupId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (GroupController) DeleteApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	// Connect to the database.
-	db, err := dbConnector.Connect()
+	db, err := dbConnector.Connect(ctx)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 	defer db.Close()
@@ -465,27 +539,379 @@ func (GroupController) DeleteApp(groupId string, appId string) (int, map[string]
 	// Get group members including app specified by appId parameter.
 	members, err := db.GetGroupMembersByAppID(groupId, appId)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
+	members = excludeStale(members)
 
 	// Request delete target application.
+	return deleteApp(ctx, db, members, appId)
+}
+
+// UpdateAppInfo request to update all of images which is included an application
+// specified by appId parameter to all members of the group.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) UpdateApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get group members including app specified by appId parameter.
+	members, err := db.GetGroupMembersByAppID(groupId, appId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	members = excludeStale(members)
+
+	// Request checking and updating all of images which is included target.
+	return controlApp(ctx, members, func(address []map[string]interface{}) ([]int, []string) {
+		return httpMessenger.UpdateApp(ctx, address, appId)
+	})
+}
+
+// StartApp request to start an application specified by appId parameter
+// to all members of the group.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) StartApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get group members including app specified by appId parameter.
+	members, err := db.GetGroupMembersByAppID(groupId, appId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	members = excludeStale(members)
+
+	// Request start target application.
+	return controlApp(ctx, members, func(address []map[string]interface{}) ([]int, []string) {
+		return httpMessenger.StartApp(ctx, address, appId)
+	})
+}
+
+// StopApp request to stop an application specified by appId parameter
+// to all members of the group.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) StopApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	// Get group members including app specified by appId parameter.
+	members, err := db.GetGroupMembersByAppID(groupId, appId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	members = excludeStale(members)
+
+	// Request stop target application.
+	return controlApp(ctx, members, func(address []map[string]interface{}) ([]int, []string) {
+		return httpMessenger.StopApp(ctx, address, appId)
+	})
+}
+
+// DeployAppBySelector request an deployment of edge services to agents
+// whose labels match the selector parameter.
+// If response code represents success, add an app id to a list of installed app and returns it.
+// Otherwise, an appropriate error will be returned.
+func (GroupController) DeployAppBySelector(ctx context.Context, selector string, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	members, err := getSelectedAgents(db, selector, "")
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	return deployApp(ctx, db, members, body)
+}
+
+// DeleteAppBySelector request to delete an application specified by appId parameter
+// to agents whose labels match the selector parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) DeleteAppBySelector(ctx context.Context, selector string, appId string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	members, err := getSelectedAgents(db, selector, appId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	return deleteApp(ctx, db, members, appId)
+}
+
+// UpdateAppBySelector request to update all of images which is included an application
+// specified by appId parameter to agents whose labels match the selector parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) UpdateAppBySelector(ctx context.Context, selector string, appId string) (int, map[string]interface{}, error) {
+	return controlAppBySelector(ctx, selector, appId, httpMessenger.UpdateApp)
+}
+
+// StartAppBySelector request to start an application specified by appId parameter
+// to agents whose labels match the selector parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) StartAppBySelector(ctx context.Context, selector string, appId string) (int, map[string]interface{}, error) {
+	return controlAppBySelector(ctx, selector, appId, httpMessenger.StartApp)
+}
+
+// StopAppBySelector request to stop an application specified by appId parameter
+// to agents whose labels match the selector parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (GroupController) StopAppBySelector(ctx context.Context, selector string, appId string) (int, map[string]interface{}, error) {
+	return controlAppBySelector(ctx, selector, appId, httpMessenger.StopApp)
+}
+
+// controlAppBySelector sends a request made by the send parameter to agents
+// whose labels match the selector and which have the application specified by appId parameter.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func controlAppBySelector(ctx context.Context, selector string, appId string,
+	send func(ctx context.Context, address []map[string]interface{}, appId string) ([]int, []string)) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	members, err := getSelectedAgents(db, selector, appId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	return controlApp(ctx, members, func(address []map[string]interface{}) ([]int, []string) {
+		return send(ctx, address, appId)
+	})
+}
+
+// rollbackApp requests to update an application specified by appId parameter with the descriptor
+// of the revision specified by number parameter to the members, and records the descriptor as
+// a new revision for each member whose response code represents success.
+// Members which have no such revision fail without being contacted, and members whose revisions
+// have the same descriptor are requested together.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func rollbackApp(ctx context.Context, dbManager db.DBManager, members []map[string]interface{}, appId string, number int) (int, map[string]interface{}, error) {
+	codes := make([]int, len(members))
+	respMap := make([]map[string]interface{}, len(members))
+	targets := make([]map[string]interface{}, len(members))
+
+	// Select the revision of each member, and group members by the descriptor of it.
+	descriptors := make([]string, 0)
+	indexes := make(map[string][]int)
+	for i, agent := range members {
+		target, err := revision.Select(dbManager, agent[ID].(string), appId, number)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			codes[i] = results.ERROR
+			respMap[i] = map[string]interface{}{ERROR_MESSAGE: err.Error()}
+			continue
+		}
+		targets[i] = target
+
+		descriptor := target[revision.DESCRIPTOR].(string)
+		if _, exists := indexes[descriptor]; !exists {
+			descriptors = append(descriptors, descriptor)
+		}
+		indexes[descriptor] = append(indexes[descriptor], i)
+	}
+
+	for _, descriptor := range descriptors {
+		selected := make([]map[string]interface{}, len(indexes[descriptor]))
+		for j, i := range indexes[descriptor] {
+			selected[j] = members[i]
+		}
+
+		address := getMemberAddress(selected)
+		selectedCodes, respStr := httpMessenger.UpdateAppInfo(ctx, address, appId, descriptor)
+		selectedResp, err := convertRespToMap(ctx, respStr)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+		for j, i := range indexes[descriptor] {
+			codes[i] = selectedCodes[j]
+			respMap[i] = selectedResp[j]
+		}
+	}
+
+	// if response code represents success, record the catalog version of the revision
+	// and the descriptor as a new revision.
+	responses := makeSeparateResponses(members, codes, respMap)
+	for i, agent := range members {
+		if targets[i] == nil {
+			continue
+		}
+		responses[i][revision.TARGET] = targets[i][revision.REVISION]
+		if !isSuccessCode(codes[i]) {
+			continue
+		}
+
+		ref := revision.Reference(targets[i])
+		err := catalog.Record(dbManager, agent, appId, ref)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+		recorded, err := revision.Record(dbManager, agent[ID].(string), appId, targets[i][revision.DESCRIPTOR].(string), ref)
+		if err != nil {
+			logger.LoggingContext(ctx, logger.ERROR, err.Error())
+			return results.ERROR, nil, err
+		}
+		responses[i][revision.REVISION] = recorded
+	}
+
+	resp := make(map[string]interface{})
+	resp[RESPONSES] = responses
+	return decideResultCode(codes), resp, nil
+}
+
+// getSelectedAgents returns a list of agents whose labels match the selector.
+// If appId is not empty, only agents which have the application are returned.
+// If no agent matches, NotFound error will be returned.
+func getSelectedAgents(dbManager db.DBManager, selector string, appId string) ([]map[string]interface{}, error) {
+	required, err := labels.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := labels.ToFilter(required)
+	if appId != "" {
+		filter[APP] = appId
+	}
+
+	agents, _, err := dbManager.GetAgentsByQuery(filter, "", 0, "")
+	if err != nil {
+		return nil, err
+	}
+	agents = excludeStale(agents)
+	if len(agents) == 0 {
+		return nil, errors.NotFound{Message: "no agent matches selector " + selector}
+	}
+	return agents, nil
+}
+
+// deployApp requests an deployment of edge services to the members, and inserts
+// the installed appId into db for each member whose response code represents success.
+// If the body is a reference to a version of an app in the catalog, the descriptor
+// of the version is deployed and the catalog version is recorded for each member.
+// Variables in the descriptor are rendered for each member with the fields and labels
+// of the member and variables given for the member in the body, and the rendered
+// descriptor is also recorded as the first revision of the app on each member.
+// An invalid descriptor is rejected with InvalidParam error before any member is contacted.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func deployApp(ctx context.Context, dbManager db.DBManager, members []map[string]interface{}, body string) (int, map[string]interface{}, error) {
+	descriptor, ref, err := catalog.Resolve(dbManager, body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	// Reject an invalid descriptor before any member is contacted.
+	err = compose.Validate(descriptor, config.Get().Agent.ComposeVersions)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	descriptors, err := renderDescriptors(members, descriptor, body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
 	address := getMemberAddress(members)
-	codes, respStr := httpMessenger.DeleteApp(address, appId)
-	respMap, err := convertRespToMap(respStr)
+	codes, respStr := sendDescriptors(descriptor, descriptors,
+		func(data string) ([]int, []string) {
+			return httpMessenger.DeployApp(ctx, address, data)
+		},
+		func(data []string) ([]int, []string) {
+			return httpMessenger.DeployAppPerMember(ctx, address, data)
+		})
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
-	// if response code represents success, delete the appId from db.
+	// if response code represents success, insert the installed appId into db.
+	installedAppId := ""
 	for i, agent := range members {
 		if isSuccessCode(codes[i]) {
-			err = db.DeleteAppFromAgent(agent[ID].(string), appId)
+			appId := respMap[i][ID].(string)
+			err = dbManager.AddAppToAgent(agent[ID].(string), appId)
+			if err != nil {
+				logger.LoggingContext(ctx, logger.ERROR, err.Error())
+				return results.ERROR, nil, err
+			}
+			err = catalog.Record(dbManager, agent, appId, ref)
+			if err != nil {
+				logger.LoggingContext(ctx, logger.ERROR, err.Error())
+				return results.ERROR, nil, err
+			}
+			_, err = revision.Record(dbManager, agent[ID].(string), appId, descriptors[i], ref)
 			if err != nil {
-				logger.Logging(logger.ERROR, err.Error())
+				logger.LoggingContext(ctx, logger.ERROR, err.Error())
 				return results.ERROR, nil, err
 			}
+			installedAppId = appId
 		}
 	}
 
@@ -494,42 +920,40 @@ func (GroupController) DeleteApp(groupId string, appId string) (int, map[string]
 		// Make separate responses to represent partial failure case.
 		resp := make(map[string]interface{})
 		resp[RESPONSES] = makeSeparateResponses(members, codes, respMap)
+		if installedAppId != "" {
+			resp[ID] = installedAppId
+		}
 		return result, resp, err
 	}
 
-	return result, nil, err
+	resp := make(map[string]interface{})
+	resp[ID] = installedAppId
+
+	return result, resp, err
 }
 
-// UpdateAppInfo request to update all of images which is included an application
-// specified by appId parameter to all members of the group.
+// deleteApp requests to delete an application specified by appId parameter to the members,
+// and deletes the appId from db for each member whose response code represents success.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (GroupController) UpdateApp(groupId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
-
-	// Connect to the database.
-	db, err := dbConnector.Connect()
-	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
-		return results.ERROR, nil, err
-	}
-	defer db.Close()
-
-	// Get group members including app specified by appId parameter.
-	members, err := db.GetGroupMembersByAppID(groupId, appId)
+func deleteApp(ctx context.Context, dbManager db.DBManager, members []map[string]interface{}, appId string) (int, map[string]interface{}, error) {
+	address := getMemberAddress(members)
+	codes, respStr := httpMessenger.DeleteApp(ctx, address, appId)
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
-	// Request checking and updating all of images which is included target.
-	address := getMemberAddress(members)
-	codes, respStr := httpMessenger.UpdateApp(address, appId)
-	respMap, err := convertRespToMap(respStr)
-	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
-		return results.ERROR, nil, err
+	// if response code represents success, delete the appId from db.
+	for i, agent := range members {
+		if isSuccessCode(codes[i]) {
+			err = dbManager.DeleteAppFromAgent(agent[ID].(string), appId)
+			if err != nil {
+				logger.LoggingContext(ctx, logger.ERROR, err.Error())
+				return results.ERROR, nil, err
+			}
+		}
 	}
 
 	result := decideResultCode(codes)
@@ -543,38 +967,63 @@ func (GroupController) UpdateApp(groupId string, appId string) (int, map[string]
 	return result, nil, err
 }
 
-// StartApp request to start an application specified by appId parameter
-// to all members of the group.
+// updateAppInfo requests to update the descriptor of an application specified by appId parameter
+// to the members, and records the catalog version and the revision of the app for each member
+// whose response code represents success.
+// Variables in the descriptor are rendered for each member as deployApp does.
+// An invalid descriptor is rejected with InvalidParam error before any member is contacted.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func (GroupController) StartApp(groupId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func updateAppInfo(ctx context.Context, dbManager db.DBManager, members []map[string]interface{}, appId string, body string) (int, map[string]interface{}, error) {
+	descriptor, ref, err := catalog.Resolve(dbManager, body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
 
-	// Connect to the database.
-	db, err := dbConnector.Connect()
+	// Reject an invalid descriptor before any member is contacted.
+	err = compose.Validate(descriptor, config.Get().Agent.ComposeVersions)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
-	defer db.Close()
 
-	// Get group members including app specified by appId parameter.
-	members, err := db.GetGroupMembersByAppID(groupId, appId)
+	descriptors, err := renderDescriptors(members, descriptor, body)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
-	// Request start target application.
 	address := getMemberAddress(members)
-	codes, respStr := httpMessenger.StartApp(address, appId)
-	respMap, err := convertRespToMap(respStr)
+	codes, respStr := sendDescriptors(descriptor, descriptors,
+		func(data string) ([]int, []string) {
+			return httpMessenger.UpdateAppInfo(ctx, address, appId, data)
+		},
+		func(data []string) ([]int, []string) {
+			return httpMessenger.UpdateAppInfoPerMember(ctx, address, appId, data)
+		})
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
+	// if response code represents success, record the catalog version and the revision of the app.
+	for i, agent := range members {
+		if isSuccessCode(codes[i]) {
+			err = catalog.Record(dbManager, agent, appId, ref)
+			if err != nil {
+				logger.LoggingContext(ctx, logger.ERROR, err.Error())
+				return results.ERROR, nil, err
+			}
+			_, err = revision.Record(dbManager, agent[ID].(string), appId, descriptors[i], ref)
+			if err != nil {
+				logger.LoggingContext(ctx, logger.ERROR, err.Error())
+				return results.ERROR, nil, err
+			}
+		}
+	}
+
 	result := decideResultCode(codes)
 	if result != results.OK {
 		// Make separate responses to represent partial failure case.
@@ -586,35 +1035,60 @@ func (GroupController) StartApp(groupId string, appId string) (int, map[string]i
 	return result, nil, err
 }
 
-// StopApp request to stop an application specified by appId parameter
-// to all members of the group.
+// renderDescriptors renders variables in the descriptor for each member
+// with variables given for the member in the body of a request.
+// Each distinct rendered descriptor is validated again, since values of variables may make it invalid.
 // If successful, this function returns an error as nil.
-// otherwise, an appropriate error will be returned.
-func (GroupController) StopApp(groupId string, appId string) (int, map[string]interface{}, error) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
-
-	// Connect to the database.
-	db, err := dbConnector.Connect()
+// otherwise, InvalidParam error will be returned.
+func renderDescriptors(members []map[string]interface{}, descriptor string, body string) ([]string, error) {
+	variables, err := compose.Variables(body)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
-		return results.ERROR, nil, err
+		return nil, err
 	}
-	defer db.Close()
 
-	// Get group members including app specified by appId parameter.
-	members, err := db.GetGroupMembersByAppID(groupId, appId)
-	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
-		return results.ERROR, nil, err
+	validated := map[string]bool{descriptor: true}
+	descriptors := make([]string, len(members))
+	for i, member := range members {
+		descriptors[i], err = compose.Render(descriptor, member, variables)
+		if err != nil {
+			return nil, err
+		}
+		if validated[descriptors[i]] {
+			continue
+		}
+		err = compose.Validate(descriptors[i], config.Get().Agent.ComposeVersions)
+		if err != nil {
+			return nil, err
+		}
+		validated[descriptors[i]] = true
+	}
+	return descriptors, nil
+}
+
+// sendDescriptors sends the descriptors rendered for the members by sendPerMember.
+// If every member has the same descriptor, a single descriptor is sent by send instead.
+func sendDescriptors(descriptor string, descriptors []string,
+	send func(data string) ([]int, []string), sendPerMember func(data []string) ([]int, []string)) ([]int, []string) {
+	for _, rendered := range descriptors {
+		if rendered != descriptors[0] {
+			return sendPerMember(descriptors)
+		}
 	}
+	if len(descriptors) != 0 {
+		descriptor = descriptors[0]
+	}
+	return send(descriptor)
+}
 
-	// Request stop target application.
-	address := getMemberAddress(members)
-	codes, respStr := httpMessenger.StopApp(address, appId)
-	respMap, err := convertRespToMap(respStr)
+// controlApp sends a request made by the send parameter to the members.
+// If all members send a success response, this function returns an error as nil.
+// otherwise, separate responses of the members will be returned.
+func controlApp(ctx context.Context, members []map[string]interface{},
+	send func(address []map[string]interface{}) ([]int, []string)) (int, map[string]interface{}, error) {
+	codes, respStr := send(getMemberAddress(members))
+	respMap, err := convertRespToMap(ctx, respStr)
 	if err != nil {
-		logger.Logging(logger.ERROR, err.Error())
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
 		return results.ERROR, nil, err
 	}
 
@@ -636,7 +1110,7 @@ func convertJsonToMap(jsonStr string) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 	err := json.Unmarshal([]byte(jsonStr), &result)
 	if err != nil {
-		return nil, errors.InvalidJSON{"Unmarshalling Failed"}
+		return nil, errors.InvalidJSON{Message: "Unmarshalling Failed"}
 	}
 	return result, err
 }
@@ -656,13 +1130,13 @@ func getMemberAddress(members []map[string]interface{}) []map[string]interface{}
 // convertRespToMap converts a response in the form of JSON data into a map.
 // If successful, this function returns an error as nil.
 // otherwise, an appropriate error will be returned.
-func convertRespToMap(respStr []string) ([]map[string]interface{}, error) {
+func convertRespToMap(ctx context.Context, respStr []string) ([]map[string]interface{}, error) {
 	respMap := make([]map[string]interface{}, len(respStr))
 	for i, v := range respStr {
 		resp, err := convertJsonToMap(v)
 		if err != nil {
-			logger.Logging(logger.ERROR, "Failed to convert response from string to map")
-			return nil, errors.InternalServerError{"Json Converting Failed"}
+			logger.LoggingContext(ctx, logger.ERROR, "Failed to convert response from string to map")
+			return nil, errors.InternalServerError{Message: "Json Converting Failed"}
 		}
 		respMap[i] = resp
 	}
@@ -703,6 +1177,47 @@ func decideResultCode(codes []int) int {
 	return result
 }
 
+// excludeStale returns the agents which are not stale, so that operations do not wait for them.
+// All agents are returned if stale agents are included by the retention policy.
+func excludeStale(agents []map[string]interface{}) []map[string]interface{} {
+	if config.Get().Retention.IncludeStale {
+		return agents
+	}
+
+	result := make([]map[string]interface{}, 0, len(agents))
+	for _, agent := range agents {
+		if agent[STATUS] != STATUS_STALE {
+			result = append(result, agent)
+		}
+	}
+	return result
+}
+
+// getAgentIds returns a list of agent ids in 'agents' field of body.
+// If the field is not included, InvalidJSON error will be returned.
+// If the field is not a list of strings, InvalidParam error will be returned.
+func getAgentIds(bodyMap map[string]interface{}) ([]string, error) {
+	value, exists := bodyMap[AGENTS]
+	if !exists {
+		return nil, errors.InvalidJSON{Message: "agents field is required"}
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.InvalidParam{Message: "agents field should be a list of agent ids"}
+	}
+
+	agentIds := make([]string, len(list))
+	for i, item := range list {
+		agentId, ok := item.(string)
+		if !ok {
+			return nil, errors.InvalidParam{Message: "agents field should be a list of agent ids"}
+		}
+		agentIds[i] = agentId
+	}
+	return agentIds, nil
+}
+
 // makeSeparateResponses used to make a separate response
 // when the group operations is a partial success.
 func makeSeparateResponses(members []map[string]interface{}, codes []int,
@@ -19,9 +19,11 @@ package group
 import (
 	"commons/errors"
 	"commons/results"
+	"context"
 	dbmocks "db/mocks"
 	msgmocks "messenger/mocks"
 	"github.com/golang/mock/gomock"
+	"net/url"
 	"reflect"
 	"testing"
 )
@@ -52,7 +54,7 @@ var (
 		"members": []string{},
 	}
 
-	body                   = `{"description":"description"}`
+	body                   = "services:\n  monitor:\n    image: monitor:1.4.0\n"
 	respCode               = []int{results.OK, results.OK}
 	partialSuccessRespCode = []int{results.OK, results.ERROR}
 	errorRespCode          = []int{results.ERROR, results.ERROR}
@@ -75,14 +77,14 @@ func TestCalledCreateGroup_ExpectSuccess(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().CreateGroup().Return(group, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, res, err := controller.CreateGroup()
+	code, res, err := controller.CreateGroup(context.Background())
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -104,12 +106,12 @@ func TestCalledCreateGroupWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T)
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.CreateGroup()
+	code, _, err := controller.CreateGroup(context.Background())
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -134,14 +136,14 @@ func TestCalledCreateGroupWhenFailedToInsertGroupToDB_ExpectErrorReturn(t *testi
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().CreateGroup().Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.CreateGroup()
+	code, _, err := controller.CreateGroup(context.Background())
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -166,14 +168,14 @@ func TestCalledGetGroup_ExpectSuccess(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroup(groupId).Return(group, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, res, err := controller.GetGroup(groupId)
+	code, res, err := controller.GetGroup(context.Background(), groupId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -188,6 +190,83 @@ func TestCalledGetGroup_ExpectSuccess(t *testing.T) {
 	}
 }
 
+func TestCalledGetAvailability_ExpectSummaryOfMembersReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	otherAgentId := "000000000000000000000003"
+	query, _ := url.ParseQuery("from=1000&to=2000")
+	groupWithMembers := map[string]interface{}{
+		"id":      groupId,
+		"members": []string{agentId, otherAgentId},
+	}
+	connected := []map[string]interface{}{{"agentid": agentId, "type": "connected", "time": int64(500)}}
+	disconnected := []map[string]interface{}{{"agentid": otherAgentId, "type": "disconnected", "time": int64(1500)}}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroup(groupId).Return(groupWithMembers, nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(gomock.Any(), "-id", 1, "").Return(connected, "", nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(gomock.Any(), "", 0, "").Return(nil, "", nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(gomock.Any(), "-id", 1, "").Return(connected, "", nil),
+		dbManagerMockObj.EXPECT().GetEventsByQuery(gomock.Any(), "", 0, "").Return(disconnected, "", nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetAvailability(context.Background(), groupId, query)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if res["id"] != groupId || res["uptime"] != 75.0 || res["disconnects"] != 1 || res["mtbd"] != int64(1500) {
+		t.Errorf("Unexpected res: %v", res)
+	}
+
+	members := res["members"].([]map[string]interface{})
+	if len(members) != 2 || members[0]["id"] != agentId || members[0]["uptime"] != 100.0 ||
+		members[1]["id"] != otherAgentId || members[1]["uptime"] != 50.0 {
+		t.Errorf("Unexpected members: %v", members)
+	}
+}
+
+func TestCalledGetAvailabilityWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroup(groupId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetAvailability(context.Background(), groupId, url.Values{})
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
 func TestCalledGetGroupWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -195,12 +274,12 @@ func TestCalledGetGroupWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetGroup(groupId)
+	code, _, err := controller.GetGroup(context.Background(), groupId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -225,14 +304,14 @@ func TestCalledGetGroupWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroup(groupId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetGroup(groupId)
+	code, _, err := controller.GetGroup(context.Background(), groupId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -259,14 +338,14 @@ func TestCalledGetGroups_ExpectSuccess(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAllGroups().Return(groups, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupsByQuery(map[string]string{}, "", 0, "").Return(groups, "", nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, res, err := controller.GetGroups()
+	code, res, err := controller.GetGroups(context.Background(), nil)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -288,12 +367,12 @@ func TestCalledGetGroupsWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetGroups()
+	code, _, err := controller.GetGroups(context.Background(), nil)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -318,14 +397,14 @@ func TestCalledGetGroupsWhenFailedToGetGroupsFromDB_ExpectErrorReturn(t *testing
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetAllGroups().Return(nil, notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupsByQuery(map[string]string{}, "", 0, "").Return(nil, "", notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetGroups()
+	code, _, err := controller.GetGroups(context.Background(), nil)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -342,6 +421,52 @@ func TestCalledGetGroupsWhenFailedToGetGroupsFromDB_ExpectErrorReturn(t *testing
 	}
 }
 
+func TestCalledGetGroupsWithQuery_ExpectFilteredPageReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	groups := []map[string]interface{}{{"id": groupId, "members": []string{agentId}}}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupsByQuery(map[string]string{"agent": agentId}, "", 1, "").Return(groups, "next", nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetGroups(context.Background(), url.Values{"agent": {agentId}, "limit": {"1"}})
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(res["groups"], groups) || res["next"] != "next" {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledGetGroupsWithUnsupportedFilter_ExpectErrorReturn(t *testing.T) {
+	code, _, err := controller.GetGroups(context.Background(), url.Values{"status": {"connected"}})
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
+	}
+}
+
 func TestCalledJoinGroup_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -350,15 +475,18 @@ func TestCalledJoinGroup_ExpectSuccess(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().JoinGroup(groupId, agentId).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
+	converged := stubConvergence()
+	defer func() { requestConvergence = convergeInBackground }()
+
 	agents := `{"agents":["000000000000000000000001"]}`
-	code, _, err := controller.JoinGroup(groupId, agents)
+	code, _, err := controller.JoinGroup(context.Background(), groupId, agents)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -367,6 +495,10 @@ func TestCalledJoinGroup_ExpectSuccess(t *testing.T) {
 	if code != results.OK {
 		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
+
+	if !reflect.DeepEqual([]string{groupId}, *converged) {
+		t.Errorf("Expected convergence of group: %s, actual: %v", groupId, *converged)
+	}
 }
 
 func TestCalledJoinGroupWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
@@ -376,13 +508,13 @@ func TestCalledJoinGroupWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
 	agents := `{"agents":["000000000000000000000001"]}`
-	code, _, err := controller.JoinGroup(groupId, agents)
+	code, _, err := controller.JoinGroup(context.Background(), groupId, agents)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -407,14 +539,14 @@ func TestCalledJoinGroupWithInvalidRequestBody_ExpectErrorReturn(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
 	invalidJsonStr := `{"invalidJson"}`
-	code, _, err := controller.JoinGroup(groupId, invalidJsonStr)
+	code, _, err := controller.JoinGroup(context.Background(), groupId, invalidJsonStr)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -431,6 +563,35 @@ func TestCalledJoinGroupWithInvalidRequestBody_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
+func TestCalledJoinGroupWithInvalidAgents_ExpectErrorReturn(t *testing.T) {
+	for _, body := range []string{`{"agents":"agentId"}`, `{"agents":[1]}`} {
+		ctrl := gomock.NewController(t)
+
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+
+		code, _, err := controller.JoinGroup(context.Background(), groupId, body)
+
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
+
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+		case errors.InvalidParam:
+		}
+		ctrl.Finish()
+	}
+}
+
 func TestCalledJoinGroupWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -439,7 +600,7 @@ func TestCalledJoinGroupWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().JoinGroup(groupId, agentId).Return(notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -447,7 +608,7 @@ func TestCalledJoinGroupWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T)
 	dbConnector = dbConnectionMockObj
 
 	agents := `{"agents":["000000000000000000000001"]}`
-	code, _, err := controller.JoinGroup(groupId, agents)
+	code, _, err := controller.JoinGroup(context.Background(), groupId, agents)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -472,7 +633,7 @@ func TestCalledLeaveGroup_ExpectSuccess(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().LeaveGroup(groupId, agentId).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -480,7 +641,7 @@ func TestCalledLeaveGroup_ExpectSuccess(t *testing.T) {
 	dbConnector = dbConnectionMockObj
 
 	agents := `{"agents":["000000000000000000000001"]}`
-	code, _, err := controller.LeaveGroup(groupId, agents)
+	code, _, err := controller.LeaveGroup(context.Background(), groupId, agents)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -498,13 +659,13 @@ func TestCalledLeaveGroupWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T)
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
 	agents := `{"agents":["000000000000000000000001"]}`
-	code, _, err := controller.LeaveGroup(groupId, agents)
+	code, _, err := controller.LeaveGroup(context.Background(), groupId, agents)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -529,14 +690,14 @@ func TestCalledLeaveGroupWithInvalidRequestBody_ExpectErrorReturn(t *testing.T)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
 	invalidJsonStr := `{"invalidJson"}`
-	code, _, err := controller.LeaveGroup(groupId, invalidJsonStr)
+	code, _, err := controller.LeaveGroup(context.Background(), groupId, invalidJsonStr)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -561,7 +722,7 @@ func TestCalledLeaveGroupWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().LeaveGroup(groupId, agentId).Return(notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
@@ -569,7 +730,7 @@ func TestCalledLeaveGroupWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T
 	dbConnector = dbConnectionMockObj
 
 	agents := `{"agents":["000000000000000000000001"]}`
-	code, _, err := controller.LeaveGroup(groupId, agents)
+	code, _, err := controller.LeaveGroup(context.Background(), groupId, agents)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -594,14 +755,14 @@ func TestCalledDeleteGroup_ExpectSuccess(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().DeleteGroup(groupId).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.DeleteGroup(groupId)
+	code, _, err := controller.DeleteGroup(context.Background(), groupId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -619,12 +780,12 @@ func TestCalledDeleteGroupWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T)
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.DeleteGroup(groupId)
+	code, _, err := controller.DeleteGroup(context.Background(), groupId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -649,14 +810,14 @@ func TestCalledDeleteGroupWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().DeleteGroup(groupId).Return(notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.DeleteGroup(groupId)
+	code, _, err := controller.DeleteGroup(context.Background(), groupId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -687,17 +848,20 @@ func TestCalledDeployApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
-		msgMockObj.EXPECT().DeployApp(membersAddress, body).Return(respCode, respStr),
-		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil).AnyTimes(),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), membersAddress, body).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.DeployApp(groupId, body)
+	code, res, err := controller.DeployApp(context.Background(), groupId, body)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -712,68 +876,78 @@ func TestCalledDeployApp_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledDeployAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWithCatalogReference_ExpectCatalogVersionRecorded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	reference := `{"app":"line-monitor","version":"1.4.0"}`
+	version := map[string]interface{}{"name": "line-monitor", "version": "1.4.0", "descriptor": body, "checksum": "sum"}
+	respStr := []string{`{"id":"000000000000000000000000"}`, `{"id":"000000000000000000000000"}`}
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
+		dbManagerMockObj.EXPECT().GetCatalogVersion("line-monitor", "1.4.0").Return(version, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), membersAddress, body).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, "line-monitor", "1.4.0").Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "line-monitor", "1.4.0", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, "line-monitor", "1.4.0").Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "line-monitor", "1.4.0", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeployApp(groupId, body)
-
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
-	}
+	code, _, err := controller.DeployApp(context.Background(), groupId, reference)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
-	case errors.DBConnectionError:
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 }
 
-func TestCalledDeployAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWithInvalidDescriptor_ExpectErrorReturnWithoutRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(nil, notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeployApp(groupId, body)
+	invalid := "services:\n  monitor:\n    image: monitor\n    build: .\n"
+	code, _, err := controller.DeployApp(context.Background(), groupId, invalid)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
-
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
 	}
 }
 
-func TestCalledDeployAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWithInvalidDescriptorWhenGroupHasNoMembers_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -782,143 +956,139 @@ func TestCalledDeployAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
-		msgMockObj.EXPECT().DeployApp(membersAddress, body).Return(respCode, invalidRespStr),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return([]map[string]interface{}{}, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeployApp(groupId, body)
+	invalid := "services:\n  monitor:\n    image: monitor\n    build: .\n"
+	code, _, err := controller.DeployApp(context.Background(), groupId, invalid)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "InternalServerError", "nil")
-	}
-
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "InternalServerError", err.Error())
-	case errors.InternalServerError:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
 	}
 }
 
-func TestCalledDeployAppWhenFailedToAddAppIdToDB_ExpectErrorReturn(t *testing.T) {
+const (
+	templatedBody = `{"descriptor":"services:\n  monitor:\n    image: monitor:1.4.0\n    environment:\n    - SITE={{ labels.site }}\n    - STATION={{ station }}\n",` +
+		`"variables":{"000000000000000000000001":{"station":"S-1"},"000000000000000000000003":{"station":"S-2"}}}`
+	otherAgentId = "000000000000000000000003"
+)
+
+var (
+	templatedMembers = []map[string]interface{}{
+		{"id": agentId, "host": host, "port": port, "labels": map[string]string{"site": "line-1"}},
+		{"id": otherAgentId, "host": host, "port": port, "labels": map[string]string{"site": "line-2"}},
+	}
+	renderedDescriptors = []string{
+		"services:\n  monitor:\n    image: monitor:1.4.0\n    environment:\n    - SITE=line-1\n    - STATION=S-1\n",
+		"services:\n  monitor:\n    image: monitor:1.4.0\n    environment:\n    - SITE=line-2\n    - STATION=S-2\n",
+	}
+)
+
+func TestCalledDeployAppWithVariables_ExpectDescriptorRenderedPerMember(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	respStr := []string{`{"id":"000000000000000000000000"}`}
+	respStr := []string{`{"id":"000000000000000000000000"}`, `{"id":"000000000000000000000000"}`}
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
-		msgMockObj.EXPECT().DeployApp(membersAddress, body).Return(respCode, respStr),
-		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(templatedMembers, nil),
+		msgMockObj.EXPECT().DeployAppPerMember(gomock.Any(), membersAddress, renderedDescriptors).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, renderedDescriptors[0], gomock.Any(), "", "", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().AddAppToAgent(otherAgentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(otherAgentId, appId, renderedDescriptors[1], gomock.Any(), "", "", gomock.Any()).Return(1, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeployApp(groupId, body)
-
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
-	}
+	code, _, err := controller.DeployApp(context.Background(), groupId, templatedBody)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
-	case errors.NotFound:
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 }
 
-func TestCalledDeployAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+func TestCalledDeployAppWithUndefinedVariable_ExpectErrorReturnWithoutRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	partialSuccessRespStr := []string{`{"id":"000000000000000000000000"}`, `{"message":"errorMsg"}`}
-	expectedRes := map[string]interface{}{
-		"id": "000000000000000000000000",
-		"responses": []map[string]interface{}{
-			map[string]interface{}{
-				"id":   agentId,
-				"code": results.OK,
-			},
-			map[string]interface{}{
-				"id":      agentId,
-				"code":    results.ERROR,
-				"message": "errorMsg",
-			},
-		},
-	}
-
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
-		msgMockObj.EXPECT().DeployApp(membersAddress, body).Return(partialSuccessRespCode, partialSuccessRespStr),
-		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.DeployApp(groupId, body)
-
-	if err != nil {
-		t.Errorf("Unexpected err: %s", err.Error())
-	}
+	code, _, err := controller.DeployApp(context.Background(), groupId, templatedBody)
 
-	if code != results.MULTI_STATUS {
-		t.Errorf("Expected code: %d, actual code: %d", results.MULTI_STATUS, code)
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
 	}
 }
 
-func TestCalledGetApps_ExpectSuccess(t *testing.T) {
+func TestCalledDeployAppWithStaleMember_ExpectStaleMemberExcluded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	expectedRes := map[string]interface{}{
-		"apps": []map[string]interface{}{{
-			"id":      appId,
-			"members": []string{agentId, agentId},
-		}},
+	staleAgent := map[string]interface{}{
+		"id":     "000000000000000000000003",
+		"host":   "192.168.0.2",
+		"port":   port,
+		"status": "stale",
 	}
+	respStr := []string{`{"id":"000000000000000000000000"}`}
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return([]map[string]interface{}{agent, staleAgent}, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), []map[string]interface{}{address}, body).Return([]int{results.OK}, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, res, err := controller.GetApps(groupId)
+	code, _, err := controller.DeployApp(context.Background(), groupId, body)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -927,25 +1097,21 @@ func TestCalledGetApps_ExpectSuccess(t *testing.T) {
 	if code != results.OK {
 		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
-
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
-	}
 }
 
-func TestCalledGetAppsWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetApps(groupId)
+	code, _, err := controller.DeployApp(context.Background(), groupId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -962,96 +1128,596 @@ func TestCalledGetAppsWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
-func TestCalledGetAppsWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.DeployApp(context.Background(), groupId, body)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledDeployAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), membersAddress, body).Return(respCode, invalidRespStr),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.DeployApp(context.Background(), groupId, body)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "InternalServerError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "InternalServerError", err.Error())
+	case errors.InternalServerError:
+	}
+}
+
+func TestCalledDeployAppWhenFailedToAddAppIdToDB_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	respStr := []string{`{"id":"000000000000000000000000"}`}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), membersAddress, body).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.DeployApp(context.Background(), groupId, body)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledDeployAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	partialSuccessRespStr := []string{`{"id":"000000000000000000000000"}`, `{"message":"errorMsg"}`}
+	expectedRes := map[string]interface{}{
+		"id": "000000000000000000000000",
+		"responses": []map[string]interface{}{
+			map[string]interface{}{
+				"id":   agentId,
+				"code": results.OK,
+			},
+			map[string]interface{}{
+				"id":      agentId,
+				"code":    results.ERROR,
+				"message": "errorMsg",
+			},
+		},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), membersAddress, body).Return(partialSuccessRespCode, partialSuccessRespStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.DeployApp(context.Background(), groupId, body)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.MULTI_STATUS {
+		t.Errorf("Expected code: %d, actual code: %d", results.MULTI_STATUS, code)
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledGetApps_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expectedRes := map[string]interface{}{
+		"apps": []map[string]interface{}{{
+			"id":      appId,
+			"members": []string{agentId, agentId},
+		}},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(members, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetApps(context.Background(), groupId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledGetAppsWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetApps(context.Background(), groupId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledGetAppsWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetApps(context.Background(), groupId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetApp_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	respStr := []string{`{"description":"description"}`, `{"description":"description"}`}
+	expectedRes := map[string]interface{}{
+		"responses": []map[string]interface{}{{
+			"description": "description",
+			"id":          members[0]["id"],
+		},
+			{
+				"description": "description",
+				"id":          members[0]["id"],
+			}},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
+		msgMockObj.EXPECT().InfoApp(gomock.Any(), membersAddress, appId).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.GetApp(context.Background(), groupId, appId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledGetAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetApp(context.Background(), groupId, appId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledGetAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.GetApp(context.Background(), groupId, appId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", err.Error())
+	case errors.NotFound:
+	}
+}
+
+func TestCalledGetAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invalidRespStr := []string{`{"invalidJson"}`, `{"invalidJson"}`}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
+		msgMockObj.EXPECT().InfoApp(gomock.Any(), membersAddress, appId).Return(respCode, invalidRespStr),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.GetApp(context.Background(), groupId, appId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if err == nil {
+		t.Errorf("Expected err: %s, actual err: %s", "InternalServerError", "nil")
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %s", "InternalServerError", err.Error())
+	case errors.InternalServerError:
+	}
+}
+
+func TestCalledGetAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	partialSuccessRespStr := []string{`{"description": "description"}`, `{"message":"errorMsg"}`}
+	expectedRes := map[string]interface{}{
+		"responses": []map[string]interface{}{
+			map[string]interface{}{
+				"id":          agentId,
+				"code":        results.OK,
+				"description": "description",
+			},
+			map[string]interface{}{
+				"id":      agentId,
+				"code":    results.ERROR,
+				"message": "errorMsg",
+			},
+		},
+	}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
+		msgMockObj.EXPECT().InfoApp(gomock.Any(), membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.GetApp(context.Background(), groupId, appId)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.MULTI_STATUS {
+		t.Errorf("Expected code: %d, actual code: %d", results.MULTI_STATUS, code)
+	}
+
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
+
+func TestCalledUpdateAppInfo_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), membersAddress, appId, body).Return(respCode, nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil).Times(2),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.UpdateAppInfo(context.Background(), groupId, appId, body)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+}
+
+func TestCalledUpdateAppInfoWithVariables_ExpectDescriptorRenderedPerMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(templatedMembers, nil),
+		msgMockObj.EXPECT().UpdateAppInfoPerMember(gomock.Any(), membersAddress, appId, renderedDescriptors).Return(respCode, nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, renderedDescriptors[0], gomock.Any(), "", "", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(otherAgentId, appId, renderedDescriptors[1], gomock.Any(), "", "", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, _, err := controller.UpdateAppInfo(context.Background(), groupId, appId, templatedBody)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+}
+
+func TestCalledUpdateAppInfoWithComposeFile_ExpectRecordedCatalogVersionRemoved(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	recorded := map[string]interface{}{
+		"id":      agentId,
+		"host":    host,
+		"port":    port,
+		"catalog": map[string]interface{}{appId: map[string]interface{}{"app": "line-monitor", "version": "1.4.0"}},
+	}
+	respStr := []string{`{"description":"description"}`, `{"description":"description"}`}
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembers(groupId).Return(nil, notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return([]map[string]interface{}{recorded, agent}, nil),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), membersAddress, appId, body).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, "", "").Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil).Times(2),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetApps(groupId)
-
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
-	}
+	code, _, err := controller.UpdateAppInfo(context.Background(), groupId, appId, body)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", err.Error())
-	case errors.NotFound:
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 }
 
-func TestCalledGetApp_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAppInfoWithInvalidDescriptor_ExpectErrorReturnWithoutRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	respStr := []string{`{"description":"description"}`, `{"description":"description"}`}
-	expectedRes := map[string]interface{}{
-		"responses": []map[string]interface{}{{
-			"description": "description",
-			"id":          members[0]["id"],
-		},
-			{
-				"description": "description",
-				"id":          members[0]["id"],
-			}},
-	}
-
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().InfoApp(membersAddress, appId).Return(respCode, respStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.GetApp(groupId, appId)
-
-	if err != nil {
-		t.Errorf("Unexpected err: %s", err.Error())
-	}
+	invalid := "services:\n  monitor:\n    image: monitor\n    build: .\n"
+	code, _, err := controller.UpdateAppInfo(context.Background(), groupId, appId, invalid)
 
-	if code != results.OK {
-		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if !reflect.DeepEqual(expectedRes, res) {
-		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	case errors.InvalidParam:
 	}
 }
 
-func TestCalledGetAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAppInfoWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetApp(groupId, appId)
+	code, _, err := controller.UpdateAppInfo(context.Background(), groupId, appId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1068,7 +1734,7 @@ func TestCalledGetAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
-func TestCalledGetAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAppInfoWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1076,14 +1742,14 @@ func TestCalledGetAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.GetApp(groupId, appId)
+	code, _, err := controller.UpdateAppInfo(context.Background(), groupId, appId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1100,7 +1766,7 @@ func TestCalledGetAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
-func TestCalledGetAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAppInfoWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1111,16 +1777,16 @@ func TestCalledGetAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *te
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().InfoApp(membersAddress, appId).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), membersAddress, appId, body).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.GetApp(groupId, appId)
+	code, _, err := controller.UpdateAppInfo(context.Background(), groupId, appId, body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1137,17 +1803,16 @@ func TestCalledGetAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *te
 	}
 }
 
-func TestCalledGetAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAppInfoWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	partialSuccessRespStr := []string{`{"description": "description"}`, `{"message":"errorMsg"}`}
+	partialSuccessRespStr := []string{`{"message": "successMsg"}`, `{"message":"errorMsg"}`}
 	expectedRes := map[string]interface{}{
 		"responses": []map[string]interface{}{
 			map[string]interface{}{
-				"id":          agentId,
-				"code":        results.OK,
-				"description": "description",
+				"id":   agentId,
+				"code": results.OK,
 			},
 			map[string]interface{}{
 				"id":      agentId,
@@ -1162,16 +1827,17 @@ func TestCalledGetAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().InfoApp(membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), membersAddress, appId, body).Return(partialSuccessRespCode, partialSuccessRespStr),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.GetApp(groupId, appId)
+	code, res, err := controller.UpdateAppInfo(context.Background(), groupId, appId, body)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1186,7 +1852,7 @@ func TestCalledGetAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing
 	}
 }
 
-func TestCalledUpdateAppInfo_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateApp_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1195,16 +1861,16 @@ func TestCalledUpdateAppInfo_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().UpdateAppInfo(membersAddress, appId, body).Return(respCode, nil),
+		msgMockObj.EXPECT().UpdateApp(gomock.Any(), membersAddress, appId).Return(respCode, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateAppInfo(groupId, appId, body)
+	code, _, err := controller.UpdateApp(context.Background(), groupId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1215,19 +1881,19 @@ func TestCalledUpdateAppInfo_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledUpdateAppInfoWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.UpdateAppInfo(groupId, appId, body)
+	code, _, err := controller.UpdateApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1244,7 +1910,7 @@ func TestCalledUpdateAppInfoWhenDBConnectionFailed_ExpectErrorReturn(t *testing.
 	}
 }
 
-func TestCalledUpdateAppInfoWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1252,14 +1918,14 @@ func TestCalledUpdateAppInfoWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testin
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.UpdateAppInfo(groupId, appId, body)
+	code, _, err := controller.UpdateApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1276,7 +1942,7 @@ func TestCalledUpdateAppInfoWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testin
 	}
 }
 
-func TestCalledUpdateAppInfoWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+func TestCalledUpdateAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1287,16 +1953,16 @@ func TestCalledUpdateAppInfoWhenMessengerReturnsInvalidResponse_ExpectErrorRetur
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().UpdateAppInfo(membersAddress, appId, body).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().UpdateApp(gomock.Any(), membersAddress, appId).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateAppInfo(groupId, appId, body)
+	code, _, err := controller.UpdateApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1313,7 +1979,7 @@ func TestCalledUpdateAppInfoWhenMessengerReturnsInvalidResponse_ExpectErrorRetur
 	}
 }
 
-func TestCalledUpdateAppInfoWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+func TestCalledUpdateAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1337,16 +2003,16 @@ func TestCalledUpdateAppInfoWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().UpdateAppInfo(membersAddress, appId, body).Return(partialSuccessRespCode, partialSuccessRespStr),
+		msgMockObj.EXPECT().UpdateApp(gomock.Any(), membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.UpdateAppInfo(groupId, appId, body)
+	code, res, err := controller.UpdateApp(context.Background(), groupId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1361,7 +2027,7 @@ func TestCalledUpdateAppInfoWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *
 	}
 }
 
-func TestCalledUpdateApp_ExpectSuccess(t *testing.T) {
+func TestCalledStartApp_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1370,16 +2036,16 @@ func TestCalledUpdateApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().UpdateApp(membersAddress, appId).Return(respCode, nil),
+		msgMockObj.EXPECT().StartApp(gomock.Any(), membersAddress, appId).Return(respCode, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateApp(groupId, appId)
+	code, _, err := controller.StartApp(context.Background(), groupId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1390,19 +2056,19 @@ func TestCalledUpdateApp_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledUpdateAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledStartAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.UpdateApp(groupId, appId)
+	code, _, err := controller.StartApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1419,7 +2085,7 @@ func TestCalledUpdateAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
-func TestCalledUpdateAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+func TestCalledStartAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1427,31 +2093,31 @@ func TestCalledUpdateAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.UpdateApp(groupId, appId)
+	code, _, err := controller.StartApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
 	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", "nil")
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
 	}
 
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFoundError", err.Error())
+		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
 	case errors.NotFound:
 	}
 }
 
-func TestCalledUpdateAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+func TestCalledStartAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1462,16 +2128,16 @@ func TestCalledUpdateAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().UpdateApp(membersAddress, appId).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().StartApp(gomock.Any(), membersAddress, appId).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.UpdateApp(groupId, appId)
+	code, _, err := controller.StartApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1488,7 +2154,7 @@ func TestCalledUpdateAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t
 	}
 }
 
-func TestCalledUpdateAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+func TestCalledStartAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1512,16 +2178,16 @@ func TestCalledUpdateAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *test
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().UpdateApp(membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
+		msgMockObj.EXPECT().StartApp(gomock.Any(), membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.UpdateApp(groupId, appId)
+	code, res, err := controller.StartApp(context.Background(), groupId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1536,7 +2202,7 @@ func TestCalledUpdateAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *test
 	}
 }
 
-func TestCalledStartApp_ExpectSuccess(t *testing.T) {
+func TestCalledStopApp_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1545,16 +2211,16 @@ func TestCalledStartApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().StartApp(membersAddress, appId).Return(respCode, nil),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), membersAddress, appId).Return(respCode, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StartApp(groupId, appId)
+	code, _, err := controller.StopApp(context.Background(), groupId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1565,19 +2231,19 @@ func TestCalledStartApp_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledStartAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledStopAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.StartApp(groupId, appId)
+	code, _, err := controller.StopApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1594,7 +2260,7 @@ func TestCalledStartAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
-func TestCalledStartAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+func TestCalledStopAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1602,14 +2268,14 @@ func TestCalledStartAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.StartApp(groupId, appId)
+	code, _, err := controller.StopApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1626,7 +2292,7 @@ func TestCalledStartAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T)
 	}
 }
 
-func TestCalledStartAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+func TestCalledStopAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1637,16 +2303,16 @@ func TestCalledStartAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().StartApp(membersAddress, appId).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), membersAddress, appId).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StartApp(groupId, appId)
+	code, _, err := controller.StopApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1663,7 +2329,7 @@ func TestCalledStartAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *
 	}
 }
 
-func TestCalledStartAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+func TestCalledStopAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1687,16 +2353,16 @@ func TestCalledStartAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testi
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().StartApp(membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
+		msgMockObj.EXPECT().StopApp(gomock.Any(), membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.StartApp(groupId, appId)
+	code, res, err := controller.StopApp(context.Background(), groupId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1711,7 +2377,125 @@ func TestCalledStartAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testi
 	}
 }
 
-func TestCalledStopApp_ExpectSuccess(t *testing.T) {
+func TestCalledRollbackApp_ExpectMembersRequestedByDescriptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	otherAgentId, newAgentId := "000000000000000000000003", "000000000000000000000004"
+	otherAgent := map[string]interface{}{"id": otherAgentId, "host": "192.168.0.3", "port": port, "apps": []string{appId}}
+	newAgent := map[string]interface{}{"id": newAgentId, "host": "192.168.0.4", "port": port, "apps": []string{appId}}
+	otherAddress := map[string]interface{}{"host": "192.168.0.3", "port": port}
+
+	previous := map[string]interface{}{"revision": 1, "descriptor": "services:", "checksum": "sum"}
+	agentRevisions := []map[string]interface{}{previous, {"revision": 2, "descriptor": body, "checksum": "sum"}}
+	otherRevisions := []map[string]interface{}{previous, {"revision": 2, "descriptor": "services: {}", "checksum": "sum"}}
+	newRevisions := []map[string]interface{}{{"revision": 1, "descriptor": body, "checksum": "sum"}}
+
+	respStr := []string{`{"message":"successMsg"}`, `{"message":"successMsg"}`}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return([]map[string]interface{}{agent, otherAgent, newAgent}, nil),
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(agentRevisions, nil),
+		dbManagerMockObj.EXPECT().GetAgentRevisions(otherAgentId, appId).Return(otherRevisions, nil),
+		dbManagerMockObj.EXPECT().GetAgentRevisions(newAgentId, appId).Return(newRevisions, nil),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), []map[string]interface{}{address, otherAddress}, appId, "services:").Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, "services:", gomock.Any(), "", "", gomock.Any()).Return(3, nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(otherAgentId, appId, "services:", gomock.Any(), "", "", gomock.Any()).Return(3, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.RollbackApp(context.Background(), groupId, appId, "")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.MULTI_STATUS {
+		t.Errorf("Expected code: %d, actual code: %d", results.MULTI_STATUS, code)
+	}
+
+	responses := res[RESPONSES].([]map[string]interface{})
+	for i, id := range []string{agentId, otherAgentId} {
+		if responses[i][ID] != id || responses[i][RESPONSE_CODE] != results.OK || responses[i]["revision"] != 3 || responses[i]["target"] != 1 {
+			t.Errorf("Unexpected response: %v", responses[i])
+		}
+	}
+	// A member without a previous revision fails without being contacted.
+	if responses[2][ID] != newAgentId || responses[2][RESPONSE_CODE] != results.ERROR || responses[2][ERROR_MESSAGE] == nil {
+		t.Errorf("Unexpected response: %v", responses[2])
+	}
+}
+
+func TestCalledRollbackAppWhenMessengerReturnsPartialSuccess_ExpectSuccessfulMemberRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revisions := []map[string]interface{}{
+		{"revision": 1, "descriptor": "services:", "checksum": "sum", "catalog": map[string]interface{}{"app": "line-monitor", "version": "1.3.0"}},
+		{"revision": 2, "descriptor": "services: {}", "checksum": "sum"},
+		{"revision": 3, "descriptor": body, "checksum": "sum"},
+	}
+	partialSuccessRespStr := []string{`{"message": "successMsg"}`, `{"message":"errorMsg"}`}
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(revisions, nil).Times(2),
+		msgMockObj.EXPECT().UpdateAppInfo(gomock.Any(), membersAddress, appId, "services:").Return(partialSuccessRespCode, partialSuccessRespStr),
+		dbManagerMockObj.EXPECT().UpdateAgentCatalog(agentId, appId, "line-monitor", "1.3.0").Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, "services:", gomock.Any(), "line-monitor", "1.3.0", gomock.Any()).Return(4, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+	httpMessenger = msgMockObj
+
+	code, res, err := controller.RollbackApp(context.Background(), groupId, appId, `{"revision":1}`)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if code != results.MULTI_STATUS {
+		t.Errorf("Expected code: %d, actual code: %d", results.MULTI_STATUS, code)
+	}
+
+	expectedRes := map[string]interface{}{
+		RESPONSES: []map[string]interface{}{
+			{ID: agentId, RESPONSE_CODE: results.OK, "revision": 4, "target": 1},
+			{ID: agentId, RESPONSE_CODE: results.ERROR, ERROR_MESSAGE: "errorMsg", "target": 1},
+		},
+	}
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %v, actual res: %v", expectedRes, res)
+	}
+}
+
+func TestCalledRollbackAppWithInvalidRevision_ExpectErrorReturn(t *testing.T) {
+	code, _, err := controller.RollbackApp(context.Background(), groupId, appId, `{"revision":"1"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	if !errors.Is(err, errors.InvalidParam{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	}
+}
+
+func TestCalledDeleteApp_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1720,16 +2504,17 @@ func TestCalledStopApp_ExpectSuccess(t *testing.T) {
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().StopApp(membersAddress, appId).Return(respCode, nil),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), membersAddress, appId).Return(respCode, nil),
+		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(nil).AnyTimes(),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StopApp(groupId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), groupId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1740,19 +2525,19 @@ func TestCalledStopApp_ExpectSuccess(t *testing.T) {
 	}
 }
 
-func TestCalledStopAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeleteAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.StopApp(groupId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1769,7 +2554,7 @@ func TestCalledStopAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
-func TestCalledStopAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeleteAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1777,14 +2562,14 @@ func TestCalledStopAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(nil, notFoundError),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.StopApp(groupId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1801,7 +2586,7 @@ func TestCalledStopAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
 	}
 }
 
-func TestCalledStopAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeleteAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1812,16 +2597,16 @@ func TestCalledStopAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *t
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().StopApp(membersAddress, appId).Return(respCode, invalidRespStr),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), membersAddress, appId).Return(respCode, invalidRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.StopApp(groupId, appId)
+	code, _, err := controller.DeleteApp(context.Background(), groupId, appId)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
@@ -1838,7 +2623,7 @@ func TestCalledStopAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *t
 	}
 }
 
-func TestCalledStopAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+func TestCalledDeleteAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1862,16 +2647,17 @@ func TestCalledStopAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testin
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
 		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().StopApp(membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
+		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.StopApp(groupId, appId)
+	code, res, err := controller.DeleteApp(context.Background(), groupId, appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1886,26 +2672,35 @@ func TestCalledStopAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testin
 	}
 }
 
-func TestCalledDeleteApp_ExpectSuccess(t *testing.T) {
+func TestCalledDeployAppBySelector_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	respStr := []string{`{"id":"000000000000000000000000"}`, `{"id":"000000000000000000000000"}`}
+	filter := map[string]string{"label.site": "plant3", "label.arch": "arm64"}
+	expectedRes := map[string]interface{}{
+		"id": "000000000000000000000000",
+	}
+
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().DeleteApp(membersAddress, appId).Return(respCode, nil),
-		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(nil).AnyTimes(),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentsByQuery(filter, "", 0, "").Return(members, "", nil),
+		msgMockObj.EXPECT().DeployApp(gomock.Any(), membersAddress, body).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
+		dbManagerMockObj.EXPECT().AddAppToAgent(agentId, appId).Return(nil),
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, body, gomock.Any(), "", "", gomock.Any()).Return(1, nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeleteApp(groupId, appId)
+	code, res, err := controller.DeployAppBySelector(context.Background(), "site=plant3,arch=arm64", body)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -1914,38 +2709,42 @@ func TestCalledDeleteApp_ExpectSuccess(t *testing.T) {
 	if code != results.OK {
 		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
-}
 
-func TestCalledDeleteAppWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	if !reflect.DeepEqual(expectedRes, res) {
+		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
+	}
+}
 
-	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+func TestCalledDeployAppBySelectorWithInvalidSelector_ExpectErrorReturn(t *testing.T) {
+	for _, selector := range []string{"", "site", "site=plant3,site=plant4", "site name=plant3"} {
+		ctrl := gomock.NewController(t)
 
-	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(nil, connectionError),
-	)
-	// pass mockObj to a real object.
-	dbConnector = dbConnectionMockObj
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
-	code, _, err := controller.DeleteApp(groupId, appId)
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
 
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
-	}
+		code, _, err := controller.DeployAppBySelector(context.Background(), selector, body)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", "nil")
-	}
+		if code != results.ERROR {
+			t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+		}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "DBConnectionError", err.Error())
-	case errors.DBConnectionError:
+		switch err.(type) {
+		default:
+			t.Errorf("Expected err: %s, actual err: %v for %s", "InvalidParam", err, selector)
+		case errors.InvalidParam:
+		}
+		ctrl.Finish()
 	}
 }
 
-func TestCalledDeleteAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeployAppBySelectorWhenNoAgentMatches_ExpectErrorReturn(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1953,72 +2752,65 @@ func TestCalledDeleteAppWhenDBHasNotMatchedGroup_ExpectErrorReturn(t *testing.T)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(nil, notFoundError),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentsByQuery(map[string]string{"label.site": "plant3"}, "", 0, "").Return([]map[string]interface{}{}, "", nil),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 
-	code, _, err := controller.DeleteApp(groupId, appId)
+	code, _, err := controller.DeployAppBySelector(context.Background(), "site=plant3", body)
 
 	if code != results.ERROR {
 		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
 	}
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", "nil")
-	}
-
 	switch err.(type) {
 	default:
-		t.Errorf("Expected err: %s, actual err: %s", "NotFound", err.Error())
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
 	case errors.NotFound:
 	}
 }
 
-func TestCalledDeleteAppWhenMessengerReturnsInvalidResponse_ExpectErrorReturn(t *testing.T) {
+func TestCalledDeleteAppBySelector_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	invalidRespStr := []string{`{"invalidJson"}`, `{"invalidJson"}`}
+	respStr := []string{`{"description":"description"}`, `{"description":"description"}`}
+	filter := map[string]string{"label.site": "plant3", "app": appId}
 
 	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
 	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().DeleteApp(membersAddress, appId).Return(respCode, invalidRespStr),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentsByQuery(filter, "", 0, "").Return(members, "", nil),
+		msgMockObj.EXPECT().DeleteApp(gomock.Any(), membersAddress, appId).Return(respCode, respStr),
+		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(nil).Times(2),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, _, err := controller.DeleteApp(groupId, appId)
-
-	if code != results.ERROR {
-		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
-	}
+	code, _, err := controller.DeleteAppBySelector(context.Background(), "site=plant3", appId)
 
-	if err == nil {
-		t.Errorf("Expected err: %s, actual err: %s", "InternalServerError", "nil")
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
 	}
 
-	switch err.(type) {
-	default:
-		t.Errorf("Expected err: %s, actual err: %s", "InternalServerError", err.Error())
-	case errors.InternalServerError:
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
 	}
 }
 
-func TestCalledDeleteAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
+func TestCalledStartAppBySelectorWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	partialSuccessRespStr := []string{`{"message": "successMsg"}`, `{"message":"errorMsg"}`}
+	filter := map[string]string{"label.site": "plant3", "app": appId}
 	expectedRes := map[string]interface{}{
 		"responses": []map[string]interface{}{
 			map[string]interface{}{
@@ -2038,17 +2830,16 @@ func TestCalledDeleteAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *test
 	msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
 
 	gomock.InOrder(
-		dbConnectionMockObj.EXPECT().Connect().Return(dbManagerMockObj, nil),
-		dbManagerMockObj.EXPECT().GetGroupMembersByAppID(groupId, appId).Return(members, nil),
-		msgMockObj.EXPECT().DeleteApp(membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
-		dbManagerMockObj.EXPECT().DeleteAppFromAgent(agentId, appId).Return(nil),
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAgentsByQuery(filter, "", 0, "").Return(members, "", nil),
+		msgMockObj.EXPECT().StartApp(gomock.Any(), membersAddress, appId).Return(partialSuccessRespCode, partialSuccessRespStr),
 		dbManagerMockObj.EXPECT().Close(),
 	)
 	// pass mockObj to a real object.
 	dbConnector = dbConnectionMockObj
 	httpMessenger = msgMockObj
 
-	code, res, err := controller.DeleteApp(groupId, appId)
+	code, res, err := controller.StartAppBySelector(context.Background(), "site=plant3", appId)
 
 	if err != nil {
 		t.Errorf("Unexpected err: %s", err.Error())
@@ -2062,3 +2853,54 @@ func TestCalledDeleteAppWhenMessengerReturnsPartialSuccess_ExpectSuccess(t *test
 		t.Errorf("Expected res: %s, actual res: %s", expectedRes, res)
 	}
 }
+
+func TestCalledStopAndUpdateAppBySelector_ExpectSuccess(t *testing.T) {
+	respStr := []string{`{"description":"description"}`, `{"description":"description"}`}
+	filter := map[string]string{"label.site": "plant3", "app": appId}
+
+	testList := []struct {
+		name   string
+		expect func(msgMockObj *msgmocks.MockMessengerInterface) *gomock.Call
+		call   func() (int, map[string]interface{}, error)
+	}{
+		{"StopApp", func(msgMockObj *msgmocks.MockMessengerInterface) *gomock.Call {
+			return msgMockObj.EXPECT().StopApp(gomock.Any(), membersAddress, appId).Return(respCode, respStr)
+		}, func() (int, map[string]interface{}, error) {
+			return controller.StopAppBySelector(context.Background(), "site=plant3", appId)
+		}},
+		{"UpdateApp", func(msgMockObj *msgmocks.MockMessengerInterface) *gomock.Call {
+			return msgMockObj.EXPECT().UpdateApp(gomock.Any(), membersAddress, appId).Return(respCode, respStr)
+		}, func() (int, map[string]interface{}, error) {
+			return controller.UpdateAppBySelector(context.Background(), "site=plant3", appId)
+		}},
+	}
+
+	for _, test := range testList {
+		ctrl := gomock.NewController(t)
+
+		dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+		dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+		msgMockObj := msgmocks.NewMockMessengerInterface(ctrl)
+
+		gomock.InOrder(
+			dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+			dbManagerMockObj.EXPECT().GetAgentsByQuery(filter, "", 0, "").Return(members, "", nil),
+			test.expect(msgMockObj),
+			dbManagerMockObj.EXPECT().Close(),
+		)
+		// pass mockObj to a real object.
+		dbConnector = dbConnectionMockObj
+		httpMessenger = msgMockObj
+
+		code, _, err := test.call()
+
+		if err != nil {
+			t.Errorf("Unexpected err: %s for %s", err.Error(), test.name)
+		}
+
+		if code != results.OK {
+			t.Errorf("Expected code: %d, actual code: %d for %s", results.OK, code, test.name)
+		}
+		ctrl.Finish()
+	}
+}
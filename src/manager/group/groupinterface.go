@@ -16,47 +16,87 @@
  *******************************************************************************/
 package group
 
+import (
+	"context"
+	"net/url"
+)
+
 type GroupInterface interface {
 	// CreateGroup inserts a new group to databases.
-	CreateGroup() (int, map[string]interface{}, error)
+	CreateGroup(ctx context.Context) (int, map[string]interface{}, error)
 
 	// GetGroup returns the information of the group specified by groupId parameter.
-	GetGroup(groupId string) (int, map[string]interface{}, error)
+	GetGroup(ctx context.Context, groupId string) (int, map[string]interface{}, error)
 
 	// GetGroups returns a list of groups that is created on databases.
-	GetGroups() (int, map[string]interface{}, error)
+	GetGroups(ctx context.Context, query url.Values) (int, map[string]interface{}, error)
+
+	// GetAvailability returns the availability of the group and its members in the period of query parameters.
+	GetAvailability(ctx context.Context, groupId string, query url.Values) (int, map[string]interface{}, error)
 
 	// JoinGroup adds the agent to a list of members.
-	JoinGroup(groupId string, body string) (int, map[string]interface{}, error)
+	JoinGroup(ctx context.Context, groupId string, body string) (int, map[string]interface{}, error)
 
 	// LeaveGroup removes the agent from a list of members.
-	LeaveGroup(groupId string, body string) (int, map[string]interface{}, error)
+	LeaveGroup(ctx context.Context, groupId string, body string) (int, map[string]interface{}, error)
 
 	// DeleteGroup deletes the group with a primary key matching the groupId argument.
-	DeleteGroup(groupId string) (int, map[string]interface{}, error)
+	DeleteGroup(ctx context.Context, groupId string) (int, map[string]interface{}, error)
 
 	// DeployApp request an deployment of edge services to a group specified by groupId parameter.
-	DeployApp(groupId string, body string) (int, map[string]interface{}, error)
+	DeployApp(ctx context.Context, groupId string, body string) (int, map[string]interface{}, error)
 
 	// GetApps request a list of applications that is deployed to a group specified by groupId parameter.
-	GetApps(groupId string) (int, map[string]interface{}, error)
+	GetApps(ctx context.Context, groupId string) (int, map[string]interface{}, error)
 
 	// GetApp gets the application's information of the group specified by groupId parameter.
-	GetApp(groupId string, appId string) (int, map[string]interface{}, error)
+	GetApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error)
 
 	// UpdateApp request to update an application specified by appId parameter to all members of the group.
-	UpdateAppInfo(groupId string, appId string, body string) (int, map[string]interface{}, error)
+	UpdateAppInfo(ctx context.Context, groupId string, appId string, body string) (int, map[string]interface{}, error)
+
+	// RollbackApp request to update an application specified by appId parameter with the descriptor
+	// of an earlier revision to all members of the group.
+	RollbackApp(ctx context.Context, groupId string, appId string, body string) (int, map[string]interface{}, error)
 
 	// DeleteApp request to delete an application specified by appId parameter to all members of the group.
-	DeleteApp(groupId string, appId string) (int, map[string]interface{}, error)
+	DeleteApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error)
 
 	// UpdateAppInfo request to update all of images which is included an application specified by
 	// appId parameter to all members of the group.
-	UpdateApp(groupId string, appId string) (int, map[string]interface{}, error)
+	UpdateApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error)
 
 	// StartApp request to start an application specified by appId parameter to all members of the group.
-	StartApp(groupId string, appId string) (int, map[string]interface{}, error)
+	StartApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error)
 
 	// StopApp request to stop an application specified by appId parameter to all members of the group.
-	StopApp(groupId string, appId string) (int, map[string]interface{}, error)
+	StopApp(ctx context.Context, groupId string, appId string) (int, map[string]interface{}, error)
+
+	// DeployAppBySelector request an deployment of edge services to agents matching the selector.
+	DeployAppBySelector(ctx context.Context, selector string, body string) (int, map[string]interface{}, error)
+
+	// DeleteAppBySelector request to delete an application specified by appId parameter to agents matching the selector.
+	DeleteAppBySelector(ctx context.Context, selector string, appId string) (int, map[string]interface{}, error)
+
+	// UpdateAppBySelector request to update all of images which is included an application
+	// specified by appId parameter to agents matching the selector.
+	UpdateAppBySelector(ctx context.Context, selector string, appId string) (int, map[string]interface{}, error)
+
+	// StartAppBySelector request to start an application specified by appId parameter to agents matching the selector.
+	StartAppBySelector(ctx context.Context, selector string, appId string) (int, map[string]interface{}, error)
+
+	// StopAppBySelector request to stop an application specified by appId parameter to agents matching the selector.
+	StopAppBySelector(ctx context.Context, selector string, appId string) (int, map[string]interface{}, error)
+
+	// SetDesiredState declares the apps which members of the group should run.
+	SetDesiredState(ctx context.Context, groupId string, body string) (int, map[string]interface{}, error)
+
+	// GetDesiredState returns the desired state of the group with the convergence of each member.
+	GetDesiredState(ctx context.Context, groupId string) (int, map[string]interface{}, error)
+
+	// DeleteDesiredState removes the desired state of the group.
+	DeleteDesiredState(ctx context.Context, groupId string) (int, map[string]interface{}, error)
+
+	// Converge converges members of the group toward its desired state right away.
+	Converge(ctx context.Context, groupId string) (int, map[string]interface{}, error)
 }
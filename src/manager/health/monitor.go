@@ -0,0 +1,372 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+// Package manager/health tracks liveness of agents by the deadlines of their heartbeats.
+// A single scheduler owns the deadlines in a heap, so heartbeats from many requests
+// are handled without a goroutine or a timer for each agent.
+package health
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to Monitor.
+// It is replaced in tests to advance the time without waiting.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer returns a timer which fires after the duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer created by Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing.
+	Stop() bool
+}
+
+// SystemClock is Clock based on the time package.
+type SystemClock struct{}
+
+type systemTimer struct {
+	timer *time.Timer
+}
+
+// Now returns the current local time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer returns a timer which fires after the duration d.
+func (SystemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// Monitor calls the expire function with the id of an agent
+// when the agent does not send a heartbeat before its deadline.
+// A heartbeat of an agent whose expire function is running waits until it returns,
+// so the heartbeat always sees the result of the expiry.
+// All methods of Monitor are safe for concurrent use.
+type Monitor struct {
+	clock  Clock
+	expire func(id string)
+
+	mutex     sync.Mutex
+	expired   *sync.Cond
+	entries   map[string]*entry
+	deadlines deadlineHeap
+
+	wake     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// entry is the state of an agent tracked by Monitor.
+// index is the position in the heap, or -1 if the deadline has passed.
+// generation is increased whenever the deadline is changed, expired is true
+// once the agent is regarded as expired, and expiring is true while the expire
+// function is running for the agent.
+type entry struct {
+	id         string
+	deadline   time.Time
+	index      int
+	generation uint64
+	expired    bool
+	expiring   bool
+}
+
+// expiry is an agent whose deadline has passed at the generation of its entry.
+type expiry struct {
+	id         string
+	generation uint64
+}
+
+// NewMonitor creates a Monitor with the clock and starts its scheduler.
+// expire is called from the scheduler, one agent at a time.
+func NewMonitor(clock Clock, expire func(id string)) *Monitor {
+	monitor := &Monitor{
+		clock:   clock,
+		expire:  expire,
+		entries: make(map[string]*entry),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	monitor.expired = sync.NewCond(&monitor.mutex)
+	go monitor.run()
+	return monitor
+}
+
+// Beat records a heartbeat of the agent, and its next heartbeat is expected within the timeout.
+// This function returns true if the agent had been expired before the heartbeat.
+func (monitor *Monitor) Beat(id string, timeout time.Duration) bool {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	monitor.waitExpiring(id)
+	expired := false
+	if e, exists := monitor.entries[id]; exists {
+		expired = e.expired
+	}
+	monitor.schedule(id, monitor.clock.Now().Add(timeout))
+	return expired
+}
+
+// Extend records a heartbeat of the agent like Beat, but keeps the deadline of the agent
+// if it is later than the timeout, e.g. when heartbeats come from more than one source.
+// This function returns true if the agent had been expired before the heartbeat.
+func (monitor *Monitor) Extend(id string, timeout time.Duration) bool {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	monitor.waitExpiring(id)
+	deadline := monitor.clock.Now().Add(timeout)
+	e, exists := monitor.entries[id]
+	if exists && e.index >= 0 && !deadline.After(e.deadline) {
+		return false
+	}
+
+	expired := exists && e.expired
+	monitor.schedule(id, deadline)
+	return expired
+}
+
+// Watch starts tracking the agent with the deadline, e.g. when the deadline is rebuilt at start-up.
+// If the deadline has already passed, the agent is regarded as expired without calling the expire function,
+// and this function returns true.
+func (monitor *Monitor) Watch(id string, deadline time.Time) bool {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	monitor.waitExpiring(id)
+	if deadline.After(monitor.clock.Now()) {
+		monitor.schedule(id, deadline)
+		return false
+	}
+
+	generation := uint64(0)
+	if e, exists := monitor.entries[id]; exists {
+		generation = e.generation + 1
+	}
+	monitor.remove(id)
+	monitor.entries[id] = &entry{id: id, deadline: deadline, index: -1, generation: generation, expired: true}
+	return true
+}
+
+// Remove stops tracking the agent.
+func (monitor *Monitor) Remove(id string) {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	monitor.waitExpiring(id)
+	monitor.remove(id)
+}
+
+// Stop stops the scheduler without expiring any agent.
+// The expire function which is already running is waited for until it returns
+// or the given context is done.
+// If successful, this function returns an error as nil.
+// otherwise, the error of the context will be returned.
+func (monitor *Monitor) Stop(ctx context.Context) error {
+	monitor.stopOnce.Do(func() {
+		close(monitor.stop)
+	})
+
+	select {
+	case <-monitor.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// schedule sets the deadline of the agent and wakes the scheduler up.
+// The caller must hold the mutex.
+func (monitor *Monitor) schedule(id string, deadline time.Time) {
+	e, exists := monitor.entries[id]
+	if !exists {
+		e = &entry{id: id, index: -1}
+		monitor.entries[id] = e
+	}
+
+	e.deadline = deadline
+	e.generation++
+	e.expired = false
+	if e.index < 0 {
+		heap.Push(&monitor.deadlines, e)
+	} else {
+		heap.Fix(&monitor.deadlines, e.index)
+	}
+
+	select {
+	case monitor.wake <- struct{}{}:
+	default:
+	}
+}
+
+// remove deletes the agent from the entries and the heap.
+// The caller must hold the mutex.
+func (monitor *Monitor) remove(id string) {
+	e, exists := monitor.entries[id]
+	if !exists {
+		return
+	}
+	if e.index >= 0 {
+		heap.Remove(&monitor.deadlines, e.index)
+	}
+	delete(monitor.entries, id)
+}
+
+// popExpired removes agents whose deadline has passed from the heap,
+// and returns them with the time to wait for the next deadline.
+// If no deadline remains, the time to wait is negative.
+func (monitor *Monitor) popExpired() ([]expiry, time.Duration) {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	now := monitor.clock.Now()
+	var expiries []expiry
+	for len(monitor.deadlines) > 0 && !monitor.deadlines[0].deadline.After(now) {
+		e := heap.Pop(&monitor.deadlines).(*entry)
+		expiries = append(expiries, expiry{id: e.id, generation: e.generation})
+	}
+
+	if len(monitor.deadlines) == 0 {
+		return expiries, -1
+	}
+	return expiries, monitor.deadlines[0].deadline.Sub(now)
+}
+
+// expireAgent calls the expire function for the agent unless the agent has sent
+// a heartbeat or has been removed after its deadline was popped.
+// Heartbeats of the agent wait until the expire function returns.
+func (monitor *Monitor) expireAgent(x expiry) {
+	monitor.mutex.Lock()
+	e, exists := monitor.entries[x.id]
+	if !exists || e.index >= 0 || e.generation != x.generation {
+		monitor.mutex.Unlock()
+		return
+	}
+	e.expired = true
+	e.expiring = true
+	monitor.mutex.Unlock()
+
+	defer func() {
+		monitor.mutex.Lock()
+		e.expiring = false
+		monitor.expired.Broadcast()
+		monitor.mutex.Unlock()
+	}()
+	monitor.expire(x.id)
+}
+
+// waitExpiring waits until the expire function for the agent returns, if it is running.
+// The caller must hold the mutex.
+func (monitor *Monitor) waitExpiring(id string) {
+	for {
+		e, exists := monitor.entries[id]
+		if !exists || !e.expiring {
+			return
+		}
+		monitor.expired.Wait()
+	}
+}
+
+// run is the scheduler which waits for the earliest deadline and expires agents.
+func (monitor *Monitor) run() {
+	defer close(monitor.done)
+
+	for {
+		expiries, wait := monitor.popExpired()
+		for _, x := range expiries {
+			select {
+			case <-monitor.stop:
+				return
+			default:
+			}
+			monitor.expireAgent(x)
+		}
+
+		var timer Timer
+		var fired <-chan time.Time
+		if wait >= 0 {
+			timer = monitor.clock.NewTimer(wait)
+			fired = timer.C()
+		}
+
+		select {
+		case <-fired:
+		case <-monitor.wake:
+		case <-monitor.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// deadlineHeap implements heap.Interface ordered by the deadline of entries.
+type deadlineHeap []*entry
+
+func (h deadlineHeap) Len() int {
+	return len(h)
+}
+
+func (h deadlineHeap) Less(i, j int) bool {
+	return h[i].deadline.Before(h[j].deadline)
+}
+
+func (h deadlineHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *deadlineHeap) Push(x interface{}) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *deadlineHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
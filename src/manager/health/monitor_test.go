@@ -0,0 +1,409 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package health
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	agentId      = "000000000000000000000001"
+	otherAgentId = "000000000000000000000002"
+	waitTime     = time.Second
+	quietTime    = 50 * time.Millisecond
+)
+
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	c        chan time.Time
+	stopped  bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1500000000, 0)}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) NewTimer(d time.Duration) Timer {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	timer := &fakeTimer{clock: clock, deadline: clock.now.Add(d), c: make(chan time.Time, 1)}
+	clock.timers = append(clock.timers, timer)
+	return timer
+}
+
+// Advance moves the time forward and fires timers whose deadline has come.
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = clock.now.Add(d)
+	pending := clock.timers[:0]
+	for _, timer := range clock.timers {
+		if timer.stopped {
+			continue
+		}
+		if timer.deadline.After(clock.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.stopped = true
+		timer.c <- clock.now
+	}
+	clock.timers = pending
+}
+
+// waitTimer blocks until a timer with the deadline is waited for by the scheduler.
+func (clock *fakeClock) waitTimer(t *testing.T, deadline time.Time) {
+	for start := time.Now(); time.Since(start) < waitTime; time.Sleep(time.Millisecond) {
+		clock.mutex.Lock()
+		for _, timer := range clock.timers {
+			if !timer.stopped && timer.deadline.Equal(deadline) {
+				clock.mutex.Unlock()
+				return
+			}
+		}
+		clock.mutex.Unlock()
+	}
+	t.Fatalf("Expected timer with deadline: %v", deadline)
+}
+
+func (timer *fakeTimer) C() <-chan time.Time {
+	return timer.c
+}
+
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.mutex.Lock()
+	defer timer.clock.mutex.Unlock()
+
+	stopped := timer.stopped
+	timer.stopped = true
+	return !stopped
+}
+
+type tearDown func()
+
+func setUp() (*Monitor, *fakeClock, chan string, tearDown) {
+	clock := newFakeClock()
+	expired := make(chan string, 100)
+	monitor := NewMonitor(clock, func(id string) {
+		expired <- id
+	})
+	return monitor, clock, expired, func() {
+		monitor.Stop(context.Background())
+	}
+}
+
+func expectExpired(t *testing.T, expired chan string, id string) {
+	select {
+	case actual := <-expired:
+		if actual != id {
+			t.Errorf("Expected expired agent: %s, actual expired agent: %s", id, actual)
+		}
+	case <-time.After(waitTime):
+		t.Errorf("Expected expired agent: %s, actual expired agent: %s", id, "none")
+	}
+}
+
+func expectNotExpired(t *testing.T, expired chan string) {
+	select {
+	case actual := <-expired:
+		t.Errorf("Unexpected expired agent: %s", actual)
+	case <-time.After(quietTime):
+	}
+}
+
+func TestCalledBeatWithoutNextBeat_ExpectExpiredAfterTimeout(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	if monitor.Beat(agentId, 10*time.Second) {
+		t.Errorf("Expected expired: %t, actual expired: %t", false, true)
+	}
+	clock.waitTimer(t, clock.Now().Add(10*time.Second))
+
+	clock.Advance(9 * time.Second)
+	expectNotExpired(t, expired)
+
+	clock.Advance(time.Second)
+	expectExpired(t, expired, agentId)
+
+	if !monitor.Beat(agentId, 10*time.Second) {
+		t.Errorf("Expected expired: %t, actual expired: %t", true, false)
+	}
+}
+
+func TestCalledBeatBeforeDeadline_ExpectDeadlineExtended(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	monitor.Beat(agentId, 10*time.Second)
+	clock.waitTimer(t, clock.Now().Add(10*time.Second))
+	clock.Advance(5 * time.Second)
+
+	if monitor.Beat(agentId, 10*time.Second) {
+		t.Errorf("Expected expired: %t, actual expired: %t", false, true)
+	}
+	clock.waitTimer(t, clock.Now().Add(10*time.Second))
+
+	clock.Advance(5 * time.Second)
+	expectNotExpired(t, expired)
+
+	clock.Advance(5 * time.Second)
+	expectExpired(t, expired, agentId)
+}
+
+func TestCalledBeatWithSeveralAgents_ExpectExpiredInOrderOfDeadline(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	monitor.Beat(otherAgentId, 20*time.Second)
+	monitor.Beat(agentId, 10*time.Second)
+	clock.waitTimer(t, clock.Now().Add(10*time.Second))
+
+	clock.Advance(30 * time.Second)
+	expectExpired(t, expired, agentId)
+	expectExpired(t, expired, otherAgentId)
+}
+
+func TestCalledBeatConcurrently_ExpectAllAgentsExpired(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			monitor.Beat(string(rune('A'+i)), time.Duration(i+1)*time.Second)
+		}(i)
+	}
+	wg.Wait()
+	clock.waitTimer(t, clock.Now().Add(time.Second))
+
+	clock.Advance(time.Minute)
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		select {
+		case id := <-expired:
+			seen[id] = true
+		case <-time.After(waitTime):
+			t.Fatalf("Expected expired agents: %d, actual expired agents: %d", 50, len(seen))
+		}
+	}
+	if len(seen) != 50 {
+		t.Errorf("Expected expired agents: %d, actual expired agents: %d", 50, len(seen))
+	}
+}
+
+func TestCalledExtendBeforeLaterDeadline_ExpectDeadlineKept(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	monitor.Beat(agentId, 20*time.Second)
+	clock.waitTimer(t, clock.Now().Add(20*time.Second))
+
+	if monitor.Extend(agentId, 10*time.Second) {
+		t.Errorf("Expected expired: %t, actual expired: %t", false, true)
+	}
+
+	clock.Advance(10 * time.Second)
+	expectNotExpired(t, expired)
+
+	if monitor.Extend(agentId, 15*time.Second) {
+		t.Errorf("Expected expired: %t, actual expired: %t", false, true)
+	}
+	clock.waitTimer(t, clock.Now().Add(15*time.Second))
+
+	clock.Advance(10 * time.Second)
+	expectNotExpired(t, expired)
+
+	clock.Advance(5 * time.Second)
+	expectExpired(t, expired, agentId)
+
+	if !monitor.Extend(agentId, 10*time.Second) {
+		t.Errorf("Expected expired: %t, actual expired: %t", true, false)
+	}
+}
+
+func TestCalledWatchWithPassedDeadline_ExpectExpiredWithoutCallback(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	if !monitor.Watch(agentId, clock.Now().Add(-time.Second)) {
+		t.Errorf("Expected overdue: %t, actual overdue: %t", true, false)
+	}
+	expectNotExpired(t, expired)
+
+	if !monitor.Beat(agentId, 10*time.Second) {
+		t.Errorf("Expected expired: %t, actual expired: %t", true, false)
+	}
+}
+
+func TestCalledWatchWithFutureDeadline_ExpectExpiredAtDeadline(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	deadline := clock.Now().Add(3 * time.Second)
+	if monitor.Watch(agentId, deadline) {
+		t.Errorf("Expected overdue: %t, actual overdue: %t", false, true)
+	}
+	clock.waitTimer(t, deadline)
+
+	clock.Advance(3 * time.Second)
+	expectExpired(t, expired, agentId)
+}
+
+func TestCalledRemove_ExpectNotExpired(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	monitor.Beat(agentId, 10*time.Second)
+	clock.waitTimer(t, clock.Now().Add(10*time.Second))
+	monitor.Remove(agentId)
+
+	clock.Advance(10 * time.Second)
+	expectNotExpired(t, expired)
+
+	if monitor.Beat(agentId, 10*time.Second) {
+		t.Errorf("Expected expired: %t, actual expired: %t", false, true)
+	}
+}
+
+func TestCalledStop_ExpectNotExpired(t *testing.T) {
+	monitor, clock, expired, _ := setUp()
+
+	monitor.Beat(agentId, 10*time.Second)
+	clock.waitTimer(t, clock.Now().Add(10*time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTime)
+	defer cancel()
+
+	err := monitor.Stop(ctx)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	clock.Advance(10 * time.Second)
+	expectNotExpired(t, expired)
+}
+
+func TestCalledStopWhenExpireIsRunning_ExpectContextErrorReturn(t *testing.T) {
+	clock := newFakeClock()
+	running := make(chan bool)
+	release := make(chan bool)
+	monitor := NewMonitor(clock, func(id string) {
+		running <- true
+		<-release
+	})
+	defer close(release)
+
+	monitor.Beat(agentId, time.Second)
+	clock.waitTimer(t, clock.Now().Add(time.Second))
+	clock.Advance(time.Second)
+	<-running
+
+	ctx, cancel := context.WithTimeout(context.Background(), quietTime)
+	defer cancel()
+
+	err := monitor.Stop(ctx)
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected err: %v, actual err: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestCalledBeatAfterDeadlinePopped_ExpectNotExpired(t *testing.T) {
+	monitor, clock, expired, tearDown := setUp()
+	defer tearDown()
+
+	monitor.Beat(agentId, time.Second)
+	clock.waitTimer(t, clock.Now().Add(time.Second))
+
+	// The deadline passes without firing the timer, so that the scheduler does not pop it.
+	clock.mutex.Lock()
+	clock.now = clock.now.Add(time.Second)
+	clock.mutex.Unlock()
+
+	expiries, _ := monitor.popExpired()
+	if len(expiries) != 1 || expiries[0].id != agentId {
+		t.Fatalf("Expected popped agent: %s, actual popped agents: %v", agentId, expiries)
+	}
+
+	// A heartbeat lands between the pop and the expiry.
+	if monitor.Beat(agentId, time.Second) {
+		t.Error("Expected heartbeat not to be regarded as after expiry")
+	}
+
+	monitor.expireAgent(expiries[0])
+	expectNotExpired(t, expired)
+}
+
+func TestCalledBeatWhileExpireIsRunning_ExpectBeatWaitsForExpire(t *testing.T) {
+	clock := newFakeClock()
+	running := make(chan bool)
+	release := make(chan bool)
+	monitor := NewMonitor(clock, func(id string) {
+		running <- true
+		<-release
+	})
+	defer monitor.Stop(context.Background())
+
+	monitor.Beat(agentId, time.Second)
+	clock.waitTimer(t, clock.Now().Add(time.Second))
+	clock.Advance(time.Second)
+	<-running
+
+	beaten := make(chan bool, 1)
+	go func() {
+		beaten <- monitor.Beat(agentId, time.Second)
+	}()
+
+	select {
+	case <-beaten:
+		t.Error("Expected heartbeat to wait for the expiry")
+	case <-time.After(quietTime):
+	}
+
+	close(release)
+
+	select {
+	case expired := <-beaten:
+		if !expired {
+			t.Error("Expected heartbeat to be regarded as after expiry")
+		}
+	case <-time.After(waitTime):
+		t.Error("Expected heartbeat to return after the expiry")
+	}
+}
@@ -0,0 +1,106 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Prober calls the probe function periodically, e.g. to check liveness of agents
+// which do not send heartbeats by themselves.
+// All methods of Prober are safe for concurrent use.
+type Prober struct {
+	clock    Clock
+	interval time.Duration
+	probe    func()
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewProber creates a Prober with the clock and starts it.
+// probe is called right away and then every interval after the previous call returns.
+func NewProber(clock Clock, interval time.Duration, probe func()) *Prober {
+	prober := &Prober{
+		clock:    clock,
+		interval: interval,
+		probe:    probe,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go prober.run()
+	return prober
+}
+
+// Stop stops calling the probe function.
+// The probe function which is already running is waited for until it returns
+// or the given context is done.
+// If successful, this function returns an error as nil.
+// otherwise, the error of the context will be returned.
+func (prober *Prober) Stop(ctx context.Context) error {
+	prober.stopOnce.Do(func() {
+		close(prober.stop)
+	})
+
+	select {
+	case <-prober.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run calls the probe function until the prober is stopped.
+func (prober *Prober) run() {
+	defer close(prober.done)
+
+	for {
+		prober.probe()
+
+		timer := prober.clock.NewTimer(prober.interval)
+		select {
+		case <-timer.C():
+		case <-prober.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// ProbeAll calls probe with each index from 0 to count-1, with at most
+// concurrency calls running at a time, and returns when all calls return.
+func ProbeAll(count int, concurrency int, probe func(index int)) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+
+	for i := 0; i < count; i++ {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(index int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			probe(index)
+		}(i)
+	}
+	wg.Wait()
+}
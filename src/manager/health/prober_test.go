@@ -0,0 +1,112 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package health
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func expectProbed(t *testing.T, probed chan bool) {
+	select {
+	case <-probed:
+	case <-time.After(waitTime):
+		t.Error("Expected probe function to be called")
+	}
+}
+
+func TestCalledNewProber_ExpectProbedEveryInterval(t *testing.T) {
+	clock := newFakeClock()
+	probed := make(chan bool, 10)
+	prober := NewProber(clock, 30*time.Second, func() {
+		probed <- true
+	})
+	defer prober.Stop(context.Background())
+
+	expectProbed(t, probed)
+	clock.waitTimer(t, clock.Now().Add(30*time.Second))
+
+	clock.Advance(29 * time.Second)
+	select {
+	case <-probed:
+		t.Error("Unexpected probe before interval")
+	case <-time.After(quietTime):
+	}
+
+	clock.Advance(time.Second)
+	expectProbed(t, probed)
+}
+
+func TestCalledStopOfProber_ExpectNotProbed(t *testing.T) {
+	clock := newFakeClock()
+	probed := make(chan bool, 10)
+	prober := NewProber(clock, 30*time.Second, func() {
+		probed <- true
+	})
+
+	expectProbed(t, probed)
+	clock.waitTimer(t, clock.Now().Add(30*time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTime)
+	defer cancel()
+
+	err := prober.Stop(ctx)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case <-probed:
+		t.Error("Unexpected probe after stop")
+	case <-time.After(quietTime):
+	}
+}
+
+func TestCalledProbeAll_ExpectAllProbedWithBoundedConcurrency(t *testing.T) {
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	probed := make([]bool, 20)
+
+	ProbeAll(len(probed), 3, func(index int) {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mutex.Lock()
+		running--
+		probed[index] = true
+		mutex.Unlock()
+	})
+
+	for i, ok := range probed {
+		if !ok {
+			t.Errorf("Expected index %d to be probed", i)
+		}
+	}
+	if maxRunning > 3 {
+		t.Errorf("Expected concurrency: %d, actual concurrency: %d", 3, maxRunning)
+	}
+}
@@ -0,0 +1,246 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package key provides operations to manage API keys which are used
+// to authenticate operators (e.g., create, get, delete, verify...).
+// Only the SHA-256 hash of a key is stored, so the key is shown once when it is created.
+// A key is granted one of roles, and may be limited to a list of groups.
+package key
+
+import (
+	"commons/errors"
+	"commons/logger"
+	"commons/results"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"db"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+)
+
+const (
+	KEYS      = "keys"   // used to indicate a list of keys.
+	ID        = "id"     // used to indicate a key id.
+	NAME      = "name"   // used to indicate a name of key.
+	ROLE      = "role"   // used to indicate a role granted to key.
+	GROUPS    = "groups" // used to indicate a list of groups which key is limited to.
+	KEY       = "key"    // used to indicate an issued key.
+	KEY_BYTES = 32       // the number of random bytes of a key.
+)
+
+const (
+	VIEWER   = "viewer"   // allowed to get information only.
+	OPERATOR = "operator" // allowed to start, stop and update apps in addition to VIEWER.
+	ADMIN    = "admin"    // allowed to every operation.
+)
+
+type KeyController struct{}
+
+var dbConnector db.DBConnection
+var randReader io.Reader
+
+func init() {
+	dbConnector = db.DBConnector{}
+	randReader = rand.Reader
+}
+
+// CreateKey issues a new API key with the name, the role and the groups given in body.
+// If groups is not given, the key is not limited to any group.
+// If successful, the key is returned with its id and name.
+// otherwise, an appropriate error will be returned.
+func (KeyController) CreateKey(ctx context.Context, body string) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	bodyMap, err := convertJsonToMap(body)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	name, ok := bodyMap[NAME].(string)
+	if !ok || name == "" {
+		return results.ERROR, nil, errors.InvalidJSON{Message: "name field is required"}
+	}
+
+	role, ok := bodyMap[ROLE].(string)
+	if !ok || !IsRole(role) {
+		return results.ERROR, nil, errors.InvalidParam{Message: "role should be one of viewer, operator and admin"}
+	}
+
+	groups, err := getGroups(bodyMap)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	random := make([]byte, KEY_BYTES)
+	if _, err = io.ReadFull(randReader, random); err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, errors.InternalServerError{Cause: err}
+	}
+	key := hex.EncodeToString(random)
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	res, err := db.AddKey(name, role, groups, hashKey(key))
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res[KEY] = key
+	return results.OK, res, err
+}
+
+// GetKeys returns all API keys in databases as an array.
+// The keys themselves are not included.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (KeyController) GetKeys(ctx context.Context) (int, map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+	defer db.Close()
+
+	keys, err := db.GetAllKeys()
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, nil, err
+	}
+
+	res := make(map[string]interface{})
+	res[KEYS] = keys
+	return results.OK, res, err
+}
+
+// DeleteKey revokes the API key with a primary key matching the keyId argument.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func (KeyController) DeleteKey(ctx context.Context, keyId string) (int, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
+	defer db.Close()
+
+	err = db.DeleteKey(keyId)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return results.ERROR, err
+	}
+
+	return results.OK, err
+}
+
+// VerifyKey returns the API key matching the key argument.
+// If the key is not issued or revoked, Unauthorized error will be returned.
+// otherwise, an appropriate error will be returned on failure.
+func (KeyController) VerifyKey(ctx context.Context, key string) (map[string]interface{}, error) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	// Connect to the database.
+	db, err := dbConnector.Connect(ctx)
+	if err != nil {
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return nil, err
+	}
+	defer db.Close()
+
+	res, err := db.GetKeyByHash(hashKey(key))
+	if err != nil {
+		switch err.(type) {
+		case errors.NotFound:
+			return nil, errors.Unauthorized{Message: "unknown api key"}
+		}
+		logger.LoggingContext(ctx, logger.ERROR, err.Error())
+		return nil, err
+	}
+
+	return res, err
+}
+
+// IsRole returns true if role is one of VIEWER, OPERATOR and ADMIN.
+func IsRole(role string) bool {
+	switch role {
+	case VIEWER, OPERATOR, ADMIN:
+		return true
+	}
+	return false
+}
+
+// getGroups returns a list of group ids in groups field of body.
+// If the field is not given, nil will be returned.
+func getGroups(bodyMap map[string]interface{}) ([]string, error) {
+	value, exists := bodyMap[GROUPS]
+	if !exists {
+		return nil, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.InvalidParam{Message: "groups should be a list of group ids"}
+	}
+
+	groups := make([]string, len(list))
+	for i, item := range list {
+		groupId, ok := item.(string)
+		if !ok || groupId == "" {
+			return nil, errors.InvalidParam{Message: "groups should be a list of group ids"}
+		}
+		groups[i] = groupId
+	}
+	return groups, nil
+}
+
+// hashKey returns the hex encoded SHA-256 hash of the key.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// convertJsonToMap converts JSON data into a map.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func convertJsonToMap(jsonStr string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	err := json.Unmarshal([]byte(jsonStr), &result)
+	if err != nil {
+		return nil, errors.InvalidJSON{Message: "Unmarshalling Failed"}
+	}
+	return result, err
+}
@@ -0,0 +1,281 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package key
+
+import (
+	"bytes"
+	"commons/errors"
+	"commons/results"
+	"context"
+	"crypto/rand"
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	"testing"
+)
+
+const (
+	keyId   = "000000000000000000000003"
+	groupId = "000000000000000000000002"
+	name    = "operator"
+)
+
+var (
+	keyMap          = map[string]interface{}{"id": keyId, "name": name}
+	notFoundError   = errors.NotFound{}
+	connectionError = errors.DBConnectionError{}
+)
+
+var controller KeyInterface
+
+func init() {
+	controller = KeyController{}
+}
+
+func TestCalledCreateKey_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	randReader = bytes.NewReader(make([]byte, KEY_BYTES))
+	defer func() { randReader = rand.Reader }()
+	expectedKey := "0000000000000000000000000000000000000000000000000000000000000000"
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().AddKey(name, OPERATOR, []string{groupId}, hashKey(expectedKey)).Return(map[string]interface{}{"id": keyId, "name": name}, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.CreateKey(context.Background(), `{"name":"operator","role":"operator","groups":["`+groupId+`"]}`)
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res[KEY] != expectedKey {
+		t.Errorf("Expected key: %s, actual key: %v", expectedKey, res[KEY])
+	}
+}
+
+func TestCalledCreateKeyWithoutName_ExpectErrorReturn(t *testing.T) {
+	code, _, err := controller.CreateKey(context.Background(), `{}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+	case errors.InvalidJSON:
+	}
+}
+
+func TestCalledCreateKeyWithInvalidParams_ExpectErrorReturn(t *testing.T) {
+	testList := []struct {
+		name string
+		body string
+	}{
+		{"WithoutRole", `{"name":"operator"}`},
+		{"UnknownRole", `{"name":"operator","role":"root"}`},
+		{"InvalidGroups", `{"name":"operator","role":"viewer","groups":"group"}`},
+		{"InvalidGroupId", `{"name":"operator","role":"viewer","groups":[1]}`},
+	}
+
+	for _, test := range testList {
+		t.Run(test.name, func(t *testing.T) {
+			code, _, err := controller.CreateKey(context.Background(), test.body)
+
+			if code != results.ERROR {
+				t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+			}
+
+			switch err.(type) {
+			default:
+				t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+			case errors.InvalidParam:
+			}
+		})
+	}
+}
+
+func TestCalledCreateKeyWhenDBConnectionFailed_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(nil, connectionError),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, _, err := controller.CreateKey(context.Background(), `{"name":"operator","role":"viewer"}`)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "DBConnectionError", err)
+	case errors.DBConnectionError:
+	}
+}
+
+func TestCalledGetKeys_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetAllKeys().Return([]map[string]interface{}{keyMap}, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, res, err := controller.GetKeys(context.Background())
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if keys, ok := res[KEYS].([]map[string]interface{}); !ok || len(keys) != 1 {
+		t.Errorf("Unexpected res: %v", res)
+	}
+}
+
+func TestCalledDeleteKey_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().DeleteKey(keyId).Return(nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.DeleteKey(context.Background(), keyId)
+
+	if code != results.OK {
+		t.Errorf("Expected code: %d, actual code: %d", results.OK, code)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+}
+
+func TestCalledDeleteKeyWhenDBHasNotMatchedKey_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().DeleteKey(keyId).Return(notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	code, err := controller.DeleteKey(context.Background(), keyId)
+
+	if code != results.ERROR {
+		t.Errorf("Expected code: %d, actual code: %d", results.ERROR, code)
+	}
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	case errors.NotFound:
+	}
+}
+
+func TestCalledVerifyKey_ExpectSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetKeyByHash(hashKey("secret")).Return(keyMap, nil),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	res, err := controller.VerifyKey(context.Background(), "secret")
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if res[NAME] != name {
+		t.Errorf("Expected name: %s, actual name: %v", name, res[NAME])
+	}
+}
+
+func TestCalledVerifyKeyWithUnknownKey_ExpectUnauthorizedReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbConnectionMockObj := dbmocks.NewMockDBConnection(ctrl)
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbConnectionMockObj.EXPECT().Connect(gomock.Any()).Return(dbManagerMockObj, nil),
+		dbManagerMockObj.EXPECT().GetKeyByHash(hashKey("unknown")).Return(nil, notFoundError),
+		dbManagerMockObj.EXPECT().Close(),
+	)
+	// pass mockObj to a real object.
+	dbConnector = dbConnectionMockObj
+
+	_, err := controller.VerifyKey(context.Background(), "unknown")
+
+	switch err.(type) {
+	default:
+		t.Errorf("Expected err: %s, actual err: %v", "Unauthorized", err)
+	case errors.Unauthorized:
+	}
+}
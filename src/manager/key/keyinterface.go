@@ -0,0 +1,33 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package key
+
+import "context"
+
+type KeyInterface interface {
+	// CreateKey issues a new API key with the name given in body.
+	CreateKey(ctx context.Context, body string) (int, map[string]interface{}, error)
+
+	// GetKeys returns all API keys in databases as an array.
+	GetKeys(ctx context.Context) (int, map[string]interface{}, error)
+
+	// DeleteKey revokes the API key with a primary key matching the keyId argument.
+	DeleteKey(ctx context.Context, keyId string) (int, error)
+
+	// VerifyKey returns the API key matching the key argument.
+	VerifyKey(ctx context.Context, key string) (map[string]interface{}, error)
+}
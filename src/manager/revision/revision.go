@@ -0,0 +1,117 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package revision provides operations on revisions of descriptors applied to apps of agents.
+// A revision is recorded whenever a descriptor is deployed or updated through the manager,
+// and an app is rolled back by applying the descriptor of an earlier revision again.
+package revision
+
+import (
+	"commons/errors"
+	"db"
+	"encoding/json"
+	"manager/catalog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	REVISION   = "revision"   // used to indicate a number of revision.
+	TARGET     = "target"     // used to indicate a number of revision which is applied again.
+	DESCRIPTOR = "descriptor" // used to indicate a descriptor of revision.
+	CATALOG    = "catalog"    // used to indicate a catalog version of revision.
+)
+
+var now = time.Now
+
+// Record records the descriptor applied to the app on the agent as a new revision,
+// with the catalog version if the descriptor is from the catalog.
+// If successful, the number of the revision is returned.
+// otherwise, an appropriate error will be returned.
+func Record(dbManager db.DBManager, agentId string, appId string, descriptor string, ref *catalog.Reference) (int, error) {
+	name, version := "", ""
+	if ref != nil {
+		name, version = ref.App, ref.Version
+	}
+	return dbManager.AddAgentRevision(agentId, appId, descriptor, catalog.Checksum(descriptor), name, version, now().Unix())
+}
+
+// ParseTarget returns the number of revision given in the revision field of the body of a rollback request.
+// If the body is empty or the field is not given, 0 is returned, which means the previous revision.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func ParseTarget(body string) (int, error) {
+	if strings.TrimSpace(body) == "" {
+		return 0, nil
+	}
+
+	bodyMap := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(body), &bodyMap); err != nil {
+		return 0, errors.InvalidJSON{Message: "Unmarshalling Failed"}
+	}
+
+	value, exists := bodyMap[REVISION]
+	if !exists {
+		return 0, nil
+	}
+
+	number, ok := value.(float64)
+	if !ok || number < 1 || number != float64(int(number)) {
+		return 0, errors.InvalidParam{Message: "revision should be a positive integer"}
+	}
+	return int(number), nil
+}
+
+// Select returns the revision of the app on the agent to roll back to.
+// If number is 0, the revision before the latest one is returned.
+// If successful, this function returns an error as nil.
+// otherwise, an appropriate error will be returned.
+func Select(dbManager db.DBManager, agentId string, appId string, number int) (map[string]interface{}, error) {
+	revisions, err := dbManager.GetAgentRevisions(agentId, appId)
+	if err != nil {
+		return nil, err
+	}
+
+	if number == 0 {
+		if len(revisions) < 2 {
+			return nil, errors.InvalidParam{Message: "no previous revision", Kind: errors.APP, ID: appId}
+		}
+		return revisions[len(revisions)-2], nil
+	}
+
+	for _, revision := range revisions {
+		if revision[REVISION] == number {
+			return revision, nil
+		}
+	}
+	return nil, errors.NotFound{Kind: errors.REVISION, ID: strconv.Itoa(number)}
+}
+
+// Reference returns the catalog version of the revision.
+// If the descriptor of the revision is not from the catalog, nil is returned.
+func Reference(revision map[string]interface{}) *catalog.Reference {
+	ref, ok := revision[CATALOG].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	name, _ := ref[catalog.APP].(string)
+	version, _ := ref[catalog.VERSION].(string)
+	checksum, _ := revision[catalog.CHECKSUM].(string)
+	return &catalog.Reference{App: name, Version: version, Checksum: checksum}
+}
@@ -0,0 +1,196 @@
+/*******************************************************************************
+ * Copyright 2017 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+package revision
+
+import (
+	"commons/errors"
+	dbmocks "db/mocks"
+	"github.com/golang/mock/gomock"
+	"manager/catalog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	agentId    = "000000000000000000000001"
+	appId      = "000000000000000000000002"
+	descriptor = "services:\n  monitor:\n    image: monitor:1.4.0\n"
+)
+
+var (
+	first = map[string]interface{}{
+		"revision":   1,
+		"descriptor": "services:\n  monitor:\n    image: monitor:1.3.0\n",
+		"checksum":   "sum1",
+		"appliedat":  int64(100),
+		"catalog":    map[string]interface{}{"app": "line-monitor", "version": "1.3.0"},
+	}
+	second = map[string]interface{}{
+		"revision":   2,
+		"descriptor": descriptor,
+		"checksum":   "sum2",
+		"appliedat":  int64(200),
+	}
+	revisions = []map[string]interface{}{first, second}
+)
+
+func TestCalledRecord_ExpectRevisionNumberReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	current := time.Unix(1000, 0)
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().AddAgentRevision(agentId, appId, descriptor, catalog.Checksum(descriptor), "line-monitor", "1.4.0", int64(1000)).Return(3, nil),
+	)
+
+	number, err := Record(dbManagerMockObj, agentId, appId, descriptor, &catalog.Reference{App: "line-monitor", Version: "1.4.0"})
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if number != 3 {
+		t.Errorf("Expected number: %d, actual number: %d", 3, number)
+	}
+}
+
+func TestCalledParseTarget_ExpectNumberReturn(t *testing.T) {
+	testList := map[string]int{
+		"":                0,
+		"{}":              0,
+		`{"revision":2}`:  2,
+		`{"revision":10}`: 10,
+	}
+
+	for body, expected := range testList {
+		number, err := ParseTarget(body)
+		if err != nil {
+			t.Errorf("Unexpected err with %q: %s", body, err.Error())
+		}
+		if number != expected {
+			t.Errorf("Expected number with %q: %d, actual number: %d", body, expected, number)
+		}
+	}
+}
+
+func TestCalledParseTargetWithInvalidRevision_ExpectErrorReturn(t *testing.T) {
+	for _, body := range []string{`{"revision":0}`, `{"revision":-1}`, `{"revision":1.5}`, `{"revision":"2"}`} {
+		_, err := ParseTarget(body)
+		if !errors.Is(err, errors.InvalidParam{}) {
+			t.Errorf("Expected err with %q: %s, actual err: %v", body, "InvalidParam", err)
+		}
+	}
+
+	_, err := ParseTarget("error")
+	if !errors.Is(err, errors.InvalidJSON{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidJSON", err)
+	}
+}
+
+func TestCalledSelect_ExpectPreviousRevisionReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(revisions, nil),
+	)
+
+	revision, err := Select(dbManagerMockObj, agentId, appId, 0)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(first, revision) {
+		t.Errorf("Expected revision: %v, actual revision: %v", first, revision)
+	}
+}
+
+func TestCalledSelectWithNumber_ExpectRevisionReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(revisions, nil),
+	)
+
+	revision, err := Select(dbManagerMockObj, agentId, appId, 2)
+
+	if err != nil {
+		t.Errorf("Unexpected err: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(second, revision) {
+		t.Errorf("Expected revision: %v, actual revision: %v", second, revision)
+	}
+}
+
+func TestCalledSelectWithoutPreviousRevision_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(revisions[1:], nil),
+	)
+
+	_, err := Select(dbManagerMockObj, agentId, appId, 0)
+
+	if !errors.Is(err, errors.InvalidParam{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "InvalidParam", err)
+	}
+}
+
+func TestCalledSelectWithUnknownNumber_ExpectErrorReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbManagerMockObj := dbmocks.NewMockDBManager(ctrl)
+
+	gomock.InOrder(
+		dbManagerMockObj.EXPECT().GetAgentRevisions(agentId, appId).Return(revisions, nil),
+	)
+
+	_, err := Select(dbManagerMockObj, agentId, appId, 5)
+
+	if !errors.Is(err, errors.NotFound{}) {
+		t.Errorf("Expected err: %s, actual err: %v", "NotFound", err)
+	}
+}
+
+func TestCalledReference_ExpectCatalogVersionReturn(t *testing.T) {
+	expected := &catalog.Reference{App: "line-monitor", Version: "1.3.0", Checksum: "sum1"}
+
+	if ref := Reference(first); !reflect.DeepEqual(expected, ref) {
+		t.Errorf("Expected ref: %v, actual ref: %v", expected, ref)
+	}
+
+	if ref := Reference(second); ref != nil {
+		t.Errorf("Expected ref: nil, actual ref: %v", ref)
+	}
+}
@@ -22,7 +22,11 @@ package messenger
 import (
 	"bytes"
 	"commons/logger"
+	"commons/requestid"
 	"commons/url"
+	"context"
+	"crypto/tls"
+	"errors"
 	"net/http"
 	"sort"
 	"sync"
@@ -31,9 +35,32 @@ import (
 func init() {
 	sendHttpRequest = httpRequester
 	httpInterface = useHttp
+	httpTag = "http://"
+	httpClient = http.DefaultClient
 }
 
-var sendHttpRequest func(method string, urls []string, dataOptional ...string) []httpResponse
+var httpTag string
+var httpClient *http.Client
+
+// SetTLSConfig makes requests to agents be sent over HTTPS with the given settings.
+// If tlsConfig is nil, requests are sent over HTTP.
+func SetTLSConfig(tlsConfig *tls.Config) {
+	if tlsConfig == nil {
+		httpTag = "http://"
+		httpClient = http.DefaultClient
+		return
+	}
+
+	httpTag = "https://"
+	httpClient = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+}
+
+var sendHttpRequest func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse
 
 // A httpResponse represents an HTTP response received from remote device.
 type httpResponse struct {
@@ -46,92 +73,125 @@ type sortRespSlice []httpResponse
 type SdamMsgrImpl struct{}
 
 // DeployApp make a url using /api/v1/deploy and send a HTTP(POST) request.
-func (SdamMsgrImpl) DeployApp(members []map[string]interface{}, data string) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) DeployApp(ctx context.Context, members []map[string]interface{}, data string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	urls := setUrlList(members, url.Deploy())
+	respList := sendHttpRequest(ctx, "POST", urls, data)
+	return changeToReturnValue(respList)
+}
+
+// DeployAppPerMember make a url using /api/v1/deploy and send a HTTP(POST) request
+// whose body is the data at the same index as the member.
+func (SdamMsgrImpl) DeployAppPerMember(ctx context.Context, members []map[string]interface{}, data []string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(members, url.Deploy())
-	respList := sendHttpRequest("POST", urls, data)
+	respList := sendHttpRequest(ctx, "POST", urls, data...)
 	return changeToReturnValue(respList)
 }
 
 // InfoApp make a url using /api/v1/apps/{appId} and send a HTTP(GET) request.
-func (SdamMsgrImpl) InfoApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) InfoApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(members, url.Apps(), "/", appId)
-	respList := sendHttpRequest("GET", urls)
+	respList := sendHttpRequest(ctx, "GET", urls)
 	return changeToReturnValue(respList)
 }
 
 // DeleteApp make a url using /api/v1/apps/{appId} and send a HTTP(DELETE) request.
-func (SdamMsgrImpl) DeleteApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) DeleteApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(members, url.Apps(), "/", appId)
-	respList := sendHttpRequest("DELETE", urls)
+	respList := sendHttpRequest(ctx, "DELETE", urls)
 	return changeToReturnValue(respList)
 }
 
 // StartApp make a url using /api/v1/apps/{appId}/start and send a HTTP(POST) request.
-func (SdamMsgrImpl) StartApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) StartApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(members, url.Apps(), "/", appId, url.Start())
-	respList := sendHttpRequest("POST", urls)
+	respList := sendHttpRequest(ctx, "POST", urls)
 	return changeToReturnValue(respList)
 }
 
 // StopApp make a url using /api/v1/apps/{appId}/stop and send a HTTP(POST) request.
-func (SdamMsgrImpl) StopApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) StopApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(members, url.Apps(), "/", appId, url.Stop())
-	respList := sendHttpRequest("POST", urls)
+	respList := sendHttpRequest(ctx, "POST", urls)
 	return changeToReturnValue(respList)
 }
 
 // UpdateApp make a url using /api/v1/apps/{appId}/update and send a HTTP(POST) request.
-func (SdamMsgrImpl) UpdateApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) UpdateApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(members, url.Apps(), "/", appId, url.Update())
-	respList := sendHttpRequest("POST", urls)
+	respList := sendHttpRequest(ctx, "POST", urls)
 	return changeToReturnValue(respList)
 }
 
 // InfoApps make a url using /api/v1/apps and send a HTTP(GET) request.
-func (SdamMsgrImpl) InfoApps(member []map[string]interface{}) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) InfoApps(ctx context.Context, member []map[string]interface{}) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(member, url.Apps())
-	respList := sendHttpRequest("GET", urls)
+	respList := sendHttpRequest(ctx, "GET", urls)
 	return changeToReturnValue(respList)
 }
 
 // UpdateAppInfo make a url using /api/v1/apps/{appId} and send a HTTP(POST) request.
-func (SdamMsgrImpl) UpdateAppInfo(member []map[string]interface{}, appId string, data string) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) UpdateAppInfo(ctx context.Context, member []map[string]interface{}, appId string, data string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(member, url.Apps(), "/", appId)
-	respList := sendHttpRequest("POST", urls, data)
+	respList := sendHttpRequest(ctx, "POST", urls, data)
+	return changeToReturnValue(respList)
+}
+
+// UpdateAppInfoPerMember make a url using /api/v1/apps/{appId} and send a HTTP(POST) request
+// whose body is the data at the same index as the member.
+func (SdamMsgrImpl) UpdateAppInfoPerMember(ctx context.Context, members []map[string]interface{}, appId string, data []string) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	urls := setUrlList(members, url.Apps(), "/", appId)
+	respList := sendHttpRequest(ctx, "POST", urls, data...)
 	return changeToReturnValue(respList)
 }
 
 // Unregister make a url using /api/v1/unregister and send a HTTP(POST) request.
-func (SdamMsgrImpl) Unregister(member []map[string]interface{}) (respCode []int, respBody []string) {
-	logger.Logging(logger.DEBUG, "IN")
-	defer logger.Logging(logger.DEBUG, "OUT")
+func (SdamMsgrImpl) Unregister(ctx context.Context, member []map[string]interface{}) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
 
 	urls := setUrlList(member, url.Unregister())
-	respList := sendHttpRequest("POST", urls)
+	respList := sendHttpRequest(ctx, "POST", urls)
+	return changeToReturnValue(respList)
+}
+
+// Health make a url using /api/v1/health and send a HTTP(GET) request.
+// The request is cancelled when ctx is done, so a deadline of ctx bounds the time to wait.
+func (SdamMsgrImpl) Health(ctx context.Context, members []map[string]interface{}) (respCode []int, respBody []string) {
+	logger.LoggingContext(ctx, logger.DEBUG, "IN")
+	defer logger.LoggingContext(ctx, logger.DEBUG, "OUT")
+
+	urls := setUrlList(members, url.Health())
+	respList := sendHttpRequest(ctx, "GET", urls)
 	return changeToReturnValue(respList)
 }
 
@@ -159,22 +219,27 @@ type _UseHttp struct{}
 var httpInterface _HTTPInterface
 var useHttp _UseHttp
 
-// DoWrapper calls Do function of http.DefaultClient to send an HTTP request.
+// DoWrapper calls Do function of the configured http client to send an HTTP request.
 func (useHttp _UseHttp) DoWrapper(req *http.Request) (*http.Response, error) {
-	return http.DefaultClient.Do(req)
+	return httpClient.Do(req)
 }
 
 // httpRequester make a new request given a method, url, and optional body.
 // and send a request to target device.
+// A single body is sent to every url, otherwise the body at the same index as the url is sent.
+// The request id carried by ctx is forwarded to the device in X-Request-ID header,
+// and the request is cancelled when ctx is done.
 // A list of httpResponse structure will be returned by this function.
-func httpRequester(method string, urls []string, dataOptional ...string) []httpResponse {
+func httpRequester(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
+	requestId := requestid.FromContext(ctx)
+
 	var wg sync.WaitGroup
 	wg.Add(len(urls))
 
 	respChannel := make(chan httpResponse, len(urls))
 	for i := range urls {
 		go func(idx int) {
-			logger.Logging(logger.DEBUG, "sending http request:", urls[idx])
+			logger.LoggingContext(ctx, logger.DEBUG, "sending http request:", urls[idx])
 
 			var err error
 			var req *http.Request
@@ -187,6 +252,10 @@ func httpRequester(method string, urls []string, dataOptional ...string) []httpR
 				req, err = http.NewRequest(method, urls[idx], bytes.NewBuffer(nil))
 			case 1:
 				req, err = http.NewRequest(method, urls[idx], bytes.NewBuffer([]byte(dataOptional[0])))
+			case len(urls):
+				req, err = http.NewRequest(method, urls[idx], bytes.NewBuffer([]byte(dataOptional[idx])))
+			default:
+				err = errors.New("number of bodies does not match number of urls")
 			}
 
 			if err != nil {
@@ -194,6 +263,10 @@ func httpRequester(method string, urls []string, dataOptional ...string) []httpR
 				resp.err = err.Error()
 				respChannel <- resp
 			} else {
+				req = req.WithContext(ctx)
+				if requestId != "" {
+					req.Header.Set(requestid.HEADER, requestId)
+				}
 				resp.resp, err = httpInterface.DoWrapper(req)
 				if err != nil {
 					resp.err = err.Error()
@@ -236,7 +309,6 @@ func changeToReturnValue(respList []httpResponse) (respCode []int, respBody []st
 
 // setUrlList make a list of urls that can be used to send a http request.
 func setUrlList(members []map[string]interface{}, api_parts ...string) (urls []string) {
-	var httpTag string = "http://"
 	var full_url bytes.Buffer
 
 	for i := range members {
@@ -17,8 +17,11 @@
 package messenger
 
 import (
+	"commons/requestid"
 	"commons/url"
 	"bytes"
+	"context"
+	"crypto/tls"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -26,13 +29,13 @@ import (
 	"testing"
 )
 
-var doSomething func(method string, urls []string, dataOptional ...string) []httpResponse
+var doSomething func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse
 
-func mockSendHttpRequest(method string, urls []string, dataOptional ...string) []httpResponse {
-	return doSomething(method, urls, dataOptional...)
+func mockSendHttpRequest(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
+	return doSomething(ctx, method, urls, dataOptional...)
 }
 
-type httpFunc func(method string, urls []string, dataOptional ...string) []httpResponse
+type httpFunc func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse
 
 var oldSendHttpRequest httpFunc
 
@@ -84,7 +87,7 @@ func TestApiFunctions(t *testing.T) {
 
 	t.Run("DeployApp", func(t *testing.T) {
 		data := "data"
-		doSomething = func(method string, urls []string, dataOptional ...string) []httpResponse {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
 			if method != "POST" {
 				t.Error()
 			}
@@ -104,12 +107,12 @@ func TestApiFunctions(t *testing.T) {
 			}
 			return respList
 		}
-		messenger.DeployApp(group_members, data)
+		messenger.DeployApp(context.Background(), group_members, data)
 	})
 
 	t.Run("InfoApp", func(t *testing.T) {
 		appId := "appId"
-		doSomething = func(method string, urls []string, dataOptional ...string) []httpResponse {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
 			if method != "GET" {
 				t.Error()
 			}
@@ -126,12 +129,12 @@ func TestApiFunctions(t *testing.T) {
 			}
 			return respList
 		}
-		messenger.InfoApp(group_members, appId)
+		messenger.InfoApp(context.Background(), group_members, appId)
 	})
 
 	t.Run("DeleteApp", func(t *testing.T) {
 		appId := "appId"
-		doSomething = func(method string, urls []string, dataOptional ...string) []httpResponse {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
 			if method != "DELETE" {
 				t.Error()
 			}
@@ -148,12 +151,12 @@ func TestApiFunctions(t *testing.T) {
 			}
 			return respList
 		}
-		messenger.DeleteApp(group_members, appId)
+		messenger.DeleteApp(context.Background(), group_members, appId)
 	})
 
 	t.Run("StartApp", func(t *testing.T) {
 		appId := "appId"
-		doSomething = func(method string, urls []string, dataOptional ...string) []httpResponse {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
 			if method != "POST" {
 				t.Error()
 			}
@@ -171,12 +174,12 @@ func TestApiFunctions(t *testing.T) {
 			}
 			return respList
 		}
-		messenger.StartApp(group_members, appId)
+		messenger.StartApp(context.Background(), group_members, appId)
 	})
 
 	t.Run("StopApp", func(t *testing.T) {
 		appId := "appId"
-		doSomething = func(method string, urls []string, dataOptional ...string) []httpResponse {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
 			if method != "POST" {
 				t.Error()
 			}
@@ -194,12 +197,12 @@ func TestApiFunctions(t *testing.T) {
 			}
 			return respList
 		}
-		messenger.StopApp(group_members, appId)
+		messenger.StopApp(context.Background(), group_members, appId)
 	})
 
 	t.Run("UpdateApp", func(t *testing.T) {
 		appId := "appId"
-		doSomething = func(method string, urls []string, dataOptional ...string) []httpResponse {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
 			if method != "POST" {
 				t.Error()
 			}
@@ -217,11 +220,11 @@ func TestApiFunctions(t *testing.T) {
 			}
 			return respList
 		}
-		messenger.UpdateApp(group_members, appId)
+		messenger.UpdateApp(context.Background(), group_members, appId)
 	})
 
 	t.Run("InfoApps", func(t *testing.T) {
-		doSomething = func(method string, urls []string, dataOptional ...string) []httpResponse {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
 			if method != "GET" {
 				t.Error()
 			}
@@ -238,13 +241,13 @@ func TestApiFunctions(t *testing.T) {
 			}
 			return respList
 		}
-		messenger.InfoApps(group_members)
+		messenger.InfoApps(context.Background(), group_members)
 	})
 
 	t.Run("UpdateAppInfo", func(t *testing.T) {
 		appId := "appId"
 		data := "data"
-		doSomething = func(method string, urls []string, dataOptional ...string) []httpResponse {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
 			if method != "POST" {
 				t.Error()
 			}
@@ -264,7 +267,91 @@ func TestApiFunctions(t *testing.T) {
 			}
 			return respList
 		}
-		messenger.UpdateAppInfo(group_members, appId, data)
+		messenger.UpdateAppInfo(context.Background(), group_members, appId, data)
+	})
+
+	t.Run("Health", func(t *testing.T) {
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
+			if method != "GET" {
+				t.Error()
+			}
+			for i := 0; i < len(urls); i++ {
+				expectedUrl := "http://" + group_members[i]["host"].(string) +
+					":" + group_members[i]["port"].(string) + "/api/v1/health"
+				if expectedUrl != urls[i] {
+					t.Error(expectedUrl, urls[i])
+				}
+			}
+			var respList []httpResponse
+			for i := 0; i < len(urls); i++ {
+				respList = append(respList, httpResponse{index: i, resp: nil, err: ""})
+			}
+			return respList
+		}
+		messenger.Health(context.Background(), group_members)
+	})
+
+	t.Run("DeployAppPerMember", func(t *testing.T) {
+		var data []string
+		for i := range group_members {
+			data = append(data, "data"+strconv.Itoa(i))
+		}
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
+			if method != "POST" {
+				t.Error()
+			}
+			if len(dataOptional) != len(urls) {
+				t.Errorf("Expected bodies: %d, actual bodies: %d", len(urls), len(dataOptional))
+			}
+			for i := 0; i < len(urls); i++ {
+				expectedUrl := "http://" + group_members[i]["host"].(string) +
+					":" + group_members[i]["port"].(string) + "/api/v1/deploy"
+				if expectedUrl != urls[i] {
+					t.Error()
+				}
+				if dataOptional[i] != data[i] {
+					t.Errorf("Expected body: %s, actual body: %s", data[i], dataOptional[i])
+				}
+			}
+			var respList []httpResponse
+			for i := 0; i < len(urls); i++ {
+				respList = append(respList, httpResponse{index: i, resp: nil, err: ""})
+			}
+			return respList
+		}
+		messenger.DeployAppPerMember(context.Background(), group_members, data)
+	})
+
+	t.Run("UpdateAppInfoPerMember", func(t *testing.T) {
+		appId := "appId"
+		var data []string
+		for i := range group_members {
+			data = append(data, "data"+strconv.Itoa(i))
+		}
+		doSomething = func(ctx context.Context, method string, urls []string, dataOptional ...string) []httpResponse {
+			if method != "POST" {
+				t.Error()
+			}
+			if len(dataOptional) != len(urls) {
+				t.Errorf("Expected bodies: %d, actual bodies: %d", len(urls), len(dataOptional))
+			}
+			for i := 0; i < len(urls); i++ {
+				expectedUrl := "http://" + group_members[i]["host"].(string) +
+					":" + group_members[i]["port"].(string) + "/api/v1/apps/" + appId
+				if expectedUrl != urls[i] {
+					t.Error()
+				}
+				if dataOptional[i] != data[i] {
+					t.Errorf("Expected body: %s, actual body: %s", data[i], dataOptional[i])
+				}
+			}
+			var respList []httpResponse
+			for i := 0; i < len(urls); i++ {
+				respList = append(respList, httpResponse{index: i, resp: nil, err: ""})
+			}
+			return respList
+		}
+		messenger.UpdateAppInfoPerMember(context.Background(), group_members, appId, data)
 	})
 }
 
@@ -385,6 +472,39 @@ func TestSetUrlList(t *testing.T) {
 	})
 }
 
+func TestSetTLSConfig(t *testing.T) {
+	defer SetTLSConfig(nil)
+
+	members := []map[string]interface{}{
+		map[string]interface{}{
+			"host": "localhost",
+			"port": "8080",
+		}}
+
+	tlsConfig := &tls.Config{ServerName: "agent"}
+	SetTLSConfig(tlsConfig)
+
+	urls := setUrlList(members, url.Apps())
+	if urls[0] != "https://localhost:8080/api/v1/apps" {
+		t.Errorf("Expected url: %s, actual url: %s", "https://localhost:8080/api/v1/apps", urls[0])
+	}
+
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig != tlsConfig {
+		t.Error("Expected the given tls config to be used by http client")
+	}
+
+	SetTLSConfig(nil)
+
+	urls = setUrlList(members, url.Apps())
+	if urls[0] != "http://localhost:8080/api/v1/apps" {
+		t.Errorf("Expected url: %s, actual url: %s", "http://localhost:8080/api/v1/apps", urls[0])
+	}
+	if httpClient != http.DefaultClient {
+		t.Error("Expected default http client")
+	}
+}
+
 type _MockHttp struct{}
 
 var doWrapperReturn func(req *http.Request) (*http.Response, error)
@@ -420,7 +540,7 @@ func TestHttpRequester(t *testing.T) {
 		}
 	}
 
-	result := httpRequester("GET", testURLs)
+	result := httpRequester(context.Background(), "GET", testURLs)
 	for i, val := range result {
 		switch {
 		case i != 2 && val.err != "":
@@ -431,6 +551,57 @@ func TestHttpRequester(t *testing.T) {
 	}
 }
 
+func TestHttpRequesterWithRequestId(t *testing.T) {
+	tearDown := setUpHttpRequester()
+	defer tearDown()
+
+	testURLs := []string{
+		"http://0.0.0.0:8080",
+		"http://0.0.0.1:8080",
+	}
+
+	forwarded := make(chan string, len(testURLs))
+	doWrapperReturn = func(req *http.Request) (*http.Response, error) {
+		forwarded <- req.Header.Get(requestid.HEADER)
+		return &http.Response{}, nil
+	}
+
+	ctx := requestid.NewContext(context.Background(), "request")
+	httpRequester(ctx, "GET", testURLs)
+	close(forwarded)
+
+	count := 0
+	for id := range forwarded {
+		if id != "request" {
+			t.Errorf("Expected request id: %s, actual request id: %s", "request", id)
+		}
+		count++
+	}
+	if count != len(testURLs) {
+		t.Errorf("Expected requests: %d, actual requests: %d", len(testURLs), count)
+	}
+}
+
+func TestHttpRequesterWithCanceledContext(t *testing.T) {
+	tearDown := setUpHttpRequester()
+	defer tearDown()
+
+	doWrapperReturn = func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		return &http.Response{}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := httpRequester(ctx, "GET", []string{"http://0.0.0.0:8080"})
+	if result[0].err != context.Canceled.Error() {
+		t.Errorf("Expected err: %s, actual err: %s", context.Canceled.Error(), result[0].err)
+	}
+}
+
 func TestHttpRequesterinBody(t *testing.T) {
 	tearDown := setUpHttpRequester()
 	defer tearDown()
@@ -450,7 +621,7 @@ func TestHttpRequesterinBody(t *testing.T) {
 			return &http.Response{}, nil
 		}
 	}
-	result := httpRequester("GET", testURLs, "testData")
+	result := httpRequester(context.Background(), "GET", testURLs, "testData")
 	for i, val := range result {
 		switch {
 		case i != 2 && val.err != "":
@@ -460,3 +631,50 @@ func TestHttpRequesterinBody(t *testing.T) {
 		}
 	}
 }
+
+func TestHttpRequesterWithBodyPerUrl(t *testing.T) {
+	tearDown := setUpHttpRequester()
+	defer tearDown()
+
+	testURLs := []string{
+		"http://0.0.0.0:8080",
+		"http://0.0.0.1:8080",
+	}
+	bodies := []string{"body0", "body1"}
+
+	doWrapperReturn = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		return &http.Response{Body: ioutil.NopCloser(bytes.NewBuffer(body))}, nil
+	}
+
+	result := httpRequester(context.Background(), "POST", testURLs, bodies...)
+	for i, val := range result {
+		body, _ := ioutil.ReadAll(val.resp.Body)
+		if string(body) != bodies[i] {
+			t.Errorf("Expected body: %s, actual body: %s", bodies[i], string(body))
+		}
+	}
+}
+
+func TestHttpRequesterWithMismatchedBodies(t *testing.T) {
+	tearDown := setUpHttpRequester()
+	defer tearDown()
+
+	testURLs := []string{
+		"http://0.0.0.0:8080",
+		"http://0.0.0.1:8080",
+		"http://0.0.0.2:8080",
+	}
+
+	doWrapperReturn = func(req *http.Request) (*http.Response, error) {
+		t.Error("Expected no request to be sent")
+		return &http.Response{}, nil
+	}
+
+	result := httpRequester(context.Background(), "POST", testURLs, "body0", "body1")
+	for _, val := range result {
+		if val.resp != nil || val.err == "" {
+			t.Errorf("Expected error response, actual response: %v", val)
+		}
+	}
+}
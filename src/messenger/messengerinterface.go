@@ -16,14 +16,19 @@
  *******************************************************************************/
 package messenger
 
+import "context"
+
 type MessengerInterface interface {
-	DeployApp(members []map[string]interface{}, data string) (respCode []int, respBody []string)
-	InfoApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string)
-	DeleteApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string)
-	StartApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string)
-	StopApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string)
-	UpdateApp(members []map[string]interface{}, appId string) (respCode []int, respBody []string)
-	InfoApps(member []map[string]interface{}) (respCode []int, respBody []string)
-	UpdateAppInfo(member []map[string]interface{}, appId string, data string) (respCode []int, respBody []string)
-	Unregister(members []map[string]interface{}) (respCode []int, respBody []string)
+	DeployApp(ctx context.Context, members []map[string]interface{}, data string) (respCode []int, respBody []string)
+	DeployAppPerMember(ctx context.Context, members []map[string]interface{}, data []string) (respCode []int, respBody []string)
+	InfoApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string)
+	DeleteApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string)
+	StartApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string)
+	StopApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string)
+	UpdateApp(ctx context.Context, members []map[string]interface{}, appId string) (respCode []int, respBody []string)
+	InfoApps(ctx context.Context, member []map[string]interface{}) (respCode []int, respBody []string)
+	UpdateAppInfo(ctx context.Context, member []map[string]interface{}, appId string, data string) (respCode []int, respBody []string)
+	UpdateAppInfoPerMember(ctx context.Context, members []map[string]interface{}, appId string, data []string) (respCode []int, respBody []string)
+	Unregister(ctx context.Context, members []map[string]interface{}) (respCode []int, respBody []string)
+	Health(ctx context.Context, members []map[string]interface{}) (respCode []int, respBody []string)
 }
@@ -21,6 +21,7 @@
 package mocks
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -49,118 +50,157 @@ func (m *MockMessengerInterface) EXPECT() *MockMessengerInterfaceMockRecorder {
 }
 
 // DeployApp mocks base method
-func (m *MockMessengerInterface) DeployApp(members []map[string]interface{}, data string) ([]int, []string) {
-	ret := m.ctrl.Call(m, "DeployApp", members, data)
+func (m *MockMessengerInterface) DeployApp(ctx context.Context, members []map[string]interface{}, data string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "DeployApp", ctx, members, data)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // DeployApp indicates an expected call of DeployApp
-func (mr *MockMessengerInterfaceMockRecorder) DeployApp(members, data interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployApp", reflect.TypeOf((*MockMessengerInterface)(nil).DeployApp), members, data)
+func (mr *MockMessengerInterfaceMockRecorder) DeployApp(ctx, members, data interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployApp", reflect.TypeOf((*MockMessengerInterface)(nil).DeployApp), ctx, members, data)
+}
+
+// DeployAppPerMember mocks base method
+func (m *MockMessengerInterface) DeployAppPerMember(ctx context.Context, members []map[string]interface{}, data []string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "DeployAppPerMember", ctx, members, data)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].([]string)
+	return ret0, ret1
+}
+
+// DeployAppPerMember indicates an expected call of DeployAppPerMember
+func (mr *MockMessengerInterfaceMockRecorder) DeployAppPerMember(ctx, members, data interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployAppPerMember", reflect.TypeOf((*MockMessengerInterface)(nil).DeployAppPerMember), ctx, members, data)
 }
 
 // InfoApp mocks base method
-func (m *MockMessengerInterface) InfoApp(members []map[string]interface{}, appId string) ([]int, []string) {
-	ret := m.ctrl.Call(m, "InfoApp", members, appId)
+func (m *MockMessengerInterface) InfoApp(ctx context.Context, members []map[string]interface{}, appId string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "InfoApp", ctx, members, appId)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // InfoApp indicates an expected call of InfoApp
-func (mr *MockMessengerInterfaceMockRecorder) InfoApp(members, appId interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InfoApp", reflect.TypeOf((*MockMessengerInterface)(nil).InfoApp), members, appId)
+func (mr *MockMessengerInterfaceMockRecorder) InfoApp(ctx, members, appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InfoApp", reflect.TypeOf((*MockMessengerInterface)(nil).InfoApp), ctx, members, appId)
 }
 
 // DeleteApp mocks base method
-func (m *MockMessengerInterface) DeleteApp(members []map[string]interface{}, appId string) ([]int, []string) {
-	ret := m.ctrl.Call(m, "DeleteApp", members, appId)
+func (m *MockMessengerInterface) DeleteApp(ctx context.Context, members []map[string]interface{}, appId string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "DeleteApp", ctx, members, appId)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // DeleteApp indicates an expected call of DeleteApp
-func (mr *MockMessengerInterfaceMockRecorder) DeleteApp(members, appId interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApp", reflect.TypeOf((*MockMessengerInterface)(nil).DeleteApp), members, appId)
+func (mr *MockMessengerInterfaceMockRecorder) DeleteApp(ctx, members, appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApp", reflect.TypeOf((*MockMessengerInterface)(nil).DeleteApp), ctx, members, appId)
 }
 
 // StartApp mocks base method
-func (m *MockMessengerInterface) StartApp(members []map[string]interface{}, appId string) ([]int, []string) {
-	ret := m.ctrl.Call(m, "StartApp", members, appId)
+func (m *MockMessengerInterface) StartApp(ctx context.Context, members []map[string]interface{}, appId string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "StartApp", ctx, members, appId)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // StartApp indicates an expected call of StartApp
-func (mr *MockMessengerInterfaceMockRecorder) StartApp(members, appId interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartApp", reflect.TypeOf((*MockMessengerInterface)(nil).StartApp), members, appId)
+func (mr *MockMessengerInterfaceMockRecorder) StartApp(ctx, members, appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartApp", reflect.TypeOf((*MockMessengerInterface)(nil).StartApp), ctx, members, appId)
 }
 
 // StopApp mocks base method
-func (m *MockMessengerInterface) StopApp(members []map[string]interface{}, appId string) ([]int, []string) {
-	ret := m.ctrl.Call(m, "StopApp", members, appId)
+func (m *MockMessengerInterface) StopApp(ctx context.Context, members []map[string]interface{}, appId string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "StopApp", ctx, members, appId)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // StopApp indicates an expected call of StopApp
-func (mr *MockMessengerInterfaceMockRecorder) StopApp(members, appId interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopApp", reflect.TypeOf((*MockMessengerInterface)(nil).StopApp), members, appId)
+func (mr *MockMessengerInterfaceMockRecorder) StopApp(ctx, members, appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopApp", reflect.TypeOf((*MockMessengerInterface)(nil).StopApp), ctx, members, appId)
 }
 
 // UpdateApp mocks base method
-func (m *MockMessengerInterface) UpdateApp(members []map[string]interface{}, appId string) ([]int, []string) {
-	ret := m.ctrl.Call(m, "UpdateApp", members, appId)
+func (m *MockMessengerInterface) UpdateApp(ctx context.Context, members []map[string]interface{}, appId string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "UpdateApp", ctx, members, appId)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // UpdateApp indicates an expected call of UpdateApp
-func (mr *MockMessengerInterfaceMockRecorder) UpdateApp(members, appId interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApp", reflect.TypeOf((*MockMessengerInterface)(nil).UpdateApp), members, appId)
+func (mr *MockMessengerInterfaceMockRecorder) UpdateApp(ctx, members, appId interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApp", reflect.TypeOf((*MockMessengerInterface)(nil).UpdateApp), ctx, members, appId)
 }
 
 // InfoApps mocks base method
-func (m *MockMessengerInterface) InfoApps(member []map[string]interface{}) ([]int, []string) {
-	ret := m.ctrl.Call(m, "InfoApps", member)
+func (m *MockMessengerInterface) InfoApps(ctx context.Context, member []map[string]interface{}) ([]int, []string) {
+	ret := m.ctrl.Call(m, "InfoApps", ctx, member)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // InfoApps indicates an expected call of InfoApps
-func (mr *MockMessengerInterfaceMockRecorder) InfoApps(member interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InfoApps", reflect.TypeOf((*MockMessengerInterface)(nil).InfoApps), member)
+func (mr *MockMessengerInterfaceMockRecorder) InfoApps(ctx, member interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InfoApps", reflect.TypeOf((*MockMessengerInterface)(nil).InfoApps), ctx, member)
 }
 
 // UpdateAppInfo mocks base method
-func (m *MockMessengerInterface) UpdateAppInfo(member []map[string]interface{}, appId, data string) ([]int, []string) {
-	ret := m.ctrl.Call(m, "UpdateAppInfo", member, appId, data)
+func (m *MockMessengerInterface) UpdateAppInfo(ctx context.Context, member []map[string]interface{}, appId, data string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "UpdateAppInfo", ctx, member, appId, data)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // UpdateAppInfo indicates an expected call of UpdateAppInfo
-func (mr *MockMessengerInterfaceMockRecorder) UpdateAppInfo(member, appId, data interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppInfo", reflect.TypeOf((*MockMessengerInterface)(nil).UpdateAppInfo), member, appId, data)
+func (mr *MockMessengerInterfaceMockRecorder) UpdateAppInfo(ctx, member, appId, data interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppInfo", reflect.TypeOf((*MockMessengerInterface)(nil).UpdateAppInfo), ctx, member, appId, data)
+}
+
+// UpdateAppInfoPerMember mocks base method
+func (m *MockMessengerInterface) UpdateAppInfoPerMember(ctx context.Context, members []map[string]interface{}, appId string, data []string) ([]int, []string) {
+	ret := m.ctrl.Call(m, "UpdateAppInfoPerMember", ctx, members, appId, data)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].([]string)
+	return ret0, ret1
+}
+
+// UpdateAppInfoPerMember indicates an expected call of UpdateAppInfoPerMember
+func (mr *MockMessengerInterfaceMockRecorder) UpdateAppInfoPerMember(ctx, members, appId, data interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppInfoPerMember", reflect.TypeOf((*MockMessengerInterface)(nil).UpdateAppInfoPerMember), ctx, members, appId, data)
 }
 
 // Unregister mocks base method
-func (m *MockMessengerInterface) Unregister(members []map[string]interface{}) ([]int, []string) {
-	ret := m.ctrl.Call(m, "Unregister", members)
+func (m *MockMessengerInterface) Unregister(ctx context.Context, members []map[string]interface{}) ([]int, []string) {
+	ret := m.ctrl.Call(m, "Unregister", ctx, members)
 	ret0, _ := ret[0].([]int)
 	ret1, _ := ret[1].([]string)
 	return ret0, ret1
 }
 
 // Unregister indicates an expected call of Unregister
-func (mr *MockMessengerInterfaceMockRecorder) Unregister(members interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unregister", reflect.TypeOf((*MockMessengerInterface)(nil).Unregister), members)
+func (mr *MockMessengerInterfaceMockRecorder) Unregister(ctx, members interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unregister", reflect.TypeOf((*MockMessengerInterface)(nil).Unregister), ctx, members)
+}
+
+// Health mocks base method
+func (m *MockMessengerInterface) Health(ctx context.Context, members []map[string]interface{}) ([]int, []string) {
+	ret := m.ctrl.Call(m, "Health", ctx, members)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].([]string)
+	return ret0, ret1
+}
+
+// Health indicates an expected call of Health
+func (mr *MockMessengerInterfaceMockRecorder) Health(ctx, members interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockMessengerInterface)(nil).Health), ctx, members)
 }
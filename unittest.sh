@@ -4,7 +4,7 @@ export GOPATH=$PWD
 
 go get github.com/golang/mock/gomock
 
-pkg_list=("api" "commons/errors" "commons/logger" "commons/url" "db" "db/mongo" "manager/agent" "manager/group" "messenger")
+pkg_list=("api" "api/router" "api/auth" "api/key" "api/catalog" "api/openapi" "commons/compose" "commons/config" "commons/errors" "commons/paging" "commons/requestid" "commons/tlsconfig" "commons/labels" "commons/logger" "commons/url" "db" "db/mongo" "manager/agent" "manager/catalog" "manager/event" "manager/group" "manager/health" "manager/key" "manager/revision" "messenger")
 
 count=0
 for pkg in "${pkg_list[@]}"; do